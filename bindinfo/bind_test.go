@@ -40,8 +40,10 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	utilparser "github.com/pingcap/tidb/util/parser"
 	"github.com/pingcap/tidb/util/stmtsummary"
@@ -2452,3 +2454,91 @@ func (s *testSerialSuite) TestOptimizeOnlyOnce(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows())
 	c.Assert(failpoint.Disable("github.com/pingcap/tidb/planner/checkOptimizeCountOne"), IsNil)
 }
+
+func (s *testSuite) TestPlanRegressionRollback(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.cleanBindingEnv(tk)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("delete from mysql.plan_regression_history")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, key(b))")
+
+	sql := "select * from t where b > 10"
+	normalizedSQL, digest := parser.NormalizeDigest(sql)
+	addPlan := func(planDigest, planHint string, latency time.Duration, startTime time.Time) {
+		stmtsummary.StmtSummaryByDigestMap.AddStatement(&stmtsummary.StmtExecInfo{
+			SchemaName:    "test",
+			OriginalSQL:   sql,
+			NormalizedSQL: normalizedSQL,
+			Digest:        digest.String(),
+			PlanDigest:    planDigest,
+			PlanGenerator: func() (string, string) { return "", planHint },
+			User:          "root",
+			TotalLatency:  latency,
+			StmtCtx:       &stmtctx.StatementContext{StmtType: "Select"},
+			CopTasks:      &stmtctx.CopTasksDetails{},
+			ExecDetail:    &execdetails.ExecDetails{},
+			StartTime:     startTime,
+			Succeed:       true,
+		})
+	}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		addPlan("good", "use_index(@`sel_1` `test`.`t` `b`)", time.Millisecond, now.Add(time.Duration(i)*time.Second))
+	}
+	// The new plan is not regarded as a regression until it's executed enough times.
+	addPlan("bad", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now.Add(10*time.Second))
+	s.domain.BindHandle().HandlePlanRegressions()
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	for i := 0; i < 2; i++ {
+		addPlan("bad", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now.Add(time.Duration(11+i)*time.Second))
+	}
+	s.domain.BindHandle().HandlePlanRegressions()
+	rows := tk.MustQuery("show global bindings").Rows()
+	c.Assert(len(rows), Equals, 1)
+	c.Assert(rows[0][0], Equals, "select * from `test` . `t` where `b` > ?")
+	c.Assert(rows[0][1], Equals, "SELECT /*+ use_index(@`sel_1` `test`.`t` `b`)*/ * FROM `test`.`t` WHERE `b` > 10")
+	c.Assert(rows[0][8], Equals, bindinfo.Regression)
+	tk.MustQuery("select schema_name, regressed_plan_digest, regressed_avg_latency, good_plan_digest, good_avg_latency, action from mysql.plan_regression_history").
+		Check(testkit.Rows("test bad 10000000 good 1000000 bind"))
+
+	// Each regression is only handled once.
+	s.domain.BindHandle().HandlePlanRegressions()
+	tk.MustQuery("select count(*) from mysql.plan_regression_history").Check(testkit.Rows("1"))
+
+	// The plans are not rolled back if the statement has a binding.
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	for i := 0; i < 3; i++ {
+		addPlan("good", "use_index(@`sel_1` `test`.`t` `b`)", time.Millisecond, now.Add(time.Duration(i)*time.Second))
+		addPlan("bad2", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now.Add(time.Duration(10+i)*time.Second))
+	}
+	s.domain.BindHandle().HandlePlanRegressions()
+	tk.MustQuery("select regressed_plan_digest, action, reason from mysql.plan_regression_history where regressed_plan_digest = 'bad2'").
+		Check(testkit.Rows("bad2 skip binding already exists"))
+
+	// The regressions evicted from the statement summary are forgotten. They're not recorded again when they're
+	// detected again, because their bindings are kept.
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	s.domain.BindHandle().HandlePlanRegressions()
+	for i := 0; i < 3; i++ {
+		addPlan("good", "use_index(@`sel_1` `test`.`t` `b`)", time.Millisecond, now.Add(time.Duration(i)*time.Second))
+		addPlan("bad", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now.Add(time.Duration(10+i)*time.Second))
+	}
+	s.domain.BindHandle().HandlePlanRegressions()
+	tk.MustQuery("select regressed_plan_digest, action, reason from mysql.plan_regression_history order by regressed_plan_digest").
+		Check(testkit.Rows("bad bind ", "bad2 skip binding already exists"))
+
+	// The regression is rolled back again if its binding is dropped.
+	tk.MustExec("drop global binding for " + sql)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	s.domain.BindHandle().HandlePlanRegressions()
+	for i := 0; i < 3; i++ {
+		addPlan("good", "use_index(@`sel_1` `test`.`t` `b`)", time.Millisecond, now.Add(time.Duration(i)*time.Second))
+		addPlan("bad", "use_index(@`sel_1` `test`.`t` )", 10*time.Millisecond, now.Add(time.Duration(10+i)*time.Second))
+	}
+	s.domain.BindHandle().HandlePlanRegressions()
+	tk.MustQuery("select regressed_plan_digest, action, reason from mysql.plan_regression_history order by regressed_plan_digest, action").
+		Check(testkit.Rows("bad bind ", "bad bind ", "bad2 skip binding already exists"))
+	tk.MustExec("delete from mysql.plan_regression_history")
+}
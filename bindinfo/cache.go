@@ -43,6 +43,8 @@ const (
 	Capture = "capture"
 	// Evolve indicates the binding is evolved by TiDB from old bindings.
 	Evolve = "evolve"
+	// Regression indicates the binding is created by TiDB to roll back a regressed plan to the previous one.
+	Regression = "regression"
 	// Builtin indicates the binding is a builtin record for internal locking purpose. It is also the status for the builtin binding.
 	Builtin = "builtin"
 )
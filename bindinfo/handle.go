@@ -82,6 +82,14 @@ type BindHandle struct {
 
 	// pendingVerifyBindRecordMap indicates the pending verify bind records that found during query.
 	pendingVerifyBindRecordMap tmpBindRecordMap
+
+	// handledRegressions records the plan regressions which have been handled, the key is the SQL digest and the
+	// regressed plan digest. Each regression is only handled once while it's detected from the statement summary,
+	// so the size is bounded by the statement summary.
+	handledRegressions struct {
+		sync.Mutex
+		m map[string]struct{}
+	}
 }
 
 // Lease influences the duration of loading bind info and handling invalid bind.
@@ -116,6 +124,7 @@ func NewBindHandle(ctx sessionctx.Context) *BindHandle {
 		// BindSQL has already been validated when coming here, so we use nil sctx parameter.
 		return handle.AddBindRecord(nil, record)
 	}
+	handle.handledRegressions.m = make(map[string]struct{})
 	variable.RegisterStatistics(handle)
 	return handle
 }
@@ -1009,6 +1018,9 @@ func (h *BindHandle) Clear() {
 	h.bindInfo.Unlock()
 	h.invalidBindRecordMap.Store(make(map[string]*bindRecordUpdate))
 	h.pendingVerifyBindRecordMap.Store(make(map[string]*bindRecordUpdate))
+	h.handledRegressions.Lock()
+	h.handledRegressions.m = make(map[string]struct{})
+	h.handledRegressions.Unlock()
 }
 
 // FlushBindings flushes the BindRecord in temp maps to storage and loads them into cache.
@@ -0,0 +1,218 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	utilparser "github.com/pingcap/tidb/util/parser"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
+	"go.uber.org/zap"
+)
+
+const (
+	// regressionFactor is the factor to decide whether the plan of a statement regresses.
+	// A plan regresses if its average latency is at least `regressionFactor` times of a previous plan.
+	regressionFactor = 2.0
+	// regressionMinExecCount is the minimum execution count of both plans to compare their latencies, so a few
+	// slow executions caused by something else are not regarded as a regression.
+	regressionMinExecCount = 3
+)
+
+const (
+	// regressionActionBind means a binding is created for the previous plan.
+	regressionActionBind = "bind"
+	// regressionActionSkip means the regression is detected but no binding is created.
+	regressionActionSkip = "skip"
+
+	// regressionReasonBindingExists is the reason of skipping a regression whose statement has a binding.
+	regressionReasonBindingExists = "binding already exists"
+)
+
+// planRegression is a plan of a statement which is slower than a previous plan of the statement.
+type planRegression struct {
+	regressed *stmtsummary.BindableStmt
+	good      *stmtsummary.BindableStmt
+}
+
+// detectPlanRegressions finds the statements whose latest plans regress. The plans of a statement are compared
+// with the latest plan, which is used after the statistics or the schema changed, and the fastest previous plan is
+// chosen as the good plan.
+func detectPlanRegressions(plans []*stmtsummary.BindableStmt) []planRegression {
+	plansByDigest := make(map[string][]*stmtsummary.BindableStmt)
+	for _, plan := range plans {
+		key := plan.Schema + "." + plan.Digest
+		plansByDigest[key] = append(plansByDigest[key], plan)
+	}
+	var regressions []planRegression
+	for _, stmtPlans := range plansByDigest {
+		if len(stmtPlans) < 2 {
+			continue
+		}
+		latest := stmtPlans[0]
+		for _, plan := range stmtPlans[1:] {
+			if plan.LastSeen.After(latest.LastSeen) {
+				latest = plan
+			}
+		}
+		if latest.ExecCount < regressionMinExecCount {
+			continue
+		}
+		var good *stmtsummary.BindableStmt
+		for _, plan := range stmtPlans {
+			if plan == latest || plan.ExecCount < regressionMinExecCount || !plan.FirstSeen.Before(latest.FirstSeen) {
+				continue
+			}
+			if good == nil || plan.AvgLatency() < good.AvgLatency() {
+				good = plan
+			}
+		}
+		if good == nil || float64(latest.AvgLatency()) < float64(good.AvgLatency())*regressionFactor {
+			continue
+		}
+		regressions = append(regressions, planRegression{regressed: latest, good: good})
+	}
+	return regressions
+}
+
+// HandlePlanRegressions detects the plan regressions from the statement summary, and creates the bindings for the
+// previous good plans. All the decisions are recorded in mysql.plan_regression_history.
+func (h *BindHandle) HandlePlanRegressions() {
+	parser4Regression := parser.New()
+	for _, regression := range h.newPlanRegressions(detectPlanRegressions(stmtsummary.StmtSummaryByDigestMap.GetBindableStmtPlans())) {
+		bindSQL, reason := h.rollbackPlanRegression(parser4Regression, regression.good)
+		if reason == regressionReasonBindingExists {
+			// The regression may have been handled before it was evicted from the statement summary, the binding
+			// created then is kept, so it's not recorded again.
+			recorded, err := h.isPlanRegressionRecorded(regression)
+			if err != nil {
+				logutil.BgLogger().Warn("[sql-bind] load plan regression history failed", zap.String("digest", regression.regressed.Digest), zap.Error(err))
+			} else if recorded {
+				continue
+			}
+		}
+		action := regressionActionBind
+		if reason != "" {
+			action = regressionActionSkip
+		}
+		logutil.BgLogger().Info("[sql-bind] plan regression detected",
+			zap.String("digest", regression.regressed.Digest),
+			zap.String("regressedPlanDigest", regression.regressed.PlanDigest),
+			zap.Duration("regressedAvgLatency", regression.regressed.AvgLatency()),
+			zap.String("goodPlanDigest", regression.good.PlanDigest),
+			zap.Duration("goodAvgLatency", regression.good.AvgLatency()),
+			zap.String("action", action),
+			zap.String("reason", reason))
+		if err := h.recordPlanRegression(regression, bindSQL, action, reason); err != nil {
+			logutil.BgLogger().Warn("[sql-bind] record plan regression failed", zap.String("digest", regression.regressed.Digest), zap.Error(err))
+		}
+	}
+}
+
+// newPlanRegressions returns the regressions which haven't been handled. Only the regressions which are still
+// detected are kept in handledRegressions, so the regressions evicted from the statement summary are forgotten.
+func (h *BindHandle) newPlanRegressions(regressions []planRegression) []planRegression {
+	h.handledRegressions.Lock()
+	defer h.handledRegressions.Unlock()
+	handled := h.handledRegressions.m
+	h.handledRegressions.m = make(map[string]struct{}, len(regressions))
+	newRegressions := regressions[:0]
+	for _, regression := range regressions {
+		handledKey := regression.regressed.Digest + "." + regression.regressed.PlanDigest
+		h.handledRegressions.m[handledKey] = struct{}{}
+		if _, ok := handled[handledKey]; !ok {
+			newRegressions = append(newRegressions, regression)
+		}
+	}
+	return newRegressions
+}
+
+// rollbackPlanRegression creates the binding for the good plan of a regressed statement. It returns the reason if
+// the binding is not created.
+func (h *BindHandle) rollbackPlanRegression(p *parser.Parser, good *stmtsummary.BindableStmt) (bindSQL string, reason string) {
+	stmt, err := p.ParseOneStmt(good.Query, good.Charset, good.Collation)
+	if err != nil {
+		return "", "parse SQL failed: " + err.Error()
+	}
+	if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+		return "", "statement is not bindable"
+	}
+	dbName := utilparser.GetDefaultDB(stmt, good.Schema)
+	normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, good.Query))
+	if r := h.GetBindRecord(digest.String(), normalizedSQL, dbName); r != nil && r.HasUsingBinding() {
+		return "", regressionReasonBindingExists
+	}
+	bindSQL = GenerateBindSQL(context.TODO(), stmt, good.PlanHint, true, dbName)
+	if bindSQL == "" {
+		return "", "no plan hint for the previous plan"
+	}
+	binding := Binding{
+		BindSQL:   bindSQL,
+		Status:    Using,
+		Charset:   good.Charset,
+		Collation: good.Collation,
+		Source:    Regression,
+	}
+	// We don't need to pass the `sctx` because the BindSQL has been validated already.
+	err = h.CreateBindRecord(nil, &BindRecord{OriginalSQL: normalizedSQL, Db: dbName, Bindings: []Binding{binding}})
+	if err != nil {
+		return bindSQL, "create binding failed: " + err.Error()
+	}
+	return bindSQL, ""
+}
+
+// isPlanRegressionRecorded checks whether the regressed plan of the statement is in mysql.plan_regression_history.
+func (h *BindHandle) isPlanRegressionRecorded(regression planRegression) (bool, error) {
+	exec := h.sctx.Context.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT 1 FROM mysql.plan_regression_history
+	WHERE digest = %? AND schema_name = %? AND regressed_plan_digest = %? LIMIT 1`,
+		regression.regressed.Digest, regression.regressed.Schema, regression.regressed.PlanDigest)
+	if err != nil {
+		return false, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (h *BindHandle) recordPlanRegression(regression planRegression, bindSQL, action, reason string) error {
+	h.sctx.Lock()
+	defer h.sctx.Unlock()
+	exec, _ := h.sctx.Context.(sqlexec.SQLExecutor)
+	now := types.NewTime(types.FromGoTime(time.Now()), mysql.TypeTimestamp, 3)
+	_, err := exec.ExecuteInternal(context.TODO(), `INSERT INTO mysql.plan_regression_history VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)`,
+		regression.regressed.Schema,
+		regression.regressed.Digest,
+		regression.regressed.NormalizedSQL,
+		regression.regressed.PlanDigest,
+		int64(regression.regressed.AvgLatency()),
+		regression.good.PlanDigest,
+		int64(regression.good.AvgLatency()),
+		bindSQL,
+		action,
+		reason,
+		now.String(),
+	)
+	return err
+}
@@ -96,7 +96,7 @@ const (
 )
 
 var (
-	supportedStorageTypes = []string{"file", "local", "s3", "noop", "gcs"}
+	supportedStorageTypes = []string{"file", "local", "s3", "noop", "gcs", "azure"}
 
 	DefaultFilter = []string{
 		"*.*",
@@ -0,0 +1,843 @@
+// Copyright 2021 PingCAP, Inc. Licensed under Apache-2.0.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/log"
+	berrors "github.com/pingcap/tidb/br/pkg/errors"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+const (
+	azblobEndpointOption    = "azblob.endpoint"
+	azblobAccountNameOption = "azblob.account-name"
+	azblobAccountKeyOption  = "azblob.account-key"
+	azblobSASTokenOption    = "azblob.sas-token"
+	azblobAccessTierOption  = "azblob.access-tier"
+
+	// azblobProviderName is the provider name of the CloudDynamic backend for Azure Blob Storage.
+	azblobProviderName = "azure"
+	// the keys of the CloudDynamic attributes.
+	azblobAccountNameAttr = "account_name"
+	azblobAccountKeyAttr  = "account_key"
+	azblobSASTokenAttr    = "sas_token"
+	azblobAccessTierAttr  = "access_tier"
+
+	// the version of the Blob service REST API used by the requests.
+	azblobAPIVersion = "2020-04-08"
+	// the blob type of the uploaded blobs, only block blobs are supported.
+	azblobBlockBlob = "BlockBlob"
+
+	// TODO make this configurable, the block size is limited to 4000 MiB by azure.
+	hardcodedAzblobChunkSize = 5 * 1024 * 1024
+
+	azblobEnvAccountName = "AZURE_STORAGE_ACCOUNT"
+	azblobEnvAccountKey  = "AZURE_STORAGE_KEY"
+	azblobEnvSASToken    = "AZURE_STORAGE_SAS_TOKEN"
+)
+
+var azblobPermissionCheckFn = map[Permission]func(context.Context, *azblobStorage) error{
+	AccessBuckets: checkAzblobContainer,
+	ListObjects:   listAzblobObjects,
+	GetObject:     getAzblobObject,
+}
+
+// AzblobBackendOptions contains options for azure blob storage.
+type AzblobBackendOptions struct {
+	Endpoint    string `json:"endpoint" toml:"endpoint"`
+	AccountName string `json:"account-name" toml:"account-name"`
+	AccountKey  string `json:"account-key" toml:"account-key"`
+	SASToken    string `json:"sas-token" toml:"sas-token"`
+	AccessTier  string `json:"access-tier" toml:"access-tier"`
+}
+
+func (options *AzblobBackendOptions) apply(dynamic *backuppb.CloudDynamic) error {
+	if options.Endpoint != "" {
+		u, err := url.Parse(options.Endpoint)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if u.Scheme == "" {
+			return errors.Annotate(berrors.ErrStorageInvalidConfig, "scheme not found in endpoint")
+		}
+		if u.Host == "" {
+			return errors.Annotate(berrors.ErrStorageInvalidConfig, "host not found in endpoint")
+		}
+	}
+	if options.AccountKey != "" && options.SASToken != "" {
+		return errors.Annotate(berrors.ErrStorageInvalidConfig, "account_key and sas_token can't be specified at the same time")
+	}
+	if options.AccountKey != "" {
+		if options.AccountName == "" {
+			return errors.Annotate(berrors.ErrStorageInvalidConfig, "account_name not found")
+		}
+		if _, err := base64.StdEncoding.DecodeString(options.AccountKey); err != nil {
+			return errors.Annotatef(berrors.ErrStorageInvalidConfig, "account_key is not base64 encoded: %v", err)
+		}
+	}
+	switch strings.ToLower(options.AccessTier) {
+	case "", "hot", "cool", "archive":
+	default:
+		return errors.Annotatef(berrors.ErrStorageInvalidConfig, "invalid access tier '%s', should be Hot, Cool or Archive", options.AccessTier)
+	}
+
+	if dynamic.Bucket == nil {
+		dynamic.Bucket = &backuppb.Bucket{}
+	}
+	dynamic.Bucket.Endpoint = options.Endpoint
+	if dynamic.Attrs == nil {
+		dynamic.Attrs = make(map[string]string)
+	}
+	// all of them are acceptable to be empty
+	setAttr := func(key, value string) {
+		if value != "" {
+			dynamic.Attrs[key] = value
+		}
+	}
+	setAttr(azblobAccountNameAttr, options.AccountName)
+	setAttr(azblobAccountKeyAttr, options.AccountKey)
+	setAttr(azblobSASTokenAttr, strings.TrimPrefix(options.SASToken, "?"))
+	setAttr(azblobAccessTierAttr, options.AccessTier)
+	return nil
+}
+
+func defineAzblobFlags(flags *pflag.FlagSet) {
+	// TODO: remove experimental tag if it's stable
+	flags.String(azblobEndpointOption, "",
+		"(experimental) Set the Azure Blob Storage endpoint URL, default to https://<account-name>.blob.core.windows.net")
+	flags.String(azblobAccountNameOption, "", "(experimental) Set the Azure storage account name")
+	flags.String(azblobAccountKeyOption, "", "(experimental) Set the Azure storage account shared key")
+	flags.String(azblobSASTokenOption, "", "(experimental) Set the Azure shared access signature token")
+	flags.String(azblobAccessTierOption, "", "(experimental) Specify the access tier for blobs, e.g. Hot, Cool")
+}
+
+func (options *AzblobBackendOptions) parseFromFlags(flags *pflag.FlagSet) error {
+	var err error
+	options.Endpoint, err = flags.GetString(azblobEndpointOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.AccountName, err = flags.GetString(azblobAccountNameOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.AccountKey, err = flags.GetString(azblobAccountKeyOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.SASToken, err = flags.GetString(azblobSASTokenOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.AccessTier, err = flags.GetString(azblobAccessTierOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// azblobError is the error returned by the Blob service.
+type azblobError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *azblobError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("azure blob request failed, status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("azure blob request failed, status code: %d, code: %s, message: %s",
+		e.StatusCode, e.Code, strings.TrimSpace(e.Message))
+}
+
+func isAzblobNotFound(err error) bool {
+	aerr, ok := errors.Cause(err).(*azblobError) // nolint:errorlint
+	return ok && aerr.StatusCode == http.StatusNotFound
+}
+
+// azblobStorage accesses the azure blob storage by the Blob service REST API.
+type azblobStorage struct {
+	options *backuppb.CloudDynamic
+	// endpoint is the URL of the storage account, the containers are located under it.
+	endpoint    *url.URL
+	container   string
+	prefix      string
+	accountName string
+	// accountKey is the decoded shared key, nil if the shared key is not used.
+	accountKey []byte
+	sasToken   url.Values
+	accessTier string
+	client     *http.Client
+}
+
+func newAzblobStorage(ctx context.Context, dynamic *backuppb.CloudDynamic, opts *ExternalStorageOptions) (*azblobStorage, error) {
+	if dynamic.Bucket == nil || dynamic.Bucket.Bucket == "" {
+		return nil, errors.Annotate(berrors.ErrStorageInvalidConfig, "please specify the container for azure blob storage")
+	}
+	if dynamic.Attrs == nil {
+		dynamic.Attrs = make(map[string]string)
+	}
+	attrs := dynamic.Attrs
+	accountName := attrs[azblobAccountNameAttr]
+	accountKey := attrs[azblobAccountKeyAttr]
+	sasToken := attrs[azblobSASTokenAttr]
+	if !opts.NoCredentials && accountKey == "" && sasToken == "" {
+		// fallback to the credentials in the environment variables
+		if accountName == "" {
+			accountName = os.Getenv(azblobEnvAccountName)
+		}
+		accountKey = os.Getenv(azblobEnvAccountKey)
+		if accountKey == "" {
+			sasToken = strings.TrimPrefix(os.Getenv(azblobEnvSASToken), "?")
+		}
+		if opts.SendCredentials {
+			if accountName != "" {
+				attrs[azblobAccountNameAttr] = accountName
+			}
+			if accountKey != "" {
+				attrs[azblobAccountKeyAttr] = accountKey
+			}
+			if sasToken != "" {
+				attrs[azblobSASTokenAttr] = sasToken
+			}
+		}
+	}
+
+	s := &azblobStorage{
+		options:     dynamic,
+		container:   dynamic.Bucket.Bucket,
+		prefix:      dynamic.Bucket.Prefix,
+		accountName: accountName,
+		accessTier:  attrs[azblobAccessTierAttr],
+		client:      opts.HTTPClient,
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+
+	endpoint := dynamic.Bucket.Endpoint
+	if endpoint == "" {
+		if accountName == "" {
+			return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig,
+				"please specify the account name or the endpoint for azure blob storage, or set %s", azblobEnvAccountName)
+		}
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", accountName)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = ""
+	s.endpoint = u
+
+	if !opts.NoCredentials {
+		if accountKey != "" {
+			if accountName == "" {
+				return nil, errors.Annotate(berrors.ErrStorageInvalidConfig, "account_name not found")
+			}
+			s.accountKey, err = base64.StdEncoding.DecodeString(accountKey)
+			if err != nil {
+				return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "account_key is not base64 encoded: %v", err)
+			}
+		} else if sasToken != "" {
+			s.sasToken, err = url.ParseQuery(sasToken)
+			if err != nil {
+				return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "invalid sas_token: %v", err)
+			}
+		}
+	}
+
+	if !opts.SendCredentials {
+		// Clear the credentials if exists so that they will not be sent to TiKV
+		delete(attrs, azblobAccountKeyAttr)
+		delete(attrs, azblobSASTokenAttr)
+	}
+
+	// TODO remove it after BR remove cfg skip-check-path
+	if !opts.SkipCheckPath {
+		if err = checkAzblobContainer(ctx, s); err != nil {
+			return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "Container %s is not accessible: %v", s.container, err)
+		}
+	}
+
+	if len(s.prefix) > 0 && !strings.HasSuffix(s.prefix, "/") {
+		s.prefix += "/"
+	}
+
+	for _, p := range opts.CheckPermissions {
+		fn, ok := azblobPermissionCheckFn[p]
+		if !ok {
+			continue
+		}
+		if err := fn(ctx, s); err != nil {
+			return nil, errors.Annotatef(berrors.ErrStorageInvalidPermission, "check permission %s failed due to %v", p, err)
+		}
+	}
+	return s, nil
+}
+
+// checkAzblobContainer checks if the container exists.
+func checkAzblobContainer(ctx context.Context, s *azblobStorage) error {
+	query := url.Values{"restype": {"container"}}
+	resp, err := s.do(ctx, http.MethodGet, s.containerURL(query), nil, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return resp.Body.Close()
+}
+
+// listAzblobObjects checks the permission of listing blobs.
+func listAzblobObjects(ctx context.Context, s *azblobStorage) error {
+	_, err := s.listBlobs(ctx, s.prefix, "", 1)
+	return errors.Trace(err)
+}
+
+// getAzblobObject checks the permission of reading blobs.
+func getAzblobObject(ctx context.Context, s *azblobStorage) error {
+	resp, err := s.do(ctx, http.MethodGet, s.blobURL("not-exists"), nil, nil)
+	if err != nil {
+		if isAzblobNotFound(err) {
+			// if blob not exists and we reach this error, that
+			// means we have the correct permission to read it
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	return resp.Body.Close()
+}
+
+func (s *azblobStorage) containerURL(query url.Values) *url.URL {
+	u := *s.endpoint
+	u.Path += "/" + s.container
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+func (s *azblobStorage) objectName(name string) string {
+	return s.prefix + name
+}
+
+func (s *azblobStorage) blobURL(name string) *url.URL {
+	u := *s.endpoint
+	u.Path += "/" + s.container + "/" + s.objectName(name)
+	return &u
+}
+
+// do sends the request to the Blob service, the requests failed by network or server errors are retried.
+// An *azblobError is returned if the response isn't successful.
+func (s *azblobStorage) do(ctx context.Context, method string, u *url.URL, header http.Header, body []byte) (*http.Response, error) {
+	var (
+		resp *http.Response
+		err  error
+	)
+	for retry := 0; ; retry++ {
+		resp, err = s.doOnce(ctx, method, u, header, body)
+		if err == nil {
+			return resp, nil
+		}
+		aerr, ok := errors.Cause(err).(*azblobError) // nolint:errorlint
+		if (ok && aerr.StatusCode < http.StatusInternalServerError) || retry >= maxErrorRetries {
+			return nil, err
+		}
+		log.Warn("azure blob request failed, retrying", zap.String("method", method),
+			zap.String("path", u.Path), zap.Int("retry", retry), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil, errors.Trace(ctx.Err())
+		case <-time.After(time.Duration(retry+1) * 100 * time.Millisecond):
+		}
+	}
+}
+
+func (s *azblobStorage) doOnce(ctx context.Context, method string, u *url.URL, header http.Header, body []byte) (*http.Response, error) {
+	reqURL := *u
+	if s.sasToken != nil {
+		query := reqURL.Query()
+		for k, vs := range s.sasToken {
+			query[k] = vs
+		}
+		reqURL.RawQuery = query.Encode()
+	}
+	var bodyReader io.Reader = http.NoBody
+	if len(body) > 0 {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), bodyReader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("x-ms-version", azblobAPIVersion)
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	if s.accountKey != nil {
+		s.signSharedKey(req)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	aerr := &azblobError{StatusCode: resp.StatusCode}
+	if data, rerr := io.ReadAll(resp.Body); rerr == nil && len(data) > 0 {
+		// the body is empty for HEAD requests, the error code is in the header then.
+		_ = xml.Unmarshal(data, aerr)
+	}
+	if aerr.Code == "" {
+		aerr.Code = resp.Header.Get("x-ms-error-code")
+	}
+	return nil, errors.Trace(aerr)
+}
+
+// signSharedKey signs the request with the shared key of the storage account.
+// See https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key.
+func (s *azblobStorage) signSharedKey(req *http.Request) {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	var sb strings.Builder
+	for _, item := range []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead.
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	} {
+		sb.WriteString(item)
+		sb.WriteByte('\n')
+	}
+
+	msHeaders := make([]string, 0, 4)
+	for k := range req.Header {
+		if k = strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			msHeaders = append(msHeaders, k)
+		}
+	}
+	sort.Strings(msHeaders)
+	for _, k := range msHeaders {
+		sb.WriteString(k)
+		sb.WriteByte(':')
+		sb.WriteString(strings.TrimSpace(req.Header.Get(k)))
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteByte('/')
+	sb.WriteString(s.accountName)
+	sb.WriteString(req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for k := range query {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := query[k]
+		sort.Strings(values)
+		sb.WriteByte('\n')
+		sb.WriteString(strings.ToLower(k))
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(values, ","))
+	}
+
+	h := hmac.New(sha256.New, s.accountKey)
+	h.Write([]byte(sb.String()))
+	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	req.Header.Set("Authorization", "SharedKey "+s.accountName+":"+signature)
+}
+
+func (s *azblobStorage) blobHeader() http.Header {
+	header := http.Header{}
+	header.Set("x-ms-blob-type", azblobBlockBlob)
+	if s.accessTier != "" {
+		header.Set("x-ms-access-tier", s.accessTier)
+	}
+	return header
+}
+
+// WriteFile writes data to a file to storage.
+func (s *azblobStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.blobURL(name), s.blobHeader(), data)
+	if err != nil {
+		return errors.Annotatef(err,
+			"failed to write azure blob, file info: container='%s', blob='%s'", s.container, s.objectName(name))
+	}
+	return resp.Body.Close()
+}
+
+// ReadFile reads the file from the storage and returns the contents.
+func (s *azblobStorage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.blobURL(name), nil, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err,
+			"failed to read azure blob, file info: container='%s', blob='%s'", s.container, s.objectName(name))
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return data, errors.Trace(err)
+}
+
+// FileExists return true if file exists.
+func (s *azblobStorage) FileExists(ctx context.Context, name string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodHead, s.blobURL(name), nil, nil)
+	if err != nil {
+		if isAzblobNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	return true, resp.Body.Close()
+}
+
+// DeleteFile delete the file in storage
+func (s *azblobStorage) DeleteFile(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.blobURL(name), nil, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return resp.Body.Close()
+}
+
+// Open a Reader by file path.
+func (s *azblobStorage) Open(ctx context.Context, path string) (ExternalFileReader, error) {
+	reader, r, err := s.open(ctx, path, 0, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &azblobObjectReader{
+		storage:   s,
+		name:      path,
+		reader:    reader,
+		ctx:       ctx,
+		rangeInfo: r,
+	}, nil
+}
+
+// if endOffset > startOffset, should return reader for bytes in [startOffset, endOffset).
+func (s *azblobStorage) open(
+	ctx context.Context,
+	path string,
+	startOffset, endOffset int64,
+) (io.ReadCloser, RangeInfo, error) {
+	// the whole blob is read without the range header, so that an empty blob can be opened.
+	header := http.Header{}
+	var rangeOffset string
+	if endOffset > startOffset {
+		rangeOffset = fmt.Sprintf("bytes=%d-%d", startOffset, endOffset-1)
+	} else if startOffset > 0 {
+		rangeOffset = fmt.Sprintf("bytes=%d-", startOffset)
+	}
+	if rangeOffset != "" {
+		header.Set("x-ms-range", rangeOffset)
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.blobURL(path), header, nil)
+	if err != nil {
+		return nil, RangeInfo{}, errors.Annotatef(err,
+			"failed to read azure blob, file info: container='%s', blob='%s'", s.container, s.objectName(path))
+	}
+
+	var r RangeInfo
+	if resp.StatusCode == http.StatusPartialContent {
+		contentRange := resp.Header.Get("Content-Range")
+		r, err = ParseRangeInfo(&contentRange)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, RangeInfo{}, errors.Trace(err)
+		}
+	} else {
+		r.Size, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, RangeInfo{}, errors.Annotatef(berrors.ErrStorageUnknown,
+				"invalid content length '%s' of blob '%s'", resp.Header.Get("Content-Length"), path)
+		}
+		r.End = r.Size - 1
+	}
+
+	if startOffset != r.Start || (endOffset != 0 && endOffset != r.End+1) {
+		_ = resp.Body.Close()
+		return nil, r, errors.Annotatef(berrors.ErrStorageUnknown, "open file '%s' failed, expected range: %s, got: %v",
+			path, rangeOffset, r)
+	}
+	return resp.Body, r, nil
+}
+
+// azblobObjectReader wraps the body of the Get Blob response and add the `Seek` method.
+type azblobObjectReader struct {
+	storage   *azblobStorage
+	name      string
+	reader    io.ReadCloser
+	pos       int64
+	rangeInfo RangeInfo
+	// reader context used for implement `io.Seek`
+	ctx      context.Context
+	retryCnt int
+}
+
+// Read implement the io.Reader interface.
+func (r *azblobObjectReader) Read(p []byte) (n int, err error) {
+	maxCnt := r.rangeInfo.End + 1 - r.pos
+	if maxCnt <= 0 {
+		return 0, io.EOF
+	}
+	if maxCnt > int64(len(p)) {
+		maxCnt = int64(len(p))
+	}
+	n, err = r.reader.Read(p[:maxCnt])
+	if err != nil && errors.Cause(err) != io.EOF && r.retryCnt < maxErrorRetries { //nolint:errorlint
+		// if can retry, reopen a new reader and try read again
+		end := r.rangeInfo.End + 1
+		if end == r.rangeInfo.Size {
+			end = 0
+		}
+		_ = r.reader.Close()
+
+		newReader, _, err1 := r.storage.open(r.ctx, r.name, r.pos, end)
+		if err1 != nil {
+			log.Warn("open new azure blob reader failed", zap.String("file", r.name), zap.Error(err1))
+			return
+		}
+		r.reader = newReader
+		r.retryCnt++
+		n, err = r.reader.Read(p[:maxCnt])
+	}
+
+	r.pos += int64(n)
+	return
+}
+
+// Close implement the io.Closer interface.
+func (r *azblobObjectReader) Close() error {
+	return r.reader.Close()
+}
+
+// Seek implement the io.Seeker interface.
+func (r *azblobObjectReader) Seek(offset int64, whence int) (int64, error) {
+	var realOffset int64
+	switch whence {
+	case io.SeekStart:
+		realOffset = offset
+	case io.SeekCurrent:
+		realOffset = r.pos + offset
+	case io.SeekEnd:
+		realOffset = r.rangeInfo.Size + offset
+	default:
+		return 0, errors.Annotatef(berrors.ErrStorageUnknown, "Seek: invalid whence '%d'", whence)
+	}
+	if realOffset < 0 {
+		return 0, errors.Annotatef(berrors.ErrInvalidArgument, "Seek: offset '%v' out of range.", realOffset)
+	}
+
+	if realOffset == r.pos {
+		return realOffset, nil
+	}
+
+	// if seek ahead no more than 64k, we discard these data
+	if realOffset > r.pos && realOffset-r.pos <= maxSkipOffsetByRead && realOffset <= r.rangeInfo.Size {
+		_, err := io.CopyN(io.Discard, r, realOffset-r.pos)
+		if err != nil {
+			return r.pos, errors.Trace(err)
+		}
+		return realOffset, nil
+	}
+
+	// close current read and open a new one which target offset
+	err := r.reader.Close()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	if realOffset >= r.rangeInfo.Size {
+		// the range is unsatisfiable for azure, so nothing is read from the end.
+		r.reader = io.NopCloser(bytes.NewReader(nil))
+		r.pos = realOffset
+		return realOffset, nil
+	}
+
+	newReader, info, err := r.storage.open(r.ctx, r.name, realOffset, 0)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	r.reader = newReader
+	r.rangeInfo = info
+	r.pos = realOffset
+	return realOffset, nil
+}
+
+type azblobListResult struct {
+	Blobs []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			ContentLength int64 `xml:"Content-Length"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (s *azblobStorage) listBlobs(ctx context.Context, prefix, marker string, maxResults int64) (*azblobListResult, error) {
+	query := url.Values{
+		"restype":    {"container"},
+		"comp":       {"list"},
+		"prefix":     {prefix},
+		"maxresults": {strconv.FormatInt(maxResults, 10)},
+	}
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.containerURL(query), nil, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	res := &azblobListResult{}
+	if err := xml.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, errors.Annotatef(berrors.ErrStorageUnknown, "failed to decode the blob list: %v", err)
+	}
+	return res, nil
+}
+
+// WalkDir traverse all the files in a dir.
+//
+// fn is the function called for each regular file visited by WalkDir.
+// The first argument is the file path that can be used in `Open`
+// function; the second argument is the size in byte of the file determined
+// by path.
+func (s *azblobStorage) WalkDir(ctx context.Context, opt *WalkOption, fn func(string, int64) error) error {
+	if opt == nil {
+		opt = &WalkOption{}
+	}
+	prefix := path.Join(s.prefix, opt.SubDir)
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	maxResults := int64(1000)
+	if opt.ListCount > 0 {
+		maxResults = opt.ListCount
+	}
+
+	marker := ""
+	for {
+		res, err := s.listBlobs(ctx, prefix, marker, maxResults)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, blob := range res.Blobs {
+			// when walk on specify directory, the result include storage.Prefix,
+			// which can not be reuse in other API(Open/Read) directly.
+			// so we use TrimPrefix to filter Prefix for next Open/Read.
+			path := strings.TrimPrefix(blob.Name, s.prefix)
+			if err = fn(path, blob.Properties.ContentLength); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if res.NextMarker == "" {
+			break
+		}
+		marker = res.NextMarker
+	}
+	return nil
+}
+
+// URI returns azure://<container>/<prefix>.
+func (s *azblobStorage) URI() string {
+	return "azure://" + s.container + "/" + s.prefix
+}
+
+// Create creates a block blob uploader.
+func (s *azblobStorage) Create(ctx context.Context, name string) (ExternalFileWriter, error) {
+	uploader := &azblobUploader{
+		storage:  s,
+		name:     name,
+		blockIDs: make([]string, 0, 128),
+	}
+	return newBufferedWriter(uploader, hardcodedAzblobChunkSize, NoCompression), nil
+}
+
+// azblobUploader uploads the data as the blocks of a block blob, the blocks are committed on Close.
+type azblobUploader struct {
+	storage  *azblobStorage
+	name     string
+	blockIDs []string
+}
+
+// Write uploads the data as a new block by Put Block.
+func (u *azblobUploader) Write(ctx context.Context, data []byte) (int, error) {
+	// the block IDs must have the same length within a blob.
+	blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", len(u.blockIDs))))
+	blockURL := u.storage.blobURL(u.name)
+	blockURL.RawQuery = url.Values{"comp": {"block"}, "blockid": {blockID}}.Encode()
+	resp, err := u.storage.do(ctx, http.MethodPut, blockURL, nil, data)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if err = resp.Body.Close(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	u.blockIDs = append(u.blockIDs, blockID)
+	return len(data), nil
+}
+
+// Close commits the uploaded blocks by Put Block List.
+func (u *azblobUploader) Close(ctx context.Context) error {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString("<BlockList>")
+	for _, blockID := range u.blockIDs {
+		body.WriteString("<Latest>")
+		body.WriteString(blockID)
+		body.WriteString("</Latest>")
+	}
+	body.WriteString("</BlockList>")
+
+	blockListURL := u.storage.blobURL(u.name)
+	blockListURL.RawQuery = url.Values{"comp": {"blocklist"}}.Encode()
+	header := http.Header{}
+	header.Set("Content-Type", "application/xml")
+	if u.storage.accessTier != "" {
+		header.Set("x-ms-access-tier", u.storage.accessTier)
+	}
+	resp, err := u.storage.do(ctx, http.MethodPut, blockListURL, header, body.Bytes())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return resp.Body.Close()
+}
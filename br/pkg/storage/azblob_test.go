@@ -0,0 +1,511 @@
+// Copyright 2021 PingCAP, Inc. Licensed under Apache-2.0.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/pingcap/check"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+)
+
+const (
+	fakeAzblobAccount   = "devstoreaccount1"
+	fakeAzblobContainer = "container"
+)
+
+var fakeAzblobKey = []byte("fake account key")
+
+// fakeAzblobServer is an in-memory stand-in of the Blob service like Azurite,
+// the blobs are addressed by http://<host>/<account>/<container>/<blob>.
+type fakeAzblobServer struct {
+	mu     sync.Mutex
+	blobs  map[string][]byte
+	blocks map[string]map[string][]byte
+	// the number of the following requests to fail with internal error.
+	failures int
+	// whether to authorize the requests by SAS token instead of shared key.
+	sas bool
+}
+
+func newFakeAzblobServer() *fakeAzblobServer {
+	return &fakeAzblobServer{
+		blobs:  make(map[string][]byte),
+		blocks: make(map[string]map[string][]byte),
+	}
+}
+
+func writeFakeAzblobError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>fake error</Message></Error>", xml.Header, code)
+}
+
+// authorized checks the signature of the request independently.
+func (s *fakeAzblobServer) authorized(r *http.Request) bool {
+	if r.Header.Get("x-ms-version") == "" || r.Header.Get("x-ms-date") == "" {
+		return false
+	}
+	if s.sas {
+		return r.URL.Query().Get("sig") == "fakesig" && r.Header.Get("Authorization") == ""
+	}
+	headers := []string{
+		"Content-Encoding", "Content-Language", "Content-Length", "Content-MD5", "Content-Type", "Date",
+		"If-Modified-Since", "If-Match", "If-None-Match", "If-Unmodified-Since", "Range",
+	}
+	lines := []string{r.Method}
+	for _, h := range headers {
+		v := r.Header.Get(h)
+		if h == "Content-Length" && r.ContentLength > 0 {
+			v = strconv.FormatInt(r.ContentLength, 10)
+		}
+		lines = append(lines, v)
+	}
+	var msHeaders []string
+	for k := range r.Header {
+		if strings.HasPrefix(strings.ToLower(k), "x-ms-") {
+			msHeaders = append(msHeaders, strings.ToLower(k)+":"+r.Header.Get(k))
+		}
+	}
+	sort.Strings(msHeaders)
+	lines = append(lines, msHeaders...)
+	resource := "/" + fakeAzblobAccount + r.URL.EscapedPath()
+	var params []string
+	for k, vs := range r.URL.Query() {
+		sort.Strings(vs)
+		params = append(params, strings.ToLower(k)+":"+strings.Join(vs, ","))
+	}
+	sort.Strings(params)
+	lines = append(lines, resource)
+	lines = append(lines, params...)
+	h := hmac.New(sha256.New, fakeAzblobKey)
+	h.Write([]byte(strings.Join(lines, "\n")))
+	expected := "SharedKey " + fakeAzblobAccount + ":" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return r.Header.Get("Authorization") == expected
+}
+
+func (s *fakeAzblobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		writeFakeAzblobError(w, http.StatusInternalServerError, "InternalError")
+		return
+	}
+	if !s.authorized(r) {
+		writeFakeAzblobError(w, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"+fakeAzblobAccount+"/"), "/", 2)
+	if parts[0] != fakeAzblobContainer {
+		writeFakeAzblobError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+	query := r.URL.Query()
+	if len(parts) == 1 {
+		if query.Get("restype") != "container" {
+			writeFakeAzblobError(w, http.StatusBadRequest, "InvalidQueryParameterValue")
+			return
+		}
+		if query.Get("comp") == "list" {
+			s.listBlobs(w, query)
+		}
+		return
+	}
+
+	name := parts[1]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		switch query.Get("comp") {
+		case "block":
+			if s.blocks[name] == nil {
+				s.blocks[name] = make(map[string][]byte)
+			}
+			s.blocks[name][query.Get("blockid")] = body
+		case "blocklist":
+			var blockList struct {
+				Latest []string `xml:"Latest"`
+			}
+			if err := xml.Unmarshal(body, &blockList); err != nil {
+				writeFakeAzblobError(w, http.StatusBadRequest, "InvalidXmlDocument")
+				return
+			}
+			var data []byte
+			for _, id := range blockList.Latest {
+				block, ok := s.blocks[name][id]
+				if !ok {
+					writeFakeAzblobError(w, http.StatusBadRequest, "InvalidBlockList")
+					return
+				}
+				data = append(data, block...)
+			}
+			delete(s.blocks, name)
+			s.blobs[name] = data
+		default:
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				writeFakeAzblobError(w, http.StatusBadRequest, "MissingRequiredHeader")
+				return
+			}
+			s.blobs[name] = body
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodHead:
+		data, ok := s.blobs[name]
+		if !ok {
+			writeFakeAzblobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		rangeHeader := r.Header.Get("x-ms-range")
+		if rangeHeader == "" || r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(data)
+			}
+			return
+		}
+		var start, end int
+		if _, err := fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end); err != nil {
+			end = len(data) - 1
+		}
+		if end >= len(data) {
+			end = len(data) - 1
+		}
+		if start > end {
+			writeFakeAzblobError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(data[start : end+1])
+	case http.MethodDelete:
+		if _, ok := s.blobs[name]; !ok {
+			writeFakeAzblobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		delete(s.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeFakeAzblobError(w, http.StatusMethodNotAllowed, "UnsupportedHttpVerb")
+	}
+}
+
+func (s *fakeAzblobServer) listBlobs(w http.ResponseWriter, query url.Values) {
+	names := make([]string, 0, len(s.blobs))
+	for name := range s.blobs {
+		if strings.HasPrefix(name, query.Get("prefix")) && name >= query.Get("marker") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	maxResults, _ := strconv.Atoi(query.Get("maxresults"))
+	nextMarker := ""
+	if maxResults > 0 && len(names) > maxResults {
+		nextMarker = names[maxResults]
+		names = names[:maxResults]
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header + "<EnumerationResults><Blobs>")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "<Blob><Name>%s</Name><Properties><Content-Length>%d</Content-Length></Properties></Blob>",
+			name, len(s.blobs[name]))
+	}
+	fmt.Fprintf(&buf, "</Blobs><NextMarker>%s</NextMarker></EnumerationResults>", nextMarker)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// setEnvs sets the environment variables and returns the function to restore them.
+func setEnvs(envs map[string]string) func() {
+	old := make(map[string]*string, len(envs))
+	for k, v := range envs {
+		if oldValue, ok := os.LookupEnv(k); ok {
+			old[k] = &oldValue
+		} else {
+			old[k] = nil
+		}
+		os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range old {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+func newFakeAzblobBackend(endpoint, prefix string, attrs map[string]string) *backuppb.CloudDynamic {
+	return &backuppb.CloudDynamic{
+		Bucket: &backuppb.Bucket{
+			Endpoint: endpoint + "/" + fakeAzblobAccount,
+			Bucket:   fakeAzblobContainer,
+			Prefix:   prefix,
+		},
+		ProviderName: azblobProviderName,
+		Attrs:        attrs,
+	}
+}
+
+func (r *testStorageSuite) TestAzblob(c *C) {
+	ctx := context.Background()
+	fake := newFakeAzblobServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	backend := newFakeAzblobBackend(server.URL, "a/b", map[string]string{
+		azblobAccountNameAttr: fakeAzblobAccount,
+		azblobAccountKeyAttr:  base64.StdEncoding.EncodeToString(fakeAzblobKey),
+	})
+	stg, err := New(ctx, &backuppb.StorageBackend{
+		Backend: &backuppb.StorageBackend_CloudDynamic{CloudDynamic: backend},
+	}, &ExternalStorageOptions{
+		SendCredentials:  false,
+		CheckPermissions: []Permission{AccessBuckets, ListObjects, GetObject},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(stg.URI(), Equals, "azure://container/a/b/")
+	// the credentials are not sent to TiKV
+	c.Assert(backend.Attrs, DeepEquals, map[string]string{azblobAccountNameAttr: fakeAzblobAccount})
+
+	err = stg.WriteFile(ctx, "key", []byte("data"))
+	c.Assert(err, IsNil)
+	err = stg.WriteFile(ctx, "key1", []byte("data1"))
+	c.Assert(err, IsNil)
+	err = stg.WriteFile(ctx, "sub/key2", []byte("data22223346757222222222289722222"))
+	c.Assert(err, IsNil)
+	err = stg.WriteFile(ctx, "name with space", []byte("data3"))
+	c.Assert(err, IsNil)
+	c.Assert(fake.blobs["a/b/key"], DeepEquals, []byte("data"))
+	c.Assert(fake.blobs["a/b/name with space"], DeepEquals, []byte("data3"))
+
+	d, err := stg.ReadFile(ctx, "key")
+	c.Assert(err, IsNil)
+	c.Assert(d, DeepEquals, []byte("data"))
+	_, err = stg.ReadFile(ctx, "key_not_exist")
+	c.Assert(err, ErrorMatches, ".*BlobNotFound.*")
+
+	exist, err := stg.FileExists(ctx, "key")
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsTrue)
+	exist, err = stg.FileExists(ctx, "key_not_exist")
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsFalse)
+
+	err = stg.WriteFile(ctx, "key_delete", []byte("data"))
+	c.Assert(err, IsNil)
+	err = stg.DeleteFile(ctx, "key_delete")
+	c.Assert(err, IsNil)
+	exist, err = stg.FileExists(ctx, "key_delete")
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsFalse)
+
+	// the failed requests are retried
+	fake.failures = maxErrorRetries
+	d, err = stg.ReadFile(ctx, "key1")
+	c.Assert(err, IsNil)
+	c.Assert(d, DeepEquals, []byte("data1"))
+	fake.failures = maxErrorRetries + 1
+	_, err = stg.ReadFile(ctx, "key1")
+	c.Assert(err, ErrorMatches, ".*InternalError.*")
+
+	list := ""
+	var totalSize int64
+	err = stg.WalkDir(ctx, &WalkOption{ListCount: 1}, func(name string, size int64) error {
+		list += name + ","
+		totalSize += size
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(list, Equals, "key,key1,name with space,sub/key2,")
+	c.Assert(totalSize, Equals, int64(4+5+5+33))
+	list = ""
+	err = stg.WalkDir(ctx, &WalkOption{SubDir: "sub"}, func(name string, size int64) error {
+		list += name + ","
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(list, Equals, "sub/key2,")
+
+	// the multi-block upload
+	data := make([]byte, hardcodedAzblobChunkSize*2+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	w, err := stg.Create(ctx, "large")
+	c.Assert(err, IsNil)
+	for i := 0; i < len(data); i += 1000 {
+		end := i + 1000
+		if end > len(data) {
+			end = len(data)
+		}
+		_, err = w.Write(ctx, data[i:end])
+		c.Assert(err, IsNil)
+	}
+	err = w.Close(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(fake.blobs["a/b/large"], DeepEquals, data)
+	c.Assert(fake.blocks, HasLen, 0)
+
+	w, err = stg.Create(ctx, "empty")
+	c.Assert(err, IsNil)
+	err = w.Close(ctx)
+	c.Assert(err, IsNil)
+	d, err = stg.ReadFile(ctx, "empty")
+	c.Assert(err, IsNil)
+	c.Assert(d, HasLen, 0)
+
+	// the range reads
+	reader, err := stg.Open(ctx, "large")
+	c.Assert(err, IsNil)
+	buf := make([]byte, 100)
+	_, err = io.ReadFull(reader, buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf, DeepEquals, data[:100])
+
+	offset, err := reader.Seek(1000, io.SeekCurrent)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(1100))
+	_, err = io.ReadFull(reader, buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf, DeepEquals, data[1100:1200])
+
+	offset, err = reader.Seek(hardcodedAzblobChunkSize, io.SeekStart)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(hardcodedAzblobChunkSize))
+	_, err = io.ReadFull(reader, buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf, DeepEquals, data[hardcodedAzblobChunkSize:hardcodedAzblobChunkSize+100])
+
+	offset, err = reader.Seek(-50, io.SeekEnd)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(len(data)-50))
+	d, err = io.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(d, DeepEquals, data[len(data)-50:])
+
+	offset, err = reader.Seek(int64(len(data)+10), io.SeekStart)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(len(data)+10))
+	n, err := reader.Read(buf)
+	c.Assert(n, Equals, 0)
+	c.Assert(err, Equals, io.EOF)
+
+	_, err = reader.Seek(-1, io.SeekStart)
+	c.Assert(err, NotNil)
+	c.Assert(reader.Close(), IsNil)
+
+	reader, err = stg.Open(ctx, "empty")
+	c.Assert(err, IsNil)
+	d, err = io.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(d, HasLen, 0)
+	c.Assert(reader.Close(), IsNil)
+}
+
+func (r *testStorageSuite) TestNewAzblobStorage(c *C) {
+	ctx := context.Background()
+	fake := newFakeAzblobServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	accountKey := base64.StdEncoding.EncodeToString(fakeAzblobKey)
+
+	{
+		backend := newFakeAzblobBackend(server.URL, "", map[string]string{
+			azblobAccountNameAttr: fakeAzblobAccount,
+			azblobAccountKeyAttr:  accountKey,
+		})
+		_, err := newAzblobStorage(ctx, backend, &ExternalStorageOptions{SendCredentials: true})
+		c.Assert(err, IsNil)
+		c.Assert(backend.Attrs[azblobAccountKeyAttr], Equals, accountKey)
+	}
+
+	{
+		backend := newFakeAzblobBackend(server.URL, "", map[string]string{
+			azblobAccountNameAttr: fakeAzblobAccount,
+			azblobAccountKeyAttr:  base64.StdEncoding.EncodeToString([]byte("wrong key")),
+		})
+		_, err := newAzblobStorage(ctx, backend, &ExternalStorageOptions{})
+		c.Assert(err, ErrorMatches, "Container container is not accessible: .*AuthenticationFailed.*")
+	}
+
+	{
+		backend := newFakeAzblobBackend(server.URL, "", map[string]string{
+			azblobAccountNameAttr: fakeAzblobAccount,
+			azblobAccountKeyAttr:  accountKey,
+		})
+		backend.Bucket.Bucket = "container-not-exist"
+		_, err := newAzblobStorage(ctx, backend, &ExternalStorageOptions{})
+		c.Assert(err, ErrorMatches, ".*ContainerNotFound.*")
+		_, err = newAzblobStorage(ctx, backend, &ExternalStorageOptions{SkipCheckPath: true})
+		c.Assert(err, IsNil)
+	}
+
+	{
+		// the credentials are read from the environment variables
+		backend := newFakeAzblobBackend(server.URL, "prefix", nil)
+		restore := setEnvs(map[string]string{
+			azblobEnvAccountName: fakeAzblobAccount,
+			azblobEnvAccountKey:  accountKey,
+		})
+		s, err := newAzblobStorage(ctx, backend, &ExternalStorageOptions{SendCredentials: true})
+		restore()
+		c.Assert(err, IsNil)
+		c.Assert(s.URI(), Equals, "azure://container/prefix/")
+		c.Assert(backend.Attrs, DeepEquals, map[string]string{
+			azblobAccountNameAttr: fakeAzblobAccount,
+			azblobAccountKeyAttr:  accountKey,
+		})
+	}
+
+	{
+		// the endpoint is required if the account name is unknown
+		restore := setEnvs(map[string]string{azblobEnvAccountName: "", azblobEnvAccountKey: "", azblobEnvSASToken: ""})
+		backend := &backuppb.CloudDynamic{
+			Bucket:       &backuppb.Bucket{Bucket: fakeAzblobContainer},
+			ProviderName: azblobProviderName,
+		}
+		_, err := newAzblobStorage(ctx, backend, &ExternalStorageOptions{SkipCheckPath: true})
+		restore()
+		c.Assert(err, ErrorMatches, "please specify the account name or the endpoint for azure blob storage.*")
+	}
+
+	{
+		fake.sas = true
+		backend := newFakeAzblobBackend(server.URL, "sas", map[string]string{
+			azblobSASTokenAttr:   "sv=2020-04-08&sig=fakesig",
+			azblobAccessTierAttr: "Cool",
+		})
+		s, err := newAzblobStorage(ctx, backend, &ExternalStorageOptions{
+			CheckPermissions: []Permission{AccessBuckets, ListObjects, GetObject},
+		})
+		c.Assert(err, IsNil)
+		c.Assert(backend.Attrs, DeepEquals, map[string]string{azblobAccessTierAttr: "Cool"})
+		err = s.WriteFile(ctx, "key", []byte("data"))
+		c.Assert(err, IsNil)
+		d, err := s.ReadFile(ctx, "key")
+		c.Assert(err, IsNil)
+		c.Assert(d, DeepEquals, []byte("data"))
+
+		backend = newFakeAzblobBackend(server.URL, "sas", map[string]string{azblobSASTokenAttr: "sig=wrong"})
+		_, err = newAzblobStorage(ctx, backend, &ExternalStorageOptions{})
+		c.Assert(err, ErrorMatches, ".*AuthenticationFailed.*")
+	}
+}
@@ -17,8 +17,9 @@ import (
 // BackendOptions further configures the storage backend not expressed by the
 // storage URL.
 type BackendOptions struct {
-	S3  S3BackendOptions  `json:"s3" toml:"s3"`
-	GCS GCSBackendOptions `json:"gcs" toml:"gcs"`
+	S3     S3BackendOptions     `json:"s3" toml:"s3"`
+	GCS    GCSBackendOptions    `json:"gcs" toml:"gcs"`
+	Azblob AzblobBackendOptions `json:"azblob" toml:"azblob"`
 }
 
 // ParseRawURL parse raw url to url object.
@@ -91,6 +92,24 @@ func ParseBackend(rawURL string, options *BackendOptions) (*backuppb.StorageBack
 		}
 		return &backuppb.StorageBackend{Backend: &backuppb.StorageBackend_Gcs{Gcs: gcs}}, nil
 
+	case "azure", "azblob":
+		if u.Host == "" {
+			return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "please specify the container for azure in %s", rawURL)
+		}
+		prefix := strings.Trim(u.Path, "/")
+		azblob := &backuppb.CloudDynamic{
+			Bucket:       &backuppb.Bucket{Bucket: u.Host, Prefix: prefix},
+			ProviderName: azblobProviderName,
+		}
+		if options == nil {
+			options = &BackendOptions{}
+		}
+		ExtractQueryParameters(u, &options.Azblob)
+		if err := options.Azblob.apply(azblob); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &backuppb.StorageBackend{Backend: &backuppb.StorageBackend_CloudDynamic{CloudDynamic: azblob}}, nil
+
 	default:
 		return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "storage %s not support yet", u.Scheme)
 	}
@@ -166,6 +185,12 @@ func FormatBackendURL(backend *backuppb.StorageBackend) (u url.URL) {
 		u.Scheme = "gcs"
 		u.Host = b.Gcs.Bucket
 		u.Path = b.Gcs.Prefix
+	case *backuppb.StorageBackend_CloudDynamic:
+		u.Scheme = b.CloudDynamic.ProviderName
+		if bucket := b.CloudDynamic.Bucket; bucket != nil {
+			u.Host = bucket.Bucket
+			u.Path = bucket.Prefix
+		}
 	}
 	return
 }
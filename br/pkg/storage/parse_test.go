@@ -128,6 +128,39 @@ func (r *testStorageSuite) TestCreateStorage(c *C) {
 	c.Assert(gcs.Prefix, Equals, "backup")
 	c.Assert(gcs.CredentialsBlob, Equals, "fakeCreds2")
 
+	s, err = ParseBackend("azure://container/backup/?account-name=devstoreaccount1&account-key=a2V5&access-tier=Cool", nil)
+	c.Assert(err, IsNil)
+	azblob := s.GetCloudDynamic()
+	c.Assert(azblob, NotNil)
+	c.Assert(azblob.ProviderName, Equals, "azure")
+	c.Assert(azblob.Bucket.Bucket, Equals, "container")
+	c.Assert(azblob.Bucket.Prefix, Equals, "backup")
+	c.Assert(azblob.Bucket.Endpoint, Equals, "")
+	c.Assert(azblob.Attrs, DeepEquals, map[string]string{
+		"account_name": "devstoreaccount1",
+		"account_key":  "a2V5",
+		"access_tier":  "Cool",
+	})
+
+	azblobOpt := &BackendOptions{Azblob: AzblobBackendOptions{Endpoint: "http://127.0.0.1:10000/devstoreaccount1"}}
+	s, err = ParseBackend("azblob://container?sas-token="+url.QueryEscape("?sv=2020-04-08&sig=abc"), azblobOpt)
+	c.Assert(err, IsNil)
+	azblob = s.GetCloudDynamic()
+	c.Assert(azblob, NotNil)
+	c.Assert(azblob.Bucket.Bucket, Equals, "container")
+	c.Assert(azblob.Bucket.Prefix, Equals, "")
+	c.Assert(azblob.Bucket.Endpoint, Equals, "http://127.0.0.1:10000/devstoreaccount1")
+	c.Assert(azblob.Attrs, DeepEquals, map[string]string{"sas_token": "sv=2020-04-08&sig=abc"})
+
+	_, err = ParseBackend("azure:///backup", nil)
+	c.Assert(err, ErrorMatches, "please specify the container for azure in azure:///backup.*")
+	_, err = ParseBackend("azure://container/backup?account-key=a2V5", nil)
+	c.Assert(err, ErrorMatches, "account_name not found.*")
+	_, err = ParseBackend("azure://container/backup?account-name=a&account-key=a2V5&sas-token=sig", nil)
+	c.Assert(err, ErrorMatches, "account_key and sas_token can't be specified at the same time.*")
+	_, err = ParseBackend("azure://container/backup?access-tier=cold", nil)
+	c.Assert(err, ErrorMatches, "invalid access tier 'cold', should be Hot, Cool or Archive.*")
+
 	s, err = ParseBackend("/test", nil)
 	c.Assert(err, IsNil)
 	local := s.GetLocal()
@@ -173,4 +206,19 @@ func (r *testStorageSuite) TestFormatBackendURL(c *C) {
 		},
 	})
 	c.Assert(url.String(), Equals, "gcs://bucket/some%20prefix/")
+
+	url = FormatBackendURL(&backuppb.StorageBackend{
+		Backend: &backuppb.StorageBackend_CloudDynamic{
+			CloudDynamic: &backuppb.CloudDynamic{
+				Bucket: &backuppb.Bucket{
+					Bucket:   "container",
+					Prefix:   "/some prefix/",
+					Endpoint: "https://account.blob.core.windows.net",
+				},
+				ProviderName: "azure",
+				Attrs:        map[string]string{"account_key": "a2V5"},
+			},
+		},
+	})
+	c.Assert(url.String(), Equals, "azure://container/some%20prefix/")
 }
@@ -430,6 +430,9 @@ type Performance struct {
 	IndexUsageSyncLease   string  `toml:"index-usage-sync-lease" json:"index-usage-sync-lease"`
 	GOGC                  int     `toml:"gogc" json:"gogc"`
 	EnforceMPP            bool    `toml:"enforce-mpp" json:"enforce-mpp"`
+	// ZstdCompressionLevel is the level used to compress the protocol packets with zstd if the client doesn't
+	// request a valid one.
+	ZstdCompressionLevel int `toml:"zstd-compression-level" json:"zstd-compression-level"`
 }
 
 // PlanCache is the PlanCache section of the config.
@@ -629,9 +632,10 @@ var defaultConf = Config{
 		MaxTxnTTL:             defTiKVCfg.MaxTxnTTL, // 1hour
 		MemProfileInterval:    "1m",
 		// TODO: set indexUsageSyncLease to 60s.
-		IndexUsageSyncLease: "0s",
-		GOGC:                100,
-		EnforceMPP:          false,
+		IndexUsageSyncLease:  "0s",
+		GOGC:                 100,
+		EnforceMPP:           false,
+		ZstdCompressionLevel: 3,
 	},
 	ProxyProtocol: ProxyProtocol{
 		Networks:      "",
@@ -890,6 +894,10 @@ func (c *Config) Valid() error {
 		return fmt.Errorf("memory-usage-alarm-ratio in [Performance] must be greater than or equal to 0 and less than or equal to 1")
 	}
 
+	if c.Performance.ZstdCompressionLevel < 1 || c.Performance.ZstdCompressionLevel > 22 {
+		return fmt.Errorf("zstd-compression-level in [Performance] should be [1, 22]")
+	}
+
 	if c.StmtSummary.MaxStmtCount <= 0 {
 		return fmt.Errorf("max-stmt-count in [stmt-summary] should be greater than 0")
 	}
@@ -16,6 +16,8 @@ package ddl_test
 
 import (
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -78,3 +80,69 @@ PARTITION BY RANGE (c) (
 	_, err = tk.Exec(`alter table t1 partition p1 attributes " nomerge , somethingelse ";`)
 	c.Assert(err, IsNil)
 }
+
+func (s *testDBSuite8) TestAlterTableTTLAttributes(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	defer tk.MustExec("drop table if exists t1, t2")
+
+	tk.MustExec(`create table t1 (id int primary key, created_at datetime, name varchar(10))
+PARTITION BY RANGE (id) (
+	PARTITION p0 VALUES LESS THAN (6),
+	PARTITION p1 VALUES LESS THAN (11)
+);`)
+	query := "select table_name, ttl_column, ttl_interval, ttl_enable from information_schema.tidb_ttl_table_status where table_schema = 'test'"
+
+	// normal cases
+	tk.MustExec(`alter table t1 attributes="ttl=created_at + interval 1 day";`)
+	tk.MustQuery(query).Check(testkit.Rows("t1 created_at INTERVAL 1 DAY ON"))
+	tk.MustExec(`alter table t1 attributes="nomerge, TTL = created_at + INTERVAL '1:30' HOUR_MINUTE, ttl_enable=off";`)
+	tk.MustQuery(query).Check(testkit.Rows("t1 created_at INTERVAL 1:30 HOUR_MINUTE OFF"))
+
+	// invalid cases
+	_, err := tk.Exec(`alter table t1 attributes="ttl=name + interval 1 day";`)
+	c.Assert(ddl.ErrUnsupportedColumnInTTLConfig.Equal(err), IsTrue)
+	_, err = tk.Exec(`alter table t1 attributes="ttl=updated_at + interval 1 day";`)
+	c.Assert(infoschema.ErrColumnNotExists.Equal(err), IsTrue)
+	_, err = tk.Exec(`alter table t1 attributes="ttl=created_at - interval 1 day";`)
+	c.Assert(ddl.ErrInvalidAttributesSpec.Equal(err), IsTrue)
+	_, err = tk.Exec(`alter table t1 attributes="ttl=created_at + interval 1 day, ttl_enable=maybe";`)
+	c.Assert(ddl.ErrInvalidAttributesSpec.Equal(err), IsTrue)
+	_, err = tk.Exec(`alter table t1 attributes="ttl_enable=on";`)
+	c.Assert(ddl.ErrSetTTLOptionForNonTTLTable.Equal(err), IsTrue)
+	_, err = tk.Exec(`alter table t1 partition p0 attributes="ttl=created_at + interval 1 day";`)
+	c.Assert(ddl.ErrInvalidAttributesSpec.Equal(err), IsTrue)
+	tk.MustQuery(query).Check(testkit.Rows("t1 created_at INTERVAL 1:30 HOUR_MINUTE OFF"))
+
+	// the TTL column can be renamed but can't be dropped
+	tk.MustExec("alter table t1 rename column created_at to create_time")
+	tk.MustQuery(query).Check(testkit.Rows("t1 create_time INTERVAL 1:30 HOUR_MINUTE OFF"))
+	_, err = tk.Exec("alter table t1 drop column create_time")
+	c.Assert(ddl.ErrTTLColumnCannotDrop.Equal(err), IsTrue)
+	tk.MustExec("alter table t1 drop column name")
+
+	// the TTL attribute is kept after truncating or renaming the table
+	tk.MustExec("truncate table t1")
+	tk.MustQuery(query).Check(testkit.Rows("t1 create_time INTERVAL 1:30 HOUR_MINUTE OFF"))
+	tk.MustExec("rename table t1 to t2")
+	tk.MustQuery(query).Check(testkit.Rows("t2 create_time INTERVAL 1:30 HOUR_MINUTE OFF"))
+	tk.MustExec("rename table t2 to mysql.t2")
+	tk.MustQuery(query).Check(testkit.Rows())
+	tk.MustQuery("select table_name from information_schema.tidb_ttl_table_status where table_schema = 'mysql'").Check(testkit.Rows("t2"))
+	tk.MustExec("rename table mysql.t2 to t1")
+
+	// the TTL attribute is removed when the attributes are reset or the table is dropped
+	tk.MustExec(`alter table t1 attributes="nomerge";`)
+	tk.MustQuery(query).Check(testkit.Rows())
+	tk.MustExec(`alter table t1 attributes="ttl=create_time + interval 1 day";`)
+	tk.MustQuery(query).Check(testkit.Rows("t1 create_time INTERVAL 1 DAY ON"))
+	tk.MustExec("drop table t1")
+	tk.MustQuery(query).Check(testkit.Rows())
+
+	// temporary tables
+	tk.MustExec("set tidb_enable_global_temporary_table = 1")
+	tk.MustExec("create global temporary table t2 (created_at datetime) on commit delete rows")
+	_, err = tk.Exec(`alter table t2 attributes="ttl=created_at + interval 1 day";`)
+	c.Assert(err, NotNil)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/store/copr"
 	"github.com/pingcap/tidb/store/driver/backoff"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
@@ -49,12 +50,16 @@ const (
 	typeAddIndexWorker     backfillWorkerType = 0
 	typeUpdateColumnWorker backfillWorkerType = 1
 	typeCleanUpIndexWorker backfillWorkerType = 2
+	// typeReorgPartitionWorker copies the rows to the new partitions of REORGANIZE PARTITION, PARTITION BY or
+	// REMOVE PARTITIONING.
+	typeReorgPartitionWorker backfillWorkerType = 3
 )
 
 // By now the DDL jobs that need backfilling include:
 // 1: add-index
 // 2: modify-column-type
 // 3: clean-up global index
+// 4: reorganize partition
 //
 // They all have a write reorganization state to back fill data into the rows existed.
 // Backfilling is time consuming, to accelerate this process, TiDB has built some sub
@@ -111,6 +116,8 @@ func (bWT backfillWorkerType) String() string {
 		return "update column"
 	case typeCleanUpIndexWorker:
 		return "clean up index"
+	case typeReorgPartitionWorker:
+		return "reorganize partition"
 	default:
 		return "unknown"
 	}
@@ -561,6 +568,18 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 		return nil
 	}
 
+	var reorgedTbl table.Table
+	if bfWorkerType == typeReorgPartitionWorker {
+		tbl, err := getTable(reorgInfo.d.store, job.SchemaID, t.Meta())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reorgedTbl = tables.GetPartitionReorgTable(tbl)
+		if reorgedTbl == nil {
+			return errCancelledDDLJob.GenWithStack("table `%v` isn't being reorganized", t.Meta().Name)
+		}
+	}
+
 	failpoint.Inject("MockCaseWhenParseFailure", func(val failpoint.Value) {
 		if val.(bool) {
 			failpoint.Return(errors.New("job.ErrCount:" + strconv.Itoa(int(job.ErrorCount)) + ", mock unknown type: ast.whenClause."))
@@ -622,6 +641,11 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 				idxWorker.priority = job.Priority
 				backfillWorkers = append(backfillWorkers, idxWorker.backfillWorker)
 				go idxWorker.backfillWorker.run(reorgInfo.d, idxWorker, job)
+			case typeReorgPartitionWorker:
+				reorgWorker := newReorgPartitionWorker(sessCtx, w, i, t, reorgedTbl, decodeColMap)
+				reorgWorker.priority = job.Priority
+				backfillWorkers = append(backfillWorkers, reorgWorker.backfillWorker)
+				go reorgWorker.backfillWorker.run(reorgInfo.d, reorgWorker, job)
 			default:
 				return errors.New("unknow backfill type")
 			}
@@ -532,6 +532,10 @@ func checkDropColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.
 			job.State = model.JobStateCancelled
 			return nil, nil, 0, nil, errors.Trace(err)
 		}
+		if err = checkDropColumnWithTTL(t, schemaID, tblInfo, colInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return nil, nil, 0, nil, errors.Trace(err)
+		}
 		newColNames = append(newColNames, colName)
 		newIfExists = append(newIfExists, ifExists[i])
 		colInfos = append(colInfos, colInfo)
@@ -545,6 +549,8 @@ func checkDropColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.
 func checkDropColumnForStatePublic(tblInfo *model.TableInfo, colInfo *model.ColumnInfo) (err error) {
 	// Set this column's offset to the last and reset all following columns' offsets.
 	adjustColumnInfoInDropColumn(tblInfo, colInfo.Offset)
+	// The check constraints which only refer to the dropping column are dropped together.
+	removeCheckConstraintsByColumn(tblInfo, colInfo.Name)
 	// When the dropping column has not-null flag and it hasn't the default value, we can backfill the column value like "add column".
 	// NOTE: If the state of StateWriteOnly can be rollbacked, we'd better reconsider the original default value.
 	// And we need consider the column without not-null flag.
@@ -661,6 +667,10 @@ func checkDropColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Col
 		job.State = model.JobStateCancelled
 		return nil, nil, nil, errors.Trace(err)
 	}
+	if err = checkDropColumnWithTTL(t, schemaID, tblInfo, colInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, nil, errors.Trace(err)
+	}
 	idxInfos := listIndicesWithColumn(colName.L, tblInfo.Indices)
 	if len(idxInfos) > 0 {
 		for _, idxInfo := range idxInfos {
@@ -0,0 +1,401 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+func allocateConstraintID(tblInfo *model.TableInfo) int64 {
+	tblInfo.MaxConstraintID++
+	return tblInfo.MaxConstraintID
+}
+
+// checkConstraintExprChecker checks the functions and the nodes that can't be used in a check constraint.
+type checkConstraintExprChecker struct {
+	name string
+	err  error
+}
+
+func (c *checkConstraintExprChecker) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
+	switch node := inNode.(type) {
+	case *ast.FuncCallExpr:
+		// Blocked functions & non-builtin functions is not allowed.
+		_, isFunctionBlocked := expression.IllegalFunctions4GeneratedColumns[node.FnName.L]
+		if isFunctionBlocked || !expression.IsFunctionSupported(node.FnName.L) {
+			c.err = ErrCheckConstraintNamedFunctionIsNotAllowed.GenWithStackByArgs(c.name, node.FnName.L)
+			return inNode, true
+		}
+		if err := expression.VerifyArgsWrapper(node.FnName.L, len(node.Args)); err != nil {
+			c.err = err
+			return inNode, true
+		}
+	case *ast.VariableExpr:
+		c.err = ErrCheckConstraintVariables.GenWithStackByArgs(c.name)
+		return inNode, true
+	case *ast.SubqueryExpr, *ast.ValuesExpr, *ast.AggregateFuncExpr, *ast.WindowFuncExpr, *ast.DefaultExpr:
+		c.err = ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(c.name)
+		return inNode, true
+	case *ast.RowExpr:
+		c.err = ErrCheckConstraintRowValue.GenWithStackByArgs(c.name)
+		return inNode, true
+	}
+	return inNode, c.err != nil
+}
+
+func (c *checkConstraintExprChecker) Leave(inNode ast.Node) (node ast.Node, ok bool) {
+	return inNode, c.err == nil
+}
+
+// setEmptyCheckConstraintName sets the name of the check constraints without a name
+// to `<table>_chk_<n>`, the same as MySQL.
+func setEmptyCheckConstraintName(tableName string, namesMap map[string]bool, constrs []*ast.Constraint) {
+	cnt := 1
+	for _, constr := range constrs {
+		if constr.Tp != ast.ConstraintCheck || constr.Name != "" {
+			continue
+		}
+		constrName := fmt.Sprintf("%s_chk_%d", tableName, cnt)
+		for namesMap[strings.ToLower(constrName)] {
+			cnt++
+			constrName = fmt.Sprintf("%s_chk_%d", tableName, cnt)
+		}
+		cnt++
+		constr.Name = constrName
+		namesMap[strings.ToLower(constrName)] = true
+	}
+}
+
+// buildCheckConstraintInfo builds the ConstraintInfo of the check constraint, the constraint name must be set.
+func buildCheckConstraintInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, constr *ast.Constraint) (*model.ConstraintInfo, error) {
+	if _, ok := constr.Expr.(*ast.ColumnNameExpr); ok {
+		return nil, ErrNonBooleanExprForCheckConstraint.GenWithStackByArgs(constr.Name)
+	}
+	checker := checkConstraintExprChecker{name: constr.Name}
+	constr.Expr.Accept(&checker)
+	if checker.err != nil {
+		return nil, errors.Trace(checker.err)
+	}
+
+	colNames := findColumnNamesInExpr(constr.Expr)
+	dependedCols := make([]model.CIStr, 0, len(colNames))
+	dependedColsMap := make(map[string]struct{}, len(colNames))
+	for _, colName := range colNames {
+		name := colName.Name
+		col := model.FindColumnInfo(tblInfo.Cols(), name.L)
+		if col == nil {
+			return nil, ErrCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constr.Name, name.O)
+		}
+		if constr.InColumn && name.L != strings.ToLower(constr.InColumnName) {
+			return nil, ErrColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(constr.Name)
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return nil, ErrCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(constr.Name)
+		}
+		if _, ok := dependedColsMap[name.L]; ok {
+			continue
+		}
+		dependedColsMap[name.L] = struct{}{}
+		dependedCols = append(dependedCols, col.Name)
+	}
+	if err := checkCheckConstraintOnFKReferActions(tblInfo, constr.Name, dependedColsMap); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err := constr.Expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	constraintInfo := &model.ConstraintInfo{
+		Name:           model.NewCIStr(constr.Name),
+		Table:          tblInfo.Name,
+		ConstraintCols: dependedCols,
+		ExprString:     sb.String(),
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		State:          model.StateNone,
+	}
+	// Make sure the expression can be rewritten and evaluated as a boolean.
+	c, err := table.ToConstraint(ctx, constraintInfo, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch c.ConstraintExpr.GetType().EvalType() {
+	case types.ETString, types.ETJson, types.ETDatetime, types.ETTimestamp, types.ETDuration:
+		return nil, ErrNonBooleanExprForCheckConstraint.GenWithStackByArgs(constr.Name)
+	}
+	return constraintInfo, nil
+}
+
+// checkCheckConstraintOnFKReferActions checks the columns of a check constraint are not used by
+// the ON UPDATE / ON DELETE CASCADE or SET NULL actions of the foreign keys.
+func checkCheckConstraintOnFKReferActions(tblInfo *model.TableInfo, constrName string, cols map[string]struct{}) error {
+	for _, fk := range tblInfo.ForeignKeys {
+		if !isFKModifyingReferAction(fk.OnUpdate) && !isFKModifyingReferAction(fk.OnDelete) {
+			continue
+		}
+		for _, col := range fk.Cols {
+			if _, ok := cols[col.L]; ok {
+				return ErrCheckConstraintUsingFKReferActionColumn.GenWithStackByArgs(col.O, constrName, fk.Name.O)
+			}
+		}
+	}
+	return nil
+}
+
+func isFKModifyingReferAction(action int) bool {
+	return action == int(ast.ReferOptionCascade) || action == int(ast.ReferOptionSetNull)
+}
+
+// findCheckConstraintsByColumn returns the check constraints which refer to the column.
+func findCheckConstraintsByColumn(tblInfo *model.TableInfo, colName model.CIStr) []*model.ConstraintInfo {
+	var constrs []*model.ConstraintInfo
+	for _, constr := range tblInfo.Constraints {
+		for _, col := range constr.ConstraintCols {
+			if col.L == colName.L {
+				constrs = append(constrs, constr)
+				break
+			}
+		}
+	}
+	return constrs
+}
+
+// checkDropColumnWithCheckConstraint checks the column can be dropped. A column can't be dropped if
+// it's referred by a check constraint that also refers to other columns.
+func checkDropColumnWithCheckConstraint(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, constr := range findCheckConstraintsByColumn(tblInfo, colName) {
+		if len(constr.ConstraintCols) > 1 {
+			return ErrDependentByCheckConstraint.GenWithStackByArgs(constr.Name.O, colName.O)
+		}
+	}
+	return nil
+}
+
+// removeCheckConstraintsByColumn removes the check constraints which only refer to the dropped column.
+func removeCheckConstraintsByColumn(tblInfo *model.TableInfo, colName model.CIStr) {
+	constrs := tblInfo.Constraints[:0]
+	for _, constr := range tblInfo.Constraints {
+		if len(constr.ConstraintCols) == 1 && constr.ConstraintCols[0].L == colName.L {
+			continue
+		}
+		constrs = append(constrs, constr)
+	}
+	tblInfo.Constraints = constrs
+}
+
+func removeCheckConstraint(tblInfo *model.TableInfo, constrName model.CIStr) {
+	constrs := tblInfo.Constraints[:0]
+	for _, constr := range tblInfo.Constraints {
+		if constr.Name.L != constrName.L {
+			constrs = append(constrs, constr)
+		}
+	}
+	tblInfo.Constraints = constrs
+}
+
+func (w *worker) onAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	schemaID := job.SchemaID
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := &model.ConstraintInfo{}
+	if err = job.DecodeArgs(constraintInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	existing := tblInfo.FindConstraintInfoByName(constraintInfo.Name.L)
+	if existing != nil {
+		if existing.State == model.StatePublic || job.SchemaState == model.StateNone {
+			job.State = model.JobStateCancelled
+			return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constraintInfo.Name.O)
+		}
+		constraintInfo = existing
+	} else {
+		constraintInfo.ID = allocateConstraintID(tblInfo)
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+
+	originalState := constraintInfo.State
+	switch constraintInfo.State {
+	case model.StateNone:
+		// none -> write only
+		job.SchemaState = model.StateWriteOnly
+		constraintInfo.State = model.StateWriteOnly
+		// A constraint which is not enforced is never checked, so it can be public directly.
+		if !constraintInfo.Enforced {
+			job.SchemaState = model.StatePublic
+			constraintInfo.State = model.StatePublic
+		}
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		if constraintInfo.State == model.StatePublic {
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		}
+	case model.StateWriteOnly:
+		// The new rows are checked by all the TiDB servers now, check the existing rows.
+		// write only -> public
+		if err = w.verifyRowsWithCheckConstraint(dbInfo.Name, tblInfo, constraintInfo); err != nil {
+			if !table.ErrCheckConstraintViolated.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			removeCheckConstraint(tblInfo, constraintInfo.Name)
+			ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+			if err1 != nil {
+				return ver, errors.Trace(err1)
+			}
+			job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StatePublic
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	schemaID := job.SchemaID
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constrName model.CIStr
+	if err = job.DecodeArgs(&constrName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constraintInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	switch constraintInfo.State {
+	case model.StatePublic:
+		// The constraint is only used to check the written rows, so it can be removed directly.
+		// public -> none
+		removeCheckConstraint(tblInfo, constrName)
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		return ver, nil
+	default:
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+}
+
+func (w *worker) onAlterCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	schemaID := job.SchemaID
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var (
+		constrName model.CIStr
+		enforced   bool
+	)
+	if err = job.DecodeArgs(&constrName, &enforced); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constraintInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil || constraintInfo.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+	if constraintInfo.Enforced == enforced {
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+	if enforced {
+		// The existing rows must satisfy the constraint before it's enforced.
+		if err = w.verifyRowsWithCheckConstraint(dbInfo.Name, tblInfo, constraintInfo); err != nil {
+			if table.ErrCheckConstraintViolated.Equal(err) {
+				job.State = model.JobStateCancelled
+			}
+			return ver, errors.Trace(err)
+		}
+	}
+	constraintInfo.Enforced = enforced
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// verifyRowsWithCheckConstraint checks whether the existing rows of the table satisfy the check constraint.
+func (w *worker) verifyRowsWithCheckConstraint(schemaName model.CIStr, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo) error {
+	var sctx sessionctx.Context
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(sctx)
+
+	// The `%` in the expression must be escaped, otherwise it's treated as a parameter.
+	sql := fmt.Sprintf("select 1 from %%n.%%n where not (%s) limit 1", strings.ReplaceAll(constraintInfo.ExprString, "%", "%%"))
+	stmt, err := sctx.(sqlexec.RestrictedSQLExecutor).ParseWithParams(w.ddlJobCtx, sql, schemaName.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedStmt(w.ddlJobCtx, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		return table.ErrCheckConstraintViolated.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	return nil
+}
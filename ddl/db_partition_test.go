@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/ddl/testutil"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
 	tmysql "github.com/pingcap/tidb/errno"
@@ -3020,11 +3021,9 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash(store_id)
 	partitions 4;`)
-	_, err := tk.Exec("alter table employees add partition partitions 8;")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
-
-	_, err = tk.Exec("alter table employees add partition (partition p5 values less than (42));")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
+	tk.MustGetErrCode("alter table employees add partition partitions 0;", tmysql.ErrAddPartitionNoNewPartition)
+	tk.MustGetErrCode("alter table employees add partition (partition p5 values less than (42));", tmysql.ErrPartitionWrongValues)
+	tk.MustGetErrCode("alter table employees add partition (partition p3);", tmysql.ErrSameNamePartition)
 
 	// coalesce partition
 	tk.MustExec(`create table clients (
@@ -3035,24 +3034,24 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash( month(signed) )
 	partitions 12;`)
-	_, err = tk.Exec("alter table clients coalesce partition 4;")
-	c.Assert(ddl.ErrUnsupportedCoalescePartition.Equal(err), IsTrue)
+	tk.MustGetErrCode("alter table clients coalesce partition 0;", tmysql.ErrCoalescePartitionNoPartition)
+	tk.MustGetErrCode("alter table clients coalesce partition 12;", tmysql.ErrDropLastPartition)
 
 	tk.MustExec(`create table t_part (a int key)
 		partition by range(a) (
 		partition p0 values less than (10),
 		partition p1 values less than (20)
 		);`)
-	_, err = tk.Exec("alter table t_part coalesce partition 4;")
+	_, err := tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
-	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
+	tk.MustGetErrCode(`alter table clients reorganize partition p0, p1 into (
 			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t_part reorganize partition;", tmysql.ErrUnsupportedDDLOperation)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part repair partition p1;", tmysql.ErrUnsupportedDDLOperation)
 
 	// Reduce the impact on DML when executing partition DDL
@@ -3204,7 +3203,7 @@ func (s *testIntegrationSuite3) TestUnsupportedPartitionManagementDDLs(c *C) {
 	`)
 
 	_, err := tk.Exec("alter table test_1465 partition by hash(a)")
-	c.Assert(err, ErrorMatches, ".*alter table partition is unsupported")
+	c.Assert(err, ErrorMatches, ".*changing the partitioning type or expression of a partitioned table, remove partitioning first")
 }
 
 func (s *testSerialDBSuite1) TestCommitWhenSchemaChange(c *C) {
@@ -3478,3 +3477,227 @@ func (s *testSerialDBSuite1) TestTruncatePartitionMultipleTimes(c *C) {
 	<-done2
 	c.Assert(errCount, LessEqual, int32(1))
 }
+
+func (s *testIntegrationSuite5) TestReorganizeRangePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec(`create table t (a int primary key, b varchar(10), key idx_b(b))
+		partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 'a'), (11, 'b'), (15, 'c'), (21, 'd'), (29, 'e')")
+
+	// Split a partition.
+	tk.MustExec(`alter table t reorganize partition p1 into (
+		partition p10 values less than (15),
+		partition p15 values less than (20))`)
+	tk.MustQuery("select * from t partition (p10)").Check(testkit.Rows("11 b"))
+	tk.MustQuery("select * from t partition (p15)").Check(testkit.Rows("15 c"))
+	tk.MustQuery("select a from t use index(idx_b) where b >= 'b' order by a").Check(testkit.Rows("11", "15", "21", "29"))
+	tk.MustExec("admin check table t")
+
+	// Merge partitions and extend the range of the last partition.
+	tk.MustExec(`alter table t reorganize partition p15, p2 into (
+		partition p2 values less than (maxvalue))`)
+	tk.MustQuery("select * from t partition (p2) order by a").Check(testkit.Rows("15 c", "21 d", "29 e"))
+	tk.MustExec("insert into t values (100, 'f')")
+	tk.MustQuery("select * from t partition (p2) where a = 100").Check(testkit.Rows("100 f"))
+	tk.MustExec("admin check table t")
+	is := domain.GetDomain(tk.Se).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	pi := tbl.Meta().Partition
+	c.Assert(pi.Definitions, HasLen, 3)
+	c.Assert(pi.AddingDefinitions, IsNil)
+	c.Assert(pi.DroppingDefinitions, IsNil)
+
+	tk.MustGetErrCode(`alter table t reorganize partition p0, p2 into (
+		partition p0 values less than (maxvalue))`, tmysql.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode(`alter table t reorganize partition p0 into (
+		partition p0 values less than (5))`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t reorganize partition p0 into (
+		partition p0 values less than (12))`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t reorganize partition p0, p10, p2, p3 into (
+		partition p0 values less than (maxvalue))`, tmysql.ErrReorgPartitionNotExist)
+	tk.MustGetErrCode(`alter table t reorganize partition p3 into (
+		partition p3 values less than (10))`, tmysql.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode(`alter table t reorganize partition p0 into (
+		partition p2 values less than (10))`, tmysql.ErrSameNamePartition)
+}
+
+func (s *testIntegrationSuite5) TestReorganizeListPartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec(`create table t (id int, name varchar(10), unique key (id))
+		partition by list (id) (
+		partition p0 values in (1, 2, 3, 4),
+		partition p1 values in (5, 6, 7, 8))`)
+	tk.MustExec("insert into t values (1, 'a'), (3, 'b'), (5, 'c'), (7, 'd')")
+
+	tk.MustExec(`alter table t reorganize partition p0 into (
+		partition p01 values in (1, 2),
+		partition p02 values in (3, 4))`)
+	tk.MustQuery("select * from t partition (p01)").Check(testkit.Rows("1 a"))
+	tk.MustQuery("select * from t partition (p02)").Check(testkit.Rows("3 b"))
+	tk.MustExec("admin check table t")
+
+	// The rows which don't belong to any of the new partitions roll the job back.
+	tk.MustGetErrCode(`alter table t reorganize partition p1 into (
+		partition p1 values in (5, 6))`, tmysql.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select * from t partition (p1) order by id").Check(testkit.Rows("5 c", "7 d"))
+	tk.MustExec("admin check table t")
+
+	tk.MustExec(`alter table t reorganize partition p01, p1 into (
+		partition p1 values in (1, 2, 5, 6, 7, 8, 9))`)
+	tk.MustQuery("select * from t partition (p1) order by id").Check(testkit.Rows("1 a", "5 c", "7 d"))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 a", "3 b", "5 c", "7 d"))
+	tk.MustExec("insert into t values (9, 'e')")
+	tk.MustExec("admin check table t")
+}
+
+func (s *testIntegrationSuite5) TestAlterTablePartitioningAndRemovePartitioning(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int not null auto_increment primary key, b int, key idx_b(b))")
+	tk.MustExec("insert into t (b) values (1), (2), (3), (4)")
+	tk.MustExec("insert into t values (100, 100)")
+
+	tk.MustExec("alter table t partition by range (a) (partition p0 values less than (3), partition p1 values less than (maxvalue))")
+	createSQL := tk.MustQuery("show create table t").Rows()[0][1].(string)
+	c.Assert(strings.HasSuffix(createSQL, "PARTITION BY RANGE ( `a` ) (\n"+
+		"  PARTITION `p0` VALUES LESS THAN (3),\n"+
+		"  PARTITION `p1` VALUES LESS THAN (MAXVALUE)\n"+
+		")"), IsTrue, Commentf("%s", createSQL))
+	tk.MustQuery("select * from t partition (p0) order by a").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustQuery("select * from t partition (p1) order by a").Check(testkit.Rows("3 3", "4 4", "100 100"))
+	tk.MustExec("admin check table t")
+
+	// Redefine the partitions with the same partitioning.
+	tk.MustExec("alter table t partition by range (a) (partition p0 values less than (4), partition p1 values less than (maxvalue))")
+	tk.MustQuery("select * from t partition (p0) order by a").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustExec("admin check table t")
+	tk.MustGetErrCode("alter table t partition by hash (a) partitions 4", tmysql.ErrUnsupportedDDLOperation)
+
+	tk.MustExec("alter table t remove partitioning")
+	is := domain.GetDomain(tk.Se).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Partition, IsNil)
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "2 2", "3 3", "4 4", "100 100"))
+	tk.MustQuery("select a from t use index(idx_b) where b > 2 order by a").Check(testkit.Rows("3", "4", "100"))
+	tk.MustExec("insert into t (b) values (5)")
+	tk.MustQuery("select count(*) from t where a > 100").Check(testkit.Rows("1"))
+	tk.MustExec("admin check table t")
+	tk.MustGetErrCode("alter table t remove partitioning", tmysql.ErrPartitionMgmtOnNonpartitioned)
+
+	// The table with the _tidb_rowid handle.
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int, b int, unique key (a))")
+	tk.MustExec("insert into t values (1, 1), (5, 5), (9, 9)")
+	tk.MustExec("alter table t partition by hash (a) partitions 2")
+	tk.MustQuery("select * from t partition (p1) order by a").Check(testkit.Rows("1 1", "5 5", "9 9"))
+	tk.MustExec("admin check table t")
+	tk.MustGetErrCode("alter table t partition by hash (a + 1) partitions 2", tmysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t remove partitioning")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "5 5", "9 9"))
+	tk.MustExec("admin check table t")
+	tk.MustGetErrCode("alter table t partition by hash (b) partitions 2", tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+}
+
+func (s *testSerialDBSuite1) TestReorganizePartitionWithDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int primary key, b int, key idx_b(b))")
+	for i := 0; i < 400; i += 2 {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	dom := domain.GetDomain(tk.Se)
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	var checkErr error
+	var states map[model.SchemaState]struct{}
+	updated := 0
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		switch job.Type {
+		case ddlutil.ActionReorganizePartition, ddlutil.ActionAlterTablePartitioning, ddlutil.ActionRemovePartitioning:
+		default:
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok || checkErr != nil {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		// Change the rows in the partitions being reorganized and the ones which aren't.
+		n := len(states) + updated
+		for _, sql := range []string{
+			fmt.Sprintf("insert into t values (%d, %d), (%d, %d)", 2*n+1, 2*n+1, 200+2*n+1, 200+2*n+1),
+			fmt.Sprintf("update t set b = b + 1000 where a in (%d, %d)", 2*n, 200+2*n),
+			fmt.Sprintf("delete from t where a in (%d, %d)", 2*n-1, 200+2*n-1),
+		} {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				return
+			}
+		}
+	}
+	dom.DDL().SetHook(hook)
+	for _, sql := range []string{
+		`alter table t partition by range (a) (
+			partition p0 values less than (200),
+			partition p1 values less than (400))`,
+		`alter table t reorganize partition p1 into (
+			partition p10 values less than (300),
+			partition p15 values less than (400))`,
+		"alter table t remove partitioning",
+	} {
+		states = make(map[model.SchemaState]struct{})
+		tk.MustExec(sql)
+		c.Assert(checkErr, IsNil)
+		c.Assert(len(states), Greater, 3)
+		updated += len(states)
+		tk.MustExec("admin check table t")
+		tk.MustQuery("select count(*) from t").Check(tk.MustQuery("select count(*) from t use index(idx_b)").Rows())
+		tk.MustQuery("select count(*) from t where b > 1000").Check(testkit.Rows(fmt.Sprintf("%d", 2*updated)))
+	}
+}
+
+func (s *testIntegrationSuite5) TestAlterTableCoalesceAndAddHashPartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int primary key, b int, key idx_b(b)) partition by hash (a) partitions 4")
+	for i := 0; i < 12; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+
+	tk.MustExec("alter table t coalesce partition 1")
+	tk.MustQuery("select a from t partition (p0) order by a").Check(testkit.Rows("0", "3", "6", "9"))
+	tk.MustQuery("select a from t partition (p2) order by a").Check(testkit.Rows("2", "5", "8", "11"))
+	tk.MustExec("admin check table t")
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	c.Assert(rows[0][3], Equals, "alter table reorganize partition")
+	c.Assert(rows[0][10], Equals, "synced")
+
+	tk.MustExec("alter table t add partition partitions 2")
+	tk.MustQuery("select a from t partition (p4) order by a").Check(testkit.Rows("4", "9"))
+	tk.MustExec("alter table t add partition (partition px comment 'added')")
+	tk.MustQuery("select a from t partition (px) order by a").Check(testkit.Rows("5", "11"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("12"))
+	tk.MustQuery("select b from t use index(idx_b) where b > 9 order by b").Check(testkit.Rows("10", "11"))
+	tk.MustExec("admin check table t")
+
+	is := domain.GetDomain(tk.Se).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	pi := tbl.Meta().Partition
+	c.Assert(pi.Num, Equals, uint64(6))
+	c.Assert(pi.Definitions, HasLen, 6)
+	c.Assert(pi.Definitions[5].Comment, Equals, "added")
+}
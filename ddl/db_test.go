@@ -6455,32 +6455,74 @@ func (s *testDBSuite4) TestColumnCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists column_check")
-	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1))")
+	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1), b int constraint b_chk check (b < 10) not enforced)")
 	defer tk.MustExec("drop table if exists column_check")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	tk.MustQuery("show create table column_check").Check(testutil.RowsWithSep("|", ""+
+		"column_check CREATE TABLE `column_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `b` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `column_check_chk_1` CHECK ((`a` > 1)),\n"+
+		"  CONSTRAINT `b_chk` CHECK ((`b` < 10)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into column_check values (1, 2, 20), (2, null, null)")
+	err := tk.ExecToErr("insert into column_check values (3, 1, 1)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[table:3819]Check constraint 'column_check_chk_1' is violated.")
+
+	tk.MustGetErrCode("create table column_check_1 (a int check (b > 1), b int)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("alter table column_check add column c int check (c > 1)", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table column_check modify column a int check (a > 2)", errno.ErrUnsupportedDDLOperation)
 }
 
 func (s *testDBSuite5) TestAlterCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("create table alter_check (pk int primary key)")
+	tk.MustExec("create table alter_check (pk int primary key, a int, constraint crcn check (a > 1))")
 	defer tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("alter table alter_check alter check crcn ENFORCED")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ALTER CHECK is not supported"))
+	tk.MustGetErrCode("alter table alter_check alter check crcn_1 enforced", errno.ErrCheckConstraintNotFound)
+
+	tk.MustExec("alter table alter_check alter check crcn not enforced")
+	tk.MustExec("insert into alter_check values (1, 0), (2, 2)")
+	tk.MustQuery("show create table alter_check").Check(testutil.RowsWithSep("|", ""+
+		"alter_check CREATE TABLE `alter_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `crcn` CHECK ((`a` > 1)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// The existing rows are checked before the constraint is enforced.
+	tk.MustGetErrCode("alter table alter_check alter check crcn enforced", errno.ErrCheckConstraintViolated)
+	tk.MustExec("delete from alter_check where a = 0")
+	tk.MustExec("alter table alter_check alter check crcn enforced")
+	tk.MustGetErrCode("insert into alter_check values (3, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update alter_check set a = 1", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from alter_check").Check(testkit.Rows("2 2"))
 }
 
 func (s *testDBSuite6) TestDropCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("create table drop_check (pk int primary key)")
+	tk.MustExec("create table drop_check (pk int primary key, a int, b int, c int, constraint a_chk check (a > 0), " +
+		"constraint ab_chk check (a < b), constraint c_chk check (c > 0))")
 	defer tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("alter table drop_check drop check crcn")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|DROP CHECK is not supported"))
+	tk.MustGetErrCode("alter table drop_check drop check crcn", errno.ErrCheckConstraintNotFound)
+	tk.MustExec("alter table drop_check drop check c_chk")
+	tk.MustExec("insert into drop_check values (1, 1, 2, -1)")
+
+	// The constraints which refer to multiple columns prevent the columns from being dropped or renamed.
+	tk.MustGetErrCode("alter table drop_check drop column b", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check rename column b to b1", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check change column a a1 int", errno.ErrDependentByCheckConstraint)
+	tk.MustExec("alter table drop_check drop check ab_chk")
+	// The constraints which only refer to the dropped column are dropped together.
+	tk.MustExec("alter table drop_check drop column a")
+	tk.MustQuery("select constraint_name from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "'").Check(testkit.Rows())
+	tk.MustExec("insert into drop_check values (2, 0, 0)")
 }
 
 func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
@@ -6489,22 +6531,61 @@ func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
 	tk.MustExec("drop table if exists add_constraint_check")
 	tk.MustExec("create table add_constraint_check (pk int primary key, a int)")
 	defer tk.MustExec("drop table if exists add_constraint_check")
-	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 1)")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ADD CONSTRAINT CHECK is not supported"))
-}
+	tk.MustExec("insert into add_constraint_check values (1, 1), (2, 2)")
 
-func (s *testDBSuite7) TestCreateTableIngoreCheckConstraint(c *C) {
+	// The existing rows must satisfy the new constraint.
+	err := tk.ExecToErr("alter table add_constraint_check add constraint crn check (a > 1)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[table:3819]Check constraint 'crn' is violated.")
+	tk.MustQuery("show create table add_constraint_check").Check(testutil.RowsWithSep("|", ""+
+		"add_constraint_check CREATE TABLE `add_constraint_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into add_constraint_check values (3, 0)")
+
+	tk.MustExec("alter table add_constraint_check add constraint crn check (a >= 0)")
+	tk.MustExec("alter table add_constraint_check add check (a < 100)")
+	tk.MustExec("alter table add_constraint_check add constraint crn_1 check (a < 10) not enforced")
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a > 1)", errno.ErrCheckConstraintDupName)
+	tk.MustQuery("select * from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "' order by constraint_name").Check(testkit.Rows(
+		"def "+s.schemaName+" add_constraint_check_chk_1 (`a` < 100)",
+		"def "+s.schemaName+" crn (`a` >= 0)",
+		"def "+s.schemaName+" crn_1 (`a` < 10)"))
+	tk.MustQuery("select constraint_name from information_schema.table_constraints where table_name = 'add_constraint_check' and constraint_type = 'CHECK' order by constraint_name").Check(
+		testkit.Rows("add_constraint_check_chk_1", "crn", "crn_1"))
+	tk.MustGetErrCode("insert into add_constraint_check values (4, -1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into add_constraint_check values (4, 100)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into add_constraint_check values (4, 10)")
+}
+
+func (s *testDBSuite7) TestCreateTableWithCheckConstraint(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
-	tk.MustExec("drop table if exists table_constraint_check")
+	tk.MustExec("drop table if exists admin_user, admin_user_like, t")
 	tk.MustExec("CREATE TABLE admin_user (enable bool, CHECK (enable IN (0, 1)));")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	defer tk.MustExec("drop table if exists admin_user, admin_user_like")
 	tk.MustQuery("show create table admin_user").Check(testutil.RowsWithSep("|", ""+
 		"admin_user CREATE TABLE `admin_user` (\n"+
-		"  `enable` tinyint(1) DEFAULT NULL\n"+
+		"  `enable` tinyint(1) DEFAULT NULL,\n"+
+		"  CONSTRAINT `admin_user_chk_1` CHECK ((`enable` in (0,1)))\n"+
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrCode("insert into admin_user values (2)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("create table admin_user_like like admin_user")
+	tk.MustGetErrCode("insert into admin_user_like values (2)", errno.ErrCheckConstraintViolated)
+
+	tk.MustGetErrCode("create table t (a int, b int, constraint c check (a > 0), constraint c check (b > 0))", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("create table t (a int, check (a))", errno.ErrNonBooleanExprForCheckConstraint)
+	tk.MustGetErrCode("create table t (a varchar(10), check (concat(a, 'x')))", errno.ErrNonBooleanExprForCheckConstraint)
+	tk.MustGetErrCode("create table t (a int, check (a > rand()))", errno.ErrCheckConstraintNamedFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t (a int, check (a > (select 1)))", errno.ErrCheckConstraintFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t (a int, check (a > @a))", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("create table t (a int, check ((a, a) > (1, 1)))", errno.ErrCheckConstraintRowValue)
+	tk.MustGetErrCode("create table t (a int auto_increment primary key, check (a > 0))", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("create table t (a int, check (b > 0))", errno.ErrCheckConstraintRefersUnknownColumn)
+	tk.MustGetErrCode("create table t (a int, b int, foreign key (a) references admin_user(enable) on delete cascade, check (a > 0))",
+		errno.ErrCheckConstraintClauseUsingFKReferActionColumn)
 }
 
 func (s *testDBSuite6) TestAlterOrderBy(c *C) {
@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl/label"
 	"github.com/pingcap/tidb/ddl/placement"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
@@ -682,7 +683,15 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
 			case ast.ColumnOptionCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				constraint := &ast.Constraint{
+					Tp:           ast.ConstraintCheck,
+					Name:         v.ConstraintName,
+					Expr:         v.Expr,
+					Enforced:     v.Enforced,
+					InColumn:     true,
+					InColumnName: colDef.Name.Name.O,
+				}
+				constraints = append(constraints, constraint)
 			}
 		}
 	}
@@ -1206,9 +1215,10 @@ func setEmptyConstraintName(namesMap map[string]bool, constr *ast.Constraint, fo
 	}
 }
 
-func checkConstraintNames(constraints []*ast.Constraint) error {
+func checkConstraintNames(tableName model.CIStr, constraints []*ast.Constraint) error {
 	constrNames := map[string]bool{}
 	fkNames := map[string]bool{}
+	checkNames := map[string]bool{}
 
 	// Check not empty constraint name whether is duplicated.
 	for _, constr := range constraints {
@@ -1217,6 +1227,15 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 			if err != nil {
 				return errors.Trace(err)
 			}
+		} else if constr.Tp == ast.ConstraintCheck {
+			if constr.Name == "" {
+				continue
+			}
+			nameLower := strings.ToLower(constr.Name)
+			if checkNames[nameLower] {
+				return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+			}
+			checkNames[nameLower] = true
 		} else {
 			err := checkDuplicateConstraint(constrNames, constr.Name, false)
 			if err != nil {
@@ -1229,10 +1248,11 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 	for _, constr := range constraints {
 		if constr.Tp == ast.ConstraintForeignKey {
 			setEmptyConstraintName(fkNames, constr, true)
-		} else {
+		} else if constr.Tp != ast.ConstraintCheck {
 			setEmptyConstraintName(constrNames, constr, false)
 		}
 	}
+	setEmptyCheckConstraintName(tableName.O, checkNames, constraints)
 
 	return nil
 }
@@ -1378,6 +1398,7 @@ func buildTableInfo(
 		tbInfo.Columns = append(tbInfo.Columns, v.ToInfo())
 		tblColumns = append(tblColumns, table.ToColumn(v.ToInfo()))
 	}
+	var checkConstraints []*ast.Constraint
 	for _, constr := range constraints {
 		// Build hidden columns if necessary.
 		hiddenCols, err := buildHiddenColumnInfo(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tblColumns)
@@ -1441,7 +1462,8 @@ func buildTableInfo(
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			// Build the check constraints after the foreign keys, which are needed to check the constraints.
+			checkConstraints = append(checkConstraints, constr)
 			continue
 		}
 		// build index info.
@@ -1461,6 +1483,9 @@ func buildTableInfo(
 		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
 			idxInfo.Unique = true
 		}
+		if err = checkMultiValuedIndex(tbInfo.Columns, idxInfo.Columns, idxInfo.Unique); err != nil {
+			return nil, err
+		}
 		// set index type.
 		if constr.Option != nil {
 			idxInfo.Comment, err = validateCommentLength(ctx.GetSessionVars(), idxInfo.Name.String(), constr.Option)
@@ -1483,6 +1508,15 @@ func buildTableInfo(
 		idxInfo.ID = allocateIndexID(tbInfo)
 		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 	}
+	for _, constr := range checkConstraints {
+		constraintInfo, err := buildCheckConstraintInfo(ctx, tbInfo, constr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		constraintInfo.ID = allocateConstraintID(tbInfo)
+		constraintInfo.State = model.StatePublic
+		tbInfo.Constraints = append(tbInfo.Constraints, constraintInfo)
+	}
 	if tbInfo.IsCommonHandle {
 		// Ensure tblInfo's each non-unique secondary-index's len + primary-key's len <= MaxIndexLength for clustered index table.
 		var pkLen, idxLen int
@@ -1603,7 +1637,7 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 			if err := checkPartitionFuncType(ctx, s.Partition.Expr, tbInfo); err != nil {
 				return errors.Trace(err)
 			}
-			if err := checkPartitioningKeysConstraints(ctx, s, tbInfo); err != nil {
+			if err := checkPartitioningKeysConstraints(ctx, s.Partition, tbInfo); err != nil {
 				return errors.Trace(err)
 			}
 		}
@@ -1665,6 +1699,14 @@ func buildTableInfoWithLike(ctx sessionctx.Context, ident ast.Ident, referTblInf
 	tblInfo.Name = ident.Name
 	tblInfo.AutoIncID = 0
 	tblInfo.ForeignKeys = nil
+	tblInfo.Constraints = make([]*model.ConstraintInfo, 0, len(referTblInfo.Constraints))
+	for _, constr := range referTblInfo.Constraints {
+		if constr.State == model.StatePublic {
+			newConstr := constr.Clone()
+			newConstr.Table = ident.Name
+			tblInfo.Constraints = append(tblInfo.Constraints, newConstr)
+		}
+	}
 	// Ignore TiFlash replicas for temporary tables.
 	if s.TemporaryKeyword != ast.TemporaryNone {
 		tblInfo.TiFlashReplica = nil
@@ -1753,7 +1795,7 @@ func buildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCh
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	err = checkConstraintNames(newConstraints)
+	err = checkConstraintNames(s.Table.Name, newConstraints)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1837,6 +1879,12 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
 		return err
 	}
+	if err = checkFKReferSchemas(ctx, schema.Name, s.Constraints); err != nil {
+		return err
+	}
+	if err = checkFKReferredTables(ctx, is, schema.Name, tbInfo, tbInfo.ForeignKeys); err != nil {
+		return err
+	}
 
 	onExist := OnExistError
 	if s.IfNotExists {
@@ -2463,7 +2511,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(sctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(sctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2471,7 +2519,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableOptimizePartition:
 			err = errors.Trace(errUnsupportedOptimizePartition)
 		case ast.AlterTableRemovePartitioning:
-			err = errors.Trace(errUnsupportedRemovePartition)
+			err = d.RemovePartitioning(sctx, ident)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(errUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -2524,7 +2572,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			case ast.ConstraintFulltext:
 				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
 			case ast.ConstraintCheck:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				err = d.CreateCheckConstraint(sctx, ident, constr)
 			default:
 				// Nothing to do now.
 			}
@@ -2550,8 +2598,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 				err = errors.New("alter partition alter placement is experimental and it is switched off by tidb_enable_alter_placement")
 			}
 		case ast.AlterTablePartition:
-			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
-			err = errors.New("alter table partition is unsupported")
+			err = d.AlterTablePartitioning(sctx, ident, spec)
 		case ast.AlterTableOption:
 			for i, opt := range spec.Options {
 				switch opt.Tp {
@@ -2602,9 +2649,9 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableIndexInvisible:
 			err = d.AlterIndexVisibility(sctx, ident, spec.IndexName, spec.Visibility)
 		case ast.AlterTableAlterCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
+			err = d.AlterCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name), spec.Constraint.Enforced)
 		case ast.AlterTableDropCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			err = d.DropCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name))
 		case ast.AlterTableWithValidation:
 			sctx.GetSessionVars().StmtCtx.AppendWarning(errUnsupportedAlterTableWithValidation)
 		case ast.AlterTableWithoutValidation:
@@ -2749,6 +2796,8 @@ func checkUnsupportedColumnConstraint(col *ast.ColumnDef, ti ast.Ident) error {
 			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint PRIMARY KEY when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
 		case ast.ColumnOptionUniqKey:
 			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint UNIQUE KEY when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
+		case ast.ColumnOptionCheck:
+			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint CHECK when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
 		case ast.ColumnOptionAutoRandom:
 			errMsg := fmt.Sprintf(autoid.AutoRandomAlterAddColumn, col.Name, ti.Schema, ti.Name)
 			return ErrInvalidAutoRandom.GenWithStackByArgs(errMsg)
@@ -2989,6 +3038,9 @@ func (d *ddl) AddTablePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	if pi == nil {
 		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
 	}
+	if pi.Type == model.PartitionTypeHash {
+		return d.reorganizeHashPartitions(ctx, schema, meta, spec)
+	}
 
 	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
 	if err != nil {
@@ -3051,21 +3103,113 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	}
 
 	switch meta.Partition.Type {
-	// We don't support coalesce partitions hash type partition now.
 	case model.PartitionTypeHash:
-		return errors.Trace(ErrUnsupportedCoalescePartition)
+		return d.reorganizeHashPartitions(ctx, schema, meta, spec)
 
-	// Key type partition cannot be constructed currently, ignoring it for now.
+	// Key type partition cannot be constructed currently.
 	case model.PartitionTypeKey:
+		return errors.Trace(ErrUnsupportedCoalescePartition)
 
 	// Coalesce partition can only be used on hash/key partitions.
 	default:
 		return errors.Trace(ErrCoalesceOnlyOnHashPartition)
 	}
+}
+
+// reorganizeHashPartitions changes the number of partitions of a hash partitioned table by ADD PARTITION or
+// COALESCE PARTITION. The partitions are reorganized as a whole since the rows are redistributed by the new modulus.
+func (d *ddl) reorganizeHashPartitions(ctx sessionctx.Context, schema *model.DBInfo, meta *model.TableInfo, spec *ast.AlterTableSpec) error {
+	if err := checkPartitionReorgSupported(meta); err != nil {
+		return errors.Trace(err)
+	}
+	pi := meta.Partition
+	partInfo, err := buildHashReorgPartitionInfo(pi, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	clonedMeta := meta.Clone()
+	clonedMeta.Partition = partInfo
+	if err = checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		if ErrSameNamePartition.Equal(err) && spec.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	partNames := make([]string, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		partNames = append(partNames, def.Name.L)
+	}
 
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       ddlutil.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
 	return errors.Trace(err)
 }
 
+// buildHashReorgPartitionInfo builds the partitions of a hash partitioned table after ADD PARTITION or COALESCE
+// PARTITION. The existing partitions keep their names, the added ones are named after their positions if they are
+// added by number.
+func buildHashReorgPartitionInfo(pi *model.PartitionInfo, spec *ast.AlterTableSpec) (*model.PartitionInfo, error) {
+	defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)+len(spec.PartDefinitions)+int(spec.Num))
+	for _, def := range pi.Definitions {
+		def.ID = 0
+		defs = append(defs, def)
+	}
+	switch spec.Tp {
+	case ast.AlterTableCoalescePartitions:
+		if spec.Num == 0 {
+			return nil, errors.Trace(ErrCoalescePartitionNoPartition)
+		}
+		if spec.Num >= uint64(len(defs)) {
+			return nil, errors.Trace(ErrDropLastPartition)
+		}
+		defs = defs[:len(defs)-int(spec.Num)]
+	case ast.AlterTableAddPartitions:
+		if len(spec.PartDefinitions) > 0 {
+			for _, def := range spec.PartDefinitions {
+				if err := def.Clause.Validate(model.PartitionTypeHash, 0); err != nil {
+					return nil, errors.Trace(err)
+				}
+				comment, _ := def.Comment()
+				defs = append(defs, model.PartitionDefinition{Name: def.Name, Comment: comment})
+			}
+			if err := checkAddPartitionTooManyPartitions(uint64(len(defs))); err != nil {
+				return nil, errors.Trace(err)
+			}
+			break
+		}
+		if spec.Num == 0 {
+			return nil, errors.Trace(ErrAddPartitionNoNewPartition)
+		}
+		if err := checkAddPartitionTooManyPartitions(uint64(len(defs)) + spec.Num); err != nil {
+			return nil, errors.Trace(err)
+		}
+		for i := len(defs); i < len(pi.Definitions)+int(spec.Num); i++ {
+			defs = append(defs, model.PartitionDefinition{Name: model.NewCIStr(fmt.Sprintf("p%v", i))})
+		}
+	}
+	return &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Enable:      pi.Enable,
+		Definitions: defs,
+		Num:         uint64(len(defs)),
+	}, nil
+}
+
 func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -3114,6 +3258,212 @@ func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 	return errors.Trace(err)
 }
 
+// ReorganizePartitions reorganizes the partitions of a range or list partitioned table into the new partitions
+// by ALTER TABLE ... REORGANIZE PARTITION ... INTO (...).
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	switch pi.Type {
+	case model.PartitionTypeRange, model.PartitionTypeList:
+	default:
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+	// REORGANIZE PARTITION without INTO rebuilds the partitions of a hash or key partitioned table in MySQL.
+	if spec.OnAllPartitions || len(spec.PartDefinitions) == 0 {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if err = checkPartitionReorgSupported(meta); err != nil {
+		return errors.Trace(err)
+	}
+	partNames := make([]string, 0, len(spec.PartitionNames))
+	for _, name := range spec.PartitionNames {
+		partNames = append(partNames, name.L)
+	}
+	droppingDefs, err := getReorganizedPartitionDefinitions(pi, partNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	// Check the partitions of the table after the reorganization.
+	clonedMeta := meta.Clone()
+	tmp := *partInfo
+	tmp.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, droppingDefs, partInfo.Definitions)
+	clonedMeta.Partition = &tmp
+	if err = checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if pi.Type == model.PartitionTypeRange {
+		if err = checkReorganizedRangePartitions(ctx, meta, droppingDefs, partInfo.Definitions); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       ddlutil.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTablePartitioning partitions a non-partitioned table, or redefines all the partitions of a partitioned table
+// with the same partitioning type and expression, by ALTER TABLE ... PARTITION BY.
+func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	if meta.Partition != nil && meta.GetPartitionInfo() == nil {
+		// The partitions of the table aren't enabled, they can't be changed.
+		return errors.Trace(errUnsupportedPartitionReorg.GenWithStackByArgs("disabled partitions"))
+	}
+	if err = checkPartitionReorgSupported(meta); err != nil {
+		return errors.Trace(err)
+	}
+	newMeta := meta.Clone()
+	newMeta.Partition = nil
+	if err = buildTablePartitionInfo(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if newMeta.Partition == nil {
+		// The partitioning isn't supported, a warning is appended to the statement.
+		return nil
+	}
+	partInfo := newMeta.Partition
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionFuncType(ctx, spec.Partition.Expr, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitioningKeysConstraints(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+
+	actionType := ddlutil.ActionAlterTablePartitioning
+	var partNames []string
+	if pi := meta.GetPartitionInfo(); pi != nil {
+		if pi.Type != partInfo.Type || pi.Expr != partInfo.Expr || !isSamePartitionColumns(pi.Columns, partInfo.Columns) {
+			return errors.Trace(errUnsupportedRepartition)
+		}
+		actionType = ddlutil.ActionReorganizePartition
+		for _, def := range pi.Definitions {
+			partNames = append(partNames, def.Name.L)
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       actionType,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func isSamePartitionColumns(a, b []model.CIStr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].L != b[i].L {
+			return false
+		}
+	}
+	return true
+}
+
+// RemovePartitioning turns a partitioned table into a non-partitioned table by ALTER TABLE ... REMOVE PARTITIONING.
+func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if err = checkPartitionReorgSupported(meta); err != nil {
+		return errors.Trace(err)
+	}
+	// The rows are copied to a new physical table, which replaces the table at last like TRUNCATE TABLE.
+	genIDs, err := d.genGlobalIDs(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	partInfo := &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Definitions: []model.PartitionDefinition{{ID: genIDs[0]}},
+	}
+	partNames := make([]string, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		partNames = append(partNames, def.Name.L)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       ddlutil.ActionRemovePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -3859,6 +4209,9 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if constrs := findCheckConstraintsByColumn(t.Meta(), originalColName); len(constrs) > 0 {
+			return nil, ErrDependentByCheckConstraint.GenWithStackByArgs(constrs[0].Name.O, originalColName.O)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -4225,6 +4578,9 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if fkInfo := getColumnForeignKeyInfo(oldColName.L, tbl.Meta().ForeignKeys); fkInfo != nil {
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
+	if constrs := findCheckConstraintsByColumn(tbl.Meta(), oldColName); len(constrs) > 0 {
+		return ErrDependentByCheckConstraint.GenWithStackByArgs(constrs[0].Name.O, oldColName.O)
+	}
 
 	// Check generated expression.
 	for _, col := range allCols {
@@ -4509,10 +4865,6 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
@@ -4542,10 +4894,9 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
-	// TODO: check whether covering index exists for cardinality / dependency types.
 
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
@@ -4824,6 +5175,9 @@ func (d *ddl) RenameTable(ctx sessionctx.Context, oldIdent, newIdent ast.Ident,
 	if schemas == nil {
 		return nil
 	}
+	if err = checkFKRenameTable(ctx, is, schemas[0], schemas[1], tableID); err != nil {
+		return err
+	}
 
 	job := &model.Job{
 		SchemaID:   schemas[1].ID,
@@ -4856,6 +5210,9 @@ func (d *ddl) RenameTables(ctx sessionctx.Context, oldIdents, newIdents []ast.Id
 		if err != nil {
 			return err
 		}
+		if err = checkFKRenameTable(ctx, is, schemas[0], schemas[1], tableID); err != nil {
+			return err
+		}
 		tableIDs = append(tableIDs, tableID)
 		tableNames = append(tableNames, &newIdents[i].Name)
 		oldSchemaIDs = append(oldSchemaIDs, schemas[0].ID)
@@ -5095,14 +5452,28 @@ func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*as
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		expr, err := expression.RewriteSimpleExprWithTableInfo(ctx, tblInfo, idxPart.Expr)
+		isMultiValued := false
+		exprNode := idxPart.Expr
+		if cast, ok := idxPart.Expr.(*ast.FuncCastExpr); ok && types.HasArrayFlag(cast.Tp.Flag) {
+			// The multi-valued key part CAST(expr AS type ARRAY) is stored as CAST(expr AS type), the hidden
+			// column stores the JSON array evaluated from expr.
+			if err := checkMultiValuedElemType(cast.Tp); err != nil {
+				return nil, err
+			}
+			isMultiValued = true
+			exprNode = cast.Expr
+		}
+		expr, err := expression.RewriteSimpleExprWithTableInfo(ctx, tblInfo, exprNode)
 		if err != nil {
 			// TODO: refine the error message.
 			return nil, err
 		}
-		if _, ok := expr.(*expression.Column); ok {
+		if _, ok := expr.(*expression.Column); ok && !isMultiValued {
 			return nil, ErrFunctionalIndexOnField
 		}
+		if isMultiValued && expr.GetType().Tp != mysql.TypeJSON {
+			return nil, errNotSupportedYet.GenWithStackByArgs("CAST-ing non-JSON data to array")
+		}
 
 		colInfo := &model.ColumnInfo{
 			Name:                idxPart.Column.Name,
@@ -5113,6 +5484,9 @@ func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*as
 			Hidden:              true,
 			FieldType:           *expr.GetType(),
 		}
+		if isMultiValued {
+			colInfo.Flag |= types.ArrayFlag
+		}
 		if colInfo.Tp == mysql.TypeDatetime || colInfo.Tp == mysql.TypeDate || colInfo.Tp == mysql.TypeTimestamp || colInfo.Tp == mysql.TypeDuration {
 			if colInfo.FieldType.Decimal == types.UnspecifiedLength {
 				colInfo.FieldType.Decimal = int(types.MaxFsp)
@@ -5137,6 +5511,40 @@ func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*as
 	return hiddenCols, nil
 }
 
+// checkMultiValuedElemType checks the type of the array elements of a multi-valued key part.
+func checkMultiValuedElemType(tp *types.FieldType) error {
+	switch tp.Tp {
+	case mysql.TypeLonglong, mysql.TypeNewDecimal, mysql.TypeDate, mysql.TypeDatetime, mysql.TypeDuration:
+		return nil
+	case mysql.TypeVarString:
+		if tp.Flen == types.UnspecifiedLength {
+			return errNotSupportedYet.GenWithStackByArgs("CAST-ing data to array of char/binary BLOBs")
+		}
+		return nil
+	}
+	return errNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("CAST-ing data to array of %s", types.TypeStr(tp.Tp)))
+}
+
+// checkMultiValuedIndex checks the restrictions of the multi-valued index.
+func checkMultiValuedIndex(columns []*model.ColumnInfo, idxCols []*model.IndexColumn, unique bool) error {
+	cnt := 0
+	for _, idxCol := range idxCols {
+		if table.IsMultiValuedColumn(columns[idxCol.Offset]) {
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		return nil
+	}
+	if cnt > 1 {
+		return errNotSupportedYet.GenWithStackByArgs("more than one multi-valued key part per index")
+	}
+	if unique {
+		return errNotSupportedYet.GenWithStackByArgs("UNIQUE multi-valued index")
+	}
+	return nil
+}
+
 func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
 	// not support Spatial and FullText index
@@ -5202,6 +5610,9 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkMultiValuedIndex(finalColumns, indexColumns, unique); err != nil {
+		return err
+	}
 
 	if !unique && tblInfo.IsCommonHandle {
 		// Ensure new created non-unique secondary-index's len + primary-key's len <= MaxIndexLength in clustered index table.
@@ -5362,6 +5773,12 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkFKReferSchemas(ctx, schema.Name, []*ast.Constraint{{Tp: ast.ConstraintForeignKey, Refer: refer}}); err != nil {
+		return err
+	}
+	if err = checkFKReferredTables(ctx, is, schema.Name, t.Meta(), []*model.FKInfo{fkInfo}); err != nil {
+		return err
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5404,6 +5821,86 @@ func (d *ddl) DropForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName model.
 	return errors.Trace(err)
 }
 
+func (d *ddl) CreateCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constr *ast.Constraint) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+	if constr.Name == "" {
+		namesMap := make(map[string]bool, len(tblInfo.Constraints))
+		for _, c := range tblInfo.Constraints {
+			namesMap[c.Name.L] = true
+		}
+		setEmptyCheckConstraintName(tblInfo.Name.O, namesMap, []*ast.Constraint{constr})
+	} else if tblInfo.FindConstraintInfoByName(constr.Name) != nil {
+		return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+	}
+	constraintInfo, err := buildCheckConstraintInfo(ctx, tblInfo, constr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constraintInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func (d *ddl) DropCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func (d *ddl) AlterCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr, enforced bool) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName, enforced},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ti.Schema)
@@ -5493,7 +5990,8 @@ func isDroppableColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
 	if fkInfo := getColumnForeignKeyInfo(colName.L, tblInfo.ForeignKeys); fkInfo != nil {
 		return errFkColumnCannotDrop.GenWithStackByArgs(colName, fkInfo.Name)
 	}
-	return nil
+	// Check the column with check constraints.
+	return checkDropColumnWithCheckConstraint(tblInfo, colName)
 }
 
 // validateCommentLength checks comment length of table, column, index and partition.
@@ -6077,6 +6575,10 @@ func (d *ddl) AlterTableAttributes(ctx sessionctx.Context, ident ast.Ident, spec
 		}
 		return ErrInvalidAttributesSpec.GenWithStackByArgs(err)
 	}
+	ttlInfo, err := buildTTLInfo(meta, rule)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	rule.Reset(meta.ID, schema.Name.L, meta.Name.L)
 
@@ -6086,7 +6588,7 @@ func (d *ddl) AlterTableAttributes(ctx sessionctx.Context, ident ast.Ident, spec
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAlterTableAttributes,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{rule},
+		Args:       []interface{}{rule, ttlInfo},
 	}
 
 	err = d.doDDLJob(ctx, job)
@@ -6125,6 +6627,9 @@ func (d *ddl) AlterTablePartitionAttributes(ctx sessionctx.Context, ident ast.Id
 		}
 		return ErrInvalidAttributesSpec.GenWithStackByArgs(sb.String(), err)
 	}
+	if err = checkPartitionAttributesWithoutTTL(rule); err != nil {
+		return errors.Trace(err)
+	}
 
 	rule.Reset(partitionID, schema.Name.L, meta.Name.L, spec.PartitionNames[0].L)
 
@@ -404,7 +404,8 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
 			err = w.deleteRange(w.ddlJobCtx, job)
 		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn:
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn,
+			util.ActionReorganizePartition, util.ActionAlterTablePartitioning, util.ActionRemovePartitioning:
 			err = w.deleteRange(w.ddlJobCtx, job)
 		}
 	}
@@ -768,6 +769,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onTruncateTablePartition(d, t, job)
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
+	case util.ActionReorganizePartition, util.ActionAlterTablePartitioning, util.ActionRemovePartitioning:
+		ver, err = w.onReorganizePartition(d, t, job)
 	case model.ActionAddColumn:
 		ver, err = onAddColumn(d, t, job)
 	case model.ActionAddColumns:
@@ -834,6 +837,12 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onAlterTableAttributes(t, job)
 	case model.ActionAlterTablePartitionAttributes:
 		ver, err = onAlterTablePartitionAttributes(t, job)
+	case model.ActionAddCheckConstraint:
+		ver, err = w.onAddCheckConstraint(t, job)
+	case model.ActionDropCheckConstraint:
+		ver, err = onDropCheckConstraint(t, job)
+	case model.ActionAlterCheckConstraint:
+		ver, err = w.onAlterCheckConstraint(t, job)
 	default:
 		// Invalid job, cancel it.
 		job.State = model.JobStateCancelled
@@ -1038,6 +1047,12 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 			OldTableID: ptTableID,
 		}
 		diff.AffectedOpts = affects
+	case util.ActionRemovePartitioning:
+		diff.TableID = job.TableID
+		if len(job.CtxVars) > 0 {
+			// The table gets the new table ID when the reads are switched to it.
+			diff.OldTableID = job.CtxVars[0].(int64)
+		}
 	case model.ActionTruncateTablePartition:
 		diff.TableID = job.TableID
 		if len(job.CtxVars) > 0 {
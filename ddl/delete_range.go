@@ -304,6 +304,33 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 				return errors.Trace(err)
 			}
 		}
+	case util.ActionReorganizePartition, util.ActionAlterTablePartitioning, util.ActionRemovePartitioning:
+		var physicalTableIDs, indexIDs []int64
+		if err := job.DecodeArgs(&physicalTableIDs, &indexIDs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, physicalTableID := range physicalTableIDs {
+			if physicalTableID != job.TableID {
+				startKey := tablecodec.EncodeTablePrefix(physicalTableID)
+				endKey := tablecodec.EncodeTablePrefix(physicalTableID + 1)
+				if err := doInsert(ctx, s, job.ID, physicalTableID, startKey, endKey, now); err != nil {
+					return errors.Trace(err)
+				}
+				continue
+			}
+			// The non-partitioned table shares the table ID with the partitioned table, only its rows and indexes are deleted.
+			startKey := tablecodec.GenTableRecordPrefix(physicalTableID)
+			endKey := startKey.PrefixNext()
+			if err := doInsert(ctx, s, job.ID, physicalTableID, startKey, endKey, now); err != nil {
+				return errors.Trace(err)
+			}
+			if len(indexIDs) == 0 {
+				continue
+			}
+			if err := doBatchDeleteIndiceRange(ctx, s, job.ID, physicalTableID, indexIDs, now); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	// ActionAddIndex, ActionAddPrimaryKey needs do it, because it needs to be rolled back when it's canceled.
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		tableID := job.TableID
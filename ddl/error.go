@@ -50,6 +50,7 @@ var (
 	errUnsupportedAlterTableWithoutValidation = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("ALTER TABLE WITHOUT VALIDATION is currently unsupported", nil))
 	errUnsupportedAlterTableOption            = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("This type of ALTER TABLE is currently unsupported", nil))
 	errUnsupportedAlterReplicaForSysTable     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("ALTER table replica for tables in system database is currently unsupported", nil))
+	errUnsupportedCrossSchemaFK               = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "foreign key referring to a table in another database"), nil))
 	errBlobKeyWithoutLength                   = dbterror.ClassDDL.NewStd(mysql.ErrBlobKeyWithoutLength)
 	errKeyPart0                               = dbterror.ClassDDL.NewStd(mysql.ErrKeyPart0)
 	errIncorrectPrefixKey                     = dbterror.ClassDDL.NewStd(mysql.ErrWrongSubKey)
@@ -63,6 +64,8 @@ var (
 	errReorgPanic                             = dbterror.ClassDDL.NewStd(mysql.ErrReorgPanic)
 	errFkColumnCannotDrop                     = dbterror.ClassDDL.NewStd(mysql.ErrFkColumnCannotDrop)
 	errFKIncompatibleColumns                  = dbterror.ClassDDL.NewStd(mysql.ErrFKIncompatibleColumns)
+	errFkCannotOpenParent                     = dbterror.ClassDDL.NewStd(mysql.ErrFkCannotOpenParent)
+	errFkNoIndexParent                        = dbterror.ClassDDL.NewStd(mysql.ErrFkNoIndexParent)
 
 	errOnlyOnRangeListPartition = dbterror.ClassDDL.NewStd(mysql.ErrOnlyOnRangeListPartition)
 	// errWrongKeyColumn is for table column cannot be indexed.
@@ -96,12 +99,21 @@ var (
 	errUnsupportedCheckPartition      = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "check partition"), nil))
 	errUnsupportedOptimizePartition   = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "optimize partition"), nil))
 	errUnsupportedRebuildPartition    = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "rebuild partition"), nil))
-	errUnsupportedRemovePartition     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "remove partitioning"), nil))
 	errUnsupportedRepairPartition     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "repair partition"), nil))
 	// ErrGeneratedColumnFunctionIsNotAllowed returns for unsupported functions for generated columns.
 	ErrGeneratedColumnFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrGeneratedColumnFunctionIsNotAllowed)
 	// ErrGeneratedColumnRowValueIsNotAllowed returns for generated columns referring to row values.
 	ErrGeneratedColumnRowValueIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrGeneratedColumnRowValueIsNotAllowed)
+	// errUnsupportedPartitionReorg returns for does not support reorganizing the partitions of some kinds of tables.
+	errUnsupportedPartitionReorg = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "changing the partitions of table with %s"), nil))
+	// errUnsupportedRepartition returns for does not support changing the partitioning type or expression of a partitioned table.
+	errUnsupportedRepartition = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "changing the partitioning type or expression of a partitioned table, remove partitioning first"), nil))
+	// ErrReorgPartitionNotExist returns more partitions to reorganize than there are partitions.
+	ErrReorgPartitionNotExist = dbterror.ClassDDL.NewStd(mysql.ErrReorgPartitionNotExist)
+	// ErrConsecutiveReorgPartitions returns the reorganized range partitions are not consecutive.
+	ErrConsecutiveReorgPartitions = dbterror.ClassDDL.NewStd(mysql.ErrConsecutiveReorgPartitions)
+	// ErrReorgOutsideRange returns the reorganized range partitions change the total range.
+	ErrReorgOutsideRange = dbterror.ClassDDL.NewStd(mysql.ErrReorgOutsideRange)
 	// ErrUnsupportedPartitionByRangeColumns returns for does unsupported partition by range columns.
 	ErrUnsupportedPartitionByRangeColumns = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "partition by range columns"), nil))
 	// ErrFunctionalIndexFunctionIsNotAllowed returns for unsupported functions for functional index.
@@ -194,6 +206,10 @@ var (
 	ErrWarnDataTruncated = dbterror.ClassDDL.NewStd(mysql.WarnDataTruncated)
 	// ErrCoalesceOnlyOnHashPartition returns coalesce partition can only be used on hash/key partitions.
 	ErrCoalesceOnlyOnHashPartition = dbterror.ClassDDL.NewStd(mysql.ErrCoalesceOnlyOnHashPartition)
+	// ErrCoalescePartitionNoPartition returns at least one partition must be coalesced.
+	ErrCoalescePartitionNoPartition = dbterror.ClassDDL.NewStd(mysql.ErrCoalescePartitionNoPartition)
+	// ErrAddPartitionNoNewPartition returns at least one partition must be added.
+	ErrAddPartitionNoNewPartition = dbterror.ClassDDL.NewStd(mysql.ErrAddPartitionNoNewPartition)
 	// ErrViewWrongList returns create view must include all columns in the select clause
 	ErrViewWrongList = dbterror.ClassDDL.NewStd(mysql.ErrViewWrongList)
 	// ErrAlterOperationNotSupported returns when alter operations is not supported.
@@ -291,8 +307,43 @@ var (
 	ErrUnsupportedLocalTempTableDDL = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("TiDB doesn't support %s for local temporary table", nil))
 	// ErrInvalidAttributesSpec is returned when meeting invalid attributes.
 	ErrInvalidAttributesSpec = dbterror.ClassDDL.NewStd(mysql.ErrInvalidAttributesSpec)
+	// ErrUnsupportedColumnInTTLConfig returns when the TTL column is not a DATETIME, DATE or TIMESTAMP column.
+	ErrUnsupportedColumnInTTLConfig = dbterror.ClassDDL.NewStd(mysql.ErrUnsupportedColumnInTTLConfig)
+	// ErrTTLColumnCannotDrop returns when dropping the TTL column.
+	ErrTTLColumnCannotDrop = dbterror.ClassDDL.NewStd(mysql.ErrTTLColumnCannotDrop)
+	// ErrSetTTLOptionForNonTTLTable returns when setting the TTL options without the TTL attribute.
+	ErrSetTTLOptionForNonTTLTable = dbterror.ClassDDL.NewStd(mysql.ErrSetTTLOptionForNonTTLTable)
+	// ErrTempTableNotAllowedWithTTL returns when setting TTL for a temporary table.
+	ErrTempTableNotAllowedWithTTL = dbterror.ClassDDL.NewStd(mysql.ErrTempTableNotAllowedWithTTL)
 	// errFunctionalIndexOnJSONOrGeometryFunction returns when creating expression index and the type of the expression is JSON.
 	errFunctionalIndexOnJSONOrGeometryFunction = dbterror.ClassDDL.NewStd(mysql.ErrFunctionalIndexOnJSONOrGeometryFunction)
+	// errNotSupportedYet returns when the feature of the multi-valued index isn't supported yet.
+	errNotSupportedYet = dbterror.ClassDDL.NewStd(mysql.ErrNotSupportedYet)
 	// errDependentByFunctionalIndex returns when the dropped column depends by expression index.
 	errDependentByFunctionalIndex = dbterror.ClassDDL.NewStd(mysql.ErrDependentByFunctionalIndex)
+
+	// ErrNonBooleanExprForCheckConstraint returns when the check constraint expression isn't a boolean expression.
+	ErrNonBooleanExprForCheckConstraint = dbterror.ClassDDL.NewStd(mysql.ErrNonBooleanExprForCheckConstraint)
+	// ErrColumnCheckConstraintReferencesOtherColumn returns when a column check constraint refers to other columns.
+	ErrColumnCheckConstraintReferencesOtherColumn = dbterror.ClassDDL.NewStd(mysql.ErrColumnCheckConstraintReferencesOtherColumn)
+	// ErrCheckConstraintNamedFunctionIsNotAllowed returns when the check constraint expression contains a disallowed function.
+	ErrCheckConstraintNamedFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintNamedFunctionIsNotAllowed)
+	// ErrCheckConstraintFunctionIsNotAllowed returns when the check constraint expression contains a disallowed function.
+	ErrCheckConstraintFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintFunctionIsNotAllowed)
+	// ErrCheckConstraintVariables returns when the check constraint expression refers to a variable.
+	ErrCheckConstraintVariables = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintVariables)
+	// ErrCheckConstraintRowValue returns when the check constraint expression refers to a row value.
+	ErrCheckConstraintRowValue = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRowValue)
+	// ErrCheckConstraintRefersAutoIncrementColumn returns when the check constraint refers to an auto-increment column.
+	ErrCheckConstraintRefersAutoIncrementColumn = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRefersAutoIncrementColumn)
+	// ErrCheckConstraintRefersUnknownColumn returns when the check constraint refers to a non-existing column.
+	ErrCheckConstraintRefersUnknownColumn = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRefersUnknownColumn)
+	// ErrCheckConstraintNotFound returns when the check constraint to alter or drop doesn't exist.
+	ErrCheckConstraintNotFound = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintNotFound)
+	// ErrCheckConstraintDupName returns when the check constraint name is duplicated.
+	ErrCheckConstraintDupName = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintDupName)
+	// ErrCheckConstraintUsingFKReferActionColumn returns when the check constraint refers to a column used by a foreign key referential action.
+	ErrCheckConstraintUsingFKReferActionColumn = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintClauseUsingFKReferActionColumn)
+	// ErrDependentByCheckConstraint returns when the dropped or renamed column is used by a check constraint.
+	ErrDependentByCheckConstraint = dbterror.ClassDDL.NewStd(mysql.ErrDependentByCheckConstraint)
 )
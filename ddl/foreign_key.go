@@ -16,9 +16,11 @@ package ddl
 
 import (
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
 )
 
 func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
@@ -40,7 +42,8 @@ func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := fkInfo.State
 	switch fkInfo.State {
 	case model.StateNone:
-		// We just support record the foreign key, so we just make it public.
+		// The existing rows are not checked, and the foreign key is enforced by the
+		// DML statements once it's public, so we just make it public.
 		// none -> public
 		fkInfo.State = model.StatePublic
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != fkInfo.State)
@@ -96,7 +99,7 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := fkInfo.State
 	switch fkInfo.State {
 	case model.StatePublic:
-		// We just support record the foreign key, so we just make it none.
+		// The DML statements stop enforcing the foreign key once it's not public, so we just make it none.
 		// public -> none
 		fkInfo.State = model.StateNone
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != fkInfo.State)
@@ -111,3 +114,98 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	}
 
 }
+
+// checkFKReferredTables checks the tables referred by the foreign keys exist, and the referred columns are
+// the leading columns of an index. It's only checked when foreign_key_checks is ON, so that a dump file can
+// create the child tables before the parent tables.
+func checkFKReferredTables(ctx sessionctx.Context, is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, fks []*model.FKInfo) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	for _, fk := range fks {
+		parent := tblInfo
+		if fk.RefTable.L != tblInfo.Name.L {
+			t, err := is.TableByName(schema, fk.RefTable)
+			if err != nil {
+				return errFkCannotOpenParent.GenWithStackByArgs(fk.RefTable.O)
+			}
+			parent = t.Meta()
+		}
+		if !hasIndexWithLeadingColumns(parent, fk.RefCols) {
+			return errFkNoIndexParent.GenWithStackByArgs(fk.Name.O, fk.RefTable.O)
+		}
+	}
+	return nil
+}
+
+// checkFKReferSchemas checks the tables referred by the foreign keys are in the schema of the child table. The
+// foreign key only records the name of the referred table, which is looked up in the schema of the child table,
+// so a table in another schema can't be referred to.
+func checkFKReferSchemas(ctx sessionctx.Context, schema model.CIStr, constraints []*ast.Constraint) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	for _, constr := range constraints {
+		if constr.Tp != ast.ConstraintForeignKey || constr.Refer == nil {
+			continue
+		}
+		if referSchema := constr.Refer.Table.Schema; referSchema.L != "" && referSchema.L != schema.L {
+			return errUnsupportedCrossSchemaFK
+		}
+	}
+	return nil
+}
+
+// checkFKRenameTable checks the table moved to another schema has no foreign key and isn't referred by any foreign
+// key, since the foreign keys can't refer to a table in another schema.
+func checkFKRenameTable(ctx sessionctx.Context, is infoschema.InfoSchema, oldSchema, newSchema *model.DBInfo, tableID int64) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks || oldSchema.ID == newSchema.ID {
+		return nil
+	}
+	t, ok := is.TableByID(tableID)
+	if !ok {
+		return nil
+	}
+	tblInfo := t.Meta()
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.State == model.StatePublic {
+			return errUnsupportedCrossSchemaFK
+		}
+	}
+	for _, child := range is.SchemaTables(oldSchema.Name) {
+		if child.Meta().ID == tblInfo.ID {
+			continue
+		}
+		for _, fk := range child.Meta().ForeignKeys {
+			if fk.State == model.StatePublic && fk.RefTable.L == tblInfo.Name.L {
+				return errUnsupportedCrossSchemaFK
+			}
+		}
+	}
+	return nil
+}
+
+// hasIndexWithLeadingColumns checks whether the cols are the int handle or the leading columns of a public index.
+func hasIndexWithLeadingColumns(tblInfo *model.TableInfo, cols []model.CIStr) bool {
+	if tblInfo.PKIsHandle && len(cols) == 1 {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && pkCol.Name.L == cols[0].L {
+			return true
+		}
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic || len(idx.Columns) < len(cols) {
+			continue
+		}
+		match := true
+		for i, col := range cols {
+			if idx.Columns[i].Name.L != col.L {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
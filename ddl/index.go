@@ -66,6 +66,16 @@ func buildIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*a
 			return nil, errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
 		}
 
+		if table.IsMultiValuedColumn(col) {
+			// The key part stores the elements of the JSON array.
+			elemTp, err := table.MultiValuedElemType(col)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			col = col.Clone()
+			col.FieldType = *elemTp
+		}
+
 		if err := checkIndexColumn(col, ip.Length); err != nil {
 			return nil, err
 		}
@@ -901,6 +911,11 @@ func (w *baseIndexWorker) getIndexRecord(idxInfo *model.IndexInfo, handle kv.Han
 		col := cols[v.Offset]
 		idxColumnVal, ok := w.rowMap[col.ID]
 		if ok {
+			if idxColumnVal.Kind() == types.KindMysqlJSON {
+				// The JSON array of the multi-valued key part may refer to the memory of the row decoder,
+				// which is reused by the next row.
+				idxColumnVal = *idxColumnVal.Clone()
+			}
 			idxVal[j] = idxColumnVal
 			continue
 		}
@@ -1402,13 +1417,14 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 	}
 
 	var pid int64
-	for i, pi := range partitionIDs {
-		if pi == reorg.PhysicalTableID {
+	for i, id := range partitionIDs {
+		if id == reorg.PhysicalTableID {
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
@@ -38,10 +38,12 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -50,8 +52,10 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/slice"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -1129,6 +1133,612 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 	return ver, nil
 }
 
+// checkPartitionReorgSupported checks whether the partitions of the table can be reorganized by REORGANIZE PARTITION,
+// PARTITION BY or REMOVE PARTITIONING.
+func checkPartitionReorgSupported(tblInfo *model.TableInfo) error {
+	if tblInfo.TempTableType != model.TempTableNone {
+		return errors.Trace(ErrPartitionNoTemporary)
+	}
+	if hasGlobalIndex(tblInfo) {
+		return errors.Trace(errUnsupportedPartitionReorg.GenWithStackByArgs("global index"))
+	}
+	if tblInfo.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedPartitionReorg.GenWithStackByArgs("TiFlash replica"))
+	}
+	return nil
+}
+
+// getReorganizedPartitionDefinitions returns the definitions of the partitions to be reorganized in the order of the
+// table. The partitions of a range partitioned table must be consecutive.
+func getReorganizedPartitionDefinitions(pi *model.PartitionInfo, partLowerNames []string) ([]model.PartitionDefinition, error) {
+	if len(partLowerNames) > len(pi.Definitions) {
+		return nil, errors.Trace(ErrReorgPartitionNotExist)
+	}
+	names := make(map[string]struct{}, len(partLowerNames))
+	for _, name := range partLowerNames {
+		names[name] = struct{}{}
+	}
+	defs := make([]model.PartitionDefinition, 0, len(names))
+	first := -1
+	for i, def := range pi.Definitions {
+		if _, ok := names[def.Name.L]; !ok {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if pi.Type == model.PartitionTypeRange && i != first+len(defs) {
+			return nil, errors.Trace(ErrConsecutiveReorgPartitions)
+		}
+		defs = append(defs, def)
+	}
+	if len(defs) != len(names) {
+		return nil, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+	}
+	return defs, nil
+}
+
+// checkReorganizedRangePartitions checks that the new range partitions cover the same range as the reorganized ones,
+// only the range of the last partition of the table can be extended.
+func checkReorganizedRangePartitions(ctx sessionctx.Context, tblInfo *model.TableInfo, droppingDefs, addingDefs []model.PartitionDefinition) error {
+	pi := tblInfo.Partition
+	oldLast, newLast := &droppingDefs[len(droppingDefs)-1], &addingDefs[len(addingDefs)-1]
+	isLast := pi.Definitions[len(pi.Definitions)-1].ID == oldLast.ID
+	shrunk, err := isRangeBoundGreater(ctx, tblInfo, oldLast, newLast)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	extended, err := isRangeBoundGreater(ctx, tblInfo, newLast, oldLast)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if shrunk || (extended && !isLast) {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	return nil
+}
+
+// isRangeBoundGreater returns whether the upper bound of range partition a is greater than the one of b.
+func isRangeBoundGreater(ctx sessionctx.Context, tblInfo *model.TableInfo, a, b *model.PartitionDefinition) (bool, error) {
+	pi := tblInfo.Partition
+	if len(pi.Columns) > 0 {
+		return checkTwoRangeColumns(ctx, a, b, pi, tblInfo)
+	}
+	aMax, bMax := strings.EqualFold(a.LessThan[0], partitionMaxValue), strings.EqualFold(b.LessThan[0], partitionMaxValue)
+	if aMax || bMax {
+		return aMax && !bMax, nil
+	}
+	isUnsigned := isColUnsigned(tblInfo.Columns, pi)
+	aValue, _, err := getRangeValue(ctx, a.LessThan[0], isUnsigned)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	bValue, _, err := getRangeValue(ctx, b.LessThan[0], isUnsigned)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if isUnsigned {
+		return aValue.(uint64) > bValue.(uint64), nil
+	}
+	return aValue.(int64) > bValue.(int64), nil
+}
+
+// checkReorganizePartition checks the partition reorganization job and returns the definitions of the partitions to
+// be reorganized. For PARTITION BY of a non-partitioned table, the whole table is reorganized.
+func checkReorganizePartition(tblInfo *model.TableInfo, partLowerNames []string) ([]model.PartitionDefinition, error) {
+	if err := checkPartitionReorgSupported(tblInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		if tblInfo.Partition != nil || len(partLowerNames) > 0 {
+			return nil, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		return []model.PartitionDefinition{{ID: tblInfo.ID}}, nil
+	}
+	if len(pi.AddingDefinitions) > 0 || len(pi.DroppingDefinitions) > 0 {
+		return nil, errors.Trace(errUnsupportedPartitionReorg.GenWithStackByArgs("partitions being added or dropped"))
+	}
+	return getReorganizedPartitionDefinitions(pi, partLowerNames)
+}
+
+// setAddingPartitionsState sets the schema state of the new partitions of the partition reorganization.
+func setAddingPartitionsState(pi *model.PartitionInfo, state model.SchemaState) {
+	for _, def := range pi.AddingDefinitions {
+		pi.SetStateByID(def.ID, state)
+	}
+}
+
+// removePartitionStates removes the schema states of the partitions.
+func removePartitionStates(pi *model.PartitionInfo, defs []model.PartitionDefinition) {
+	states := pi.States[:0]
+	for _, state := range pi.States {
+		found := false
+		for _, def := range defs {
+			if def.ID == state.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			states = append(states, state)
+		}
+	}
+	pi.States = states
+	if len(pi.States) == 0 {
+		pi.States = nil
+	}
+}
+
+// onReorganizePartition reorganizes the partitions of a table, it handles REORGANIZE PARTITION, PARTITION BY and
+// REMOVE PARTITIONING. The new partitions are put into AddingDefinitions and the reorganized ones into
+// DroppingDefinitions, a definition without name stands for the non-partitioned table. The rows are copied to the
+// new partitions in the write reorganization state, then the reads are switched to the new partitions and the
+// reorganized ones are dropped at last. See tables.partitionReorg for how the rows are written during the job.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	if err := job.DecodeArgs(&partNames, &partInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(t, job, tblInfo)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		droppingDefs, err := checkReorganizePartition(tblInfo, partNames)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if tblInfo.Partition == nil {
+			// The partitions of a non-partitioned table are enabled when the reads are switched to them.
+			tblInfo.Partition = &model.PartitionInfo{Type: partInfo.Type, Expr: partInfo.Expr, Columns: partInfo.Columns}
+		}
+		pi := tblInfo.Partition
+		pi.AddingDefinitions = partInfo.Definitions
+		pi.DroppingDefinitions = droppingDefs
+		// none -> delete only
+		setAddingPartitionsState(pi, model.StateDeleteOnly)
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		setAddingPartitionsState(tblInfo.Partition, model.StateWriteOnly)
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		setAddingPartitionsState(tblInfo.Partition, model.StateWriteReorganization)
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		// reorganization -> delete reorganization, the reads are switched to the new partitions.
+		done, ver, err := w.runPartitionReorg(d, t, job, tblInfo)
+		if err != nil || !done {
+			return ver, errors.Trace(err)
+		}
+		removePartitionStates(tblInfo.Partition, tblInfo.Partition.AddingDefinitions)
+		ver, err = switchReorganizedPartitions(d, t, job, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteReorganization
+	case model.StateDeleteReorganization:
+		// delete reorganization -> none, the reorganized partitions are dropped.
+		pi := tblInfo.Partition
+		addingDefs, droppingDefs := pi.AddingDefinitions, pi.DroppingDefinitions
+		physicalTableIDs := getPartitionIDsFromDefinitions(droppingDefs)
+		var indexIDs []int64
+		if physicalTableIDs[0] == tblInfo.ID {
+			// The old non-partitioned table shares the table ID with the partitioned table,
+			// only its rows and indexes are deleted.
+			for _, idxInfo := range tblInfo.Indices {
+				indexIDs = append(indexIDs, idxInfo.ID)
+			}
+		} else if !tables.IsWholeTableDefinitions(droppingDefs) {
+			if err = dropRuleBundles(d, physicalTableIDs); err != nil {
+				return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+			}
+		}
+		pi.AddingDefinitions, pi.DroppingDefinitions = nil, nil
+		if tables.IsWholeTableDefinitions(addingDefs) {
+			tblInfo.Partition = nil
+		}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		asyncNotifyEvent(d, &util.Event{Tp: job.Type, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: addingDefs}})
+		// A background job will be created to delete the data of the reorganized partitions.
+		job.Args = []interface{}{physicalTableIDs, indexIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// runPartitionReorg copies the rows of the reorganized partitions to the new partitions,
+// it returns true when all the rows are copied.
+func (w *worker) runPartitionReorg(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+	pi := tblInfo.Partition
+	physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+	elements := []*meta.Element{{ID: pi.AddingDefinitions[0].ID, TypeKey: meta.PartitionElementKey}}
+	var reorgInfo *reorgInfo
+	if tblInfo.GetPartitionInfo() == nil {
+		reorgInfo, err = getReorgInfo(d, t, job, tbl, elements)
+	} else {
+		reorgInfo, err = getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+	}
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tblInfo, d.lease, func() (reorgErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+			func() {
+				reorgErr = errCancelledDDLJob.GenWithStack("reorganize partitions of table `%v` panic", tblInfo.Name)
+			}, false)
+		return w.reorgPartitionData(tbl, physicalTableIDs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || table.ErrNoPartitionForGivenValue.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			ver, err = convertReorgPartitionJob2RollbackJob(t, job, tblInfo, err)
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
+// switchReorganizedPartitions switches the reads of the table to the new partitions of the partition reorganization.
+// The reorganized partitions are kept in DroppingDefinitions, the rows are still written to them until the job is done.
+func switchReorganizedPartitions(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, err error) {
+	pi := tblInfo.Partition
+	addingDefs := pi.AddingDefinitions
+	switch {
+	case tables.IsWholeTableDefinitions(addingDefs):
+		return switchToNonPartitionedTable(d, t, job, tblInfo, addingDefs[0].ID)
+	case pi.Enable:
+		pi.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, pi.DroppingDefinitions, addingDefs)
+	default:
+		pi.Enable = true
+		pi.Definitions = append([]model.PartitionDefinition(nil), addingDefs...)
+	}
+	pi.Num = uint64(len(pi.Definitions))
+	return updateVersionAndTableInfo(t, job, tblInfo, true)
+}
+
+// switchToNonPartitionedTable switches a partitioned table to the non-partitioned table of REMOVE PARTITIONING,
+// the table gets the new table ID like TRUNCATE TABLE but keeps its auto IDs.
+func switchToNonPartitionedTable(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, newTableID int64) (ver int64, err error) {
+	schemaID, oldTableID := job.SchemaID, tblInfo.ID
+	autoIncID, err := t.GetAutoTableID(schemaID, oldTableID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	autoRandID, err := t.GetAutoRandomID(schemaID, oldTableID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	ttlInfo, err := t.GetTableTTL(schemaID, oldTableID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if oldBundle, ok := d.infoCache.GetLatest().BundleByName(placement.GroupID(oldTableID)); ok {
+		if err = infosync.PutRuleBundles(context.TODO(), []*placement.Bundle{oldBundle.Clone().Reset(newTableID)}); err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+	}
+
+	if err = t.DropTableOrView(schemaID, oldTableID, true); err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.Partition
+	pi.Enable = false
+	pi.Definitions = nil
+	pi.Num = 0
+	tblInfo.ID = newTableID
+	if err = t.CreateTableAndSetAutoID(schemaID, tblInfo, autoIncID, autoRandID); err != nil {
+		return ver, errors.Trace(err)
+	}
+	if ttlInfo != nil {
+		if err = t.SetTableTTL(schemaID, newTableID, ttlInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	job.TableID = newTableID
+	// used by ApplyDiff in updateSchemaVersion
+	job.CtxVars = []interface{}{oldTableID}
+	return updateSchemaVersion(t, job)
+}
+
+// convertReorgPartitionJob2RollbackJob converts the partition reorganization job to a rolling back job when the rows
+// can't be copied to the new partitions.
+func convertReorgPartitionJob2RollbackJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, occuredErr error) (ver int64, err error) {
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.State = model.JobStateRollingback
+	return ver, errors.Trace(occuredErr)
+}
+
+// rollbackReorganizePartition removes the new partitions of the partition reorganization,
+// it's only possible before the reads are switched to the new partitions.
+func rollbackReorganizePartition(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, _ error) {
+	var physicalTableIDs []int64
+	if pi := tblInfo.Partition; pi != nil && len(pi.AddingDefinitions) > 0 {
+		physicalTableIDs = getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+		removePartitionStates(pi, pi.AddingDefinitions)
+		if pi.DroppingDefinitions[0].ID == tblInfo.ID {
+			// It's PARTITION BY of a non-partitioned table.
+			tblInfo.Partition = nil
+		} else {
+			pi.AddingDefinitions, pi.DroppingDefinitions = nil, nil
+		}
+	}
+	ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// A background job will be created to delete the data of the new partitions.
+	job.Args = []interface{}{physicalTableIDs, []int64(nil)}
+	return ver, nil
+}
+
+// reorgPartitionData copies the rows of the reorganized partitions, or the ones of the non-partitioned table,
+// to the new partitions.
+func (w *worker) reorgPartitionData(t table.Table, physicalTableIDs []int64, reorgInfo *reorgInfo) error {
+	pt, ok := t.(table.PartitionedTable)
+	if !ok {
+		return w.reorgPhysicalTablePartitionData(t.(table.PhysicalTable), reorgInfo)
+	}
+	for {
+		p := pt.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		if err := w.reorgPhysicalTablePartitionData(p, reorgInfo); err != nil {
+			return errors.Trace(err)
+		}
+		finish, err := w.updateReorgInfoForPartitions(pt, reorgInfo, physicalTableIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if finish {
+			return nil
+		}
+	}
+}
+
+func (w *worker) reorgPhysicalTablePartitionData(t table.PhysicalTable, reorgInfo *reorgInfo) error {
+	logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+	return w.writePhysicalTableRecord(t, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+}
+
+type reorgPartitionRecord struct {
+	key    kv.Key
+	handle kv.Handle
+	vals   []byte
+	row    []types.Datum
+}
+
+// reorgPartitionWorker copies the rows of a reorganized partition, or the ones of the non-partitioned table,
+// to the new partitions.
+type reorgPartitionWorker struct {
+	*backfillWorker
+	// reorgedTbl is the table of the new partitions.
+	reorgedTbl    table.Table
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*reorgPartitionRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, reorgedTbl table.Table, decodeColMap map[int64]decoder.Column) *reorgPartitionWorker {
+	// The rows are decoded in UTC, so the partitioning expression is evaluated in UTC too.
+	sessCtx.GetSessionVars().TimeZone = time.UTC
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		reorgedTbl:     reorgedTbl,
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*reorgPartitionRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in reorgPartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle.
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	nextKey := taskRange.endKey.Next()
+	if !taskDone {
+		nextKey = lastAccessedHandle.Next()
+	}
+	return w.rowRecords, nextKey, taskDone, errors.Trace(err)
+}
+
+func (w *reorgPartitionWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, time.UTC, w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		} else if val.Kind() == types.KindMysqlJSON {
+			// The JSON value may refer to the memory of the row decoder, which is reused by the next row.
+			val = *val.Clone()
+		}
+		row[i] = val
+	}
+	w.rowRecords = append(w.rowRecords, &reorgPartitionRecord{
+		key:    recordKey,
+		handle: handle,
+		vals:   append([]byte(nil), rawRow...),
+		row:    row,
+	})
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+	return nil
+}
+
+// BackfillDataInTxn copies the rows to the new partitions in a transaction, it locks the row keys so that the rows
+// changed by others after the snapshot are not copied, they are written to the new partitions by the changes.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+			err = txn.LockKeys(context.Background(), new(kv.LockCtx), record.key)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err = w.copyRecord(txn, record); err != nil {
+				return errors.Trace(err)
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ReorgPartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
+// copyRecord writes the row and its index entries to the new partition it belongs to.
+func (w *reorgPartitionWorker) copyRecord(txn kv.Transaction, record *reorgPartitionRecord) error {
+	var tbl table.PhysicalTable
+	switch t := w.reorgedTbl.(type) {
+	case table.PartitionedTable:
+		p, err := t.GetPartitionByRow(w.sessCtx, record.row)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		tbl = p
+	case table.PhysicalTable:
+		tbl = t
+	}
+	if err := txn.Set(tablecodec.EncodeRecordKey(tbl.RecordPrefix(), record.handle), record.vals); err != nil {
+		return errors.Trace(err)
+	}
+	for _, idx := range tbl.Indices() {
+		if !tables.IsIndexWritable(idx) || idx.Meta().Global {
+			continue
+		}
+		vals, err := idx.FetchValues(record.row, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rsData := tables.TryGetHandleRestoredDataWrapper(tbl, record.row, nil, idx.Meta())
+		handle, err := idx.Create(w.sessCtx, txn, vals, record.handle, rsData)
+		if err != nil {
+			if kv.ErrKeyExists.Equal(err) && record.handle.Equal(handle) {
+				// The index entry is already written by the change of the row.
+				continue
+			}
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // onExchangeTablePartition exchange partition data
 func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	var (
@@ -1509,23 +2119,23 @@ func getPartitionRuleIDs(dbName string, table *model.TableInfo) []string {
 }
 
 // checkPartitioningKeysConstraints checks that the range partitioning key is included in the table constraint.
-func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTableStmt, tblInfo *model.TableInfo) error {
+func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.PartitionOptions, tblInfo *model.TableInfo) error {
 	// Returns directly if there are no unique keys in the table.
 	if len(tblInfo.Indices) == 0 && !tblInfo.PKIsHandle {
 		return nil
 	}
 
 	var partCols stringSlice
-	if s.Partition.Expr != nil {
+	if s.Expr != nil {
 		extractCols := newPartitionExprChecker(sctx, tblInfo)
-		s.Partition.Expr.Accept(extractCols)
+		s.Expr.Accept(extractCols)
 		partColumns, err := extractCols.columns, extractCols.err
 		if err != nil {
 			return err
 		}
 		partCols = columnInfoSlice(partColumns)
-	} else if len(s.Partition.ColumnNames) > 0 {
-		partCols = columnNameSlice(s.Partition.ColumnNames)
+	} else if len(s.ColumnNames) > 0 {
+		partCols = columnNameSlice(s.ColumnNames)
 	} else {
 		// TODO: Check keys constraints for list, key partition type and so on.
 		return nil
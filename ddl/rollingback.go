@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/logutil"
@@ -329,6 +330,30 @@ func rollingbackAddTablePartition(t *meta.Meta, job *model.Job) (ver int64, err
 	return convertAddTablePartitionJob2RollbackJob(t, job, errCancelledDDLJob, tblInfo)
 }
 
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(errCancelledDDLJob)
+	case model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the work is copying the rows.
+		if job.SnapshotVer != 0 {
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			return w.onReorganizePartition(d, t, job)
+		}
+	case model.StateDeleteReorganization:
+		// The reads have been switched to the new partitions, the job can't be rolled back.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	return convertReorgPartitionJob2RollbackJob(t, job, tblInfo, errCancelledDDLJob)
+}
+
 func rollingbackDropTableOrView(t *meta.Meta, job *model.Job) error {
 	tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
 	if err != nil {
@@ -414,6 +439,8 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackAddIndex(w, d, t, job, true)
 	case model.ActionAddTablePartition:
 		ver, err = rollingbackAddTablePartition(t, job)
+	case util.ActionReorganizePartition, util.ActionAlterTablePartitioning, util.ActionRemovePartitioning:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
 	case model.ActionDropColumn:
 		ver, err = rollingbackDropColumn(t, job)
 	case model.ActionDropColumns:
@@ -437,7 +464,8 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
-		model.ActionExchangeTablePartition:
+		model.ActionExchangeTablePartition, model.ActionAddCheckConstraint,
+		model.ActionDropCheckConstraint, model.ActionAlterCheckConstraint:
 		ver, err = cancelOnlyNotHandledJob(job)
 	default:
 		job.State = model.JobStateCancelled
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/pingcap/errors"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/admin"
@@ -72,7 +73,7 @@ func (d *ddl) Stats(vars *variable.SessionVars) (map[string]interface{}, error)
 	// TODO: Add all job information if needed.
 	job := ddlInfo.Jobs[0]
 	m[ddlJobID] = job.ID
-	m[ddlJobAction] = job.Type.String()
+	m[ddlJobAction] = ddlutil.ActionTypeString(job.Type)
 	m[ddlJobStartTS] = job.StartTS / 1e9 // unit: second
 	m[ddlJobState] = job.State.String()
 	m[ddlJobRows] = job.RowCount
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/ttl"
 	tidb_util "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/gcutil"
 )
@@ -467,6 +468,11 @@ func onTruncateTable(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ erro
 		job.State = model.JobStateCancelled
 		return ver, infoschema.ErrTableNotExists.GenWithStackByArgs(job.SchemaName, tblInfo.Name.O)
 	}
+	ttlInfo, err := t.GetTableTTL(schemaID, tblInfo.ID)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
 
 	err = t.DropTableOrView(schemaID, tblInfo.ID, true)
 	if err != nil {
@@ -530,6 +536,13 @@ func onTruncateTable(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ erro
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
+	if ttlInfo != nil {
+		err = t.SetTableTTL(schemaID, newTableID, ttlInfo)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+	}
 
 	failpoint.Inject("mockTruncateTableUpdateVersionError", func(val failpoint.Value) {
 		if val.(bool) {
@@ -748,6 +761,7 @@ func checkAndRenameTables(t *meta.Meta, job *model.Job, oldSchemaID int64, newSc
 
 	var autoTableID int64
 	var autoRandID int64
+	var ttlInfo *ttl.Info
 	shouldDelAutoID := false
 	if newSchemaID != oldSchemaID {
 		shouldDelAutoID = true
@@ -761,6 +775,11 @@ func checkAndRenameTables(t *meta.Meta, job *model.Job, oldSchemaID int64, newSc
 			job.State = model.JobStateCancelled
 			return ver, tblInfo, errors.Trace(err)
 		}
+		ttlInfo, err = t.GetTableTTL(oldSchemaID, tblInfo.ID)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, tblInfo, errors.Trace(err)
+		}
 		// It's compatible with old version.
 		// TODO: Remove it.
 		tblInfo.OldSchemaID = 0
@@ -799,6 +818,13 @@ func checkAndRenameTables(t *meta.Meta, job *model.Job, oldSchemaID int64, newSc
 			job.State = model.JobStateCancelled
 			return ver, tblInfo, errors.Trace(err)
 		}
+		if ttlInfo != nil {
+			err = t.SetTableTTL(newSchemaID, tblInfo.ID, ttlInfo)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, tblInfo, errors.Trace(err)
+			}
+		}
 	}
 
 	return ver, tblInfo, nil
@@ -1124,7 +1150,8 @@ func onRepairTable(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error)
 
 func onAlterTableAttributes(t *meta.Meta, job *model.Job) (ver int64, err error) {
 	rule := label.NewRule()
-	err = job.DecodeArgs(&rule)
+	var ttlInfo *ttl.Info
+	err = job.DecodeArgs(&rule, &ttlInfo)
 	if err != nil {
 		job.State = model.JobStateCancelled
 		return 0, errors.Trace(err)
@@ -1135,6 +1162,16 @@ func onAlterTableAttributes(t *meta.Meta, job *model.Job) (ver int64, err error)
 		return 0, err
 	}
 
+	if ttlInfo != nil {
+		err = t.SetTableTTL(job.SchemaID, tblInfo.ID, ttlInfo)
+	} else {
+		err = t.DropTableTTL(job.SchemaID, tblInfo.ID)
+	}
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return 0, errors.Trace(err)
+	}
+
 	err = infosync.PutLabelRule(context.TODO(), rule)
 	if err != nil {
 		job.State = model.JobStateCancelled
@@ -0,0 +1,109 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/ddl/label"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/ttl"
+	"github.com/pingcap/tidb/types"
+)
+
+// buildTTLInfo removes the TTL attributes from the labels of the rule, and builds the TTL info
+// of the table from them. It returns nil if the TTL attribute is not set.
+func buildTTLInfo(tblInfo *model.TableInfo, rule *label.Rule) (*ttl.Info, error) {
+	var ttlExpr, ttlEnable string
+	labels := rule.Labels[:0]
+	for _, l := range rule.Labels {
+		key, value, ok := ttl.SplitAttribute(l.Key)
+		if !ok {
+			labels = append(labels, l)
+			continue
+		}
+		switch key {
+		case ttl.AttributeTTL:
+			ttlExpr = value
+		case ttl.AttributeTTLEnable:
+			ttlEnable = value
+		}
+	}
+	rule.Labels = labels
+
+	if ttlExpr == "" {
+		if ttlEnable != "" {
+			return nil, ErrSetTTLOptionForNonTTLTable.GenWithStackByArgs(strings.ToUpper(ttl.AttributeTTLEnable))
+		}
+		return nil, nil
+	}
+	if tblInfo.TempTableType != model.TempTableNone {
+		return nil, ErrTempTableNotAllowedWithTTL
+	}
+	colName, intervalExpr, intervalUnit, err := ttl.ParseExpr(ttlExpr)
+	if err != nil {
+		return nil, ErrInvalidAttributesSpec.GenWithStackByArgs(ttl.AttributeTTL+"="+ttlExpr, err)
+	}
+	colInfo := model.FindColumnInfo(tblInfo.Columns, colName.L)
+	if colInfo == nil || colInfo.Hidden || colInfo.State != model.StatePublic {
+		return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(colName.O, tblInfo.Name.O)
+	}
+	if !types.IsTypeTime(colInfo.Tp) {
+		return nil, ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(colInfo.Name.O)
+	}
+
+	ttlInfo := &ttl.Info{
+		ColumnID:     colInfo.ID,
+		IntervalExpr: intervalExpr,
+		IntervalUnit: intervalUnit,
+		Enable:       true,
+	}
+	switch strings.ToLower(ttlEnable) {
+	case "", "on", "true", "1":
+	case "off", "false", "0":
+		ttlInfo.Enable = false
+	default:
+		return nil, ErrInvalidAttributesSpec.GenWithStackByArgs(ttl.AttributeTTLEnable+"="+ttlEnable, "the value should be ON or OFF")
+	}
+	return ttlInfo, nil
+}
+
+// checkPartitionAttributesWithoutTTL checks the TTL attributes are not set on a partition.
+func checkPartitionAttributesWithoutTTL(rule *label.Rule) error {
+	for _, l := range rule.Labels {
+		if _, _, ok := ttl.SplitAttribute(l.Key); ok {
+			return ErrInvalidAttributesSpec.GenWithStackByArgs(l.Key, "TTL can only be set on a table")
+		}
+	}
+	return nil
+}
+
+// checkDropColumnWithTTL checks whether the dropped column is used by the TTL attribute of the table.
+func checkDropColumnWithTTL(t *meta.Meta, schemaID int64, tblInfo *model.TableInfo, colInfo *model.ColumnInfo) error {
+	if colInfo.State != model.StatePublic {
+		return nil
+	}
+	ttlInfo, err := t.GetTableTTL(schemaID, tblInfo.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ttlInfo != nil && ttlInfo.ColumnID == colInfo.ID {
+		return ErrTTLColumnCannotDrop.GenWithStackByArgs(colInfo.Name.O)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
@@ -40,6 +41,30 @@ const (
 	loadGlobalVars               = `SELECT HIGH_PRIORITY variable_name, variable_value from mysql.global_variables where variable_name in (` // + nameList + ")"
 )
 
+// The DDL action types which are not defined in the parser yet.
+// Their values are the same as the ones used by later versions of the parser.
+const (
+	// ActionReorganizePartition is the type of `ALTER TABLE ... REORGANIZE PARTITION`.
+	ActionReorganizePartition model.ActionType = 67
+	// ActionAlterTablePartitioning is the type of `ALTER TABLE ... PARTITION BY`.
+	ActionAlterTablePartitioning model.ActionType = 71
+	// ActionRemovePartitioning is the type of `ALTER TABLE ... REMOVE PARTITIONING`.
+	ActionRemovePartitioning model.ActionType = 72
+)
+
+// ActionTypeString returns the name of the DDL action type, including the ones not defined in the parser.
+func ActionTypeString(tp model.ActionType) string {
+	switch tp {
+	case ActionReorganizePartition:
+		return "alter table reorganize partition"
+	case ActionAlterTablePartitioning:
+		return "alter table partition by"
+	case ActionRemovePartitioning:
+		return "alter table remove partitioning"
+	}
+	return tp.String()
+}
+
 // DelRangeTask is for run delete-range command in gc_worker.
 type DelRangeTask struct {
 	JobID, ElementID int64
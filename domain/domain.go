@@ -49,11 +49,13 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/telemetry"
+	"github.com/pingcap/tidb/ttl/ttlworker"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/domainutil"
 	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/servermemorylimit"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/tikv/client-go/v2/txnkv/transaction"
 	"go.etcd.io/etcd/clientv3"
@@ -66,26 +68,27 @@ import (
 // Domain represents a storage space. Different domains can use the same database name.
 // Multiple domains can be used in parallel without synchronization.
 type Domain struct {
-	store                kv.Storage
-	infoCache            *infoschema.InfoCache
-	privHandle           *privileges.Handle
-	bindHandle           *bindinfo.BindHandle
-	statsHandle          unsafe.Pointer
-	statsLease           time.Duration
-	ddl                  ddl.DDL
-	info                 *infosync.InfoSyncer
-	m                    sync.Mutex
-	SchemaValidator      SchemaValidator
-	sysSessionPool       *sessionPool
-	exit                 chan struct{}
-	etcdClient           *clientv3.Client
-	sysVarCache          SysVarCache // replaces GlobalVariableCache
-	slowQuery            *topNSlowQueries
-	expensiveQueryHandle *expensivequery.Handle
-	wg                   sync.WaitGroup
-	statsUpdating        sync2.AtomicInt32
-	cancel               context.CancelFunc
-	indexUsageSyncLease  time.Duration
+	store                   kv.Storage
+	infoCache               *infoschema.InfoCache
+	privHandle              *privileges.Handle
+	bindHandle              *bindinfo.BindHandle
+	statsHandle             unsafe.Pointer
+	statsLease              time.Duration
+	ddl                     ddl.DDL
+	info                    *infosync.InfoSyncer
+	m                       sync.Mutex
+	SchemaValidator         SchemaValidator
+	sysSessionPool          *sessionPool
+	exit                    chan struct{}
+	etcdClient              *clientv3.Client
+	sysVarCache             SysVarCache // replaces GlobalVariableCache
+	slowQuery               *topNSlowQueries
+	expensiveQueryHandle    *expensivequery.Handle
+	serverMemoryLimitHandle *servermemorylimit.Handle
+	wg                      sync.WaitGroup
+	statsUpdating           sync2.AtomicInt32
+	cancel                  context.CancelFunc
+	indexUsageSyncLease     time.Duration
 
 	serverID             uint64
 	serverIDSession      *concurrency.Session
@@ -647,6 +650,7 @@ func NewDomain(store kv.Storage, ddlLease time.Duration, statsLease time.Duratio
 
 	do.SchemaValidator = NewSchemaValidator(ddlLease, do)
 	do.expensiveQueryHandle = expensivequery.NewExpensiveQueryHandle(do.exit)
+	do.serverMemoryLimitHandle = servermemorylimit.NewServerMemoryLimitHandle(do.exit)
 	return do
 }
 
@@ -1024,6 +1028,9 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if variable.TiDBOptOn(variable.CapturePlanBaseline.GetVal()) {
 					do.bindHandle.CaptureBaselines()
 				}
+				if variable.EnablePlanRegressionRollback.Load() {
+					do.bindHandle.HandlePlanRegressions()
+				}
 				do.bindHandle.SaveEvolveTasksToStore()
 			case <-gcBindTicker.C:
 				if !owner.IsOwner() {
@@ -1099,6 +1106,45 @@ func (do *Domain) TelemetryReportLoop(ctx sessionctx.Context) {
 	}()
 }
 
+// TTLJobLoop creates a goroutine that deletes the expired rows of the tables with the TTL attribute in a loop,
+// it should be called only once in BootstrapSession.
+func (do *Domain) TTLJobLoop(ctx sessionctx.Context) {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("TTLJobLoop exited.")
+			util.Recover(metrics.LabelDomain, "TTLJobLoop", nil, false)
+		}()
+		owner := do.newOwnerManager(ttlworker.Prompt, ttlworker.OwnerKey)
+		manager := ttlworker.NewJobManager(ctx, do.InfoSchema)
+		keepRunning := func() bool {
+			select {
+			case <-do.exit:
+				return false
+			default:
+				return owner.IsOwner()
+			}
+		}
+		for {
+			select {
+			case <-do.exit:
+				owner.Cancel()
+				return
+			case <-time.After(ttlworker.CheckInterval):
+				if !owner.IsOwner() {
+					continue
+				}
+				err := manager.RunJobs(context.Background(), time.Now(), keepRunning)
+				if err != nil {
+					logutil.BgLogger().Warn("run TTL jobs failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
 // TelemetryRotateSubWindowLoop create a goroutine that rotates the telemetry window regularly.
 func (do *Domain) TelemetryRotateSubWindowLoop(ctx sessionctx.Context) {
 	ctx.GetSessionVars().InRestrictedSQL = true
@@ -1307,6 +1353,10 @@ func (do *Domain) updateStatsWorker(ctx sessionctx.Context, owner owner.Manager)
 			if err != nil {
 				logutil.BgLogger().Debug("dump stats delta failed", zap.Error(err))
 			}
+			err = statsHandle.DumpColStatsUsageToKV()
+			if err != nil {
+				logutil.BgLogger().Debug("dump column stats usage failed", zap.Error(err))
+			}
 			statsHandle.UpdateErrorRate(do.InfoSchema())
 		case <-loadFeedbackTicker.C:
 			statsHandle.UpdateStatsByLocalFeedback(do.InfoSchema())
@@ -1360,6 +1410,11 @@ func (do *Domain) ExpensiveQueryHandle() *expensivequery.Handle {
 	return do.expensiveQueryHandle
 }
 
+// ServerMemoryLimitHandle returns the server memory limit handle.
+func (do *Domain) ServerMemoryLimitHandle() *servermemorylimit.Handle {
+	return do.serverMemoryLimitHandle
+}
+
 const (
 	privilegeKey   = "/tidb/privilege"
 	sysVarCacheKey = "/tidb/sysvars"
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
@@ -215,6 +216,54 @@ func checkEnableServerGlobalVar(name, sVal string) {
 			break
 		}
 		storekv.StoreLimit.Store(val)
+	case variable.TiDBEnableInstancePlanCache:
+		variable.SetEnableInstancePlanCache(variable.TiDBOptOn(sVal))
+	case variable.TiDBInstancePlanCacheMaxMemSize:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		kvcache.InstancePlanCache.SetQuota(val)
+	case variable.TiDBEnablePlanRegressionRollback:
+		variable.EnablePlanRegressionRollback.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBEnableColumnTracking:
+		variable.EnableColumnTracking.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBPartitionStatsCacheMemQuota:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.PartitionStatsCacheMemQuota.Store(val)
+	case variable.TiDBStatsHistoryMaxVersions:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.StatsHistoryMaxVersions.Store(val)
+	case variable.TiDBServerMemoryLimit:
+		var val uint64
+		val, err = variable.ParseServerMemoryLimit(sVal)
+		if err != nil {
+			break
+		}
+		variable.ServerMemoryLimit.Store(val)
+	case variable.TiDBServerMemoryLimitGCTrigger:
+		var val float64
+		val, err = strconv.ParseFloat(sVal, 64)
+		if err != nil {
+			break
+		}
+		variable.ServerMemoryLimitGCTrigger.Store(val)
+	case variable.TiDBServerMemoryLimitSessMinSize:
+		var val uint64
+		val, err = strconv.ParseUint(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.ServerMemoryLimitSessMinSize.Store(val)
 	}
 	if err != nil {
 		logutil.BgLogger().Error(fmt.Sprintf("load global variable %s error", name), zap.Error(err))
@@ -216,6 +216,9 @@ type ExecStmt struct {
 	OutputNames []*types.FieldName
 	PsStmt      *plannercore.CachedPrepareStmt
 	Ti          *TelemetryInfo
+
+	// checkedOutPlans are the plans of the instance plan cache used by the statement.
+	checkedOutPlans plannercore.CheckedOutPlans
 }
 
 // PointGet short path for point exec directly from plan, keep only necessary steps
@@ -301,6 +304,7 @@ func (a *ExecStmt) RebuildPlan(ctx context.Context) (int64, error) {
 	}
 	a.OutputNames = names
 	a.Plan = p
+	a.checkedOutPlans = append(a.checkedOutPlans, plannercore.TakeCheckedOutPlans(a.Ctx)...)
 	return a.InfoSchema.SchemaMetaVersion(), nil
 }
 
@@ -901,6 +905,10 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	sessVars.DurationParse = 0
 	// Clean the stale read flag when statement execution finish
 	sessVars.StmtCtx.IsStaleness = false
+	// The plans used by the statement can be used by the other sessions now.
+	a.checkedOutPlans = append(a.checkedOutPlans, plannercore.TakeCheckedOutPlans(a.Ctx)...)
+	a.checkedOutPlans.Release()
+	a.checkedOutPlans = nil
 }
 
 // CloseRecordSet will finish the execution of current statement and do some record work
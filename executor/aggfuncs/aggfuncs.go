@@ -213,3 +213,28 @@ type MaxMinSlidingWindowAggFunc interface {
 	// SetWindowStart sets the start position of window
 	SetWindowStart(start uint64)
 }
+
+// PartitionRows is the rows of a window partition, the rows may be stored in disk.
+type PartitionRows interface {
+	// NumRows returns the number of rows in the partition.
+	NumRows() uint64
+	// GetRow returns the idx-th row in the partition.
+	GetRow(idx uint64) chunk.Row
+}
+
+// PartitionRowsWindowFunc is the interface for the window functions which look at the other rows
+// of the partition, like LEAD and RANK. These functions keep the rows passed by UpdatePartialResult
+// in the partial result, SetPartitionRows lets them read the rows from the executor instead, so the
+// rows of a large partition can be spilled to disk.
+type PartitionRowsWindowFunc interface {
+	// SetPartitionRows sets the rows of the partition, it is used instead of UpdatePartialResult.
+	SetPartitionRows(pr PartialResult, rows PartitionRows)
+}
+
+// PartialResultAppender is implemented by the aggregate functions whose final result is not the
+// input of the same function in FinalMode, like AVG. It is used to spill the partial results to disk.
+type PartialResultAppender interface {
+	// AppendPartialResult2Chunk appends the partial result to the columns colIdxs of chk, the
+	// columns are in the order of the arguments of the function in FinalMode.
+	AppendPartialResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk, colIdxs []int) error
+}
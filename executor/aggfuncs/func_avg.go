@@ -80,6 +80,20 @@ func (e *baseAvgDecimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Par
 	return nil
 }
 
+var _ PartialResultAppender = &baseAvgDecimal{}
+
+// AppendPartialResult2Chunk implements the PartialResultAppender interface, it appends the count and sum.
+func (e *baseAvgDecimal) AppendPartialResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk, colIdxs []int) error {
+	p := (*partialResult4AvgDecimal)(pr)
+	chk.AppendInt64(colIdxs[0], p.count)
+	if p.count == 0 {
+		chk.AppendNull(colIdxs[1])
+		return nil
+	}
+	chk.AppendMyDecimal(colIdxs[1], &p.sum)
+	return nil
+}
+
 type avgOriginal4Decimal struct {
 	baseAvgDecimal
 }
@@ -303,6 +317,20 @@ func (e *baseAvgFloat64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Par
 	return nil
 }
 
+var _ PartialResultAppender = &baseAvgFloat64{}
+
+// AppendPartialResult2Chunk implements the PartialResultAppender interface, it appends the count and sum.
+func (e *baseAvgFloat64) AppendPartialResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk, colIdxs []int) error {
+	p := (*partialResult4AvgFloat64)(pr)
+	chk.AppendInt64(colIdxs[0], p.count)
+	if p.count == 0 {
+		chk.AppendNull(colIdxs[1])
+		return nil
+	}
+	chk.AppendFloat64(colIdxs[1], p.sum)
+	return nil
+}
+
 type avgOriginal4Float64HighPrecision struct {
 	baseAvgFloat64
 }
@@ -34,7 +34,7 @@ type cumeDist struct {
 type partialResult4CumeDist struct {
 	curIdx   int
 	lastRank int
-	rows     []chunk.Row
+	rows     windowRows
 }
 
 func (r *cumeDist) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -45,20 +45,24 @@ func (r *cumeDist) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4CumeDist)(pr)
 	p.curIdx = 0
 	p.lastRank = 0
-	p.rows = p.rows[:0]
+	p.rows.reset()
 }
 
 func (r *cumeDist) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4CumeDist)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
-	memDelta += int64(len(rowsInGroup)) * DefRowSize
+	memDelta += p.rows.append(rowsInGroup)
 	return memDelta, nil
 }
 
+func (r *cumeDist) SetPartitionRows(pr PartialResult, rows PartitionRows) {
+	p := (*partialResult4CumeDist)(pr)
+	p.rows.partitionRows = rows
+}
+
 func (r *cumeDist) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4CumeDist)(pr)
-	numRows := len(p.rows)
-	for p.lastRank < numRows && r.compareRows(p.rows[p.curIdx], p.rows[p.lastRank]) == 0 {
+	numRows := int(p.rows.numRows())
+	for p.lastRank < numRows && r.compareRows(p.rows.getRow(uint64(p.curIdx)), p.rows.getRow(uint64(p.lastRank))) == 0 {
 		p.lastRank++
 	}
 	p.curIdx++
@@ -36,7 +36,7 @@ type baseLeadLag struct {
 }
 
 type partialResult4LeadLag struct {
-	rows   []chunk.Row
+	rows   windowRows
 	curIdx uint64
 }
 
@@ -46,17 +46,21 @@ func (v *baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
 
 func (v *baseLeadLag) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4LeadLag)(pr)
-	p.rows = p.rows[:0]
+	p.rows.reset()
 	p.curIdx = 0
 }
 
 func (v *baseLeadLag) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LeadLag)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
-	memDelta += int64(len(rowsInGroup)) * DefRowSize
+	memDelta += p.rows.append(rowsInGroup)
 	return memDelta, nil
 }
 
+func (v *baseLeadLag) SetPartitionRows(pr PartialResult, rows PartitionRows) {
+	p := (*partialResult4LeadLag)(pr)
+	p.rows.partitionRows = rows
+}
+
 type lead struct {
 	baseLeadLag
 }
@@ -64,10 +68,10 @@ type lead struct {
 func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx+v.offset < uint64(len(p.rows)) {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx+v.offset])
+	if p.curIdx+v.offset < p.rows.numRows() {
+		_, err = v.evaluateRow(sctx, v.args[0], p.rows.getRow(p.curIdx+v.offset))
 	} else {
-		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows.getRow(p.curIdx))
 	}
 	if err != nil {
 		return err
@@ -85,9 +89,9 @@ func (v *lag) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult,
 	p := (*partialResult4LeadLag)(pr)
 	var err error
 	if p.curIdx >= v.offset {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx-v.offset])
+		_, err = v.evaluateRow(sctx, v.args[0], p.rows.getRow(p.curIdx-v.offset))
 	} else {
-		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows.getRow(p.curIdx))
 	}
 	if err != nil {
 		return err
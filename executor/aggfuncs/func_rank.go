@@ -36,7 +36,7 @@ type rank struct {
 type partialResult4Rank struct {
 	curIdx   int64
 	lastRank int64
-	rows     []chunk.Row
+	rows     windowRows
 }
 
 func (r *rank) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -47,16 +47,20 @@ func (r *rank) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4Rank)(pr)
 	p.curIdx = 0
 	p.lastRank = 0
-	p.rows = p.rows[:0]
+	p.rows.reset()
 }
 
 func (r *rank) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4Rank)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
-	memDelta += int64(len(rowsInGroup)) * DefRowSize
+	memDelta += p.rows.append(rowsInGroup)
 	return memDelta, nil
 }
 
+func (r *rank) SetPartitionRows(pr PartialResult, rows PartitionRows) {
+	p := (*partialResult4Rank)(pr)
+	p.rows.partitionRows = rows
+}
+
 func (r *rank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4Rank)(pr)
 	p.curIdx++
@@ -65,7 +69,7 @@ func (r *rank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult
 		chk.AppendInt64(r.ordinal, p.lastRank)
 		return nil
 	}
-	if r.compareRows(p.rows[p.curIdx-2], p.rows[p.curIdx-1]) == 0 {
+	if r.compareRows(p.rows.getRow(uint64(p.curIdx-2)), p.rows.getRow(uint64(p.curIdx-1))) == 0 {
 		chk.AppendInt64(r.ordinal, p.lastRank)
 		return nil
 	}
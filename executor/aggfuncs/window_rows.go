@@ -0,0 +1,50 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// windowRows stores the rows of a partition for the window functions which look at the other rows.
+// The rows are either appended by UpdatePartialResult or read from the PartitionRows set by the executor.
+type windowRows struct {
+	rows          []chunk.Row
+	partitionRows PartitionRows
+}
+
+func (r *windowRows) append(rows []chunk.Row) (memDelta int64) {
+	r.rows = append(r.rows, rows...)
+	return int64(len(rows)) * DefRowSize
+}
+
+func (r *windowRows) numRows() uint64 {
+	if r.partitionRows != nil {
+		return r.partitionRows.NumRows()
+	}
+	return uint64(len(r.rows))
+}
+
+func (r *windowRows) getRow(idx uint64) chunk.Row {
+	if r.partitionRows != nil {
+		return r.partitionRows.GetRow(idx)
+	}
+	return r.rows[idx]
+}
+
+func (r *windowRows) reset() {
+	r.rows = r.rows[:0]
+	r.partitionRows = nil
+}
@@ -60,6 +60,11 @@ type baseHashAggWorker struct {
 
 	memTracker *memory.Tracker
 	BInMap     int // indicate there are 2^BInMap buckets in Golang Map.
+
+	// spillHelper is nil if the partial results can't be spilled.
+	spillHelper *parallelHashAggSpillHelper
+	// spillTimes is the spill times of the spill helper seen by the worker.
+	spillTimes uint32
 }
 
 const (
@@ -70,7 +75,7 @@ const (
 )
 
 func newBaseHashAggWorker(ctx sessionctx.Context, finishCh <-chan struct{}, aggFuncs []aggfuncs.AggFunc,
-	maxChunkSize int, memTrack *memory.Tracker) baseHashAggWorker {
+	maxChunkSize int, memTrack *memory.Tracker, spillHelper *parallelHashAggSpillHelper) baseHashAggWorker {
 	baseWorker := baseHashAggWorker{
 		ctx:          ctx,
 		finishCh:     finishCh,
@@ -78,6 +83,12 @@ func newBaseHashAggWorker(ctx sessionctx.Context, finishCh <-chan struct{}, aggF
 		maxChunkSize: maxChunkSize,
 		memTracker:   memTrack,
 		BInMap:       0,
+		spillHelper:  spillHelper,
+	}
+	if spillHelper != nil {
+		// The worker tracks its own memory usage, so that it can release the memory of the spilled partial results.
+		baseWorker.memTracker = memory.NewTracker(memory.LabelForHashAggWorker, -1)
+		baseWorker.memTracker.AttachTo(memTrack)
 	}
 	return baseWorker
 }
@@ -112,6 +123,10 @@ type HashAggFinalWorker struct {
 	outputCh            chan *AfFinalResult
 	finalResultHolderCh chan *chunk.Chunk
 	groupKeys           [][]byte
+
+	// idx is the index of the final worker, it owns the partitions idx, idx+concurrency, ... of the spilled partial results.
+	idx         int
+	concurrency int
 }
 
 // AfFinalResult indicates aggregation functions final result.
@@ -218,6 +233,14 @@ type HashAggExec struct {
 	spillAction *AggSpillDiskAction
 	// isChildDrained indicates whether the all data from child has been taken out.
 	isChildDrained bool
+
+	// spillFieldTypes and spillColIdxs describe the chunks of the spilled partial results of the parallel execution.
+	// The partial result of the i-th aggregate function is spilled into the columns spillColIdxs[i], and the group
+	// key is spilled into the last column. spillFieldTypes is nil if some of the aggregate functions can't be spilled.
+	spillFieldTypes []*types.FieldType
+	spillColIdxs    [][]int
+	// spillHelper spills the partial results of the parallel execution.
+	spillHelper *parallelHashAggSpillHelper
 }
 
 // HashAggInput indicates the input of hash agg exec.
@@ -294,6 +317,10 @@ func (e *HashAggExec) Close() error {
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
+		if e.spillHelper != nil {
+			terror.Log(e.spillHelper.close())
+			e.spillHelper, e.spillAction = nil, nil
+		}
 	}
 	return e.baseExecutor.Close()
 }
@@ -380,10 +407,18 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	e.finalWorkers = make([]HashAggFinalWorker, finalConcurrency)
 	e.initRuntimeStats()
 
+	atomic.StoreUint32(&e.inSpillMode, 0)
+	if e.spillFieldTypes != nil && sessionVars.TrackAggregateMemoryUsage && config.GetGlobalConfig().OOMUseTmpStorage {
+		e.diskTracker = disk.NewTracker(e.id, -1)
+		e.diskTracker.AttachTo(sessionVars.StmtCtx.DiskTracker)
+		e.spillHelper = newParallelHashAggSpillHelper(e, finalConcurrency)
+		sessionVars.StmtCtx.MemTracker.FallbackOldAndSetNewActionForSoftLimit(e.ActionSpill())
+	}
+
 	// Init partial workers.
 	for i := 0; i < partialConcurrency; i++ {
 		w := HashAggPartialWorker{
-			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, e.memTracker),
+			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, e.memTracker, e.spillHelper),
 			inputCh:           e.partialInputChs[i],
 			outputChs:         e.partialOutputChs,
 			giveBackCh:        e.inputCh,
@@ -414,7 +449,7 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	for i := 0; i < finalConcurrency; i++ {
 		groupSet, setSize := set.NewStringSetWithMemoryUsage()
 		w := HashAggFinalWorker{
-			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, e.memTracker),
+			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, e.memTracker, e.spillHelper),
 			partialResultMap:    make(aggPartialResultMapper),
 			groupSet:            groupSet,
 			inputCh:             e.partialOutputChs[i],
@@ -423,6 +458,8 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			rowBuffer:           make([]types.Datum, 0, e.Schema().Len()),
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
 			groupKeys:           make([][]byte, 0, 8),
+			idx:                 i,
+			concurrency:         finalConcurrency,
 		}
 		// There is a bucket in the empty partialResultsMap.
 		e.memTracker.Consume(defBucketMemoryUsage*(1<<w.BInMap) + setSize)
@@ -490,6 +527,12 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 			w.globalOutputCh <- &AfFinalResult{err: err}
 			return
 		}
+		if w.spillHelper != nil && w.spillHelper.needSpill(&w.spillTimes) {
+			if err := w.spill(ctx); err != nil {
+				w.globalOutputCh <- &AfFinalResult{err: err}
+				return
+			}
+		}
 		if w.stats != nil {
 			w.stats.ExecTime += int64(time.Since(execStart))
 			w.stats.TaskNum += 1
@@ -500,6 +543,17 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 	}
 }
 
+// spill spills the partial results to disk and releases their memory.
+func (w *HashAggPartialWorker) spill(ctx sessionctx.Context) error {
+	if err := w.spillHelper.spill(ctx, w.aggFuncs, w.partialResultsMap, w.maxChunkSize); err != nil {
+		return err
+	}
+	w.partialResultsMap = make(aggPartialResultMapper)
+	w.BInMap = 0
+	w.memTracker.Consume(getGroupKeyMemUsage(w.groupKey) - w.memTracker.BytesConsumed())
+	return nil
+}
+
 func getGroupKeyMemUsage(groupKey [][]byte) int64 {
 	mem := int64(0)
 	for _, key := range groupKey {
@@ -685,6 +739,11 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 			}
 			w.memTracker.Consume(allMemDelta)
 		}
+		if w.spillHelper != nil && w.spillHelper.needSpill(&w.spillTimes) {
+			if err = w.spill(sctx); err != nil {
+				return err
+			}
+		}
 		if w.stats != nil {
 			w.stats.ExecTime += int64(time.Since(execStart))
 			w.stats.TaskNum += 1
@@ -692,14 +751,91 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 	}
 }
 
-func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
+// spill spills the partial results to disk and releases their memory.
+func (w *HashAggFinalWorker) spill(sctx sessionctx.Context) error {
+	if err := w.spillHelper.spill(sctx, w.aggFuncs, w.partialResultMap, w.maxChunkSize); err != nil {
+		return err
+	}
+	w.resetPartialResults()
+	return nil
+}
+
+func (w *HashAggFinalWorker) resetPartialResults() {
+	w.partialResultMap = make(aggPartialResultMapper)
+	w.groupSet, _ = set.NewStringSetWithMemoryUsage()
+	w.BInMap = 0
+	w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - w.memTracker.BytesConsumed())
+}
+
+// restorePartition restores the spilled partial results of a partition into memory.
+func (w *HashAggFinalWorker) restorePartition(sctx sessionctx.Context, partition *spilledPartition) error {
+	sc := sctx.GetSessionVars().StmtCtx
+	keyColIdx := w.spillHelper.keyColIdx
+	rows := make([]chunk.Row, 1)
+	for chkIdx := 0; chkIdx < partition.list.NumChunks(); chkIdx++ {
+		chk, err := partition.list.GetChunk(chkIdx)
+		if err != nil {
+			return err
+		}
+		memSize := getGroupKeyMemUsage(w.groupKeys)
+		w.groupKeys = w.groupKeys[:0]
+		for i := 0; i < chk.NumRows(); i++ {
+			w.groupKeys = append(w.groupKeys, []byte(chk.GetRow(i).GetString(keyColIdx)))
+		}
+		w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - memSize)
+		partialResults := w.getPartialResult(sc, w.groupKeys, w.partialResultMap)
+		allMemDelta := int64(0)
+		for i, groupKey := range w.groupKeys {
+			if !w.groupSet.Exist(string(groupKey)) {
+				allMemDelta += w.groupSet.Insert(string(groupKey))
+			}
+			rows[0] = chk.GetRow(i)
+			for j, af := range w.aggFuncs {
+				memDelta, err := af.UpdatePartialResult(sctx, rows, partialResults[i][j])
+				if err != nil {
+					return err
+				}
+				allMemDelta += memDelta
+			}
+		}
+		w.memTracker.Consume(allMemDelta)
+	}
+	return nil
+}
+
+// getSpilledFinalResult spills the partial results in memory, then restores the partitions owned
+// by the worker and returns their final results one partition at a time.
+func (w *HashAggFinalWorker) getSpilledFinalResult(sctx sessionctx.Context) error {
+	if len(w.partialResultMap) > 0 {
+		if err := w.spill(sctx); err != nil {
+			return err
+		}
+	}
+	for i := w.idx; i < len(w.spillHelper.partitions); i += w.concurrency {
+		partition := w.spillHelper.partitions[i]
+		if partition.numChunks() == 0 {
+			continue
+		}
+		if err := w.restorePartition(sctx, partition); err != nil {
+			return err
+		}
+		if finished := w.getFinalResult(sctx); finished {
+			return nil
+		}
+		w.resetPartialResults()
+	}
+	return nil
+}
+
+// getFinalResult returns the final results to the main thread, finished is true if the executor is closed.
+func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) (finished bool) {
 	waitStart := time.Now()
 	result, finished := w.receiveFinalResultHolder()
 	if w.stats != nil {
 		w.stats.WaitTime += int64(time.Since(waitStart))
 	}
 	if finished {
-		return
+		return true
 	}
 	execStart := time.Now()
 	memSize := getGroupKeyMemUsage(w.groupKeys)
@@ -723,7 +859,7 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 			w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.finalResultHolderCh}
 			result, finished = w.receiveFinalResultHolder()
 			if finished {
-				return
+				return true
 			}
 		}
 	}
@@ -731,6 +867,7 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 	if w.stats != nil {
 		w.stats.ExecTime += int64(time.Since(execStart))
 	}
+	return false
 }
 
 func (w *HashAggFinalWorker) receiveFinalResultHolder() (*chunk.Chunk, bool) {
@@ -756,6 +893,12 @@ func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGro
 	if err := w.consumeIntermData(ctx); err != nil {
 		w.outputCh <- &AfFinalResult{err: err}
 	}
+	if w.spillHelper != nil && w.spillHelper.isSpilled(w.idx, w.concurrency) {
+		if err := w.getSpilledFinalResult(ctx); err != nil {
+			w.outputCh <- &AfFinalResult{err: err}
+		}
+		return
+	}
 	w.getFinalResult(ctx)
 }
 
@@ -1893,7 +2036,7 @@ func (e *HashAggExec) ActionSpill() *AggSpillDiskAction {
 // maxSpillTimes indicates how many times the data can spill at most.
 const maxSpillTimes = 10
 
-// AggSpillDiskAction implements memory.ActionOnExceed for unparalleled HashAgg.
+// AggSpillDiskAction implements memory.ActionOnExceed for HashAgg.
 // If the memory quota of a query is exceeded, AggSpillDiskAction.Action is
 // triggered.
 type AggSpillDiskAction struct {
@@ -1912,6 +2055,9 @@ func (a *AggSpillDiskAction) Action(t *memory.Tracker) {
 			zap.Int64("consumed", t.BytesConsumed()),
 			zap.Int64("quota", t.GetBytesLimit()))
 		atomic.StoreUint32(&a.e.inSpillMode, 1)
+		if a.e.spillHelper != nil {
+			a.e.spillHelper.triggerSpill()
+		}
 		return
 	}
 	if fallback := a.GetFallback(); fallback != nil {
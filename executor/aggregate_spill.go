@@ -0,0 +1,173 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/twmb/murmur3"
+)
+
+// spilledPartitionNumPerFinalWorker is the number of the partitions of the spilled partial results
+// restored by each final worker. The final worker restores its partitions one by one, so the more
+// partitions, the less memory is used to restore a partition.
+const spilledPartitionNumPerFinalWorker = 8
+
+// isSpillableAggFunc checks whether the partial result of the aggregate function can be spilled
+// in the parallel hash aggregation. The spilled partial result is consumed by the function in FinalMode.
+func isSpillableAggFunc(aggDesc *aggregation.AggFuncDesc) bool {
+	if aggDesc.HasDistinct || len(aggDesc.OrderByItems) > 0 {
+		return false
+	}
+	switch aggDesc.Name {
+	case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin,
+		ast.AggFuncBitOr, ast.AggFuncBitXor, ast.AggFuncBitAnd:
+		return true
+	}
+	return false
+}
+
+// spilledPartition is a partition of the spilled partial results.
+type spilledPartition struct {
+	sync.Mutex
+	list *chunk.ListInDisk
+}
+
+// parallelHashAggSpillHelper spills the partial results of the parallel hash aggregation to disk.
+//
+// When the spill action is triggered, every partial worker and final worker spills all of its partial
+// results, each group into the partition hash(groupKey) % len(partitions), and releases the memory.
+// The partition i is owned by the final worker i % finalConcurrency, which is also the final worker the
+// groups are shuffled to. After consuming the intermediate data, a final worker whose partitions are not
+// empty spills the partial results in memory as well, then restores its partitions and returns the final
+// results one partition at a time.
+type parallelHashAggSpillHelper struct {
+	// spillTimes is increased by the spill action, the workers spill when they find it changed.
+	spillTimes uint32
+	// inSpillMode points to HashAggExec.inSpillMode, it's reset by the worker after spilling.
+	inSpillMode *uint32
+
+	partitions []*spilledPartition
+	fieldTypes []*types.FieldType
+	colIdxs    [][]int
+	keyColIdx  int
+}
+
+func newParallelHashAggSpillHelper(e *HashAggExec, finalConcurrency int) *parallelHashAggSpillHelper {
+	h := &parallelHashAggSpillHelper{
+		inSpillMode: &e.inSpillMode,
+		partitions:  make([]*spilledPartition, finalConcurrency*spilledPartitionNumPerFinalWorker),
+		fieldTypes:  e.spillFieldTypes,
+		colIdxs:     e.spillColIdxs,
+		keyColIdx:   len(e.spillFieldTypes) - 1,
+	}
+	for i := range h.partitions {
+		list := chunk.NewListInDisk(h.fieldTypes)
+		list.GetDiskTracker().AttachTo(e.diskTracker)
+		h.partitions[i] = &spilledPartition{list: list}
+	}
+	return h
+}
+
+// triggerSpill is called by the spill action to let the workers spill their partial results.
+func (h *parallelHashAggSpillHelper) triggerSpill() {
+	atomic.AddUint32(&h.spillTimes, 1)
+}
+
+// needSpill checks whether the worker should spill, spillTimes is the spill times seen by the worker.
+func (h *parallelHashAggSpillHelper) needSpill(spillTimes *uint32) bool {
+	if t := atomic.LoadUint32(&h.spillTimes); t != *spillTimes {
+		*spillTimes = t
+		return true
+	}
+	return false
+}
+
+// spill spills the partial results in mapper to the partitions.
+func (h *parallelHashAggSpillHelper) spill(sctx sessionctx.Context, aggFuncs []aggfuncs.AggFunc, mapper aggPartialResultMapper, maxChunkSize int) (err error) {
+	defer atomic.StoreUint32(h.inSpillMode, 0)
+	chks := make([]*chunk.Chunk, len(h.partitions))
+	for groupKey, prs := range mapper {
+		idx := int(murmur3.Sum32([]byte(groupKey))) % len(h.partitions)
+		if chks[idx] == nil {
+			chks[idx] = chunk.NewChunkWithCapacity(h.fieldTypes, maxChunkSize)
+		}
+		chk := chks[idx]
+		for i, af := range aggFuncs {
+			if appender, ok := af.(aggfuncs.PartialResultAppender); ok {
+				err = appender.AppendPartialResult2Chunk(sctx, prs[i], chk, h.colIdxs[i])
+			} else {
+				err = af.AppendFinalResult2Chunk(sctx, prs[i], chk)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		chk.AppendString(h.keyColIdx, groupKey)
+		if chk.IsFull() {
+			if err = h.partitions[idx].add(chk); err != nil {
+				return err
+			}
+			chk.Reset()
+		}
+	}
+	for idx, chk := range chks {
+		if chk != nil && chk.NumRows() > 0 {
+			if err = h.partitions[idx].add(chk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isSpilled checks whether some partial results owned by the final worker have been spilled.
+func (h *parallelHashAggSpillHelper) isSpilled(finalWorkerIdx, finalConcurrency int) bool {
+	for i := finalWorkerIdx; i < len(h.partitions); i += finalConcurrency {
+		if h.partitions[i].numChunks() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *parallelHashAggSpillHelper) close() error {
+	var firstErr error
+	for _, p := range h.partitions {
+		if err := p.list.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *spilledPartition) add(chk *chunk.Chunk) error {
+	p.Lock()
+	defer p.Unlock()
+	return p.list.Add(chk)
+}
+
+func (p *spilledPartition) numChunks() int {
+	p.Lock()
+	defer p.Unlock()
+	return p.list.NumChunks()
+}
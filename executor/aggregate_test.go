@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
@@ -1510,3 +1511,53 @@ func (s *testSerialSuite) TestAggInDisk(c *C) {
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t;").Check(testkit.Rows("0"))
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t group by c1;").Check(testkit.Rows())
 }
+
+func (s *testSerialSuite) TestParallelAggInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 3")
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c decimal(10, 2), d varchar(10))")
+	sql := "insert into t values "
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			sql += ","
+		}
+		sql += fmt.Sprintf("(%v, %v, %v.5, '%v')", i%300, i, i%7, i%11)
+	}
+	tk.MustExec(sql)
+
+	sqls := []string{
+		"select /*+ HASH_AGG() */ a, count(*), sum(b), avg(b), avg(c), max(d), min(b) from t group by a",
+		"select /*+ HASH_AGG() */ count(b), sum(c), bit_xor(b), bit_or(b), bit_and(b) from t group by a, d",
+		"select /*+ HASH_AGG() */ distinct a, d from t",
+		"select /*+ HASH_AGG() */ avg(b), count(d) from t",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set tidb_mem_quota_query = default")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Equals, int64(0))
+
+		tk.MustExec("set tidb_mem_quota_query = 100")
+		tk.MustQuery(sql).Sort().Check(expected)
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+	}
+
+	// The aggregate functions with distinct are executed in unparallel mode.
+	tk.MustExec("set tidb_mem_quota_query = default")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 1")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 1")
+	sql = "select /*+ HASH_AGG() */ a, group_concat(d order by b) from t group by a"
+	expected := tk.MustQuery(sql).Sort().Rows()
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 3")
+	tk.MustExec("set tidb_mem_quota_query = 100")
+	tk.MustQuery(sql).Sort().Check(expected)
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/statistics/handle"
 	derr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
@@ -123,6 +124,8 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	// The meaning of key in map is the structure that used to store the tableID and indexID.
 	// The meaning of value in map is some additional information needed to build global-level stats.
 	globalStatsMap := make(map[globalStatsKey]globalStatsInfo)
+	// analyzedTables are the tables whose stats are recorded to the stats history after analyze.
+	analyzedTables := make(map[int64]struct{})
 	finishJobWithLogFn := func(ctx context.Context, job *statistics.AnalyzeJob, meetError bool) {
 		job.Finish(meetError)
 		if job != nil {
@@ -174,6 +177,7 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			finishJobWithLogFn(ctx, results.Job, true)
 		} else {
 			finishJobWithLogFn(ctx, results.Job, false)
+			analyzedTables[results.TableID.TableID] = struct{}{}
 		}
 	}
 	for _, task := range e.tasks {
@@ -203,7 +207,32 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			}
 		}
 	}
-	return statsHandle.Update(e.ctx.GetInfoSchema().(infoschema.InfoSchema))
+	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
+	if err = statsHandle.Update(is); err != nil {
+		return err
+	}
+	if variable.StatsHistoryMaxVersions.Load() > 0 {
+		recordHistoricalStats(ctx, statsHandle, is, analyzedTables)
+	}
+	return nil
+}
+
+// recordHistoricalStats saves the stats of the analyzed tables to the stats history. The failures are only logged
+// because the stats themselves have been saved.
+func recordHistoricalStats(ctx context.Context, statsHandle *handle.Handle, is infoschema.InfoSchema, tableIDs map[int64]struct{}) {
+	for tableID := range tableIDs {
+		tbl, ok := is.TableByID(tableID)
+		if !ok {
+			continue
+		}
+		db, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		if _, err := statsHandle.RecordHistoricalStatsToStorage(db.Name.O, tbl.Meta()); err != nil {
+			logutil.Logger(ctx).Warn("record historical stats failed", zap.Int64("table_id", tableID), zap.Error(err))
+		}
+	}
 }
 
 func getBuildStatsConcurrency(ctx sessionctx.Context) (int, error) {
@@ -321,7 +321,7 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 		}
 
 		e.handles = make([]kv.Handle, 0, len(toFetchIndexKeys))
-		if e.tblInfo.Partition != nil {
+		if e.tblInfo.GetPartitionInfo() != nil {
 			e.physIDs = make([]int64, 0, len(toFetchIndexKeys))
 		}
 		for _, key := range toFetchIndexKeys {
@@ -337,7 +337,7 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 			if rc {
 				indexKeys = append(indexKeys, key)
 			}
-			if e.tblInfo.Partition != nil {
+			if e.tblInfo.GetPartitionInfo() != nil {
 				pid := tablecodec.DecodeTableID(key)
 				e.physIDs = append(e.physIDs, pid)
 				if e.lock {
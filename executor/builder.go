@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor/aggfuncs"
@@ -424,6 +425,7 @@ func (b *executorBuilder) buildCheckTable(v *plannercore.CheckTable) Executor {
 		exitCh:       make(chan struct{}),
 		retCh:        make(chan error, len(readerExecs)),
 		checkIndex:   v.CheckIndex,
+		mvIndexInfos: v.MultiValuedIndexInfos,
 	}
 	return e
 }
@@ -827,6 +829,8 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
 		rowLen:                    v.RowLen,
+		fkChecker:                 newForeignKeyChecker(b.ctx, b.is),
+		constraintChecker:         newCheckConstraintChecker(b.ctx),
 	}
 	err := ivs.initInsertColumns()
 	if err != nil {
@@ -851,12 +855,14 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) Executor {
 		return nil
 	}
 	insertVal := &InsertValues{
-		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
-		Table:        tbl,
-		Columns:      v.Columns,
-		GenExprs:     v.GenCols.Exprs,
-		isLoadData:   true,
-		txnInUse:     sync.Mutex{},
+		baseExecutor:      newBaseExecutor(b.ctx, nil, v.ID()),
+		Table:             tbl,
+		Columns:           v.Columns,
+		GenExprs:          v.GenCols.Exprs,
+		isLoadData:        true,
+		txnInUse:          sync.Mutex{},
+		fkChecker:         newForeignKeyChecker(b.ctx, b.is),
+		constraintChecker: newCheckConstraintChecker(b.ctx),
 	}
 	loadDataInfo := &LoadDataInfo{
 		row:                make([]types.Datum, 0, len(insertVal.insertColumns)),
@@ -1320,17 +1326,22 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 	if finalCon, partialCon := sessionVars.HashAggFinalConcurrency(), sessionVars.HashAggPartialConcurrency(); finalCon <= 0 || partialCon <= 0 || finalCon == 1 && partialCon == 1 {
 		e.isUnparallelExec = true
 	}
-	partialOrdinal := 0
+	spillable := !e.isUnparallelExec
+	spillColIdx := len(v.AggFuncs)
 	for i, aggDesc := range v.AggFuncs {
 		if e.isUnparallelExec {
 			e.PartialAggFuncs = append(e.PartialAggFuncs, aggfuncs.Build(b.ctx, aggDesc, i))
 		} else {
-			ordinal := []int{partialOrdinal}
-			partialOrdinal++
+			// The final aggregate functions merge the partial results directly, their arguments are only used
+			// to consume the spilled partial results. The partial result of the i-th aggregate function is
+			// spilled into the i-th column, except that the count of AVG is spilled into an extra column.
+			ordinal := []int{i}
 			if aggDesc.Name == ast.AggFuncAvg {
-				ordinal = append(ordinal, partialOrdinal+1)
-				partialOrdinal++
+				ordinal = []int{spillColIdx, i}
+				spillColIdx++
 			}
+			e.spillColIdxs = append(e.spillColIdxs, ordinal)
+			spillable = spillable && isSpillableAggFunc(aggDesc)
 			partialAggDesc, finalDesc := aggDesc.Split(ordinal)
 			partialAggFunc := aggfuncs.Build(b.ctx, partialAggDesc, i)
 			finalAggFunc := aggfuncs.Build(b.ctx, finalDesc, i)
@@ -1349,6 +1360,18 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 		}
 	}
 
+	if spillable {
+		e.spillFieldTypes = make([]*types.FieldType, 0, spillColIdx+1)
+		for _, aggDesc := range v.AggFuncs {
+			e.spillFieldTypes = append(e.spillFieldTypes, aggDesc.RetTp)
+		}
+		for i := len(v.AggFuncs); i < spillColIdx; i++ {
+			e.spillFieldTypes = append(e.spillFieldTypes, types.NewFieldType(mysql.TypeLonglong))
+		}
+		// The last column is the group key.
+		e.spillFieldTypes = append(e.spillFieldTypes, types.NewFieldType(mysql.TypeVarString))
+	}
+
 	executorCounterHashAggExec.Inc()
 	return e
 }
@@ -1604,7 +1627,12 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableClientErrorsSummaryGlobal),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByUser),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByHost),
-			strings.ToLower(infoschema.TableRegionLabel):
+			strings.ToLower(infoschema.TableRegionLabel),
+			strings.ToLower(infoschema.TableCheckConstraints),
+			strings.ToLower(infoschema.TableTiDBTTLTableStatus),
+			strings.ToLower(infoschema.TableInstancePlanCache),
+			strings.ToLower(infoschema.TableMemoryUsageOpsHistory),
+			strings.ToLower(infoschema.ClusterTableMemoryUsageOpsHistory):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -1967,6 +1995,8 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		tblID2table:               tblID2table,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
+		fkChecker:                 newForeignKeyChecker(b.ctx, b.is),
+		constraintChecker:         newCheckConstraintChecker(b.ctx),
 	}
 	return updateExec
 }
@@ -2009,6 +2039,7 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 		tblID2Table:    tblID2table,
 		IsMultiTable:   v.IsMultiTable,
 		tblColPosInfos: v.TblColPosInfos,
+		fkChecker:      newForeignKeyChecker(b.ctx, b.is),
 	}
 	return deleteExec
 }
@@ -2849,7 +2880,7 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 		storeType:      v.StoreType,
 		batchCop:       v.BatchCop,
 	}
-	if tbl.Meta().Partition != nil {
+	if tbl.Meta().GetPartitionInfo() != nil {
 		e.extraPIDColumnIndex = extraPIDColumnIndex(v.Schema())
 	}
 	e.buildVirtualColumnInfo()
@@ -4036,7 +4067,10 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 		resultColIdx++
 	}
 
-	if b.ctx.GetSessionVars().EnablePipelinedWindowExec {
+	// The pipelined window executor has to buffer the whole partition if the frame ends at the end of the partition,
+	// use the window executor instead in that case so that the partition can be spilled to disk.
+	needWholePartition := v.Frame == nil || v.Frame.End.UnBounded
+	if b.ctx.GetSessionVars().EnablePipelinedWindowExec && !(needWholePartition && config.GetGlobalConfig().OOMUseTmpStorage) {
 		exec := &PipelinedWindowExec{
 			baseExecutor:   base,
 			groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
@@ -0,0 +1,78 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+// checkConstraintChecker enforces the CHECK constraints for the INSERT, REPLACE,
+// UPDATE and LOAD DATA executors. The constraints are only checked for the rows
+// written by the statements, the rows written by the DDL backfill and the
+// internal writes are not re-evaluated.
+type checkConstraintChecker struct {
+	sctx sessionctx.Context
+
+	// constraints caches the writable check constraints of the tables, the key is the table ID.
+	constraints map[int64][]*table.Constraint
+}
+
+func newCheckConstraintChecker(sctx sessionctx.Context) *checkConstraintChecker {
+	return &checkConstraintChecker{
+		sctx:        sctx,
+		constraints: make(map[int64][]*table.Constraint),
+	}
+}
+
+// checkRow checks whether the row to be written to the table satisfies the check constraints.
+func (cc *checkConstraintChecker) checkRow(t table.Table, row []types.Datum) error {
+	if len(t.Meta().Constraints) == 0 {
+		return nil
+	}
+	constraints, err := cc.getConstraints(t.Meta())
+	if err != nil {
+		return err
+	}
+	for _, c := range constraints {
+		if err := c.CheckRow(cc.sctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cc *checkConstraintChecker) getConstraints(tblInfo *model.TableInfo) ([]*table.Constraint, error) {
+	if constraints, ok := cc.constraints[tblInfo.ID]; ok {
+		return constraints, nil
+	}
+	constraints := make([]*table.Constraint, 0, len(tblInfo.Constraints))
+	for _, constraintInfo := range tblInfo.Constraints {
+		if constraintInfo.State == model.StateNone {
+			continue
+		}
+		constraint, err := table.ToConstraint(cc.sctx, constraintInfo, tblInfo)
+		if err != nil {
+			return nil, err
+		}
+		if constraint.IsWritable() {
+			constraints = append(constraints, constraint)
+		}
+	}
+	cc.constraints[tblInfo.ID] = constraints
+	return constraints, nil
+}
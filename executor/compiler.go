@@ -94,6 +94,8 @@ func (c *Compiler) Compile(ctx context.Context, stmtNode ast.StmtNode) (*ExecStm
 		Ctx:           c.Ctx,
 		OutputNames:   names,
 		Ti:            &TelemetryInfo{},
+
+		checkedOutPlans: plannercore.TakeCheckedOutPlans(c.Ctx),
 	}, nil
 }
 
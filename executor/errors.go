@@ -38,6 +38,9 @@ var (
 	ErrCannotUser                    = dbterror.ClassExecutor.NewStd(mysql.ErrCannotUser)
 	ErrGrantRole                     = dbterror.ClassExecutor.NewStd(mysql.ErrGrantRole)
 	ErrPasswordFormat                = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordFormat)
+	ErrNotValidPassword              = dbterror.ClassExecutor.NewStd(mysql.ErrNotValidPassword)
+	ErrCredentialsContradictHistory  = dbterror.ClassExecutor.NewStd(mysql.ErrCredentialsContradictToHistory)
+	ErrSetPasswordAuthPlugin         = dbterror.ClassExecutor.NewStd(mysql.ErrSetPasswordAuthPlugin)
 	ErrCantChangeTxCharacteristics   = dbterror.ClassExecutor.NewStd(mysql.ErrCantChangeTxCharacteristics)
 	ErrPsManyParam                   = dbterror.ClassExecutor.NewStd(mysql.ErrPsManyParam)
 	ErrAdminCheckTable               = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckTable)
@@ -62,6 +65,10 @@ var (
 	ErrDataInConsistentExtraIndex    = dbterror.ClassExecutor.NewStd(mysql.ErrDataInConsistentExtraIndex)
 	ErrDataInConsistentMisMatchIndex = dbterror.ClassExecutor.NewStd(mysql.ErrDataInConsistentMisMatchIndex)
 
+	ErrNoReferencedRow2               = dbterror.ClassExecutor.NewStd(mysql.ErrNoReferencedRow2)
+	ErrRowIsReferenced2               = dbterror.ClassExecutor.NewStd(mysql.ErrRowIsReferenced2)
+	ErrForeignKeyCascadeDepthExceeded = dbterror.ClassExecutor.NewStd(mysql.ErrForeignKeyCascadeDepthExceeded)
+
 	errUnsupportedFlashbackTmpTable = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("Recover/flashback table is not supported on temporary tables", nil))
 	errTruncateWrongInsertValue     = dbterror.ClassTable.NewStdErr(mysql.ErrTruncatedWrongValue, parser_mysql.Message("Incorrect %-.32s value: '%-.128s' for column '%.192s' at row %d", nil))
 )
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
@@ -495,7 +496,7 @@ func (e *DDLJobRetriever) appendJobToChunk(req *chunk.Chunk, job *model.Job, che
 	req.AppendInt64(0, job.ID)
 	req.AppendString(1, schemaName)
 	req.AppendString(2, tableName)
-	req.AppendString(3, job.Type.String())
+	req.AppendString(3, ddlutil.ActionTypeString(job.Type))
 	req.AppendString(4, job.SchemaState.String())
 	req.AppendInt64(5, job.SchemaID)
 	req.AppendInt64(6, job.TableID)
@@ -665,6 +666,8 @@ type CheckTableExec struct {
 	exitCh     chan struct{}
 	retCh      chan error
 	checkIndex bool
+	// mvIndexInfos are the multi-valued indexes, they are checked against the table records.
+	mvIndexInfos []*model.IndexInfo
 }
 
 // Open implements the Executor Open interface.
@@ -741,11 +744,24 @@ func (e *CheckTableExec) handlePanic(r interface{}) {
 
 // Next implements the Executor Next interface.
 func (e *CheckTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
-	if e.done || len(e.srcs) == 0 {
+	if e.done || (len(e.srcs) == 0 && len(e.mvIndexInfos) == 0) {
 		return nil
 	}
 	defer func() { e.done = true }()
 
+	for _, idxInfo := range e.mvIndexInfos {
+		err := e.checkMultiValuedIndex(idxInfo)
+		if err != nil && admin.ErrDataInConsistent.Equal(err) {
+			return ErrAdminCheckTable.GenWithStack("%v err:%v", e.table.Meta().Name, err)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(e.srcs) == 0 {
+		return nil
+	}
+
 	idxNames := make([]string, 0, len(e.indexInfos))
 	for _, idx := range e.indexInfos {
 		idxNames = append(idxNames, idx.Name.O)
@@ -820,6 +836,30 @@ func (e *CheckTableExec) checkTableRecord(idxOffset int) error {
 	return nil
 }
 
+func (e *CheckTableExec) checkMultiValuedIndex(idxInfo *model.IndexInfo) error {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	check := func(t table.Table, physicalID int64) error {
+		idx := tables.NewIndex(physicalID, e.table.Meta(), idxInfo)
+		if err := admin.CheckRecordAndIndex(e.ctx, txn, t, idx); err != nil {
+			return errors.Trace(err)
+		}
+		return admin.CheckMultiValuedIndexCount(e.ctx, txn, t, idx)
+	}
+	info := e.table.Meta().GetPartitionInfo()
+	if info == nil {
+		return check(e.table, e.table.Meta().ID)
+	}
+	for _, def := range info.Definitions {
+		if err := check(e.table.(table.PartitionedTable).GetPartition(def.ID), def.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ShowSlowExec represents the executor of showing the slow queries.
 // It is build from the "admin show slow" statement:
 //	admin show slow top [internal | all] N
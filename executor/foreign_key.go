@@ -0,0 +1,805 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// maxForeignKeyCascadeDepth is the max depth of the cascading referential actions, it's the same as MySQL.
+const maxForeignKeyCascadeDepth = 15
+
+// foreignKeyChecker enforces the FOREIGN KEY constraints for the DML executors.
+// When a child row is written, it checks the referenced parent row exists.
+// When a parent row is updated or deleted, it checks or applies the ON UPDATE / ON DELETE
+// referential actions of the foreign keys referring to it.
+// A nil foreignKeyChecker does nothing, it's used when foreign_key_checks is OFF.
+type foreignKeyChecker struct {
+	sctx sessionctx.Context
+	is   infoschema.InfoSchema
+
+	// referredFKs caches the foreign keys referring to a table, the key is the parent table ID.
+	referredFKs map[int64][]*referredFK
+	// dbNames caches the schema names of the tables, the key is the table ID.
+	dbNames map[int64]model.CIStr
+	// genExprs caches the generated column expressions of the child tables, the key is the table ID.
+	genExprs map[int64][]expression.Expression
+	// depth is the depth of the current cascading referential action.
+	depth int
+	// memTracker tracks the memory usage of the child rows changed by the referential actions.
+	memTracker *memory.Tracker
+}
+
+// referredFK is a foreign key of the child table which refers to a parent table.
+type referredFK struct {
+	fk    *model.FKInfo
+	child table.Table
+}
+
+func newForeignKeyChecker(sctx sessionctx.Context, is infoschema.InfoSchema) *foreignKeyChecker {
+	if !sctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	fkc := &foreignKeyChecker{
+		sctx:        sctx,
+		is:          is,
+		referredFKs: make(map[int64][]*referredFK),
+		dbNames:     make(map[int64]model.CIStr),
+		genExprs:    make(map[int64][]expression.Expression),
+		memTracker:  memory.NewTracker(memory.LabelForForeignKey, -1),
+	}
+	fkc.memTracker.AttachTo(sctx.GetSessionVars().StmtCtx.MemTracker)
+	return fkc
+}
+
+// checkInsert checks the parent rows of the row to be inserted exist.
+func (fkc *foreignKeyChecker) checkInsert(ctx context.Context, t table.Table, row []types.Datum) error {
+	if fkc == nil {
+		return nil
+	}
+	for _, fk := range t.Meta().ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
+		if err := fkc.checkParentExists(ctx, t, fk, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUpdate checks a row update before it's written. The parent rows of the modified
+// foreign key columns must exist, and the referenced columns can't be changed if there are
+// child rows and the ON UPDATE action is RESTRICT or NO ACTION.
+func (fkc *foreignKeyChecker) checkUpdate(ctx context.Context, t table.Table, h kv.Handle, oldRow, newRow []types.Datum, modified []bool) error {
+	if fkc == nil {
+		return nil
+	}
+	for _, fk := range t.Meta().ForeignKeys {
+		if fk.State != model.StatePublic || !fkColsModified(t, fk.Cols, modified) {
+			continue
+		}
+		if err := fkc.checkParentExists(ctx, t, fk, newRow); err != nil {
+			return err
+		}
+	}
+	fks, err := fkc.getReferredFKs(t)
+	if err != nil {
+		return err
+	}
+	for _, rfk := range fks {
+		if !isRestrictAction(rfk.fk.OnUpdate) || !fkColsModified(t, rfk.fk.RefCols, modified) {
+			continue
+		}
+		if err := fkc.checkNoChildRows(ctx, t, h, rfk, oldRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDelete checks there is no child row referring to the row to be deleted if the
+// ON DELETE action is RESTRICT or NO ACTION.
+func (fkc *foreignKeyChecker) checkDelete(ctx context.Context, t table.Table, h kv.Handle, row []types.Datum) error {
+	if fkc == nil {
+		return nil
+	}
+	fks, err := fkc.getReferredFKs(t)
+	if err != nil {
+		return err
+	}
+	for _, rfk := range fks {
+		if !isRestrictAction(rfk.fk.OnDelete) {
+			continue
+		}
+		if err := fkc.checkNoChildRows(ctx, t, h, rfk, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onUpdate applies the ON UPDATE CASCADE / SET NULL actions to the child rows after the parent row is updated.
+func (fkc *foreignKeyChecker) onUpdate(ctx context.Context, t table.Table, h kv.Handle, oldRow, newRow []types.Datum, modified []bool) error {
+	if fkc == nil {
+		return nil
+	}
+	fks, err := fkc.getReferredFKs(t)
+	if err != nil {
+		return err
+	}
+	for _, rfk := range fks {
+		action := ast.ReferOptionType(rfk.fk.OnUpdate)
+		if isRestrictAction(rfk.fk.OnUpdate) || !fkColsModified(t, rfk.fk.RefCols, modified) {
+			continue
+		}
+		var newVals []types.Datum
+		if action == ast.ReferOptionCascade {
+			if newVals, err = fkColValues(t, rfk.fk.RefCols, newRow); err != nil {
+				return err
+			}
+		}
+		if err = fkc.cascade(ctx, t, h, rfk, oldRow, newVals, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onDelete applies the ON DELETE CASCADE / SET NULL actions to the child rows after the parent row is deleted.
+func (fkc *foreignKeyChecker) onDelete(ctx context.Context, t table.Table, h kv.Handle, row []types.Datum) error {
+	if fkc == nil {
+		return nil
+	}
+	fks, err := fkc.getReferredFKs(t)
+	if err != nil {
+		return err
+	}
+	for _, rfk := range fks {
+		if isRestrictAction(rfk.fk.OnDelete) {
+			continue
+		}
+		if err = fkc.cascade(ctx, t, h, rfk, row, nil, ast.ReferOptionType(rfk.fk.OnDelete)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cascade applies the referential action to the child rows referring to the old parent row.
+// For ON UPDATE CASCADE, newVals are the new values of the referenced columns.
+func (fkc *foreignKeyChecker) cascade(ctx context.Context, parent table.Table, h kv.Handle, rfk *referredFK, oldParentRow, newVals []types.Datum, action ast.ReferOptionType) error {
+	vals, err := fkColValues(parent, rfk.fk.RefCols, oldParentRow)
+	if err != nil || hasNullValue(vals) {
+		return err
+	}
+	type childRow struct {
+		h   kv.Handle
+		row []types.Datum
+	}
+	var rows []childRow
+	err = fkc.iterRows(ctx, rfk.child, rfk.fk.Cols, vals, true, func(childHandle kv.Handle, row []types.Datum) (bool, error) {
+		if parent.Meta().ID != rfk.child.Meta().ID || !childHandle.Equal(h) {
+			rows = append(rows, childRow{childHandle, row})
+		}
+		return true, nil
+	})
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	if fkc.depth >= maxForeignKeyCascadeDepth {
+		return ErrForeignKeyCascadeDepthExceeded.GenWithStackByArgs(maxForeignKeyCascadeDepth)
+	}
+	txn, err := fkc.sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	memUsageOfRows := types.EstimatedMemUsage(rows[0].row, len(rows))
+	fkc.memTracker.Consume(memUsageOfRows)
+	defer fkc.memTracker.Consume(-memUsageOfRows)
+	if fkc.depth == 0 {
+		// The nested referential actions are included by the outermost one.
+		memUsageOfTxnState := txn.Size()
+		defer func() { fkc.memTracker.Consume(int64(txn.Size() - memUsageOfTxnState)) }()
+	}
+	fkc.depth++
+	defer func() { fkc.depth-- }()
+	for _, r := range rows {
+		if action == ast.ReferOptionCascade && newVals == nil {
+			err = fkc.removeChildRow(ctx, rfk.child, r.h, r.row)
+		} else {
+			err = fkc.updateChildRow(ctx, rfk.child, r.h, rfk.fk, r.row, newVals)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fkc *foreignKeyChecker) removeChildRow(ctx context.Context, t table.Table, h kv.Handle, row []types.Datum) error {
+	if err := fkc.checkDelete(ctx, t, h, row); err != nil {
+		return err
+	}
+	if err := t.RemoveRecord(fkc.sctx, h, row); err != nil {
+		return err
+	}
+	return fkc.onDelete(ctx, t, h, row)
+}
+
+// updateChildRow sets the foreign key columns of the child row to newVals, or NULL if newVals is nil.
+func (fkc *foreignKeyChecker) updateChildRow(ctx context.Context, t table.Table, h kv.Handle, fk *model.FKInfo, oldRow, newVals []types.Datum) error {
+	newRow := make([]types.Datum, len(oldRow))
+	copy(newRow, oldRow)
+	modified := make([]bool, len(oldRow))
+	handleChanged := false
+	for i, name := range fk.Cols {
+		col := table.FindCol(t.Cols(), name.L)
+		if col == nil {
+			return errors.Errorf("foreign key column %s not found in table %s", name.O, t.Meta().Name.O)
+		}
+		val := types.NewDatum(nil)
+		if newVals != nil {
+			var err error
+			if val, err = table.CastValue(fkc.sctx, newVals[i], col.ToInfo(), false, false); err != nil {
+				return err
+			}
+		} else if err := col.CheckNotNull(&val); err != nil {
+			return err
+		}
+		newRow[col.Offset] = val
+		modified[col.Offset] = true
+		if col.IsPKHandleColumn(t.Meta()) || col.IsCommonHandleColumn(t.Meta()) {
+			handleChanged = true
+		}
+	}
+	if err := fkc.fillGeneratedColumns(t, newRow, modified); err != nil {
+		return err
+	}
+	if err := fkc.checkUpdate(ctx, t, h, oldRow, newRow, modified); err != nil {
+		return err
+	}
+	if handleChanged {
+		if err := t.RemoveRecord(fkc.sctx, h, oldRow); err != nil {
+			return err
+		}
+		if _, err := t.AddRecord(fkc.sctx, newRow, table.IsUpdate, table.WithCtx(ctx)); err != nil {
+			return err
+		}
+	} else if err := t.UpdateRecord(ctx, fkc.sctx, h, oldRow, newRow, modified); err != nil {
+		return err
+	}
+	return fkc.onUpdate(ctx, t, h, oldRow, newRow, modified)
+}
+
+// fillGeneratedColumns re-evaluates the generated columns of a child row changed by a referential action.
+func (fkc *foreignKeyChecker) fillGeneratedColumns(t table.Table, row []types.Datum, modified []bool) error {
+	exprs, err := fkc.getGenExprs(t)
+	if err != nil || len(exprs) == 0 {
+		return err
+	}
+	gIdx := 0
+	for _, col := range t.WritableCols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		val, err := exprs[gIdx].Eval(chunk.MutRowFromDatums(row).ToRow())
+		if err != nil {
+			return err
+		}
+		if row[col.Offset], err = table.CastValue(fkc.sctx, val, col.ToInfo(), false, false); err != nil {
+			return err
+		}
+		modified[col.Offset] = true
+		gIdx++
+	}
+	return nil
+}
+
+// checkParentExists checks the parent row referred by the foreign key of the child row exists.
+func (fkc *foreignKeyChecker) checkParentExists(ctx context.Context, child table.Table, fk *model.FKInfo, row []types.Datum) error {
+	vals, err := fkColValues(child, fk.Cols, row)
+	if err != nil {
+		return err
+	}
+	// The constraint is satisfied if any column of the foreign key is NULL, the same as MySQL.
+	if hasNullValue(vals) {
+		return nil
+	}
+	parent, err := fkc.is.TableByName(fkc.dbName(child.Meta()), fk.RefTable)
+	if err != nil {
+		if infoschema.ErrTableNotExists.Equal(err) {
+			return ErrNoReferencedRow2.GenWithStackByArgs(fkc.fkString(child.Meta(), fk))
+		}
+		return err
+	}
+	if parent.Meta().ID == child.Meta().ID {
+		// The row refers to itself.
+		refVals, err := fkColValues(parent, fk.RefCols, row)
+		if err != nil {
+			return err
+		}
+		if equal, err := fkValuesEqual(fkc.sctx.GetSessionVars().StmtCtx, refVals, vals); err != nil || equal {
+			return err
+		}
+	}
+	found := false
+	// The parent row is locked only in pessimistic transactions, see fkGetter.
+	lock := fkc.sctx.GetSessionVars().TxnCtx.IsPessimistic
+	err = fkc.iterRows(ctx, parent, fk.RefCols, vals, lock, func(kv.Handle, []types.Datum) (bool, error) {
+		found = true
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNoReferencedRow2.GenWithStackByArgs(fkc.fkString(child.Meta(), fk))
+	}
+	return nil
+}
+
+// checkNoChildRows checks there is no child row referring to the parent row.
+func (fkc *foreignKeyChecker) checkNoChildRows(ctx context.Context, parent table.Table, h kv.Handle, rfk *referredFK, parentRow []types.Datum) error {
+	vals, err := fkColValues(parent, rfk.fk.RefCols, parentRow)
+	if err != nil || hasNullValue(vals) {
+		return err
+	}
+	found := false
+	err = fkc.iterRows(ctx, rfk.child, rfk.fk.Cols, vals, true, func(childHandle kv.Handle, _ []types.Datum) (bool, error) {
+		// A row referring to itself doesn't block its own deletion.
+		found = parent.Meta().ID != rfk.child.Meta().ID || !childHandle.Equal(h)
+		return !found, nil
+	})
+	if err != nil {
+		return err
+	}
+	if found {
+		return ErrRowIsReferenced2.GenWithStackByArgs(fkc.fkString(rfk.child.Meta(), rfk.fk))
+	}
+	return nil
+}
+
+// iterRows calls fn on every row of t whose cols equal vals, until fn returns false.
+// It looks the rows up by the handle or an index whose leading columns are cols if there
+// is any, otherwise it scans the whole table. If lock is true, the rows passed to fn are
+// locked in the transaction, so they can't be changed by other transactions before it finishes.
+func (fkc *foreignKeyChecker) iterRows(ctx context.Context, t table.Table, cols []model.CIStr, vals []types.Datum, lock bool,
+	fn func(h kv.Handle, row []types.Datum) (bool, error)) error {
+	sc := fkc.sctx.GetSessionVars().StmtCtx
+	tblInfo := t.Meta()
+	colInfos := make([]*table.Column, len(cols))
+	keyVals := make([]types.Datum, len(vals))
+	for i, name := range cols {
+		colInfos[i] = table.FindCol(t.Cols(), name.L)
+		if colInfos[i] == nil {
+			return errors.Errorf("foreign key column %s not found in table %s", name.O, tblInfo.Name.O)
+		}
+		v, err := vals[i].ConvertTo(sc, &colInfos[i].FieldType)
+		if err != nil {
+			return err
+		}
+		keyVals[i] = v
+	}
+	txn, err := fkc.sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	getter := &fkGetter{fkc: fkc, txn: txn, lock: lock}
+	genExprs, err := fkc.getGenExprs(t)
+	if err != nil {
+		return err
+	}
+	visit := func(pt table.Table, h kv.Handle) (bool, error) {
+		row, err := getOldRow(ctx, fkc.sctx, getter, pt, h, genExprs)
+		if err != nil {
+			if kv.IsErrNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		rowVals := make([]types.Datum, len(colInfos))
+		for i, col := range colInfos {
+			rowVals[i] = row[col.Offset]
+		}
+		if equal, err := fkValuesEqual(sc, keyVals, rowVals); err != nil || !equal {
+			return err == nil, err
+		}
+		return fn(h, row)
+	}
+
+	if tblInfo.PKIsHandle && len(cols) == 1 && colInfos[0].IsPKHandleColumn(tblInfo) {
+		// Point lookup by the int handle.
+		for _, pt := range physicalTables(t) {
+			if more, err := visit(pt, kv.IntHandle(keyVals[0].GetInt64())); err != nil || !more {
+				return err
+			}
+		}
+		return nil
+	}
+	idxInfo, encodedVals, err := fkc.findLookupKey(t, colInfos, keyVals)
+	if err != nil {
+		return err
+	}
+	for _, pt := range physicalTables(t) {
+		pid := pt.(table.PhysicalTable).GetPhysicalID()
+		var (
+			prefix     kv.Key
+			filterCols []*table.Column
+		)
+		switch {
+		case idxInfo != nil:
+			prefix = tablecodec.EncodeIndexSeekKey(pid, idxInfo.ID, encodedVals)
+		case encodedVals != nil:
+			// The columns are the leading columns of the clustered index.
+			prefix = tablecodec.EncodeRowKey(pid, encodedVals)
+		default:
+			prefix = tablecodec.GenTableRecordPrefix(pid)
+			filterCols = colInfos
+		}
+		// The handles are read in batches, so the transaction memory buffer isn't
+		// changed by the locking reads while it's iterated.
+		for start := prefix; start != nil; {
+			var handles []kv.Handle
+			handles, start, err = fkc.scanHandles(txn, pt, start, prefix.PrefixNext(), idxInfo, filterCols, keyVals)
+			if err != nil {
+				return err
+			}
+			for _, h := range handles {
+				if more, err := visit(pt, h); err != nil || !more {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fkScanBatchSize is the max number of handles read by a scanHandles call.
+const fkScanBatchSize = 64
+
+// scanHandles reads the handles in the range [start, end) of the index, or the records of t if idxInfo is nil.
+// If filterCols is not nil, only the records whose filterCols equal vals are returned. The start key of the
+// next batch is returned, it's nil if the range is exhausted.
+func (fkc *foreignKeyChecker) scanHandles(txn kv.Transaction, t table.Table, start, end kv.Key, idxInfo *model.IndexInfo,
+	filterCols []*table.Column, vals []types.Datum) (handles []kv.Handle, next kv.Key, err error) {
+	sc := fkc.sctx.GetSessionVars().StmtCtx
+	it, err := fkc.newIter(txn, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	var decodeCols []*table.Column
+	if filterCols != nil {
+		// DecodeRawRowData fills the default values by the column offsets.
+		decodeCols = make([]*table.Column, len(t.WritableCols()))
+		for _, col := range filterCols {
+			decodeCols[col.Offset] = col
+		}
+	}
+	rowVals := make([]types.Datum, len(filterCols))
+	for ; it.Valid(); err = it.Next() {
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(handles) >= fkScanBatchSize {
+			return handles, it.Key().Clone(), nil
+		}
+		// The key was deleted in the transaction.
+		if len(it.Value()) == 0 {
+			continue
+		}
+		var h kv.Handle
+		if idxInfo != nil {
+			h, err = tablecodec.DecodeIndexHandle(it.Key(), it.Value(), len(idxInfo.Columns))
+		} else {
+			h, err = tablecodec.DecodeRowKey(it.Key())
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if filterCols != nil {
+			row, _, err := tables.DecodeRawRowData(fkc.sctx, t.Meta(), h, decodeCols, it.Value())
+			if err != nil {
+				return nil, nil, err
+			}
+			for i, col := range filterCols {
+				rowVals[i] = row[col.Offset]
+			}
+			if equal, err := fkValuesEqual(sc, vals, rowVals); err != nil || !equal {
+				if err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+		}
+		handles = append(handles, h)
+	}
+	return handles, nil, err
+}
+
+// newIter creates an iterator over the transaction. Like the DML readers, a pessimistic transaction
+// reads the latest data at its for update ts.
+func (fkc *foreignKeyChecker) newIter(txn kv.Transaction, start, end kv.Key) (kv.Iterator, error) {
+	txnCtx := fkc.sctx.GetSessionVars().TxnCtx
+	if !txnCtx.IsPessimistic {
+		return txn.Iter(start, end)
+	}
+	snapshot := fkc.sctx.GetStore().GetSnapshot(kv.NewVersion(txnCtx.GetForUpdateTS()))
+	snapIter, err := snapshot.Iter(start, end)
+	if err != nil {
+		return nil, err
+	}
+	dirtyIter, err := txn.GetMemBuffer().Iter(start, end)
+	if err != nil {
+		snapIter.Close()
+		return nil, err
+	}
+	return NewUnionIter(dirtyIter, snapIter, false)
+}
+
+// findLookupKey finds the way to look up the rows whose cols equal vals. If the cols are the leading columns
+// of a public index, the index and the encoded values are returned; if the cols are the leading columns of
+// the clustered index, only the encoded values are returned; if the cols are the int handle, nothing is returned.
+func (fkc *foreignKeyChecker) findLookupKey(t table.Table, cols []*table.Column, vals []types.Datum) (*model.IndexInfo, []byte, error) {
+	tblInfo := t.Meta()
+	if tblInfo.PKIsHandle && len(cols) == 1 && cols[0].IsPKHandleColumn(tblInfo) {
+		return nil, nil, nil
+	}
+	sc := fkc.sctx.GetSessionVars().StmtCtx
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic || idx.Global || len(idx.Columns) < len(cols) {
+			continue
+		}
+		match := true
+		for i, col := range cols {
+			if idx.Columns[i].Offset != col.Offset {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		keyVals := make([]types.Datum, len(vals))
+		copy(keyVals, vals)
+		tablecodec.TruncateIndexValues(tblInfo, idx, keyVals)
+		encoded, err := codec.EncodeKey(sc, nil, keyVals...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if idx.Primary && tblInfo.IsCommonHandle {
+			return nil, encoded, nil
+		}
+		return idx, encoded, nil
+	}
+	return nil, nil, nil
+}
+
+// getReferredFKs returns the public foreign keys referring to the parent table. A foreign key only records the
+// name of the referred table, which is in the schema of the child table, so only the tables in the schema of the
+// parent table are scanned. The DDL rejects the foreign keys referring to a table in another schema.
+func (fkc *foreignKeyChecker) getReferredFKs(parent table.Table) ([]*referredFK, error) {
+	tblInfo := parent.Meta()
+	if fks, ok := fkc.referredFKs[tblInfo.ID]; ok {
+		return fks, nil
+	}
+	var fks []*referredFK
+	dbName := fkc.dbName(tblInfo)
+	for _, child := range fkc.is.SchemaTables(dbName) {
+		for _, fk := range child.Meta().ForeignKeys {
+			if fk.State == model.StatePublic && fk.RefTable.L == tblInfo.Name.L {
+				fks = append(fks, &referredFK{fk: fk, child: child})
+			}
+		}
+	}
+	fkc.referredFKs[tblInfo.ID] = fks
+	return fks, nil
+}
+
+func (fkc *foreignKeyChecker) getGenExprs(t table.Table) ([]expression.Expression, error) {
+	tblInfo := t.Meta()
+	if exprs, ok := fkc.genExprs[tblInfo.ID]; ok {
+		return exprs, nil
+	}
+	var exprs []expression.Expression
+	for _, col := range t.WritableCols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, err := expression.ParseSimpleExprWithTableInfo(fkc.sctx, col.GeneratedExprString, tblInfo)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	fkc.genExprs[tblInfo.ID] = exprs
+	return exprs, nil
+}
+
+func (fkc *foreignKeyChecker) dbName(tblInfo *model.TableInfo) model.CIStr {
+	if name, ok := fkc.dbNames[tblInfo.ID]; ok {
+		return name
+	}
+	var name model.CIStr
+	if db, ok := fkc.is.SchemaByTable(tblInfo); ok {
+		name = db.Name
+	}
+	fkc.dbNames[tblInfo.ID] = name
+	return name
+}
+
+// fkString formats the foreign key for the error messages, e.g.
+// `test`.`child`, CONSTRAINT `fk_1` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`) ON DELETE CASCADE
+func (fkc *foreignKeyChecker) fkString(child *model.TableInfo, fk *model.FKInfo) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)",
+		fkc.dbName(child).O, child.Name.O, fk.Name.O, joinColNames(fk.Cols), fk.RefTable.O, joinColNames(fk.RefCols))
+	if ast.ReferOptionType(fk.OnDelete) != ast.ReferOptionNoOption {
+		fmt.Fprintf(&buf, " ON DELETE %s", ast.ReferOptionType(fk.OnDelete))
+	}
+	if ast.ReferOptionType(fk.OnUpdate) != ast.ReferOptionNoOption {
+		fmt.Fprintf(&buf, " ON UPDATE %s", ast.ReferOptionType(fk.OnUpdate))
+	}
+	return buf.String()
+}
+
+// fkGetter is a kv.Getter which reads the rows for checking the foreign keys. In pessimistic
+// transactions the latest value is returned.
+//
+// If lock is true, the keys it reads are locked, a missing key is locked as well, so the check
+// result of the foreign key holds until the transaction finishes. TiKV has no shared lock, so the
+// parent rows are locked exclusively in pessimistic transactions, and the transactions writing the
+// child rows of the same parent row wait for each other. In optimistic transactions, the parent
+// rows are read without locking, so the transactions writing the child rows don't conflict with
+// each other, at the cost that a parent row deleted by a concurrent transaction isn't detected.
+type fkGetter struct {
+	fkc  *foreignKeyChecker
+	txn  kv.Transaction
+	lock bool
+}
+
+// Get implements the kv.Getter interface.
+func (g *fkGetter) Get(ctx context.Context, key kv.Key) ([]byte, error) {
+	val, err := g.txn.GetMemBuffer().Get(ctx, key)
+	if err == nil {
+		if len(val) == 0 {
+			return nil, kv.ErrNotExist
+		}
+		return val, nil
+	}
+	if !kv.IsErrNotFound(err) {
+		return nil, err
+	}
+	vars := g.fkc.sctx.GetSessionVars()
+	txnCtx := vars.TxnCtx
+	if !g.lock {
+		if txnCtx.IsPessimistic {
+			return g.fkc.sctx.GetStore().GetSnapshot(kv.NewVersion(txnCtx.GetForUpdateTS())).Get(ctx, key)
+		}
+		return g.txn.Get(ctx, key)
+	}
+	lockCtx := newLockCtx(vars, vars.LockWaitTimeout)
+	if txnCtx.IsPessimistic {
+		lockCtx.InitReturnValues(1)
+	}
+	if err = g.txn.LockKeys(ctx, lockCtx, key); err != nil {
+		return nil, err
+	}
+	if txnCtx.IsPessimistic {
+		lockCtx.IterateValuesNotLocked(func(k, v []byte) {
+			txnCtx.SetPessimisticLockCache(kv.Key(k), v)
+		})
+		if val, ok := txnCtx.GetKeyInPessimisticLockCache(key); ok {
+			if len(val) == 0 {
+				return nil, kv.ErrNotExist
+			}
+			return val, nil
+		}
+	}
+	return g.txn.Get(ctx, key)
+}
+
+// physicalTables returns the partitions of a partitioned table, or the table itself.
+func physicalTables(t table.Table) []table.Table {
+	pt, ok := t.(table.PartitionedTable)
+	if !ok {
+		return []table.Table{t}
+	}
+	pi := t.Meta().GetPartitionInfo()
+	tbls := make([]table.Table, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		tbls = append(tbls, pt.GetPartition(def.ID))
+	}
+	return tbls
+}
+
+func fkColValues(t table.Table, cols []model.CIStr, row []types.Datum) ([]types.Datum, error) {
+	vals := make([]types.Datum, len(cols))
+	for i, name := range cols {
+		col := table.FindCol(t.Cols(), name.L)
+		if col == nil {
+			return nil, errors.Errorf("foreign key column %s not found in table %s", name.O, t.Meta().Name.O)
+		}
+		vals[i] = row[col.Offset]
+	}
+	return vals, nil
+}
+
+func fkColsModified(t table.Table, cols []model.CIStr, modified []bool) bool {
+	for _, name := range cols {
+		if col := table.FindCol(t.Cols(), name.L); col != nil && modified[col.Offset] {
+			return true
+		}
+	}
+	return false
+}
+
+// fkValuesEqual compares the values with the collations of a, which are the collations of the
+// looked up columns, the same as the index lookup.
+func fkValuesEqual(sc *stmtctx.StatementContext, a, b []types.Datum) (bool, error) {
+	for i := range a {
+		cmp, err := a[i].CompareDatum(sc, &b[i])
+		if err != nil || cmp != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func hasNullValue(vals []types.Datum) bool {
+	for _, v := range vals {
+		if v.IsNull() {
+			return true
+		}
+	}
+	return false
+}
+
+// isRestrictAction returns whether the referential action is checked before the parent row changes.
+// SET DEFAULT is rejected by InnoDB, we treat it as RESTRICT.
+func isRestrictAction(action int) bool {
+	switch ast.ReferOptionType(action) {
+	case ast.ReferOptionCascade, ast.ReferOptionSetNull:
+		return false
+	}
+	return true
+}
+
+func joinColNames(cols []model.CIStr) string {
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, "`"+col.O+"`")
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,209 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite8) TestForeignKeyCheckOnInsertAndUpdate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent(id int primary key, code varchar(10), unique key(code))")
+	tk.MustExec("create table fk_child(id int primary key, pid int, pcode varchar(10), key(pid), " +
+		"constraint fk_pid foreign key (pid) references fk_parent(id), " +
+		"constraint fk_pcode foreign key (pcode) references fk_parent(code))")
+
+	// foreign_key_checks is OFF by default, nothing is checked.
+	tk.MustExec("insert into fk_child values (1, 100, 'x')")
+	tk.MustExec("delete from fk_child")
+
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("insert into fk_parent values (1, 'a'), (2, 'b')")
+	tk.MustExec("insert into fk_child values (1, 1, 'a'), (2, null, null), (3, 2, null)")
+	tk.MustGetErrCode("insert into fk_child values (4, 3, null)", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("insert into fk_child values (4, 1, 'c')", errno.ErrNoReferencedRow2)
+	err := tk.ExecToErr("insert into fk_child values (4, 3, null)")
+	c.Assert(err.Error(), Equals, "[executor:1452]Cannot add or update a child row: a foreign key constraint fails "+
+		"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`))")
+	tk.MustGetErrCode("update fk_child set pid = 3 where id = 1", errno.ErrNoReferencedRow2)
+	tk.MustExec("update fk_child set pid = 2, pcode = 'b' where id = 1")
+	tk.MustExec("insert ignore into fk_child values (4, 3, null), (5, 1, null)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1452 Cannot add or update a child row: a foreign key constraint fails " +
+		"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`))"))
+	tk.MustQuery("select * from fk_child order by id").Check(testkit.Rows("1 2 b", "2 <nil> <nil>", "3 2 <nil>", "5 1 <nil>"))
+
+	// The parent rows inserted in the same transaction are visible.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into fk_parent values (3, 'c')")
+	tk.MustExec("insert into fk_child values (6, 3, 'c')")
+	tk.MustExec("commit")
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert into fk_parent values (4, 'd')")
+	tk.MustExec("insert into fk_child values (7, 4, 'd')")
+	tk.MustExec("commit")
+	tk.MustQuery("select count(*) from fk_child").Check(testkit.Rows("6"))
+
+	// The referenced rows can't be deleted or changed.
+	tk.MustGetErrCode("delete from fk_parent where id = 1", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("update fk_parent set code = 'z' where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("replace into fk_parent values (2, 'bb')", errno.ErrRowIsReferenced2)
+	tk.MustExec("update fk_parent set code = 'z' where id = 1")
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("delete from fk_parent where id = 1")
+	tk.MustQuery("select count(*) from fk_child where pid = 1").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite8) TestForeignKeyReferentialActions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("drop table if exists fk_c1, fk_c2, fk_c3, fk_p")
+	tk.MustExec("create table fk_p(id int primary key, name varchar(10))")
+	tk.MustExec("create table fk_c1(id int primary key, pid int, index(pid), foreign key (pid) references fk_p(id) on delete cascade on update cascade)")
+	tk.MustExec("create table fk_c2(id varchar(10) primary key clustered, pid int, foreign key (pid) references fk_p(id) on delete set null on update set null)")
+	tk.MustExec("create table fk_c3(id int primary key, c1 int, index(c1), foreign key (c1) references fk_c1(id) on delete cascade)")
+	tk.MustExec("insert into fk_p values (1, 'a'), (2, 'b'), (3, 'c')")
+	tk.MustExec("insert into fk_c1 values (10, 1), (11, 1), (12, 2)")
+	tk.MustExec("insert into fk_c2 values ('x', 1), ('y', 2)")
+	tk.MustExec("insert into fk_c3 values (100, 10), (101, 12)")
+
+	// ON UPDATE CASCADE and SET NULL.
+	tk.MustExec("update fk_p set id = 4 where id = 1")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from fk_c1 order by id").Check(testkit.Rows("10 4", "11 4", "12 2"))
+	tk.MustQuery("select * from fk_c2 order by id").Check(testkit.Rows("x <nil>", "y 2"))
+	tk.MustExec("admin check table fk_c1")
+	tk.MustExec("admin check table fk_c2")
+
+	// ON DELETE CASCADE goes through multiple levels.
+	tk.MustExec("delete from fk_p where id = 2")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from fk_c1 order by id").Check(testkit.Rows("10 4", "11 4"))
+	tk.MustQuery("select * from fk_c2 order by id").Check(testkit.Rows("x <nil>", "y <nil>"))
+	tk.MustQuery("select * from fk_c3 order by id").Check(testkit.Rows("100 10"))
+	tk.MustExec("delete from fk_p")
+	tk.MustQuery("select count(*) from fk_c1").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from fk_c3").Check(testkit.Rows("0"))
+	tk.MustExec("admin check table fk_c1")
+	tk.MustExec("admin check table fk_c3")
+
+	// A foreign key without an index on the child table is looked up by a table scan.
+	tk.MustExec("drop table if exists fk_c4")
+	tk.MustExec("create table fk_c4(id int, pid int, foreign key (pid) references fk_p(id) on delete cascade)")
+	tk.MustExec("insert into fk_p values (1, 'a'), (2, 'b')")
+	tk.MustExec("insert into fk_c4 values (1, 1), (2, 2), (3, 1)")
+	tk.MustExec("delete from fk_p where id = 1")
+	tk.MustQuery("select * from fk_c4").Check(testkit.Rows("2 2"))
+	tk.MustExec("drop table fk_c4")
+
+	// The failed cascade is rolled back with the statement.
+	tk.MustExec("create table fk_c4(id int primary key, c1 int, index(c1), foreign key (c1) references fk_c1(id))")
+	tk.MustExec("insert into fk_c1 values (20, 2)")
+	tk.MustExec("insert into fk_c4 values (1, 20)")
+	tk.MustGetErrCode("delete from fk_p where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustQuery("select * from fk_p").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select * from fk_c1").Check(testkit.Rows("20 2"))
+}
+
+func (s *testSuite8) TestForeignKeySelfReferenceAndDepth(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("drop table if exists fk_tree")
+	tk.MustExec("create table fk_tree(id int primary key, pid int, index(pid), foreign key (pid) references fk_tree(id) on delete cascade)")
+	// A row can refer to itself.
+	tk.MustExec("insert into fk_tree values (1, 1)")
+	for i := 2; i <= 16; i++ {
+		tk.MustExec(fmt.Sprintf("insert into fk_tree values (%d, %d)", i, i-1))
+	}
+	tk.MustExec("delete from fk_tree where id = 15")
+	tk.MustQuery("select count(*) from fk_tree").Check(testkit.Rows("14"))
+	tk.MustExec("insert into fk_tree values (15, 14), (16, 15), (17, 16)")
+	err := tk.ExecToErr("delete from fk_tree where id = 1")
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "Foreign key cascade delete/update exceeds max depth of 15."), IsTrue, Commentf("%v", err))
+	tk.MustQuery("select count(*) from fk_tree").Check(testkit.Rows("17"))
+	tk.MustExec("delete from fk_tree where id = 3")
+	tk.MustQuery("select id from fk_tree order by id").Check(testkit.Rows("1", "2"))
+}
+
+func (s *testSuite8) TestForeignKeyDDLChecks(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fk_ddl_child, fk_ddl_parent")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustGetErrCode("create table fk_ddl_child(id int, pid int, foreign key (pid) references fk_ddl_parent(id))", errno.ErrFkCannotOpenParent)
+	tk.MustExec("create table fk_ddl_parent(id int, name varchar(10), key(id))")
+	tk.MustGetErrCode("create table fk_ddl_child(id int, pid int, foreign key (pid) references fk_ddl_parent(name))", errno.ErrFkNoIndexParent)
+	tk.MustExec("create table fk_ddl_child(id int, pid int, constraint fk_1 foreign key (pid) references fk_ddl_parent(id))")
+	tk.MustGetErrCode("alter table fk_ddl_child add foreign key fk_2 (id) references fk_ddl_parent(name)", errno.ErrFkNoIndexParent)
+	tk.MustExec("alter table fk_ddl_child drop foreign key fk_1")
+	tk.MustExec("insert into fk_ddl_child values (1, 1)")
+
+	// The foreign keys can't refer to the tables in another database.
+	tk.MustExec("drop database if exists fk_ddl_db")
+	tk.MustExec("create database fk_ddl_db")
+	defer tk.MustExec("drop database fk_ddl_db")
+	tk.MustGetErrCode("create table fk_ddl_db.fk_ddl_child(id int, pid int, foreign key (pid) references test.fk_ddl_parent(id))", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create table fk_ddl_db.fk_ddl_other(id int primary key)")
+	tk.MustGetErrCode("alter table fk_ddl_child add foreign key fk_2 (pid) references fk_ddl_db.fk_ddl_other(id)", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table fk_ddl_child add constraint fk_1 foreign key (pid) references test.fk_ddl_parent(id)")
+	tk.MustGetErrCode("rename table fk_ddl_child to fk_ddl_db.fk_ddl_child", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("rename table fk_ddl_parent to fk_ddl_db.fk_ddl_parent", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table fk_ddl_parent rename to fk_ddl_db.fk_ddl_parent", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("rename table fk_ddl_child to fk_ddl_child2")
+	tk.MustExec("alter table fk_ddl_child2 drop foreign key fk_1")
+	tk.MustExec("rename table fk_ddl_child2 to fk_ddl_db.fk_ddl_child")
+}
+
+func (s *testSuite8) TestForeignKeyConcurrentChildWrites(c *C) {
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustExec("set @@foreign_key_checks = 1")
+	tk1.MustExec("drop table if exists fk_cc_child, fk_cc_parent")
+	tk1.MustExec("create table fk_cc_parent(id int primary key)")
+	tk1.MustExec("create table fk_cc_child(id int primary key, pid int, key(pid), foreign key (pid) references fk_cc_parent(id))")
+	tk1.MustExec("insert into fk_cc_parent values (1)")
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+
+	// The parent rows aren't locked in optimistic transactions, so the transactions writing the child rows of
+	// the same parent row don't conflict.
+	tk1.MustExec("begin optimistic")
+	tk2.MustExec("begin optimistic")
+	tk1.MustExec("insert into fk_cc_child values (1, 1)")
+	tk2.MustExec("insert into fk_cc_child values (2, 1)")
+	tk1.MustExec("commit")
+	tk2.MustExec("commit")
+	tk1.MustQuery("select * from fk_cc_child order by id").Check(testkit.Rows("1 1", "2 1"))
+
+	// The parent rows are locked in pessimistic transactions.
+	tk1.MustExec("insert into fk_cc_parent values (2)")
+	tk1.MustExec("begin pessimistic")
+	tk1.MustExec("insert into fk_cc_child values (3, 2)")
+	tk2.MustExec("set @@innodb_lock_wait_timeout = 1")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustGetErrCode("delete from fk_cc_parent where id = 2", errno.ErrLockWaitTimeout)
+	tk2.MustExec("rollback")
+	tk1.MustExec("commit")
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/privilege"
@@ -65,6 +66,7 @@ import (
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/resourcegrouptag"
 	"github.com/pingcap/tidb/util/sem"
+	"github.com/pingcap/tidb/util/servermemorylimit"
 	"github.com/pingcap/tidb/util/set"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
@@ -166,6 +168,15 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForClientErrorsSummary(sctx, e.table.Name.O)
 		case infoschema.TableRegionLabel:
 			err = e.setDataForRegionLabel(sctx)
+		case infoschema.TableCheckConstraints:
+			e.setDataFromCheckConstraints(sctx, dbs)
+		case infoschema.TableTiDBTTLTableStatus:
+			err = e.setDataForTTLTableStatus(ctx, sctx, dbs)
+		case infoschema.TableInstancePlanCache:
+			err = e.setDataForInstancePlanCache(sctx)
+		case infoschema.TableMemoryUsageOpsHistory,
+			infoschema.ClusterTableMemoryUsageOpsHistory:
+			err = e.setDataForMemoryUsageOpsHistory(sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -1613,9 +1624,118 @@ func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context,
 				)
 				rows = append(rows, record)
 			}
+
+			for _, constr := range tbl.Constraints {
+				if constr.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,          // CONSTRAINT_CATALOG
+					schema.Name.O,                  // CONSTRAINT_SCHEMA
+					constr.Name.O,                  // CONSTRAINT_NAME
+					schema.Name.O,                  // TABLE_SCHEMA
+					tbl.Name.O,                     // TABLE_NAME
+					infoschema.CheckConstraintType, // CONSTRAINT_TYPE
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+// setDataFromCheckConstraints constructs data for table information_schema.check_constraints. See https://dev.mysql.com/doc/refman/8.0/en/information-schema-check-constraints-table.html
+func (e *memtableRetriever) setDataFromCheckConstraints(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tbl := range schema.Tables {
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, constr := range tbl.Constraints {
+				if constr.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,                  // CONSTRAINT_CATALOG
+					schema.Name.O,                          // CONSTRAINT_SCHEMA
+					constr.Name.O,                          // CONSTRAINT_NAME
+					fmt.Sprintf("(%s)", constr.ExprString), // CHECK_CLAUSE
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+// setDataForTTLTableStatus constructs data for table information_schema.tidb_ttl_table_status.
+func (e *memtableRetriever) setDataForTTLTableStatus(ctx context.Context, sctx sessionctx.Context, schemas []*model.DBInfo) error {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, `select table_id, job_state, job_start_time, job_finish_time, expire_time,
+		total_regions, scanned_regions, deleted_rows, error_message from mysql.tidb_ttl_table_status`)
+	if err != nil {
+		return err
+	}
+	statusRows, fields, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	statusMap := make(map[int64]chunk.Row, len(statusRows))
+	for _, row := range statusRows {
+		statusMap[row.GetInt64(0)] = row
+	}
+
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	t := meta.NewSnapshotMeta(sctx.GetStore().GetSnapshot(kv.NewVersion(txn.StartTS())))
+	checker := privilege.GetPrivilegeManager(sctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		ttlInfos, err := t.ListTableTTLs(schema.ID)
+		if err != nil {
+			return err
+		}
+		for _, tbl := range schema.Tables {
+			ttlInfo, ok := ttlInfos[tbl.ID]
+			if !ok {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			var colName string
+			for _, col := range tbl.Columns {
+				if col.ID == ttlInfo.ColumnID {
+					colName = col.Name.O
+				}
+			}
+			enable := "ON"
+			if !ttlInfo.Enable {
+				enable = "OFF"
+			}
+			record := types.MakeDatums(
+				schema.Name.O,            // TABLE_SCHEMA
+				tbl.Name.O,               // TABLE_NAME
+				tbl.ID,                   // TABLE_ID
+				colName,                  // TTL_COLUMN
+				ttlInfo.IntervalString(), // TTL_INTERVAL
+				enable,                   // TTL_ENABLE
+				nil, nil, nil, nil, nil, nil, nil, nil,
+			)
+			if row, ok := statusMap[tbl.ID]; ok {
+				for i := 1; i < len(fields); i++ {
+					record[5+i] = row.GetDatum(i, &fields[i].Column.FieldType)
+				}
+			}
+			rows = append(rows, record)
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 // tableStorageStatsRetriever is used to read slow log data.
@@ -1948,6 +2068,60 @@ func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, s
 	e.rows = rows
 }
 
+func (e *memtableRetriever) setDataForInstancePlanCache(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	entries := plannercore.InstancePlanCacheEntries()
+	rows := make([][]types.Datum, 0, len(entries))
+	for _, entry := range entries {
+		lastAccess := types.NewTime(types.FromGoTime(entry.LastAccess.In(ctx.GetSessionVars().Location())), mysql.TypeTimestamp, types.DefaultFsp)
+		rows = append(rows, types.MakeDatums(
+			entry.SchemaName,
+			entry.Digest,
+			entry.NormalizedSQL,
+			entry.PlanCount,
+			entry.Hits,
+			entry.MemUsage,
+			lastAccess,
+		))
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForMemoryUsageOpsHistory(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	records := servermemorylimit.OpsHistory()
+	rows := make([][]types.Datum, 0, len(records))
+	for _, record := range records {
+		opsTime := types.NewTime(types.FromGoTime(record.Time.In(ctx.GetSessionVars().Location())), mysql.TypeDatetime, types.DefaultFsp)
+		rows = append(rows, types.MakeDatums(
+			opsTime,
+			record.Ops,
+			record.MemoryLimit,
+			record.MemoryCurrent,
+			record.ConnID,
+			record.Mem,
+			record.Host,
+			record.DB,
+			record.User,
+			record.SQLDigest,
+			record.SQLText,
+		))
+	}
+	if e.table.Name.O == infoschema.ClusterTableMemoryUsageOpsHistory {
+		var err error
+		if rows, err = infoschema.AppendHostInfoToRows(ctx, rows); err != nil {
+			return err
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
 func (e *memtableRetriever) setDataForStatementsSummaryEvicted(ctx sessionctx.Context) error {
 	if !hasPriv(ctx, mysql.ProcessPriv) {
 		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
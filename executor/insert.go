@@ -200,7 +200,7 @@ func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, kvGetter
 	}
 
 	err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, e.OnDuplicate)
-	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
+	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && (kv.ErrKeyExists.Equal(err) || table.ErrCheckConstraintViolated.Equal(err)) {
 		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 		return nil
 	}
@@ -399,7 +399,7 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	}
 
 	newData := e.row4Update[:len(oldRow)]
-	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
+	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker, e.fkChecker, e.constraintChecker)
 	if err != nil {
 		return err
 	}
@@ -82,6 +82,9 @@ type InsertValues struct {
 	// https://dev.mysql.com/doc/refman/8.0/en/innodb-auto-increment-handling.html
 	lazyFillAutoID bool
 	memTracker     *memory.Tracker
+	fkChecker      *foreignKeyChecker
+	// constraintChecker checks the CHECK constraints of the inserted rows.
+	constraintChecker *checkConstraintChecker
 
 	rowLen int
 
@@ -1127,6 +1130,20 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
 	vars := e.ctx.GetSessionVars()
+	if err = e.constraintChecker.checkRow(e.Table, row); err != nil {
+		if table.ErrCheckConstraintViolated.Equal(err) && vars.StmtCtx.DupKeyAsWarning {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
+	if err = e.fkChecker.checkInsert(ctx, e.Table, row); err != nil {
+		if ErrNoReferencedRow2.Equal(err) && vars.StmtCtx.DupKeyAsWarning {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
 	}
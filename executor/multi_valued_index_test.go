@@ -0,0 +1,103 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+// arrayCastMarker marks the CASTs in the statement as CAST(... AS type ARRAY).
+type arrayCastMarker struct{}
+
+func (m *arrayCastMarker) Enter(n ast.Node) (ast.Node, bool) {
+	if cast, ok := n.(*ast.FuncCastExpr); ok {
+		cast.Tp.Flag |= types.ArrayFlag
+	}
+	return n, false
+}
+
+func (m *arrayCastMarker) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// execWithArrayCast executes the statement in which the CASTs are CAST(... AS type ARRAY), since the parser
+// doesn't support the ARRAY keyword yet.
+func execWithArrayCast(c *C, tk *testkit.TestKit, sql string) error {
+	stmt, err := parser.New().ParseOneStmt(sql, "", "")
+	c.Assert(err, IsNil)
+	stmt.Accept(&arrayCastMarker{})
+	rs, err := tk.Se.ExecuteStmt(context.Background(), stmt)
+	if rs != nil {
+		c.Assert(rs.Close(), IsNil)
+	}
+	return err
+}
+
+func (s *testSuite) TestMultiValuedIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, j json)")
+	tk.MustExec(`insert into t values (1, '{"tags": [1, 2, 3]}'), (2, '{"tags": [3, 4]}'), (3, '{"tags": []}'), (4, null), (5, '{"tags": 5}')`)
+	c.Assert(execWithArrayCast(c, tk, "alter table t add index idx((cast(j->'$.tags' as unsigned)))"), IsNil)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `j` json DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `idx` ((cast(json_extract(`j`, _utf8mb4'$.tags') as unsigned array)))\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("admin check table t")
+	tk.MustExec(`insert into t values (6, '{"tags": [6, 6, 7]}')`)
+	tk.MustExec(`update t set j = '{"tags": [1, 8]}' where id = 2`)
+	tk.MustExec("delete from t where id = 1")
+	tk.MustExec("admin check table t")
+}
+
+func (s *testSuite) TestMultiValuedIndexQuery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, j json)")
+	tk.MustExec(`insert into t values (1, '[1, 2, 3]'), (2, '[3, 4]'), (3, '[]'), (4, null), (5, '5'), (6, '[6, 6, 7]')`)
+	c.Assert(execWithArrayCast(c, tk, "alter table t add index idx((cast(j as unsigned)))"), IsNil)
+
+	tk.MustQuery("explain format = 'brief' select id from t where json_memberof(3, j)").Check(testkit.Rows(
+		"Projection 8000.00 root  test.t.id",
+		"└─Selection 8000.00 root  json_memberof(cast(3, json BINARY), test.t.j)",
+		"  └─IndexMerge 10.00 root  ",
+		"    ├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:idx(cast(`j` as unsigned array)) range:[3,3], keep order:false, stats:pseudo",
+		"    └─TableRowIDScan(Probe) 10.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("select id from t where json_memberof(3, j) order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where json_memberof(5, j)").Check(testkit.Rows("5"))
+	tk.MustQuery("select id from t where json_memberof(8, j)").Check(testkit.Rows())
+	tk.MustQuery("select id from t where json_overlaps(j, '[1, 4, 7]') order by id").Check(testkit.Rows("1", "2", "6"))
+	tk.MustQuery("select id from t where json_overlaps('[3, 6]', j) order by id").Check(testkit.Rows("1", "2", "6"))
+	tk.MustQuery("select id from t where json_contains(j, '[3, 4]')").Check(testkit.Rows("2"))
+	tk.MustQuery("select id from t where json_contains(j, '6')").Check(testkit.Rows("6"))
+	// The multi-valued index isn't used inside a write transaction.
+	tk.MustExec("begin")
+	tk.MustExec(`insert into t values (7, '[3]')`)
+	tk.MustQuery("select id from t where json_memberof(3, j) order by id").Check(testkit.Rows("1", "2", "7"))
+	tk.MustExec("commit")
+	tk.MustQuery("select id from t where json_memberof(3, j) order by id").Check(testkit.Rows("1", "2", "7"))
+	tk.MustExec("admin check table t")
+	tk.MustExec("admin check index t idx")
+}
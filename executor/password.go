@@ -0,0 +1,373 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bufio"
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// passwordHistoryTable stores the previous passwords of the accounts, it is used by the password reuse policy.
+const passwordHistoryTable = "password_history"
+
+// minDictionaryWordLength is the minimum length of the substrings of a password checked against the dictionary.
+const minDictionaryWordLength = 4
+
+// validatePasswordStrength checks the cleartext password against the policy set by the validate_password_* system
+// variables. It does nothing when validate_password_enable is OFF.
+func validatePasswordStrength(sctx sessionctx.Context, userName, pwd string) error {
+	accessor := sctx.GetSessionVars().GlobalVarsAccessor
+	enable, err := accessor.GetGlobalSysVar(variable.ValidatePasswordEnable)
+	if err != nil {
+		return err
+	}
+	if !variable.TiDBOptOn(enable) {
+		return nil
+	}
+	checkUserName, err := accessor.GetGlobalSysVar(variable.ValidatePasswordCheckUserName)
+	if err != nil {
+		return err
+	}
+	if variable.TiDBOptOn(checkUserName) && userName != "" && (pwd == userName || pwd == reverseString(userName)) {
+		return ErrNotValidPassword.GenWithStackByArgs()
+	}
+
+	var length, numberCount, mixedCaseCount, specialCharCount int
+	for _, item := range []struct {
+		name  string
+		value *int
+	}{
+		{variable.ValidatePasswordLength, &length},
+		{variable.ValidatePasswordNumberCount, &numberCount},
+		{variable.ValidatePasswordMixedCaseCount, &mixedCaseCount},
+		{variable.ValidatePasswordSpecialCharCount, &specialCharCount},
+	} {
+		val, err := accessor.GetGlobalSysVar(item.name)
+		if err != nil {
+			return err
+		}
+		if *item.value, err = strconv.Atoi(val); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// Like MySQL, the effective minimum length can't be less than the number of the required characters.
+	if minLength := numberCount + specialCharCount + 2*mixedCaseCount; length < minLength {
+		length = minLength
+	}
+	if utf8.RuneCountInString(pwd) < length {
+		return ErrNotValidPassword.GenWithStackByArgs()
+	}
+
+	policy, err := accessor.GetGlobalSysVar(variable.ValidatePasswordPolicy)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(policy, "LOW") {
+		return nil
+	}
+	var numbers, lowers, uppers, specials int
+	for _, r := range pwd {
+		switch {
+		case unicode.IsDigit(r):
+			numbers++
+		case unicode.IsLower(r):
+			lowers++
+		case unicode.IsUpper(r):
+			uppers++
+		case !unicode.IsLetter(r):
+			specials++
+		}
+	}
+	if numbers < numberCount || lowers < mixedCaseCount || uppers < mixedCaseCount || specials < specialCharCount {
+		return ErrNotValidPassword.GenWithStackByArgs()
+	}
+	if strings.EqualFold(policy, "MEDIUM") {
+		return nil
+	}
+
+	file, err := accessor.GetGlobalSysVar(variable.ValidatePasswordDictionaryFile)
+	if err != nil {
+		return err
+	}
+	words, err := passwordDictionary.load(file)
+	if err != nil {
+		return err
+	}
+	runes := []rune(strings.ToLower(pwd))
+	for i := 0; i < len(runes); i++ {
+		for j := i + minDictionaryWordLength; j <= len(runes); j++ {
+			if _, ok := words[string(runes[i:j])]; ok {
+				return ErrNotValidPassword.GenWithStackByArgs()
+			}
+		}
+	}
+	return nil
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// passwordDictionary caches the words of validate_password_dictionary_file, it is reloaded when the file or its
+// modification time changes.
+var passwordDictionary = &dictionaryCache{}
+
+type dictionaryCache struct {
+	sync.Mutex
+	file    string
+	modTime time.Time
+	words   map[string]struct{}
+}
+
+func (c *dictionaryCache) load(file string) (map[string]struct{}, error) {
+	if file == "" {
+		return nil, nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.file == file && c.modTime.Equal(info.ModTime()) {
+		return c.words, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	words := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if utf8.RuneCountInString(word) >= minDictionaryWordLength {
+			words[word] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.file, c.modTime, c.words = file, info.ModTime(), words
+	return words, nil
+}
+
+// passwordReusePolicy forbids reusing a password which is among the latest `history` passwords of the account, or
+// which was changed within the latest `interval` days.
+type passwordReusePolicy struct {
+	history  int64
+	interval int64
+}
+
+func (p passwordReusePolicy) enabled() bool {
+	return p.history > 0 || p.interval > 0
+}
+
+// loadPasswordReusePolicy loads the password reuse policy of the account from mysql.user. The global password_history
+// and password_reuse_interval are used when the account doesn't set its own.
+func loadPasswordReusePolicy(ctx context.Context, sctx sessionctx.Context, user *auth.UserIdentity) (passwordReusePolicy, error) {
+	policy := passwordReusePolicy{history: -1, interval: -1}
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, `SELECT Password_reuse_history, Password_reuse_time FROM %n.%n WHERE User = %? AND Host = %?`,
+		mysql.SystemDB, mysql.UserTable, user.Username, user.Hostname)
+	if err != nil {
+		return policy, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return policy, err
+	}
+	if len(rows) > 0 {
+		if !rows[0].IsNull(0) {
+			policy.history = rows[0].GetInt64(0)
+		}
+		if !rows[0].IsNull(1) {
+			policy.interval = rows[0].GetInt64(1)
+		}
+	}
+	accessor := sctx.GetSessionVars().GlobalVarsAccessor
+	for _, item := range []struct {
+		name  string
+		value *int64
+	}{
+		{variable.PasswordHistory, &policy.history},
+		{variable.PasswordReuseInterval, &policy.interval},
+	} {
+		if *item.value >= 0 {
+			continue
+		}
+		val, err := accessor.GetGlobalSysVar(item.name)
+		if err != nil {
+			return policy, err
+		}
+		if *item.value, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return policy, errors.Trace(err)
+		}
+	}
+	return policy, nil
+}
+
+// checkPasswordReuse returns an error if the new password is forbidden by the password reuse policy. The encoded
+// password is compared with the history directly, the cleartext one is also checked against the salted sha2 hashes.
+func checkPasswordReuse(ctx context.Context, sctx sessionctx.Context, user *auth.UserIdentity, policy passwordReusePolicy, pwd string, cleartext bool, encoded string) error {
+	if !policy.enabled() || encoded == "" {
+		return nil
+	}
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, `SELECT Password, Password_timestamp >= DATE_SUB(NOW(6), INTERVAL %? DAY) FROM %n.%n WHERE User = %? AND Host = %? ORDER BY Password_timestamp DESC`,
+		policy.interval, mysql.SystemDB, passwordHistoryTable, user.Username, user.Hostname)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if int64(i) >= policy.history && (row.IsNull(1) || row.GetInt64(1) == 0) {
+			continue
+		}
+		previous := row.GetString(0)
+		if previous == encoded {
+			return ErrCredentialsContradictHistory.GenWithStackByArgs(user.Username, user.Hostname)
+		}
+		if cleartext && len(previous) == mysql.SHAPWDHashLen {
+			if match, err := auth.CheckShaPassword([]byte(previous), pwd); err == nil && match {
+				return ErrCredentialsContradictHistory.GenWithStackByArgs(user.Username, user.Hostname)
+			}
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory records the new password of the account, and removes the previous passwords which are no
+// longer covered by the password reuse policy.
+func recordPasswordHistory(ctx context.Context, sctx sessionctx.Context, user *auth.UserIdentity, policy passwordReusePolicy, encoded string) error {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	if !policy.enabled() {
+		return execRestrictedSQL(ctx, exec, `DELETE FROM %n.%n WHERE User = %? AND Host = %?`,
+			mysql.SystemDB, passwordHistoryTable, user.Username, user.Hostname)
+	}
+	if encoded == "" {
+		return nil
+	}
+	err := execRestrictedSQL(ctx, exec, `INSERT INTO %n.%n (Host, User, Password) VALUES (%?, %?, %?)`,
+		mysql.SystemDB, passwordHistoryTable, user.Hostname, user.Username, encoded)
+	if err != nil {
+		return err
+	}
+	stmt, err := exec.ParseWithParams(ctx, `SELECT Password_timestamp FROM %n.%n WHERE User = %? AND Host = %? ORDER BY Password_timestamp DESC LIMIT %?, 1`,
+		mysql.SystemDB, passwordHistoryTable, user.Username, user.Hostname, policy.history)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return execRestrictedSQL(ctx, exec, `DELETE FROM %n.%n WHERE User = %? AND Host = %? AND Password_timestamp <= %? AND Password_timestamp < DATE_SUB(NOW(6), INTERVAL %? DAY)`,
+		mysql.SystemDB, passwordHistoryTable, user.Username, user.Hostname, rows[0].GetTime(0).String(), policy.interval)
+}
+
+func execRestrictedSQL(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, sql string, args ...interface{}) error {
+	stmt, err := exec.ParseWithParams(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	return err
+}
+
+// passwordOrLockOptionsAssignments builds the assignments of mysql.user for the PASSWORD EXPIRE and ACCOUNT LOCK
+// options, it returns an empty string if there is no such option.
+func passwordOrLockOptionsAssignments(options []*ast.PasswordOrLockOption) (string, error) {
+	assignments := make([]string, 0, len(options))
+	for _, option := range options {
+		switch option.Type {
+		case ast.PasswordExpire:
+			assignments = append(assignments, "Password_expired = 'Y'")
+		case ast.PasswordExpireDefault:
+			assignments = append(assignments, "Password_lifetime = NULL")
+		case ast.PasswordExpireNever:
+			assignments = append(assignments, "Password_lifetime = 0")
+		case ast.PasswordExpireInterval:
+			if option.Count <= 0 || option.Count > math.MaxUint16 {
+				return "", types.ErrWrongValue.GenWithStackByArgs("DAY", strconv.FormatInt(option.Count, 10))
+			}
+			assignments = append(assignments, "Password_lifetime = "+strconv.FormatInt(option.Count, 10))
+		case ast.Lock:
+			assignments = append(assignments, "Account_locked = 'Y'")
+		case ast.Unlock:
+			assignments = append(assignments, "Account_locked = 'N'", "Failed_login_count = 0", "Password_locked_time = NULL")
+		}
+	}
+	return strings.Join(assignments, ", "), nil
+}
+
+// applyPasswordOrLockOptions updates the PASSWORD EXPIRE and ACCOUNT LOCK options of the account.
+func applyPasswordOrLockOptions(ctx context.Context, sctx sessionctx.Context, user *auth.UserIdentity, assignments string) error {
+	if assignments == "" {
+		return nil
+	}
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, `UPDATE %n.%n SET `, mysql.SystemDB, mysql.UserTable)
+	sql.WriteString(assignments)
+	sqlexec.MustFormatSQL(sql, ` WHERE User = %? AND Host = %?`, user.Username, user.Hostname)
+	return execRestrictedSQL(ctx, sctx.(sqlexec.RestrictedSQLExecutor), sql.String())
+}
+
+// checkNewPassword checks the new password of the account against the strength and reuse policies. The returned
+// reuse policy is used to record the password after it's changed.
+func checkNewPassword(ctx context.Context, sctx sessionctx.Context, user *auth.UserIdentity, pwd string, cleartext bool, encoded string) (passwordReusePolicy, error) {
+	if cleartext {
+		if err := validatePasswordStrength(sctx, user.Username, pwd); err != nil {
+			return passwordReusePolicy{}, err
+		}
+	}
+	policy, err := loadPasswordReusePolicy(ctx, sctx, user)
+	if err != nil {
+		return policy, err
+	}
+	return policy, checkPasswordReuse(ctx, sctx, user, policy, pwd, cleartext, encoded)
+}
+
+// authOptString returns the cleartext password of the auth option, or an empty string if it's not given.
+func authOptString(authOpt *ast.AuthOption) string {
+	if authOpt == nil || !authOpt.ByAuthString {
+		return ""
+	}
+	return authOpt.AuthString
+}
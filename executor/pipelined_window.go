@@ -25,12 +25,15 @@ import (
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 type dataInfo struct {
 	chk         *chunk.Chunk
 	remaining   uint64
 	accumulated uint64
+	// memUsage is the memory usage of the child chunk referenced by chk.
+	memUsage int64
 }
 
 // PipelinedWindowExec is the executor for window functions.
@@ -74,10 +77,18 @@ type PipelinedWindowExec struct {
 	isRangeFrame             bool
 	emptyFrame               bool
 	initializedSlidingWindow bool
+
+	memTracker *memory.Tracker
 }
 
 // Close implements the Executor Close interface.
 func (e *PipelinedWindowExec) Close() error {
+	if e.memTracker != nil {
+		for _, d := range e.data {
+			e.memTracker.Consume(-d.memUsage)
+		}
+		e.data = nil
+	}
 	return errors.Trace(e.baseExecutor.Close())
 }
 
@@ -96,6 +107,8 @@ func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
 		}
 	}
 	e.rows = make([]chunk.Row, 0)
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
 	return e.baseExecutor.Open(ctx)
 }
 
@@ -162,6 +175,7 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 	}
 	if len(e.data) > 0 {
 		chk.SwapColumns(e.data[0].chk)
+		e.memTracker.Consume(-e.data[0].memUsage)
 		e.data = e.data[1:]
 		e.dataIdx--
 	}
@@ -223,7 +237,9 @@ func (e *PipelinedWindowExec) fetchChild(ctx context.Context) (EOF bool, err err
 		return false, err
 	}
 	e.accumulated += uint64(numRows)
-	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated})
+	memUsage := childResult.MemoryUsage()
+	e.memTracker.Consume(memUsage)
+	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated, memUsage: memUsage})
 
 	e.childResult = childResult
 	return false, nil
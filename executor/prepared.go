@@ -348,6 +348,8 @@ func CompileExecutePreparedStmt(ctx context.Context, sctx sessionctx.Context,
 		OutputNames: names,
 		Ti:          &TelemetryInfo{},
 		SnapshotTS:  snapshotTS,
+
+		checkedOutPlans: plannercore.TakeCheckedOutPlans(sctx),
 	}
 	if preparedPointer, ok := sctx.GetSessionVars().PreparedStmts[ID]; ok {
 		preparedObj, ok := preparedPointer.(*plannercore.CachedPrepareStmt)
@@ -84,10 +84,16 @@ func (e *ReplaceExec) removeRow(ctx context.Context, kvGetter kv.Getter, handle
 		return true, nil
 	}
 
+	if err = e.fkChecker.checkDelete(ctx, r.t, handle, oldRow); err != nil {
+		return false, err
+	}
 	err = r.t.RemoveRecord(e.ctx, handle, oldRow)
 	if err != nil {
 		return false, err
 	}
+	if err = e.fkChecker.onDelete(ctx, r.t, handle, oldRow); err != nil {
+		return false, err
+	}
 	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
 	return false, nil
 }
@@ -93,6 +93,7 @@ func (e *SetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 				sessionVars.UserVarTypes[name] = v.Expr.GetType()
 			}
 			sessionVars.UsersLock.Unlock()
+			sessionVars.SessionTracker.MarkStateChanged()
 			continue
 		}
 
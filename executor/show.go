@@ -646,7 +646,7 @@ func (e *ShowExec) fetchShowIndex() error {
 			var expression interface{}
 			if tblCol.Hidden {
 				colName = "NULL"
-				expression = tblCol.GeneratedExprString
+				expression = table.IndexExprString(tblCol)
 			}
 
 			e.appendRow([]interface{}{
@@ -941,8 +941,8 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		cols := make([]string, 0, len(idxInfo.Columns))
 		var colInfo string
 		for _, c := range idxInfo.Columns {
-			if tableInfo.Columns[c.Offset].Hidden {
-				colInfo = fmt.Sprintf("(%s)", tableInfo.Columns[c.Offset].GeneratedExprString)
+			if col := tableInfo.Columns[c.Offset]; col.Hidden {
+				colInfo = fmt.Sprintf("(%s)", table.IndexExprString(col))
 			} else {
 				colInfo = stringutil.Escape(c.Name.O, sqlMode)
 				if c.Length != types.UnspecifiedLength {
@@ -993,6 +993,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constr := range tableInfo.Constraints {
+		if constr.State != model.StatePublic {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s CHECK ((%s))", stringutil.Escape(constr.Name.O, sqlMode), constr.ExprString))
+		if !constr.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	switch tableInfo.TempTableType {
@@ -1368,7 +1378,7 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 
-	stmt, err := exec.ParseWithParams(ctx, `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
+	stmt, err := exec.ParseWithParams(ctx, `SELECT plugin, Password_expired, Password_lifetime, Account_locked FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1387,6 +1397,20 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	if len(rows) == 1 && rows[0].GetString(0) != "" {
 		authplugin = rows[0].GetString(0)
 	}
+	passwordExpire := "PASSWORD EXPIRE DEFAULT"
+	switch {
+	case rows[0].GetEnum(1).String() == "Y":
+		passwordExpire = "PASSWORD EXPIRE"
+	case rows[0].IsNull(2):
+	case rows[0].GetInt64(2) == 0:
+		passwordExpire = "PASSWORD EXPIRE NEVER"
+	default:
+		passwordExpire = fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", rows[0].GetInt64(2))
+	}
+	accountLock := "ACCOUNT UNLOCK"
+	if rows[0].GetEnum(3).String() == "Y" {
+		accountLock = "ACCOUNT LOCK"
+	}
 
 	stmt, err = exec.ParseWithParams(ctx, `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
@@ -1408,8 +1432,8 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 		require = privValue.RequireStr()
 	}
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, authplugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s %s %s",
+		e.User.Username, e.User.Hostname, authplugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require, passwordExpire, accountLock)
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
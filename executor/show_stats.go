@@ -83,10 +83,10 @@ func (e *ShowExec) appendTableForStatsExtended(dbName string, tbl *model.TableIn
 			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		case ast.StatsTypeDependency:
 			statsType = "dependency"
-			statsVal = item.StringVals
+			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		case ast.StatsTypeCardinality:
 			statsType = "cardinality"
-			statsVal = item.StringVals
+			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		}
 		e.appendRow([]interface{}{
 			dbName,
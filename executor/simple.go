@@ -40,6 +40,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
@@ -558,6 +559,7 @@ func (e *SimpleExec) executeUse(s *ast.UseStmt) error {
 	e.ctx.GetSessionVars().CurrentDBChanged = dbname.O != e.ctx.GetSessionVars().CurrentDB
 	e.ctx.GetSessionVars().CurrentDB = dbname.O
 	sessionVars := e.ctx.GetSessionVars()
+	sessionVars.SessionTracker.MarkSchemaChanged()
 	dbCollate := dbinfo.Collate
 	if dbCollate == "" {
 		dbCollate = getDefaultCollate(dbinfo.Charset)
@@ -756,7 +758,14 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin) VALUES `, mysql.SystemDB, mysql.UserTable)
 	}
 
+	assignments, err := passwordOrLockOptionsAssignments(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
+	passwords := make([]string, 0, len(s.Specs))
+	policies := make([]passwordReusePolicy, 0, len(s.Specs))
 	for _, spec := range s.Specs {
 		if len(users) > 0 {
 			sqlexec.MustFormatSQL(sql, ",")
@@ -777,7 +786,7 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
+		pwd, ok := encodedPassword(spec)
 
 		if !ok {
 			return errors.Trace(ErrPasswordFormat)
@@ -786,12 +795,21 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		if spec.AuthOpt != nil && spec.AuthOpt.AuthPlugin != "" {
 			authPlugin = spec.AuthOpt.AuthPlugin
 		}
+		var policy passwordReusePolicy
+		if !ldap.IsAuthPlugin(authPlugin) {
+			policy, err = checkNewPassword(ctx, e.ctx, spec.User, authOptString(spec.AuthOpt), spec.AuthOpt != nil && spec.AuthOpt.ByAuthString, pwd)
+			if err != nil {
+				return err
+			}
+		}
 		if s.IsCreateRole {
 			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, "Y")
 		} else {
 			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin)
 		}
 		users = append(users, spec.User)
+		passwords = append(passwords, pwd)
+		policies = append(policies, policy)
 	}
 	if len(users) == 0 {
 		return nil
@@ -834,6 +852,14 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
 		return errors.Trace(err)
 	}
+	for i, user := range users {
+		if err = applyPasswordOrLockOptions(ctx, e.ctx, user, assignments); err != nil {
+			return err
+		}
+		if err = recordPasswordHistory(ctx, e.ctx, user, policies[i], passwords[i]); err != nil {
+			return err
+		}
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
 	return err
 }
@@ -870,6 +896,11 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 	hasRestrictedUserPriv := checker.RequestDynamicVerification(activeRoles, "RESTRICTED_USER_ADMIN", false)
 	hasSystemSchemaPriv := checker.RequestVerification(activeRoles, mysql.SystemDB, mysql.UserTable, "", mysql.UpdatePriv)
 
+	assignments, err := passwordOrLockOptionsAssignments(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+
 	for _, spec := range s.Specs {
 		user := e.ctx.GetSessionVars().User
 		if spec.User.CurrentUser || ((user != nil) && (user.Username == spec.User.Username) && (user.AuthHostname == spec.User.Hostname)) {
@@ -923,18 +954,32 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 		}
 		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 		if spec.AuthOpt != nil {
-			pwd, ok := spec.EncodedPassword()
+			pwd, ok := encodedPassword(spec)
 			if !ok {
 				return errors.Trace(ErrPasswordFormat)
 			}
-			stmt, err := exec.ParseWithParams(ctx, `UPDATE %n.%n SET authentication_string=%? WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, spec.User.Hostname, spec.User.Username)
+			var policy passwordReusePolicy
+			if !ldap.IsAuthPlugin(authplugin) {
+				policy, err = checkNewPassword(ctx, e.ctx, spec.User, authOptString(spec.AuthOpt), spec.AuthOpt.ByAuthString, pwd)
+				if err != nil {
+					return err
+				}
+			}
+			stmt, err := exec.ParseWithParams(ctx, `UPDATE %n.%n SET authentication_string=%?, Password_last_changed=NOW(), Password_expired='N' WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, spec.User.Hostname, spec.User.Username)
 			if err != nil {
 				return err
 			}
 			_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
 			if err != nil {
 				failedUsers = append(failedUsers, spec.User.String())
+			} else if err = recordPasswordHistory(ctx, e.ctx, spec.User, policy, pwd); err != nil {
+				return err
 			}
+			e.leaveSandBoxMode(spec.User.Username, spec.User.Hostname)
+		}
+
+		if err := applyPasswordOrLockOptions(ctx, e.ctx, spec.User, assignments); err != nil {
+			failedUsers = append(failedUsers, spec.User.String())
 		}
 
 		if len(privData) > 0 {
@@ -1119,6 +1164,12 @@ func (e *SimpleExec) executeRenameUser(s *ast.RenameUserStmt) error {
 			break
 		}
 
+		// rename the password history from mysql.password_history
+		if err = renameUserHostInSystemTable(sqlExecutor, passwordHistoryTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " mysql." + passwordHistoryTable + " error"
+			break
+		}
+
 		//TODO: need update columns_priv once we implement columns_priv functionality.
 		// When that is added, please refactor both executeRenameUser and executeDropUser to use an array of tables
 		// to loop over, so it is easier to maintain.
@@ -1281,6 +1332,14 @@ func (e *SimpleExec) executeDropUser(ctx context.Context, s *ast.DropUserStmt) e
 			break
 		}
 
+		// delete the password history from mysql.password_history
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, passwordHistoryTable, user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
 		//TODO: need delete columns_priv once we implement columns_priv functionality.
 	}
 
@@ -1335,6 +1394,18 @@ func userExistsInternal(sqlExecutor sqlexec.SQLExecutor, name string, host strin
 	return rows > 0, err
 }
 
+// encodedPassword returns the authentication string of the user spec. The authentication string of
+// the LDAP plugins is the user DN, which is stored as is.
+func encodedPassword(spec *ast.UserSpec) (string, bool) {
+	if spec.AuthOpt != nil && ldap.IsAuthPlugin(spec.AuthOpt.AuthPlugin) {
+		if spec.AuthOpt.ByAuthString {
+			return spec.AuthOpt.AuthString, true
+		}
+		return spec.AuthOpt.HashString, true
+	}
+	return spec.EncodedPassword()
+}
+
 func (e *SimpleExec) userAuthPlugin(name string, host string) (string, error) {
 	pm := privilege.GetPrivilegeManager(e.ctx)
 	authplugin, err := pm.GetAuthPlugin(name, host)
@@ -1373,24 +1444,44 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 	if err != nil {
 		return err
 	}
+	if ldap.IsAuthPlugin(authplugin) {
+		return ErrSetPasswordAuthPlugin.GenWithStackByArgs()
+	}
 	var pwd string
 	if authplugin == mysql.AuthCachingSha2Password {
 		pwd = auth.NewSha2Password(s.Password)
 	} else {
 		pwd = auth.EncodePassword(s.Password)
 	}
+	user := &auth.UserIdentity{Username: u, Hostname: h}
+	policy, err := checkNewPassword(ctx, e.ctx, user, s.Password, true, pwd)
+	if err != nil {
+		return err
+	}
 
 	// update mysql.user
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	stmt, err := exec.ParseWithParams(ctx, `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
+	stmt, err := exec.ParseWithParams(ctx, `UPDATE %n.%n SET authentication_string=%?, Password_last_changed=NOW(), Password_expired='N' WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
 	if err != nil {
 		return err
 	}
 	_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	if err == nil {
+		err = recordPasswordHistory(ctx, e.ctx, user, policy, pwd)
+		e.leaveSandBoxMode(u, h)
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
 	return err
 }
 
+// leaveSandBoxMode lets the session leave the sandbox mode after the expired password of the current user is changed.
+func (e *SimpleExec) leaveSandBoxMode(u, h string) {
+	vars := e.ctx.GetSessionVars()
+	if vars.User != nil && vars.User.AuthUsername == u && vars.User.AuthHostname == h {
+		vars.InSandBoxMode = false
+	}
+}
+
 func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error {
 	if !config.GetGlobalConfig().Experimental.EnableGlobalKill {
 		conf := config.GetGlobalConfig()
@@ -16,6 +16,7 @@ package executor_test
 
 import (
 	"context"
+	"os"
 	"strconv"
 
 	. "github.com/pingcap/check"
@@ -514,6 +515,102 @@ func (s *testSuite3) TestSetPwd(c *C) {
 
 }
 
+func (s *testSuite3) TestValidatePasswordStrength(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'testvalidate'@'localhost'")
+	tk.MustExec("SET GLOBAL validate_password_enable = ON")
+	defer func() {
+		tk.MustExec("SET GLOBAL validate_password_enable = DEFAULT")
+		tk.MustExec("SET GLOBAL validate_password_policy = DEFAULT")
+		tk.MustExec("SET GLOBAL validate_password_check_user_name = DEFAULT")
+		tk.MustExec("SET GLOBAL validate_password_dictionary_file = DEFAULT")
+		tk.MustExec("DROP USER 'testvalidate'@'localhost'")
+	}()
+	checkInvalid := func(sql string) {
+		err := tk.ExecToErr(sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	}
+
+	// MEDIUM policy requires the length, digits, mixed case and special characters.
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'Ab1!'")
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'Abcdefg1'")
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'abcdef1!'")
+	checkInvalid("SET PASSWORD FOR 'testvalidate'@'localhost' = 'abcdefgh'")
+	checkInvalid("CREATE USER 'testvalidate2'@'localhost' IDENTIFIED BY 'abc'")
+	tk.MustExec("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'Abcdef1!'")
+	// The password hash can't be validated.
+	tk.MustExec("ALTER USER 'testvalidate'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '*0D3CED9BEC10A777AEC23CCC353A8C08A633045E'")
+
+	// LOW policy only checks the length.
+	tk.MustExec("SET GLOBAL validate_password_policy = LOW")
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'abcdefg'")
+	tk.MustExec("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'abcdefgh'")
+
+	tk.MustExec("SET GLOBAL validate_password_check_user_name = ON")
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'testvalidate'")
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'etadilavtset'")
+
+	// STRONG policy also rejects the passwords containing the words in the dictionary.
+	file, err := os.CreateTemp("", "dictionary")
+	c.Assert(err, IsNil)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("password\nabc\n")
+	c.Assert(err, IsNil)
+	c.Assert(file.Close(), IsNil)
+	tk.MustExec("SET GLOBAL validate_password_policy = STRONG")
+	tk.MustExec("SET GLOBAL validate_password_dictionary_file = ?", file.Name())
+	checkInvalid("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'myPassWord1!'")
+	tk.MustExec("ALTER USER 'testvalidate'@'localhost' IDENTIFIED BY 'Abcdef1!'")
+}
+
+func (s *testSuite3) TestPasswordReusePolicy(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd1'")
+	defer func() {
+		tk.MustExec("SET GLOBAL password_history = DEFAULT")
+		tk.MustExec("SET GLOBAL password_reuse_interval = DEFAULT")
+	}()
+	checkHistory := func(expected string) {
+		tk.MustQuery("SELECT COUNT(*) FROM mysql.password_history WHERE User = 'testreuse' AND Host = 'localhost'").Check(testkit.Rows(expected))
+	}
+	checkReused := func(sql string) {
+		err := tk.ExecToErr(sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictHistory), IsTrue, Commentf("err %v", err))
+	}
+
+	// The passwords are neither checked nor recorded without the reuse policy.
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd1'")
+	checkHistory("0")
+
+	tk.MustExec("SET GLOBAL password_history = 2")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd2'")
+	checkReused("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd2'")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd3'")
+	checkReused("SET PASSWORD FOR 'testreuse'@'localhost' = 'pwd2'")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd4'")
+	checkHistory("2")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd2'")
+
+	// The policy of the account overrides the global one.
+	tk.MustExec("UPDATE mysql.user SET Password_reuse_history = 0, Password_reuse_time = 0 WHERE User = 'testreuse' AND Host = 'localhost'")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd4'")
+	checkHistory("0")
+
+	tk.MustExec("UPDATE mysql.user SET Password_reuse_history = NULL, Password_reuse_time = NULL WHERE User = 'testreuse' AND Host = 'localhost'")
+	tk.MustExec("SET GLOBAL password_history = 0")
+	tk.MustExec("SET GLOBAL password_reuse_interval = 1")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd5'")
+	tk.MustExec("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd6'")
+	checkReused("ALTER USER 'testreuse'@'localhost' IDENTIFIED BY 'pwd5'")
+	checkHistory("2")
+
+	tk.MustExec("RENAME USER 'testreuse'@'localhost' TO 'testreuse2'@'localhost'")
+	checkHistory("0")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.password_history WHERE User = 'testreuse2' AND Host = 'localhost'").Check(testkit.Rows("2"))
+	tk.MustExec("DROP USER 'testreuse2'@'localhost'")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.password_history WHERE User = 'testreuse2' AND Host = 'localhost'").Check(testkit.Rows("0"))
+}
+
 func (s *testSuite3) TestKillStmt(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
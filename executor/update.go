@@ -57,6 +57,8 @@ type UpdateExec struct {
 	virtualAssignmentsOffset  int
 	drained                   bool
 	memTracker                *memory.Tracker
+	fkChecker                 *foreignKeyChecker
+	constraintChecker         *checkConstraintChecker
 
 	stats *runtimeStatsWithSnapshot
 
@@ -186,14 +188,14 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		flags := bAssignFlag[content.Start:content.End]
 
 		// Update row
-		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker)
+		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker, e.fkChecker, e.constraintChecker)
 		if err1 == nil {
 			e.updatedRowKeys[content.Start].Set(handle, changed)
 			continue
 		}
 
 		sc := e.ctx.GetSessionVars().StmtCtx
-		if kv.ErrKeyExists.Equal(err1) && sc.DupKeyAsWarning {
+		if (kv.ErrKeyExists.Equal(err1) || table.ErrCheckConstraintViolated.Equal(err1)) && sc.DupKeyAsWarning {
 			sc.AppendWarning(err1)
 			continue
 		}
@@ -16,15 +16,21 @@ package executor
 
 import (
 	"context"
+	"sort"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // WindowExec is the executor for window functions.
@@ -36,162 +42,316 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// partition stores the rows of the current partition, it may be spilled to disk.
+	partition *windowPartition
+	// producedRows is the number of rows in the partition that have been returned.
+	producedRows uint64
+	// childColIdxs stores the indices of the child columns in the output.
+	childColIdxs []int
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.executed = false
+	e.producedRows = 0
+	e.groupChecker.reset()
+	e.processor.resetPartialResult()
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.childColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.childColIdxs = append(e.childColIdxs, col.Index)
+	}
+
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.childResult = newFirstChunk(e.children[0])
+	e.memTracker.Consume(e.childResult.MemoryUsage())
+
+	e.partition = newWindowPartition(retTypes(e.children[0]), e.maxChunkSize)
+	e.partition.rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	e.partition.rowContainer.GetMemTracker().SetLabel(memory.LabelForWindowPartition)
+	e.partition.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
+	e.partition.rowContainer.GetDiskTracker().SetLabel(memory.LabelForWindowPartition)
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		actionSpill := e.partition.rowContainer.ActionSpill()
+		failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				actionSpill = e.partition.rowContainer.ActionSpillForTest()
+				defer actionSpill.WaitForTest()
+			}
+		})
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(actionSpill)
+	}
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
+	if e.partition != nil {
+		e.processor.resetPartialResult()
+		if err := e.partition.close(); err != nil {
+			return err
+		}
+		e.partition = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.childResult.MemoryUsage())
+		e.childResult = nil
+	}
 	return errors.Trace(e.baseExecutor.Close())
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
-		err := e.consumeOneGroup(ctx)
-		if err != nil {
+	for !chk.IsFull() {
+		if e.producedRows < e.partition.NumRows() {
+			if err := e.appendResult2Chunk(chk); err != nil {
+				e.executed = true
+				return err
+			}
+			continue
+		}
+		if e.executed {
+			break
+		}
+		if err := e.consumeOnePartition(ctx); err != nil {
 			e.executed = true
 			return err
 		}
 	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
-	}
 	return nil
 }
 
-func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
-}
-
-func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
-	if e.groupChecker.isExhausted() {
-		eof, err := e.fetchChild(ctx)
-		if err != nil {
-			return errors.Trace(err)
+// consumeOnePartition fetches all the rows of the next partition into e.partition, and consumes them by the processor.
+func (e *WindowExec) consumeOnePartition(ctx context.Context) error {
+	// The partial results may reference the rows of the last partition, reset them before reusing the row container.
+	e.processor.resetPartialResult()
+	if err := e.partition.reset(); err != nil {
+		return err
+	}
+	e.producedRows = 0
+	for {
+		if e.groupChecker.isExhausted() {
+			eof, err := e.fetchChild(ctx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if eof {
+				e.executed = true
+				break
+			}
+			isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !isFirstGroupSameAsPrev && e.partition.NumRows() > 0 {
+				break
+			}
 		}
-		if eof {
-			e.executed = true
-			return e.consumeGroupRows(groupRows)
+		begin, end := e.groupChecker.getNextGroup()
+		if err := e.partition.add(e.childResult, begin, end); err != nil {
+			return err
 		}
-		_, err = e.groupChecker.splitIntoGroups(e.childResult)
-		if err != nil {
-			return errors.Trace(err)
+		if end < e.childResult.NumRows() {
+			break
 		}
 	}
-	begin, end := e.groupChecker.getNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
+	if e.partition.NumRows() == 0 {
+		return nil
 	}
+	return e.processor.consumeGroupRows(e.ctx, e.partition)
+}
 
-	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
-		meetLastGroup = false
-		eof, err := e.fetchChild(ctx)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if eof {
-			e.executed = true
-			return e.consumeGroupRows(groupRows)
-		}
+// appendResult2Chunk appends the next rows of the partition and their window function results to chk.
+func (e *WindowExec) appendResult2Chunk(chk *chunk.Chunk) error {
+	remained := mathutil.Min(chk.RequiredRows()-chk.NumRows(), int(e.partition.NumRows()-e.producedRows))
+	for i := 0; i < remained; i++ {
+		chk.AppendPartialRowByColIdxs(0, e.partition.GetRow(e.producedRows+uint64(i)), e.childColIdxs)
+	}
+	if err := e.partition.error(); err != nil {
+		return err
+	}
+	if err := e.processor.appendResult2Chunk(e.ctx, e.partition, chk, remained); err != nil {
+		return err
+	}
+	e.producedRows += uint64(remained)
+	return e.partition.error()
+}
 
-		isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
-		if err != nil {
-			return errors.Trace(err)
-		}
+func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
+	oldMemUsage := e.childResult.MemoryUsage()
+	err = Next(ctx, e.children[0], e.childResult)
+	e.memTracker.Consume(e.childResult.MemoryUsage() - oldMemUsage)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	// No more data.
+	return e.childResult.NumRows() == 0, nil
+}
 
-		if isFirstGroupSameAsPrev {
-			begin, end = e.groupChecker.getNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
-			}
-			meetLastGroup = end == e.childResult.NumRows()
-		}
+// windowChunkCacheSize is the number of chunks cached by windowPartition.
+const windowChunkCacheSize = 4
+
+// windowPartition stores the rows of a window partition. The rows are kept in a chunk.RowContainer,
+// so they are spilled to disk when the memory quota of the query is exceeded.
+type windowPartition struct {
+	rowContainer *chunk.RowContainer
+	// chkOffsets stores the index of the first row of each chunk in the row container.
+	chkOffsets []uint64
+	numRows    uint64
+	lastChkIdx int
+
+	// chkCache caches the recently used chunks, so the rows of a spilled partition
+	// are not read from disk one by one.
+	chkCache     [windowChunkCacheSize]*chunk.Chunk
+	chkCacheIdxs [windowChunkCacheSize]int
+	nextCacheIdx int
+
+	rowsBuf []chunk.Row
+	// nullRow is returned by GetRow when an error occurs, the error can be got by error().
+	nullRow chunk.Row
+	err     error
+}
+
+func newWindowPartition(fieldTypes []*types.FieldType, chunkSize int) *windowPartition {
+	p := &windowPartition{
+		rowContainer: chunk.NewRowContainer(fieldTypes, chunkSize),
+		nullRow:      chunk.MutRowFromTypes(fieldTypes).ToRow(),
 	}
-	return e.consumeGroupRows(groupRows)
+	p.resetCache()
+	return p
 }
 
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
-	if remainingRowsInGroup == 0 {
+// add appends the rows [begin, end) of src to the partition.
+func (p *windowPartition) add(src *chunk.Chunk, begin, end int) error {
+	if begin >= end {
 		return nil
 	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := mathutil.Min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
-
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.ctx, groupRows)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		_, err = e.processor.appendResult2Chunk(e.ctx, groupRows, e.resultChunks[i], remained)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if remainingRowsInGroup == 0 {
-			e.processor.resetPartialResult()
-			break
-		}
+	chk := p.rowContainer.AllocChunk()
+	chk.Append(src, begin, end)
+	if err := p.rowContainer.Add(chk); err != nil {
+		return err
 	}
+	p.chkOffsets = append(p.chkOffsets, p.numRows)
+	p.numRows += uint64(end - begin)
 	return nil
 }
 
-func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+// NumRows implements the aggfuncs.PartitionRows interface.
+func (p *windowPartition) NumRows() uint64 {
+	return p.numRows
+}
+
+// GetRow implements the aggfuncs.PartitionRows interface.
+func (p *windowPartition) GetRow(idx uint64) chunk.Row {
+	chkIdx := p.chunkIdxOf(idx)
+	chk, err := p.getChunk(chkIdx)
 	if err != nil {
-		return false, errors.Trace(err)
+		if p.err == nil {
+			p.err = err
+		}
+		return p.nullRow
 	}
-	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
-		return true, nil
+	return chk.GetRow(int(idx - p.chkOffsets[chkIdx]))
+}
+
+// getRows returns the rows [start, end) of the partition, the returned slice is reused by the next call.
+func (p *windowPartition) getRows(start, end uint64) []chunk.Row {
+	p.rowsBuf = p.rowsBuf[:0]
+	for idx := start; idx < end; idx++ {
+		p.rowsBuf = append(p.rowsBuf, p.GetRow(idx))
 	}
+	return p.rowsBuf
+}
+
+func (p *windowPartition) numChunks() int {
+	return len(p.chkOffsets)
+}
 
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
+// getChunkRows returns the rows of the chkIdx-th chunk, the returned slice is reused by the next call.
+func (p *windowPartition) getChunkRows(chkIdx int) ([]chunk.Row, error) {
+	chk, err := p.getChunk(chkIdx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
+	p.rowsBuf = p.rowsBuf[:0]
+	for i := 0; i < chk.NumRows(); i++ {
+		p.rowsBuf = append(p.rowsBuf, chk.GetRow(i))
+	}
+	return p.rowsBuf, nil
+}
 
-	e.childResult = childResult
-	return false, nil
+func (p *windowPartition) chunkIdxOf(idx uint64) int {
+	if idx >= p.chkOffsets[p.lastChkIdx] && (p.lastChkIdx+1 == len(p.chkOffsets) || idx < p.chkOffsets[p.lastChkIdx+1]) {
+		return p.lastChkIdx
+	}
+	p.lastChkIdx = sort.Search(len(p.chkOffsets), func(i int) bool { return p.chkOffsets[i] > idx }) - 1
+	return p.lastChkIdx
 }
 
-func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
+func (p *windowPartition) getChunk(chkIdx int) (*chunk.Chunk, error) {
+	for i, idx := range p.chkCacheIdxs {
+		if idx == chkIdx {
+			return p.chkCache[i], nil
 		}
 	}
-	return nil
+	chk, err := p.rowContainer.GetChunk(chkIdx)
+	if err != nil {
+		return nil, err
+	}
+	p.chkCache[p.nextCacheIdx] = chk
+	p.chkCacheIdxs[p.nextCacheIdx] = chkIdx
+	p.nextCacheIdx = (p.nextCacheIdx + 1) % windowChunkCacheSize
+	return chk, nil
+}
+
+func (p *windowPartition) resetCache() {
+	for i := range p.chkCache {
+		p.chkCache[i] = nil
+		p.chkCacheIdxs[i] = -1
+	}
+	p.nextCacheIdx = 0
+}
+
+// error returns the error occurred in GetRow or getRows.
+func (p *windowPartition) error() error {
+	return p.err
+}
+
+func (p *windowPartition) reset() error {
+	p.chkOffsets = p.chkOffsets[:0]
+	p.numRows = 0
+	p.lastChkIdx = 0
+	p.resetCache()
+	p.err = nil
+	return p.rowContainer.Reset()
+}
+
+func (p *windowPartition) close() error {
+	p.resetCache()
+	p.rowsBuf = nil
+	return p.rowContainer.Close()
 }
 
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
-	// consumeGroupRows updates the result for an window function using the input rows
-	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
-	// appendResult2Chunk appends the final results to chunk.
-	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	// consumeGroupRows updates the result for an window function using the rows of the partition.
+	consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error
+	// appendResult2Chunk appends the final results of the next `remained` rows in the partition to chunk.
+	// It is called when all the rows of current partition are consumed.
+	appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -201,30 +361,50 @@ type aggWindowProcessor struct {
 	partialResults []aggfuncs.PartialResult
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	var rowsFuncs []aggfuncs.AggFunc
 	for i, windowFunc := range p.windowFuncs {
-		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+		if f, ok := windowFunc.(aggfuncs.PartitionRowsWindowFunc); ok {
+			f.SetPartitionRows(p.partialResults[i], rows)
+			continue
+		}
+		rowsFuncs = append(rowsFuncs, windowFunc)
+	}
+	if len(rowsFuncs) == 0 {
+		return nil
+	}
+	// The rows are consumed chunk by chunk, so a spilled partition is not read into memory at once.
+	for chkIdx := 0; chkIdx < rows.numChunks(); chkIdx++ {
+		chkRows, err := rows.getChunkRows(chkIdx)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for i, windowFunc := range p.windowFuncs {
+			if _, ok := windowFunc.(aggfuncs.PartitionRowsWindowFunc); ok {
+				continue
+			}
+			// @todo Add memory trace
+			_, err = windowFunc.UpdatePartialResult(ctx, chkRows, p.partialResults[i])
+			if err != nil {
+				return err
+			}
 		}
 	}
-	rows = rows[:0]
-	return rows, nil
+	return nil
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -287,12 +467,12 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
+	numRows := rows.NumRows()
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -320,16 +500,14 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = slidingWindowAggFunc.Slide(ctx, rows.GetRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -338,9 +516,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = slidingWindowAggFunc.Slide(ctx, rows.GetRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
 				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
 				// whether elements inside deque are out of current window.
@@ -348,14 +524,14 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				_, err = windowFunc.UpdatePartialResult(ctx, rows.getRows(start, end), p.partialResults[i])
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -368,7 +544,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return rows.error()
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -388,16 +564,16 @@ type rangeFrameWindowProcessor struct {
 	expectedCmpResult int64
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows *windowPartition) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
+	numRows := rows.NumRows()
 	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
 		var res int64
 		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows.GetRow(p.lastStartOffset), rows.GetRow(p.curRowIdx))
 			if err != nil {
 				return 0, err
 			}
@@ -414,8 +590,8 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows *windowPartition) (uint64, error) {
+	numRows := rows.NumRows()
 	if p.end.UnBounded {
 		return numRows, nil
 	}
@@ -423,7 +599,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 		var res int64
 		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows.GetRow(p.curRowIdx), rows.GetRow(p.lastEndOffset))
 			if err != nil {
 				return 0, err
 			}
@@ -440,7 +616,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -460,11 +636,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start, err = p.getStartOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		end, err = p.getEndOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		p.curRowIdx++
 		remained--
@@ -474,16 +650,14 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = slidingWindowAggFunc.Slide(ctx, rows.GetRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -492,21 +666,19 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = slidingWindowAggFunc.Slide(ctx, rows.GetRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
 				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				_, err = windowFunc.UpdatePartialResult(ctx, rows.getRows(start, end), p.partialResults[i])
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -519,11 +691,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return rows.error()
 }
 
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	return nil
 }
 
 func (p *rangeFrameWindowProcessor) resetPartialResult() {
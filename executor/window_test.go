@@ -15,9 +15,11 @@
 package executor_test
 
 import (
+	"bytes"
 	"fmt"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -471,3 +473,45 @@ func (s *testSuite7) TestIssue24264(c *C) {
 		"8297270320597030697",
 		"<nil>"))
 }
+
+func (s *testSerialSuite1) TestWindowInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values ")
+	for i := 0; i < 1024; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(fmt.Sprintf("(%v, %v, %v)", i%3, i, i%7))
+	}
+	tk.MustExec(buf.String())
+	tk.MustExec("set @@tidb_max_chunk_size=32")
+
+	sqls := []string{
+		"select a, b, lag(b, 3) over w, lead(b, 100, -1) over w from t window w as (partition by a order by b)",
+		"select a, b, rank() over w, percent_rank() over w, cume_dist() over w from t window w as (partition by a order by c)",
+		"select a, b, sum(b) over (partition by a), count(c) over (partition by a) from t order by a, b",
+		"select a, b, sum(b) over w, max(c) over w from t window w as (partition by a order by b rows between 5 preceding and unbounded following)",
+		"select a, b, sum(b) over w from t window w as (partition by a order by c, b range between current row and unbounded following)",
+		"select b, row_number() over w, first_value(b) over w, last_value(b) over w from t window w as (order by b desc)",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_mem_quota_query=default")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Equals, int64(0))
+
+		tk.MustExec("set @@tidb_mem_quota_query=1")
+		tk.MustQuery(sql).Sort().Check(expected)
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.BytesConsumed(), Equals, int64(0))
+	}
+}
@@ -46,11 +46,13 @@ var (
 // updateRecord updates the row specified by the handle `h`, from `oldData` to `newData`.
 // `modified` means which columns are really modified. It's used for secondary indices.
 // Length of `oldData` and `newData` equals to length of `t.WritableCols()`.
+// `fkChecker` checks the FOREIGN KEY constraints and applies the ON UPDATE actions, it can be nil.
+// `constraintChecker` checks the CHECK constraints of the new row.
 // The return values:
 //     1. changed (bool) : does the update really change the row values. e.g. update set i = 1 where i = 1;
 //     2. err (error) : error in the update.
 func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, modified []bool, t table.Table,
-	onDup bool, memTracker *memory.Tracker) (bool, error) {
+	onDup bool, memTracker *memory.Tracker, fkChecker *foreignKeyChecker, constraintChecker *checkConstraintChecker) (bool, error) {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("executor.updateRecord", opentracing.ChildOf(span.Context()))
 		defer span1.Finish()
@@ -171,7 +173,15 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 	}
 
-	// 5. If handle changed, remove the old then add the new record, otherwise update the record.
+	// 5. Check the CHECK and foreign key constraints before the row is written.
+	if err = constraintChecker.checkRow(t, newData); err != nil {
+		return false, err
+	}
+	if err = fkChecker.checkUpdate(ctx, t, h, oldData, newData, modified); err != nil {
+		return false, err
+	}
+
+	// 6. If handle changed, remove the old then add the new record, otherwise update the record.
 	if handleChanged {
 		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`
 		// we use the staging buffer so that we don't need to precheck the existence of handle or unique keys by sending
@@ -211,6 +221,11 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 
 	}
+
+	// 7. Apply the ON UPDATE actions to the child rows referring to the row.
+	if err = fkChecker.onUpdate(ctx, t, h, oldData, newData, modified); err != nil {
+		return false, err
+	}
 	if onDup {
 		sc.AddAffectedRows(2)
 	} else {
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
@@ -4089,6 +4090,49 @@ func (s *testSuite) TestIssue21232(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("a", "b"))
 }
 
+func (s *testSuite) TestCheckConstraint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t(a int primary key, b int, c int, constraint b_chk check (b > 0), check (b < c))")
+	tk.MustExec("insert into t values (1, 1, 2), (2, null, 2)")
+	err := tk.ExecToErr("insert into t values (3, 3, 2)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[table:3819]Check constraint 't_chk_1' is violated.")
+	tk.MustGetErrCode("insert into t values (3, 0, 2)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("replace into t values (1, 0, 2)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t set b = c + 1", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t values (1, 1, 2) on duplicate key update b = 0", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 2", "2 <nil> 2"))
+
+	// IGNORE turns the violations into warnings.
+	tk.MustExec("insert ignore into t values (3, 0, 2), (4, 1, 2)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 'b_chk' is violated."))
+	tk.MustExec("update ignore t set b = b + 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 't_chk_1' is violated.", "Warning 3819 Check constraint 't_chk_1' is violated."))
+	tk.MustExec("insert ignore into t values (2, 1, 3) on duplicate key update b = -1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 'b_chk' is violated."))
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 2", "2 <nil> 2", "4 1 2"))
+
+	// The constraints are enforced by the statements, the internal writes of the table don't evaluate them.
+	tbl, err := s.domain.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tk.MustExec("begin")
+	_, err = tbl.AddRecord(tk.Se, types.MakeDatums(5, 0, 2))
+	c.Assert(err, IsNil)
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t where a = 5").Check(testkit.Rows("5 0 2"))
+	tk.MustExec("delete from t where a = 5")
+
+	// The constraints are checked for the partitioned tables.
+	tk.MustExec("create table tp(a int, b int, check (a < b)) partition by hash(a) partitions 4")
+	tk.MustExec("insert into tp values (1, 2), (2, 3)")
+	tk.MustGetErrCode("insert into tp values (3, 3)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update tp set a = 5 where a = 1", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from tp order by a").Check(testkit.Rows("1 2", "2 3"))
+	tk.MustExec("drop table t, tp")
+}
+
 func testEqualDatumsAsBinary(c *C, a []interface{}, b []interface{}, same bool) {
 	sc := new(stmtctx.StatementContext)
 	re := new(executor.ReplaceExec)
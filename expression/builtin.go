@@ -842,6 +842,10 @@ var funcs = map[string]functionClass{
 	ast.IsFalsity:          &isTrueOrFalseFunctionClass{baseFunctionClass{ast.IsFalsity, 1, 1}, opcode.IsFalsity, false},
 	ast.Like:               &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
 	ast.Regexp:             &regexpFunctionClass{baseFunctionClass{ast.Regexp, 2, 2}},
+	RegexpLike:             &regexpLikeFunctionClass{baseFunctionClass{RegexpLike, 2, 3}},
+	RegexpInStr:            &regexpInStrFunctionClass{baseFunctionClass{RegexpInStr, 2, 6}},
+	RegexpSubstr:           &regexpSubstrFunctionClass{baseFunctionClass{RegexpSubstr, 2, 5}},
+	RegexpReplace:          &regexpReplaceFunctionClass{baseFunctionClass{RegexpReplace, 3, 6}},
 	ast.Case:               &caseWhenFunctionClass{baseFunctionClass{ast.Case, 1, -1}},
 	ast.RowFunc:            &rowFunctionClass{baseFunctionClass{ast.RowFunc, 2, -1}},
 	ast.SetVar:             &setVarFunctionClass{baseFunctionClass{ast.SetVar, 2, 2}},
@@ -893,6 +897,8 @@ var funcs = map[string]functionClass{
 	ast.JSONDepth:         &jsonDepthFunctionClass{baseFunctionClass{ast.JSONDepth, 1, 1}},
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
+	JSONMemberOf:          &jsonMemberOfFunctionClass{baseFunctionClass{JSONMemberOf, 2, 2}},
+	JSONOverlaps:          &jsonOverlapsFunctionClass{baseFunctionClass{JSONOverlaps, 2, 2}},
 
 	// TiDB internal function.
 	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
@@ -452,6 +452,67 @@ func (s *testEvaluatorSuite) TestJSONContains(c *C) {
 	}
 }
 
+func (s *testEvaluatorSuite) TestJSONMemberOf(c *C) {
+	fc := funcs[JSONMemberOf]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+	}{
+		{[]interface{}{nil, `[1, 2]`}, nil},
+		{[]interface{}{1, nil}, nil},
+		{[]interface{}{1, `[1, 2]`}, 1},
+		{[]interface{}{3, `[1, 2]`}, 0},
+		{[]interface{}{"a", `["a", "b"]`}, 1},
+		// The string isn't parsed as JSON.
+		{[]interface{}{"1", `[1, 2]`}, 0},
+		{[]interface{}{1, `1`}, 1},
+		{[]interface{}{1, `{"a": 1}`}, 0},
+	}
+	for _, t := range tbl {
+		args := types.MakeDatums(t.input...)
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetInt64(), Equals, int64(t.expected.(int)))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONOverlaps(c *C) {
+	fc := funcs[JSONOverlaps]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+	}{
+		{[]interface{}{nil, `[1, 2]`}, nil},
+		{[]interface{}{`[1, 2]`, nil}, nil},
+		{[]interface{}{`[1, 2]`, `[2, 3]`}, 1},
+		{[]interface{}{`[1, 2]`, `[3]`}, 0},
+		{[]interface{}{`[]`, `[]`}, 0},
+		{[]interface{}{`1`, `[1, 2]`}, 1},
+		{[]interface{}{`[1, 2]`, `2`}, 1},
+		{[]interface{}{`{"a": 1}`, `{"a": 1, "b": 2}`}, 1},
+		{[]interface{}{`{"a": 1}`, `{"a": 2}`}, 0},
+		{[]interface{}{`{"a": 1}`, `[{"a": 1}]`}, 1},
+	}
+	for _, t := range tbl {
+		args := types.MakeDatums(t.input...)
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetInt64(), Equals, int64(t.expected.(int)))
+		}
+	}
+}
+
 func (s *testEvaluatorSuite) TestJSONContainsPath(c *C) {
 	fc := funcs[ast.JSONContainsPath]
 	jsonString := `{"a": 1, "b": 2, "c": {"d": 4}}`
@@ -1266,3 +1266,56 @@ func (b *builtinJSONMergePatchSig) vecEvalJSON(input *chunk.Chunk, result *chunk
 
 	return nil
 }
+
+func (b *builtinJSONMemberOfSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONMemberOfSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecJSONPredicate(b.ctx, b.args, b.bufAllocator, input, result, json.MemberOfBinary)
+}
+
+func (b *builtinJSONOverlapsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONOverlapsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecJSONPredicate(b.ctx, b.args, b.bufAllocator, input, result, json.OverlapsBinary)
+}
+
+// vecJSONPredicate evaluates the predicate on the two JSON arguments for the rows of the input.
+func vecJSONPredicate(ctx sessionctx.Context, args []Expression, bufAllocator columnBufferAllocator, input *chunk.Chunk, result *chunk.Column,
+	predicate func(json.BinaryJSON, json.BinaryJSON) bool) error {
+	nr := input.NumRows()
+	lhsCol, err := bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer bufAllocator.put(lhsCol)
+	if err := args[0].VecEvalJSON(ctx, input, lhsCol); err != nil {
+		return err
+	}
+	rhsCol, err := bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer bufAllocator.put(rhsCol)
+	if err := args[1].VecEvalJSON(ctx, input, rhsCol); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(nr, false)
+	result.MergeNulls(lhsCol, rhsCol)
+	i64s := result.Int64s()
+	for i := 0; i < nr; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if predicate(lhsCol.GetJSON(i), rhsCol.GetJSON(i)) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
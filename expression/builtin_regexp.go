@@ -0,0 +1,548 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// The names of the REGEXP_* functions introduced in MySQL 8.0.
+const (
+	RegexpLike    = "regexp_like"
+	RegexpInStr   = "regexp_instr"
+	RegexpSubstr  = "regexp_substr"
+	RegexpReplace = "regexp_replace"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeFuncSig{}
+	_ builtinFunc = &builtinRegexpInStrFuncSig{}
+	_ builtinFunc = &builtinRegexpSubstrFuncSig{}
+	_ builtinFunc = &builtinRegexpReplaceFuncSig{}
+)
+
+const (
+	regexpInvalidMatchType    = "Invalid match type"
+	regexpIndexOutOfBounds    = "Index out of bounds in regular expression search"
+	regexpInvalidReturnOption = "Incorrect arguments to regexp_instr: return_option must be 1 or 0"
+)
+
+// regexpBaseFuncSig is the shared part of the REGEXP_* functions. The first two arguments are the
+// expression and the pattern, the optional match type argument is at matchTypeIdx.
+//
+// The positions are counted in characters, or in bytes if the collation is binary. The pattern is
+// case-insensitive if the collation is, which can be overridden by the match type.
+type regexpBaseFuncSig struct {
+	baseBuiltinFunc
+	matchTypeIdx int
+
+	// memorizedRegexp and memorizedErr are the compiled regexp when the pattern and the match type are constant.
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+	isMemorized     bool
+	once            sync.Once
+}
+
+func (re *regexpBaseFuncSig) clone(from *regexpBaseFuncSig) {
+	re.cloneFrom(&from.baseBuiltinFunc)
+	re.matchTypeIdx = from.matchTypeIdx
+}
+
+// setRegexpCollationByPB sets the collation of the REGEXP_* function built from the protobuf, the collation
+// of the int result doesn't tell how the strings are matched.
+func setRegexpCollationByPB(bf *baseBuiltinFunc, isBinary bool) {
+	if isBinary {
+		bf.SetCharsetAndCollation(charset.CharsetBin, charset.CollationBin)
+		return
+	}
+	if bf.collation == charset.CollationBin {
+		tp := bf.args[0].GetType()
+		bf.SetCharsetAndCollation(tp.Charset, tp.Collate)
+		bf.setCollator(collate.GetCollator(tp.Collate))
+	}
+}
+
+func (re *regexpBaseFuncSig) isBinaryCollation() bool {
+	return re.collation == charset.CollationBin
+}
+
+// getRegexpFlags returns the flags of the Go regexp according to the collation and the match type.
+func (re *regexpBaseFuncSig) getRegexpFlags(matchType string) (string, error) {
+	caseInsensitive := collate.IsCICollation(re.collation)
+	var flags strings.Builder
+	for _, c := range matchType {
+		switch c {
+		case 'c':
+			caseInsensitive = false
+		case 'i':
+			caseInsensitive = true
+		case 'm':
+			flags.WriteByte('m')
+		case 'n':
+			flags.WriteByte('s')
+		case 'u':
+			// Only the '\n' is recognized as the line terminator by the Go regexp.
+		default:
+			return "", ErrRegexp.GenWithStackByArgs(regexpInvalidMatchType)
+		}
+	}
+	// The binary strings are always matched in case-sensitive fashion.
+	if caseInsensitive && !re.isBinaryCollation() {
+		flags.WriteByte('i')
+	}
+	return flags.String(), nil
+}
+
+func (re *regexpBaseFuncSig) compile(pat, matchType string) (*regexp.Regexp, error) {
+	flags, err := re.getRegexpFlags(matchType)
+	if err != nil {
+		return nil, err
+	}
+	if len(flags) > 0 {
+		pat = "(?" + flags + ")" + pat
+	}
+	r, err := regexp.Compile(pat)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return r, nil
+}
+
+func (re *regexpBaseFuncSig) canMemorize() bool {
+	sc := re.ctx.GetSessionVars().StmtCtx
+	return re.args[1].ConstItem(sc) && (re.matchTypeIdx >= len(re.args) || re.args[re.matchTypeIdx].ConstItem(sc))
+}
+
+// getRegexp returns the compiled regexp, which is memorized if the pattern and the match type are constant.
+func (re *regexpBaseFuncSig) getRegexp(pat, matchType string) (*regexp.Regexp, error) {
+	re.once.Do(func() {
+		if re.canMemorize() {
+			re.memorizedRegexp, re.memorizedErr = re.compile(pat, matchType)
+			re.isMemorized = true
+		}
+	})
+	if re.isMemorized {
+		return re.memorizedRegexp, re.memorizedErr
+	}
+	return re.compile(pat, matchType)
+}
+
+// evalMatchType evaluates the optional match type argument.
+func (re *regexpBaseFuncSig) evalMatchType(row chunk.Row) (string, bool, error) {
+	if re.matchTypeIdx >= len(re.args) {
+		return "", false, nil
+	}
+	return re.args[re.matchTypeIdx].EvalString(re.ctx, row)
+}
+
+// evalOptionalInt evaluates the optional int argument at idx, it returns defaultVal if the argument is absent.
+func (re *regexpBaseFuncSig) evalOptionalInt(row chunk.Row, idx int, defaultVal int64) (int64, bool, error) {
+	if idx >= len(re.args) {
+		return defaultVal, false, nil
+	}
+	return re.args[idx].EvalInt(re.ctx, row)
+}
+
+// byteOffset converts the 1-based position pos in expr to the byte offset.
+func (re *regexpBaseFuncSig) byteOffset(expr string, pos int64) (int, error) {
+	if pos < 1 {
+		return 0, ErrRegexp.GenWithStackByArgs(regexpIndexOutOfBounds)
+	}
+	if re.isBinaryCollation() {
+		if pos > int64(len(expr))+1 {
+			return 0, ErrRegexp.GenWithStackByArgs(regexpIndexOutOfBounds)
+		}
+		return int(pos - 1), nil
+	}
+	offset := 0
+	for i := int64(1); i < pos; i++ {
+		if offset >= len(expr) {
+			return 0, ErrRegexp.GenWithStackByArgs(regexpIndexOutOfBounds)
+		}
+		_, size := utf8.DecodeRuneInString(expr[offset:])
+		offset += size
+	}
+	return offset, nil
+}
+
+// position converts the byte offset in expr to the 1-based position.
+func (re *regexpBaseFuncSig) position(expr string, offset int) int64 {
+	if re.isBinaryCollation() {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(expr[:offset])) + 1
+}
+
+// findOccurrence finds the occurrence-th match of r in expr starting from the byte offset, it returns the
+// submatch indexes in expr, or nil if there is no such match.
+func findOccurrence(r *regexp.Regexp, expr string, offset int, occurrence int64) []int {
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches := r.FindAllStringSubmatchIndex(expr[offset:], int(occurrence))
+	if int64(len(matches)) < occurrence {
+		return nil
+	}
+	match := matches[occurrence-1]
+	for i := range match {
+		if match[i] >= 0 {
+			match[i] += offset
+		}
+	}
+	return match
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString}
+	if len(args) == 3 {
+		argTp = append(argTp, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTp...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := newBuiltinRegexpLikeFuncSig(bf)
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpLikeFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpLikeFuncSig(bf baseBuiltinFunc) *builtinRegexpLikeFuncSig {
+	return &builtinRegexpLikeFuncSig{regexpBaseFuncSig{baseBuiltinFunc: bf, matchTypeIdx: 2}}
+}
+
+func (b *builtinRegexpLikeFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_LIKE(expr, pat[, match_type])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	r, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(r.MatchString(expr)), false, nil
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTp[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	sig := newBuiltinRegexpInStrFuncSig(bf)
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpInStrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpInStrFuncSig(bf baseBuiltinFunc) *builtinRegexpInStrFuncSig {
+	return &builtinRegexpInStrFuncSig{regexpBaseFuncSig{baseBuiltinFunc: bf, matchTypeIdx: 5}}
+}
+
+func (b *builtinRegexpInStrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pos, isNull, err := b.evalOptionalInt(row, 2, 1)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	occurrence, isNull, err := b.evalOptionalInt(row, 3, 1)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	returnOption, isNull, err := b.evalOptionalInt(row, 4, 0)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.instr(expr, pat, pos, occurrence, returnOption, matchType)
+	if err != nil {
+		return 0, true, err
+	}
+	return res, false, nil
+}
+
+func (b *builtinRegexpInStrFuncSig) instr(expr, pat string, pos, occurrence, returnOption int64, matchType string) (int64, error) {
+	if returnOption != 0 && returnOption != 1 {
+		return 0, ErrRegexp.GenWithStackByArgs(regexpInvalidReturnOption)
+	}
+	r, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := b.byteOffset(expr, pos)
+	if err != nil {
+		return 0, err
+	}
+	match := findOccurrence(r, expr, offset, occurrence)
+	if match == nil {
+		return 0, nil
+	}
+	return b.position(expr, match[returnOption]), nil
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTp[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = args[0].GetType().Flen
+	sig := newBuiltinRegexpSubstrFuncSig(bf)
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpSubstrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpSubstrFuncSig(bf baseBuiltinFunc) *builtinRegexpSubstrFuncSig {
+	return &builtinRegexpSubstrFuncSig{regexpBaseFuncSig{baseBuiltinFunc: bf, matchTypeIdx: 4}}
+}
+
+func (b *builtinRegexpSubstrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalOptionalInt(row, 2, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalOptionalInt(row, 3, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.substr(expr, pat, pos, occurrence, matchType)
+}
+
+func (b *builtinRegexpSubstrFuncSig) substr(expr, pat string, pos, occurrence int64, matchType string) (string, bool, error) {
+	r, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return "", true, err
+	}
+	offset, err := b.byteOffset(expr, pos)
+	if err != nil {
+		return "", true, err
+	}
+	match := findOccurrence(r, expr, offset, occurrence)
+	if match == nil {
+		return "", true, nil
+	}
+	return expr[match[0]:match[1]], false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTp[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxBlobWidth
+	sig := newBuiltinRegexpReplaceFuncSig(bf)
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpReplaceFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpReplaceFuncSig(bf baseBuiltinFunc) *builtinRegexpReplaceFuncSig {
+	return &builtinRegexpReplaceFuncSig{regexpBaseFuncSig{baseBuiltinFunc: bf, matchTypeIdx: 5}}
+}
+
+func (b *builtinRegexpReplaceFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	repl, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalOptionalInt(row, 3, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalOptionalInt(row, 4, 0)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, err := b.replace(expr, pat, repl, pos, occurrence, matchType)
+	if err != nil {
+		return "", true, err
+	}
+	return res, false, nil
+}
+
+// replace replaces the occurrence-th match of the pattern, or all the matches if occurrence is not positive.
+func (b *builtinRegexpReplaceFuncSig) replace(expr, pat, repl string, pos, occurrence int64, matchType string) (string, error) {
+	r, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return "", err
+	}
+	offset, err := b.byteOffset(expr, pos)
+	if err != nil {
+		return "", err
+	}
+	if occurrence < 1 {
+		return expr[:offset] + r.ReplaceAllString(expr[offset:], repl), nil
+	}
+	match := findOccurrence(r, expr, offset, occurrence)
+	if match == nil {
+		return expr, nil
+	}
+	var sb strings.Builder
+	sb.WriteString(expr[:match[0]])
+	sb.Write(r.ExpandString(nil, repl, expr, match))
+	sb.WriteString(expr[match[1]:])
+	return sb.String(), nil
+}
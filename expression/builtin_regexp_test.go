@@ -0,0 +1,242 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestRegexpLike(c *C) {
+	tests := []struct {
+		args  []interface{}
+		match interface{}
+		err   error
+	}{
+		{[]interface{}{"abc", "b"}, 1, nil},
+		{[]interface{}{"abc", "^b"}, 0, nil},
+		{[]interface{}{"abc", "B"}, 0, nil},
+		{[]interface{}{"abc", "B", "i"}, 1, nil},
+		{[]interface{}{"abc", "B", "ic"}, 0, nil},
+		{[]interface{}{"a\nb", "^b$"}, 0, nil},
+		{[]interface{}{"a\nb", "^b$", "m"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b"}, 0, nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, 1, nil},
+		{[]interface{}{"你好", "^.好$"}, 1, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", "(", ""}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, ErrRegexp},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, RegexpLike, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		match, err := f.Eval(chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), Commentf("%v", tt))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpInStr(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"dog cat dog", "dog"}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, 0, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 1, 1}, 4, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, 1, nil},
+		{[]interface{}{"你好世界好", "好", 1, 2}, 5, nil},
+		{[]interface{}{"你好世界好", "好", 3}, 5, nil},
+		{[]interface{}{"abc", "c", 4}, 0, nil},
+		{[]interface{}{"abc", "c", 5}, nil, ErrRegexp},
+		{[]interface{}{"abc", "c", 0}, nil, ErrRegexp},
+		{[]interface{}{"abc", "c", 1, 1, 2}, nil, ErrRegexp},
+		{[]interface{}{nil, "c"}, nil, nil},
+		{[]interface{}{"abc", "c", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, RegexpInStr, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		res, err := f.Eval(chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), Commentf("%v", tt))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpSubstr(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2, 1}, "bc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", 1, 1, "i"}, "abc", nil},
+		{[]interface{}{"你好世界", "世.", 2}, "世界", nil},
+		{[]interface{}{"abc", "x"}, nil, nil},
+		{[]interface{}{"abc", "b", 5}, nil, ErrRegexp},
+		{[]interface{}{nil, "b"}, nil, nil},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, RegexpSubstr, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		res, err := f.Eval(chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), Commentf("%v", tt))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpReplace(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 3}, "abc def X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 2}, "aX X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5, 0}, "abc X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "${2}${1}", 1, 2}, "abc efd", nil},
+		{[]interface{}{"ABC abc", "b", "X", 1, 0, "i"}, "AXC aXc", nil},
+		{[]interface{}{"你好世界", "好", "坏"}, "你坏世界", nil},
+		{[]interface{}{"abc", "b", "X", 0}, nil, ErrRegexp},
+		{[]interface{}{"abc", nil, "X"}, nil, nil},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		f, err := newFunctionForTest(s.ctx, RegexpReplace, s.primitiveValsToConstants(tt.args)...)
+		c.Assert(err, IsNil)
+		res, err := f.Eval(chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, Commentf("%v", tt))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), Commentf("%v", tt))
+	}
+}
+
+func (s *testEvaluatorSerialSuites) TestRegexpCollation(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	tests := []struct {
+		collation string
+		funcName  string
+		args      []interface{}
+		res       interface{}
+	}{
+		{"utf8mb4_general_ci", RegexpLike, []interface{}{"ABC", "b"}, 1},
+		{"utf8mb4_general_ci", RegexpLike, []interface{}{"ABC", "b", "c"}, 0},
+		{"utf8mb4_bin", RegexpLike, []interface{}{"ABC", "b"}, 0},
+		{"utf8mb4_bin", RegexpLike, []interface{}{"ABC", "b", "i"}, 1},
+		// The binary strings are matched in case-sensitive fashion and the positions are counted in bytes.
+		{charset.CollationBin, RegexpLike, []interface{}{"ABC", "b", "i"}, 0},
+		{charset.CollationBin, RegexpInStr, []interface{}{"你好", "好"}, 4},
+		{"utf8mb4_bin", RegexpInStr, []interface{}{"你好", "好"}, 2},
+		{charset.CollationBin, RegexpSubstr, []interface{}{"你好", "好", 4}, "好"},
+	}
+	for _, tt := range tests {
+		args := s.primitiveValsToConstants(tt.args)
+		for _, arg := range args {
+			tp := arg.GetType()
+			if tp.EvalType() == types.ETString {
+				tp.Charset, tp.Collate = charset.CharsetUTF8MB4, tt.collation
+				if tt.collation == charset.CollationBin {
+					tp.Charset = charset.CharsetBin
+				}
+			}
+		}
+		f, err := newFunctionForTest(s.ctx, tt.funcName, args...)
+		c.Assert(err, IsNil)
+		res, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), Commentf("%v", tt))
+	}
+}
+
+var vecBuiltinRegexpCases = map[string][]vecExprBenchCase{
+	RegexpLike: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "^[a-z]+$", "[0-9]"})},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"A", "^b"}), newSelectStringGener([]string{"", "i", "c", "mn"})},
+		},
+	},
+	RegexpInStr: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "[a-z]+", "[0-9]"})},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "[a-z]"}), newRangeInt64Gener(1, 2),
+				newRangeInt64Gener(0, 3), newRangeInt64Gener(0, 2), newSelectStringGener([]string{"", "i"})},
+		},
+	},
+	RegexpSubstr: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "[a-z]+", "[0-9]"})},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "[a-z]"}), newRangeInt64Gener(1, 2),
+				newRangeInt64Gener(0, 3), newSelectStringGener([]string{"", "i"})},
+		},
+	},
+	RegexpReplace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "[a-z]+", "[0-9]"}), nil},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "[a-z]"}), nil, newRangeInt64Gener(1, 2),
+				newRangeInt64Gener(0, 3), newSelectStringGener([]string{"", "i"})},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinRegexpFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinRegexpCases)
+}
+
+func BenchmarkVectorizedBuiltinRegexpFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinRegexpCases)
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// regexpVecArgs holds the vectorized evaluated arguments of the REGEXP_* functions.
+type regexpVecArgs []*chunk.Column
+
+// vecEvalArgs evaluates all the arguments, the caller should call putVecArgs to release the buffers.
+func (re *regexpBaseFuncSig) vecEvalArgs(input *chunk.Chunk) (regexpVecArgs, error) {
+	args := make(regexpVecArgs, len(re.args))
+	for i, arg := range re.args {
+		buf, err := re.bufAllocator.get()
+		if err != nil {
+			re.putVecArgs(args)
+			return nil, err
+		}
+		args[i] = buf
+		if arg.GetType().EvalType() == types.ETInt {
+			err = arg.VecEvalInt(re.ctx, input, buf)
+		} else {
+			err = arg.VecEvalString(re.ctx, input, buf)
+		}
+		if err != nil {
+			re.putVecArgs(args)
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func (re *regexpBaseFuncSig) putVecArgs(args regexpVecArgs) {
+	for _, buf := range args {
+		if buf != nil {
+			re.bufAllocator.put(buf)
+		}
+	}
+}
+
+func (args regexpVecArgs) getString(argIdx, rowIdx int) string {
+	if argIdx >= len(args) {
+		return ""
+	}
+	return args[argIdx].GetString(rowIdx)
+}
+
+func (args regexpVecArgs) getInt(argIdx, rowIdx int, defaultVal int64) int64 {
+	if argIdx >= len(args) {
+		return defaultVal
+	}
+	return args[argIdx].GetInt64(rowIdx)
+}
+
+func (args regexpVecArgs) isNull(rowIdx int) bool {
+	for _, buf := range args {
+		if buf.IsNull(rowIdx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *builtinRegexpLikeFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	args, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putVecArgs(args)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(args...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		r, err := b.getRegexp(args.getString(1, i), args.getString(b.matchTypeIdx, i))
+		if err != nil {
+			return err
+		}
+		i64s[i] = boolToInt64(r.MatchString(args.getString(0, i)))
+	}
+	return nil
+}
+
+func (b *builtinRegexpInStrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpInStrFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	args, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putVecArgs(args)
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(args...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, err := b.instr(args.getString(0, i), args.getString(1, i), args.getInt(2, i, 1), args.getInt(3, i, 1),
+			args.getInt(4, i, 0), args.getString(b.matchTypeIdx, i))
+		if err != nil {
+			return err
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinRegexpSubstrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	args, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putVecArgs(args)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if args.isNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.substr(args.getString(0, i), args.getString(1, i), args.getInt(2, i, 1),
+			args.getInt(3, i, 1), args.getString(b.matchTypeIdx, i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinRegexpReplaceFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	args, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putVecArgs(args)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if args.isNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, err := b.replace(args.getString(0, i), args.getString(1, i), args.getString(2, i), args.getInt(3, i, 1),
+			args.getInt(4, i, 0), args.getString(b.matchTypeIdx, i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
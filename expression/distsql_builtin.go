@@ -653,6 +653,18 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 	// 	f = &builtinRegexpSig{base}
 	// case tipb.ScalarFuncSig_RegexpUTF8Sig:
 	// 	f = &builtinRegexpUTF8Sig{base}
+	case tipb.ScalarFuncSig_RegexpLikeSig, tipb.ScalarFuncSig_RegexpLikeUTF8Sig:
+		setRegexpCollationByPB(&base, sigCode == tipb.ScalarFuncSig_RegexpLikeSig)
+		f = newBuiltinRegexpLikeFuncSig(base)
+	case tipb.ScalarFuncSig_RegexpInStrSig, tipb.ScalarFuncSig_RegexpInStrUTF8Sig:
+		setRegexpCollationByPB(&base, sigCode == tipb.ScalarFuncSig_RegexpInStrSig)
+		f = newBuiltinRegexpInStrFuncSig(base)
+	case tipb.ScalarFuncSig_RegexpSubstrSig, tipb.ScalarFuncSig_RegexpSubstrUTF8Sig:
+		setRegexpCollationByPB(&base, sigCode == tipb.ScalarFuncSig_RegexpSubstrSig)
+		f = newBuiltinRegexpSubstrFuncSig(base)
+	case tipb.ScalarFuncSig_RegexpReplaceSig, tipb.ScalarFuncSig_RegexpReplaceUTF8Sig:
+		setRegexpCollationByPB(&base, sigCode == tipb.ScalarFuncSig_RegexpReplaceSig)
+		f = newBuiltinRegexpReplaceFuncSig(base)
 	case tipb.ScalarFuncSig_JsonExtractSig:
 		f = &builtinJSONExtractSig{base}
 	case tipb.ScalarFuncSig_JsonUnquoteSig:
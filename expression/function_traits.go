@@ -253,6 +253,9 @@ var booleanFunctions = map[string]struct{}{
 	ast.IsNull:             {},
 	ast.Like:               {},
 	ast.Regexp:             {},
+	RegexpLike:             {},
+	JSONMemberOf:           {},
+	JSONOverlaps:           {},
 	ast.IsIPv4:             {},
 	ast.IsIPv4Compat:       {},
 	ast.IsIPv4Mapped:       {},
@@ -5149,7 +5149,9 @@ func (s *testIntegrationSuite) TestForeignKeyVar(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
 	tk.MustExec("SET FOREIGN_KEY_CHECKS=1")
-	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 8047 variable 'foreign_key_checks' does not yet support value: 1"))
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows())
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("1"))
+	tk.MustExec("SET FOREIGN_KEY_CHECKS=0")
 }
 
 func (s *testIntegrationSuite) TestUserVarMockWindFunc(c *C) {
@@ -6245,6 +6247,39 @@ func (s *testIntegrationSerialSuite) TestCacheRegexpr(c *C) {
 	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("R1"))
 }
 
+func (s *testIntegrationSerialSuite) TestRegexpFunctions(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a varchar(20) collate utf8mb4_general_ci, b varchar(20) collate utf8mb4_bin, c varbinary(20))")
+	tk.MustExec("insert into t values ('Abc 你好', 'Abc 你好', 'Abc 你好'), (null, null, null)")
+	tk.MustQuery("select regexp_like(a, 'abc'), regexp_like(b, 'abc'), regexp_like(b, 'abc', 'i'), regexp_like(c, 'abc', 'i') from t").
+		Check(testkit.Rows("1 0 1 0", "<nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select regexp_instr(a, '好'), regexp_instr(c, '好'), regexp_instr(b, '你', 1, 1, 1) from t").
+		Check(testkit.Rows("6 8 6", "<nil> <nil> <nil>"))
+	tk.MustQuery("select regexp_substr(a, '[a-z]+', 2), regexp_substr(b, '[a-z]+', 1, 2) from t").
+		Check(testkit.Rows("bc <nil>", "<nil> <nil>"))
+	tk.MustQuery("select regexp_replace(a, '[a-z]', '*'), regexp_replace(b, '[a-z]', '*', 1, 2) from t").
+		Check(testkit.Rows("*** 你好 Ab* 你好", "<nil> <nil>"))
+	tk.MustQuery("select a from t where regexp_like(a, '^a')").Check(testkit.Rows("Abc 你好"))
+	for _, sql := range []string{
+		"select regexp_like(a, 'a', 'x') from t",
+		"select regexp_instr(a, 'a', 30) from t",
+		"select regexp_like(a, '(') from t",
+	} {
+		err := tk.QueryToErr(sql)
+		c.Assert(terror.ErrorEqual(err, expression.ErrRegexp), IsTrue, Commentf("%s: %v", sql, err))
+	}
+
+	tk.MustExec("prepare stmt from 'select regexp_substr(a, ?) from t where a is not null'")
+	tk.MustExec("set @p='[a-z]+'")
+	tk.MustQuery("execute stmt using @p").Check(testkit.Rows("Abc"))
+	tk.MustExec("set @p='[0-9]+'")
+	tk.MustQuery("execute stmt using @p").Check(testkit.Rows("<nil>"))
+}
+
 func (s *testIntegrationSerialSuite) TestCacheRefineArgs(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	orgEnable := plannercore.PreparedPlanCacheEnabled()
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl/placement"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
@@ -70,6 +71,13 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
+	case ddlutil.ActionRemovePartitioning:
+		// The table gets the new table ID only when the reads are switched to the non-partitioned table.
+		oldTableID = diff.TableID
+		if diff.OldTableID != 0 {
+			oldTableID = diff.OldTableID
+		}
+		newTableID = diff.TableID
 	default:
 		oldTableID = diff.TableID
 		newTableID = diff.TableID
@@ -83,6 +91,13 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
 			return nil, errors.Trace(err)
 		}
+	case ddlutil.ActionRemovePartitioning:
+		if oldTableID != newTableID {
+			b.applyPlacementDelete(placement.GroupID(oldTableID))
+			if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 	case model.ActionRecoverTable:
 		if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
 			return nil, errors.Trace(err)
@@ -228,6 +243,15 @@ func appendAffectedIDs(affected []int64, tblInfo *model.TableInfo) []int64 {
 			affected = append(affected, def.ID)
 		}
 	}
+	if pi := tblInfo.Partition; pi != nil {
+		// The partitions being reorganized are written as well.
+		for _, def := range pi.AddingDefinitions {
+			affected = append(affected, def.ID)
+		}
+		for _, def := range pi.DroppingDefinitions {
+			affected = append(affected, def.ID)
+		}
+	}
 	return affected
 }
 
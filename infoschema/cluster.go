@@ -44,6 +44,8 @@ const (
 	ClusterTableTiDBTrx = "CLUSTER_TIDB_TRX"
 	// ClusterTableDeadlocks is the string constant of cluster dead lock table.
 	ClusterTableDeadlocks = "CLUSTER_DEADLOCKS"
+	// ClusterTableMemoryUsageOpsHistory is the string constant of cluster memory usage operation history table.
+	ClusterTableMemoryUsageOpsHistory = "CLUSTER_MEMORY_USAGE_OPS_HISTORY"
 )
 
 // memTableToClusterTables means add memory table to cluster table.
@@ -55,6 +57,7 @@ var memTableToClusterTables = map[string]string{
 	TableStatementsSummaryEvicted: ClusterTableStatementsSummaryEvicted,
 	TableTiDBTrx:                  ClusterTableTiDBTrx,
 	TableDeadlocks:                ClusterTableDeadlocks,
+	TableMemoryUsageOpsHistory:    ClusterTableMemoryUsageOpsHistory,
 }
 
 func init() {
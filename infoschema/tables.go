@@ -177,6 +177,14 @@ const (
 	TableDataLockWaits = "DATA_LOCK_WAITS"
 	// TableRegionLabel is the string constant of region label table.
 	TableRegionLabel = "REGION_LABEL"
+	// TableCheckConstraints is the string constant of CHECK_CONSTRAINTS.
+	TableCheckConstraints = "CHECK_CONSTRAINTS"
+	// TableTiDBTTLTableStatus is the string constant of TIDB_TTL_TABLE_STATUS.
+	TableTiDBTTLTableStatus = "TIDB_TTL_TABLE_STATUS"
+	// TableInstancePlanCache is the string constant of INSTANCE_PLAN_CACHE.
+	TableInstancePlanCache = "INSTANCE_PLAN_CACHE"
+	// TableMemoryUsageOpsHistory is the string constant of MEMORY_USAGE_OPS_HISTORY.
+	TableMemoryUsageOpsHistory = "MEMORY_USAGE_OPS_HISTORY"
 )
 
 const (
@@ -272,6 +280,11 @@ var tableIDMap = map[string]int64{
 	TableStatementsSummaryEvicted:           autoid.InformationSchemaDBID + 75,
 	ClusterTableStatementsSummaryEvicted:    autoid.InformationSchemaDBID + 76,
 	TableRegionLabel:                        autoid.InformationSchemaDBID + 77,
+	TableCheckConstraints:                   autoid.InformationSchemaDBID + 78,
+	TableTiDBTTLTableStatus:                 autoid.InformationSchemaDBID + 79,
+	TableInstancePlanCache:                  autoid.InformationSchemaDBID + 80,
+	TableMemoryUsageOpsHistory:              autoid.InformationSchemaDBID + 81,
+	ClusterTableMemoryUsageOpsHistory:       autoid.InformationSchemaDBID + 82,
 }
 
 type columnInfo struct {
@@ -564,6 +577,54 @@ var tableConstraintsCols = []columnInfo{
 	{name: "CONSTRAINT_TYPE", tp: mysql.TypeVarchar, size: 64},
 }
 
+var tableCheckConstraintsCols = []columnInfo{
+	{name: "CONSTRAINT_CATALOG", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CHECK_CLAUSE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag},
+}
+
+var tableTiDBTTLTableStatusCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "TTL_COLUMN", tp: mysql.TypeVarchar, size: 64},
+	{name: "TTL_INTERVAL", tp: mysql.TypeVarchar, size: 64},
+	{name: "TTL_ENABLE", tp: mysql.TypeVarchar, size: 3},
+	{name: "JOB_STATE", tp: mysql.TypeVarchar, size: 16},
+	{name: "JOB_START_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "JOB_FINISH_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "EXPIRE_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "TOTAL_REGIONS", tp: mysql.TypeLonglong, size: 21},
+	{name: "SCANNED_REGIONS", tp: mysql.TypeLonglong, size: 21},
+	{name: "DELETED_ROWS", tp: mysql.TypeLonglong, size: 21},
+	{name: "ERROR_MESSAGE", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+}
+
+var tableInstancePlanCacheCols = []columnInfo{
+	{name: "SCHEMA_NAME", tp: mysql.TypeVarchar, size: 64, comment: "The current schema when the plans are built"},
+	{name: "DIGEST", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, comment: "Digest of the statement"},
+	{name: "DIGEST_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag, comment: "Normalized statement"},
+	{name: "PLAN_COUNT", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Number of the cached plans which are not being used"},
+	{name: "HITS", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Number of the times the cached plans are used"},
+	{name: "MEM_BYTES", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag, comment: "Estimated memory usage of the cached plans"},
+	{name: "LAST_ACCESS_TIME", tp: mysql.TypeTimestamp, size: 26, comment: "The last time the cached plans are accessed"},
+}
+
+var tableMemoryUsageOpsHistoryCols = []columnInfo{
+	{name: "TIME", tp: mysql.TypeDatetime, size: 26, flag: mysql.NotNullFlag, comment: "The time when the operation is taken"},
+	{name: "OPS", tp: mysql.TypeVarchar, size: 20, flag: mysql.NotNullFlag, comment: "The operation taken, SessionKill means the query using the most memory is canceled"},
+	{name: "MEMORY_LIMIT", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The value of tidb_server_memory_limit in bytes"},
+	{name: "MEMORY_CURRENT", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The heap in use in bytes when the operation is taken"},
+	{name: "PROCESSID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The connection ID of the canceled query"},
+	{name: "MEM", tp: mysql.TypeLonglong, size: 21, comment: "The memory usage in bytes of the canceled query"},
+	{name: "CLIENT", tp: mysql.TypeVarchar, size: 64, comment: "The host of the client"},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64, comment: "The current database of the session"},
+	{name: "USER", tp: mysql.TypeVarchar, size: 16, comment: "The user of the session"},
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "The digest of the canceled query"},
+	{name: "SQL_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The text of the canceled query"},
+}
+
 var tableTriggersCols = []columnInfo{
 	{name: "TRIGGER_CATALOG", tp: mysql.TypeVarchar, size: 512},
 	{name: "TRIGGER_SCHEMA", tp: mysql.TypeVarchar, size: 64},
@@ -777,6 +838,9 @@ var tableProcesslistCols = []columnInfo{
 	{name: "MEM", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "DISK", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "TxnStart", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "CURSORS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "CURSOR_MEM", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "CURSOR_DISK", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 }
 
 var tableTiDBIndexesCols = []columnInfo{
@@ -1461,6 +1525,8 @@ const (
 	PrimaryConstraint = "PRIMARY"
 	// UniqueKeyType is the string constant of UNIQUE.
 	UniqueKeyType = "UNIQUE"
+	// CheckConstraintType is the string constant of CHECK.
+	CheckConstraintType = "CHECK"
 )
 
 // ServerInfo represents the basic server information of single cluster component
@@ -1812,6 +1878,10 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableDeadlocks:                          tableDeadlocksCols,
 	TableDataLockWaits:                      tableDataLockWaitsCols,
 	TableRegionLabel:                        tableRegionLabelCols,
+	TableCheckConstraints:                   tableCheckConstraintsCols,
+	TableTiDBTTLTableStatus:                 tableTiDBTTLTableStatusCols,
+	TableInstancePlanCache:                  tableInstancePlanCacheCols,
+	TableMemoryUsageOpsHistory:              tableMemoryUsageOpsHistoryCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
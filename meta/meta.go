@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/structure"
+	"github.com/pingcap/tidb/ttl"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -54,6 +55,7 @@ var (
 //		Table:2 -> table meta data []byte
 //		TID:1 -> int64
 //		TID:2 -> int64
+//		TTL:1 -> table ttl info []byte
 //	}
 //
 
@@ -68,6 +70,7 @@ var (
 	mSeqCyclePrefix   = "SequenceCycle"
 	mTableIDPrefix    = "TID"
 	mRandomIDPrefix   = "TARID"
+	mTTLPrefix        = "TTL"
 	mBootstrapKey     = []byte("BootstrapKey")
 	mSchemaDiffPrefix = "Diff"
 )
@@ -157,6 +160,10 @@ func (m *Meta) autoRandomTableIDKey(tableID int64) []byte {
 	return []byte(fmt.Sprintf("%s:%d", mRandomIDPrefix, tableID))
 }
 
+func (m *Meta) ttlKey(tableID int64) []byte {
+	return []byte(fmt.Sprintf("%s:%d", mTTLPrefix, tableID))
+}
+
 func (m *Meta) tableKey(tableID int64) []byte {
 	return []byte(fmt.Sprintf("%s:%d", mTablePrefix, tableID))
 }
@@ -427,7 +434,7 @@ func (m *Meta) DropSequence(dbID int64, tblID int64, delAutoID bool) error {
 }
 
 // DropTableOrView drops table in database.
-// If delAutoID is true, it will delete the auto_increment id key-value of the table.
+// If delAutoID is true, it will delete the auto_increment id key-value and the TTL info of the table.
 // For rename table, we do not need to rename auto_increment id key-value.
 func (m *Meta) DropTableOrView(dbID int64, tblID int64, delAutoID bool) error {
 	// Check if db exists.
@@ -452,6 +459,9 @@ func (m *Meta) DropTableOrView(dbID int64, tblID int64, delAutoID bool) error {
 		if err := m.txn.HDel(dbKey, m.autoRandomTableIDKey(tblID)); err != nil {
 			return errors.Trace(err)
 		}
+		if err := m.txn.HDel(dbKey, m.ttlKey(tblID)); err != nil {
+			return errors.Trace(err)
+		}
 	}
 	return nil
 }
@@ -520,6 +530,57 @@ func (m *Meta) ListTables(dbID int64) ([]*model.TableInfo, error) {
 	return tables, nil
 }
 
+// SetTableTTL sets the TTL info of the table.
+func (m *Meta) SetTableTTL(dbID int64, tableID int64, ttlInfo *ttl.Info) error {
+	data, err := json.Marshal(ttlInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(m.txn.HSet(m.dbKey(dbID), m.ttlKey(tableID), data))
+}
+
+// GetTableTTL gets the TTL info of the table, it returns nil if the table has no TTL.
+func (m *Meta) GetTableTTL(dbID int64, tableID int64) (*ttl.Info, error) {
+	data, err := m.txn.HGet(m.dbKey(dbID), m.ttlKey(tableID))
+	if err != nil || data == nil {
+		return nil, errors.Trace(err)
+	}
+	ttlInfo := &ttl.Info{}
+	err = json.Unmarshal(data, ttlInfo)
+	return ttlInfo, errors.Trace(err)
+}
+
+// DropTableTTL removes the TTL info of the table.
+func (m *Meta) DropTableTTL(dbID int64, tableID int64) error {
+	return errors.Trace(m.txn.HDel(m.dbKey(dbID), m.ttlKey(tableID)))
+}
+
+// ListTableTTLs shows the TTL info of all tables in database, the key of the result is the table ID.
+func (m *Meta) ListTableTTLs(dbID int64) (map[int64]*ttl.Info, error) {
+	res, err := m.txn.HGetAll(m.dbKey(dbID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ttls := make(map[int64]*ttl.Info)
+	for _, r := range res {
+		field := string(r.Field)
+		if !strings.HasPrefix(field, mTTLPrefix+":") {
+			continue
+		}
+		tableID, err := strconv.ParseInt(field[len(mTTLPrefix)+1:], 10, 64)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ttlInfo := &ttl.Info{}
+		if err = json.Unmarshal(r.Value, ttlInfo); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ttls[tableID] = ttlInfo
+	}
+	return ttls, nil
+}
+
 // ListDatabases shows all databases.
 func (m *Meta) ListDatabases() ([]*model.DBInfo, error) {
 	res, err := m.txn.HGetAll(mDBs)
@@ -923,6 +984,8 @@ var (
 	ColumnElementKey ElementKeyType = []byte("_col_")
 	// IndexElementKey is the key for index element.
 	IndexElementKey ElementKeyType = []byte("_idx_")
+	// PartitionElementKey is the key for partition element.
+	PartitionElementKey ElementKeyType = []byte("_prt_")
 )
 
 const elementKeyLen = 5
@@ -963,6 +1026,8 @@ func DecodeElement(b []byte) (*Element, error) {
 		tp = IndexElementKey
 	case string(ColumnElementKey):
 		tp = ColumnElementKey
+	case string(PartitionElementKey):
+		tp = PartitionElementKey
 	default:
 		return nil, errors.Errorf("invalid encoded element key prefix %q", prefix)
 	}
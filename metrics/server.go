@@ -36,6 +36,14 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(4, 4, 21), // 4Bytes ~ 4TB
 		}, []string{LblType})
 
+	CompressedPacketIOCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "compressed_packet_io_bytes",
+			Help:      "Counter of bytes of the compressed protocol packets before and after compression.",
+		}, []string{LblType})
+
 	QueryDurationHistogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "tidb",
@@ -129,6 +137,22 @@ var (
 			Help:      "Counter of query using plan cache.",
 		}, []string{LblType})
 
+	PlanCacheEvictCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "plan_cache_evict_total",
+			Help:      "Counter of plans evicted from the plan cache.",
+		}, []string{LblType})
+
+	PlanCacheMemoryUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "plan_cache_memory_usage",
+			Help:      "Memory usage of the plan cache in bytes.",
+		}, []string{LblType})
+
 	HandShakeErrorCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "tidb",
@@ -188,6 +212,14 @@ var (
 			Help:      "The value of GOGC",
 		})
 
+	ServerMemoryLimitKillCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "memory_limit_kill_total",
+			Help:      "Counter of the queries canceled because the memory usage exceeds tidb_server_memory_limit.",
+		})
+
 	ConnIdleDurationHistogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "tidb",
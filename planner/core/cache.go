@@ -74,6 +74,8 @@ type pstmtPlanCacheKey struct {
 	timezoneOffset       int
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
+	// stmtText is the parameterized SQL of the statement cached by the non-prepared plan cache.
+	stmtText string
 
 	hash []byte
 }
@@ -104,6 +106,7 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 			key.hash = append(key.hash, kv.TiFlash.Name()...)
 		}
 		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		key.hash = append(key.hash, hack.Slice(key.stmtText)...)
 	}
 	return key.hash
 }
@@ -146,6 +149,35 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 	return key
 }
 
+// NewNonPreparedPlanCacheKey creates a new pstmtPlanCacheKey object for the statement cached by the non-prepared plan
+// cache, the statement is identified by its parameterized SQL instead of the prepared statement ID.
+func NewNonPreparedPlanCacheKey(sessionVars *variable.SessionVars, stmtText string, schemaVersion int64) kvcache.Key {
+	key := NewPSTMTPlanCacheKey(sessionVars, 0, schemaVersion).(*pstmtPlanCacheKey)
+	key.stmtText = stmtText
+	return key
+}
+
+// nonPreparedStmtKey is the key of the CachedPrepareStmt built for the statement cached by the non-prepared plan
+// cache. It shares the LRU of the plan cache with pstmtPlanCacheKey.
+type nonPreparedStmtKey struct {
+	database string
+	stmtText string
+
+	hash []byte
+}
+
+// Hash implements Key interface.
+func (key *nonPreparedStmtKey) Hash() []byte {
+	if len(key.hash) == 0 {
+		// The leading zero byte distinguishes the key from pstmtPlanCacheKey, since a database name can't contain it.
+		key.hash = make([]byte, 0, 1+len(key.database)+8+len(key.stmtText))
+		key.hash = append(key.hash, 0)
+		key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(key.database))
+		key.hash = append(key.hash, hack.Slice(key.stmtText)...)
+	}
+	return key.hash
+}
+
 // FieldSlice is the slice of the types.FieldType
 type FieldSlice []types.FieldType
 
@@ -206,4 +238,7 @@ type CachedPrepareStmt struct {
 	PlanDigest          *parser.Digest
 	ForUpdateRead       bool
 	SnapshotTSEvaluator func(sessionctx.Context) (uint64, error)
+	// StmtText is the parameterized SQL of the statement cached by the non-prepared plan cache, it's empty for the
+	// statement prepared by the client.
+	StmtText string
 }
@@ -0,0 +1,142 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util"
+)
+
+// tableColumnID is the ID of a column of a table.
+type tableColumnID struct {
+	tableID  int64
+	columnID int64
+}
+
+// predicateColumnCollector collects the columns of the tables which are used in the predicates, the join conditions
+// and the group-by items of a logical plan.
+type predicateColumnCollector struct {
+	// colMap maps the unique ID of a column in the plan to the columns of the tables it's derived from.
+	colMap map[int64]map[tableColumnID]struct{}
+	// predicateCols are the collected columns of the tables.
+	predicateCols map[tableColumnID]struct{}
+}
+
+func newPredicateColumnCollector() *predicateColumnCollector {
+	return &predicateColumnCollector{
+		colMap:        make(map[int64]map[tableColumnID]struct{}),
+		predicateCols: make(map[tableColumnID]struct{}),
+	}
+}
+
+// deriveColumn records that the column is derived from the columns used in the expressions.
+func (c *predicateColumnCollector) deriveColumn(col *expression.Column, exprs ...expression.Expression) {
+	for _, expr := range exprs {
+		for _, usedCol := range expression.ExtractColumns(expr) {
+			for id := range c.colMap[usedCol.UniqueID] {
+				if c.colMap[col.UniqueID] == nil {
+					c.colMap[col.UniqueID] = make(map[tableColumnID]struct{})
+				}
+				c.colMap[col.UniqueID][id] = struct{}{}
+			}
+		}
+	}
+}
+
+// addPredicates records the columns of the tables used in the expressions.
+func (c *predicateColumnCollector) addPredicates(exprs ...expression.Expression) {
+	for _, expr := range exprs {
+		for _, col := range expression.ExtractColumns(expr) {
+			for id := range c.colMap[col.UniqueID] {
+				c.predicateCols[id] = struct{}{}
+			}
+		}
+		for _, corCol := range expression.ExtractCorColumns(expr) {
+			for id := range c.colMap[corCol.UniqueID] {
+				c.predicateCols[id] = struct{}{}
+			}
+		}
+	}
+}
+
+func (c *predicateColumnCollector) addJoinConditions(join *LogicalJoin) {
+	for _, cond := range join.EqualConditions {
+		c.addPredicates(cond)
+	}
+	c.addPredicates(join.LeftConditions...)
+	c.addPredicates(join.RightConditions...)
+	c.addPredicates(join.OtherConditions...)
+}
+
+func (c *predicateColumnCollector) collect(lp LogicalPlan) {
+	for _, child := range lp.Children() {
+		c.collect(child)
+	}
+	switch x := lp.(type) {
+	case *DataSource:
+		// The statistics of the memory tables and the system tables are not collected by ANALYZE.
+		if util.IsMemOrSysDB(x.DBName.L) {
+			return
+		}
+		for i, colInfo := range x.Columns {
+			if colInfo.ID == model.ExtraHandleID || i >= x.schema.Len() {
+				continue
+			}
+			c.colMap[x.schema.Columns[i].UniqueID] = map[tableColumnID]struct{}{
+				{tableID: x.tableInfo.ID, columnID: colInfo.ID}: {},
+			}
+		}
+		c.addPredicates(x.allConds...)
+	case *LogicalSelection:
+		c.addPredicates(x.Conditions...)
+	case *LogicalProjection:
+		for i, expr := range x.Exprs {
+			c.deriveColumn(x.schema.Columns[i], expr)
+		}
+	case *LogicalAggregation:
+		c.addPredicates(x.GroupByItems...)
+		for i, aggFunc := range x.AggFuncs {
+			c.deriveColumn(x.schema.Columns[i], aggFunc.Args...)
+		}
+	case *LogicalJoin:
+		c.addJoinConditions(x)
+	case *LogicalApply:
+		c.addJoinConditions(&x.LogicalJoin)
+	case *LogicalUnionAll:
+		for i, col := range x.schema.Columns {
+			for _, child := range x.children {
+				c.deriveColumn(col, child.Schema().Columns[i])
+			}
+		}
+	case *LogicalPartitionUnionAll:
+		for i, col := range x.schema.Columns {
+			for _, child := range x.children {
+				c.deriveColumn(col, child.Schema().Columns[i])
+			}
+		}
+	}
+}
+
+// collectPredicateColumns records the columns of the tables used in the predicates, the join conditions and the
+// group-by items of the plan to the session, they're used to decide which columns are analyzed.
+func collectPredicateColumns(sctx sessionctx.Context, lp LogicalPlan) {
+	c := newPredicateColumnCollector()
+	c.collect(lp)
+	for id := range c.predicateCols {
+		sctx.StoreColumnStatsUsage(id.tableID, id.columnID)
+	}
+}
@@ -86,6 +86,8 @@ type CheckTable struct {
 	IndexInfos         []*model.IndexInfo
 	IndexLookUpReaders []*PhysicalIndexLookUpReader
 	CheckIndex         bool
+	// MultiValuedIndexInfos are the multi-valued indexes to check, they have no IndexLookUpReader.
+	MultiValuedIndexInfos []*model.IndexInfo
 }
 
 // RecoverIndex is used for backfilling corrupted index data.
@@ -224,6 +226,11 @@ func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Cont
 	if !ok {
 		return errors.Errorf("invalid CachedPrepareStmt type")
 	}
+	return e.optimizePreparedPlan(ctx, sctx, is, preparedObj)
+}
+
+func (e *Execute) optimizePreparedPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, preparedObj *CachedPrepareStmt) error {
+	vars := sctx.GetSessionVars()
 	prepared := preparedObj.PreparedAst
 	vars.StmtCtx.StmtType = prepared.StmtType
 
@@ -384,18 +391,12 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 	stmtCtx := sessVars.StmtCtx
 	prepared := preparedStmt.PreparedAst
 	stmtCtx.UseCache = prepared.UseCache
+	useInstanceCache := prepared.UseCache && useInstancePlanCache(sctx)
 	var cacheKey kvcache.Key
 	if prepared.UseCache {
-		cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
-	}
-	tps := make([]*types.FieldType, len(e.UsingVars))
-	for i, param := range e.UsingVars {
-		name := param.(*expression.ScalarFunction).GetArgs()[0].String()
-		tps[i] = sctx.GetSessionVars().UserVarTypes[name]
-		if tps[i] == nil {
-			tps[i] = types.NewFieldType(mysql.TypeNull)
-		}
+		cacheKey = e.newPlanCacheKey(sessVars, preparedStmt, useInstanceCache)
 	}
+	tps := e.getParamTypes(sctx, preparedStmt)
 	if prepared.CachedPlan != nil {
 		// Rewriting the expression in the select.where condition  will convert its
 		// type from "paramMarker" to "Constant".When Point Select queries are executed,
@@ -422,7 +423,11 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 		stmtCtx.PointExec = true
 		return nil
 	}
-	if prepared.UseCache {
+	if useInstanceCache {
+		if ok, err := e.getPlanFromInstancePlanCache(ctx, sctx, is, preparedStmt, cacheKey.(*instancePlanCacheKey), tps); err != nil || ok {
+			return err
+		}
+	} else if prepared.UseCache {
 		if cacheValue, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
 			if err := e.checkPreparedPriv(ctx, sctx, preparedStmt, is); err != nil {
 				return err
@@ -469,7 +474,16 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 
 REBUILD:
 	stmt := TryAddExtraLimit(sctx, prepared.Stmt)
-	p, names, err := OptimizeAstNode(ctx, sctx, stmt, is)
+	var (
+		optCtx   = sctx
+		cacheCtx *instancePlanCacheCtx
+	)
+	if useInstanceCache {
+		// The plan is built with a context wrapping the session, so it can be used by the other sessions.
+		cacheCtx = &instancePlanCacheCtx{Context: sctx}
+		optCtx = cacheCtx
+	}
+	p, names, err := OptimizeAstNode(ctx, optCtx, stmt, is)
 	if err != nil {
 		return err
 	}
@@ -484,13 +498,25 @@ REBUILD:
 		// rebuild key to exclude kv.TiFlash when stmt is not read only
 		if _, isolationReadContainTiFlash := sessVars.IsolationReadEngines[kv.TiFlash]; isolationReadContainTiFlash && !IsReadOnly(stmt, sessVars) {
 			delete(sessVars.IsolationReadEngines, kv.TiFlash)
-			cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+			cacheKey = e.newPlanCacheKey(sessVars, preparedStmt, useInstanceCache)
 			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
 		}
 		cached := NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps)
 		preparedStmt.NormalizedPlan, preparedStmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlanDigest(preparedStmt.NormalizedPlan, preparedStmt.PlanDigest)
-		if cacheVals, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
+		if useInstanceCache {
+			// The point plan cached in the statement is only used by this session.
+			if prepared.CachedPlan != p {
+				// The plan is put into the instance plan cache after the statement finishes.
+				checkOutInstancePlan(sctx, cacheKey.(*instancePlanCacheKey), &instancePlanCacheValue{
+					PSTMTPlanCacheValue: cached,
+					sctx:                cacheCtx,
+					normalizedPlan:      preparedStmt.NormalizedPlan,
+					planDigest:          preparedStmt.PlanDigest,
+					memUsage:            estimatePlanMemUsage(p) + int64(len(stmt.Text())),
+				})
+			}
+		} else if cacheVals, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
 			hitVal := false
 			for i, cacheVal := range cacheVals.([]*PSTMTPlanCacheValue) {
 				if cacheVal.UserVarTypes.Equal(tps) {
@@ -511,6 +537,75 @@ REBUILD:
 	return err
 }
 
+// newPlanCacheKey creates the plan cache key of the statement. The statement cached by the non-prepared plan cache
+// has no prepared statement ID, so it's identified by its parameterized SQL.
+func (e *Execute) newPlanCacheKey(sessVars *variable.SessionVars, preparedStmt *CachedPrepareStmt, useInstanceCache bool) kvcache.Key {
+	if useInstanceCache {
+		return newInstancePlanCacheKey(sessVars, preparedStmt)
+	}
+	if preparedStmt.StmtText != "" {
+		return NewNonPreparedPlanCacheKey(sessVars, preparedStmt.StmtText, preparedStmt.PreparedAst.SchemaVersion)
+	}
+	return NewPSTMTPlanCacheKey(sessVars, e.ExecID, preparedStmt.PreparedAst.SchemaVersion)
+}
+
+// getPlanFromInstancePlanCache tries to get the plan from the instance plan cache, it returns false if there's no
+// plan can be used.
+func (e *Execute) getPlanFromInstancePlanCache(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema,
+	preparedStmt *CachedPrepareStmt, cacheKey *instancePlanCacheKey, tps []*types.FieldType) (bool, error) {
+	cachedVal := takeInstancePlan(sctx, cacheKey, tps)
+	if cachedVal == nil {
+		return false, nil
+	}
+	if err := e.checkPreparedPriv(ctx, sctx, preparedStmt, is); err != nil {
+		return false, err
+	}
+	for tblInfo, unionScan := range cachedVal.TblInfo2UnionScan {
+		if !unionScan && tableHasDirtyContent(sctx, tblInfo) {
+			// The plan is still valid for the other sessions, so it's not evicted.
+			return false, nil
+		}
+	}
+	if err := e.rebuildRange(cachedVal.Plan); err != nil {
+		logutil.BgLogger().Debug("rebuild range failed", zap.Error(err))
+		return false, nil
+	}
+	if err := e.setFoundInPlanCache(sctx, true); err != nil {
+		return false, err
+	}
+	if metrics.ResettablePlanCacheCounterFortTest {
+		metrics.PlanCacheCounter.WithLabelValues("instance").Inc()
+	} else {
+		instancePlanCacheCounter.Inc()
+	}
+	e.names = cachedVal.OutPutNames
+	e.Plan = cachedVal.Plan
+	sctx.GetSessionVars().StmtCtx.SetPlanDigest(cachedVal.normalizedPlan, cachedVal.planDigest)
+	return true, nil
+}
+
+// getParamTypes returns the types of the parameters which are used to distinguish the cached plans of a statement.
+func (e *Execute) getParamTypes(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt) []*types.FieldType {
+	if preparedStmt.StmtText != "" {
+		// The literals of the same parameterized SQL may have different types, e.g. `a = 1` and `a = '1'`.
+		tps := make([]*types.FieldType, len(e.PrepareParams))
+		for i := range e.PrepareParams {
+			tps[i] = types.NewFieldType(mysql.TypeUnspecified)
+			types.DefaultParamTypeForValue(e.PrepareParams[i].GetValue(), tps[i])
+		}
+		return tps
+	}
+	tps := make([]*types.FieldType, len(e.UsingVars))
+	for i, param := range e.UsingVars {
+		name := param.(*expression.ScalarFunction).GetArgs()[0].String()
+		tps[i] = sctx.GetSessionVars().UserVarTypes[name]
+		if tps[i] == nil {
+			tps[i] = types.NewFieldType(mysql.TypeNull)
+		}
+	}
+	return tps
+}
+
 // tryCachePointPlan will try to cache point execution plan, there may be some
 // short paths for these executions, currently "point select" and "point update"
 func (e *Execute) tryCachePointPlan(ctx context.Context, sctx sessionctx.Context,
@@ -112,7 +112,7 @@ func (p *PhysicalIndexScan) AccessObject(normalized bool) string {
 		buffer.WriteString(", index:" + p.Index.Name.O + "(")
 		for i, idxCol := range p.Index.Columns {
 			if tblCol := p.Table.Columns[idxCol.Offset]; tblCol.Hidden {
-				buffer.WriteString(tblCol.GeneratedExprString)
+				buffer.WriteString(table.IndexExprString(tblCol))
 			} else {
 				buffer.WriteString(idxCol.Name.O)
 			}
@@ -1046,7 +1046,7 @@ func (p *LogicalIndexScan) ExplainInfo() string {
 		buffer.WriteString(", index:")
 		for i, idxCol := range index.Columns {
 			if tblCol := p.Source.tableInfo.Columns[idxCol.Offset]; tblCol.Hidden {
-				buffer.WriteString(tblCol.GeneratedExprString)
+				buffer.WriteString(table.IndexExprString(tblCol))
 			} else {
 				buffer.WriteString(idxCol.Name.O)
 			}
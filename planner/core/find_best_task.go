@@ -1586,6 +1586,9 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 		colSet.Insert(col.UniqueID)
 		curCorr := float64(0)
 		for _, item := range histColl.ExtendedStats.Stats {
+			if item.Tp != ast.StatsTypeCorrelation {
+				continue
+			}
 			if (col.ID == item.ColIDs[0] && path.FullIdxCols[0].ID == item.ColIDs[1]) ||
 				(col.ID == item.ColIDs[1] && path.FullIdxCols[0].ID == item.ColIDs[0]) {
 				curCorr = item.ScalarVals
@@ -0,0 +1,316 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+// estimatedPlanNodeSize is the estimated memory usage of an operator of the cached plans, including its
+// expressions, schema and statistics. The plans don't track their memory usage, so the memory usage of a plan is
+// estimated by the number of its operators.
+const estimatedPlanNodeSize = 4 << 10
+
+var (
+	instancePlanCacheCounter = metrics.PlanCacheCounter.WithLabelValues("instance")
+	instancePlanCacheEvict   = metrics.PlanCacheEvictCounter.WithLabelValues("instance")
+	instancePlanCacheMemory  = metrics.PlanCacheMemoryUsage.WithLabelValues("instance")
+)
+
+func init() {
+	kvcache.InstancePlanCache.SetOnEvict(func(kvcache.Key, kvcache.Value) {
+		instancePlanCacheEvict.Inc()
+	})
+}
+
+// instancePlanCacheCheckoutKey is used to get the plans taken from the instance plan cache by the current statement.
+const instancePlanCacheCheckoutKey = stringutil.StringerStr("instancePlanCacheCheckoutKey")
+
+// instancePlanCacheCtx is the session context of the plans in the instance plan cache. The plans are built with it
+// instead of the session, so a plan is re-bound to another session by changing the session it wraps, rather than
+// walking all the operators and expressions of the plan.
+type instancePlanCacheCtx struct {
+	sessionctx.Context
+}
+
+// The session context is asserted to these interfaces when the expressions are evaluated, so they're forwarded to
+// the session the context wraps.
+var (
+	_ sqlexec.RestrictedSQLExecutor = &instancePlanCacheCtx{}
+	_ sqlexec.SQLExecutor           = &instancePlanCacheCtx{}
+	_ sqlexec.SQLParser             = &instancePlanCacheCtx{}
+)
+
+// ParseWithParams implements the sqlexec.RestrictedSQLExecutor interface.
+func (c *instancePlanCacheCtx) ParseWithParams(ctx context.Context, sql string, args ...interface{}) (ast.StmtNode, error) {
+	exec, ok := c.Context.(sqlexec.RestrictedSQLExecutor)
+	if !ok {
+		return nil, errors.New("restricted sql can't be executed in this context")
+	}
+	return exec.ParseWithParams(ctx, sql, args...)
+}
+
+// ExecRestrictedStmt implements the sqlexec.RestrictedSQLExecutor interface.
+func (c *instancePlanCacheCtx) ExecRestrictedStmt(ctx context.Context, stmt ast.StmtNode, opts ...sqlexec.OptionFuncAlias) ([]chunk.Row, []*ast.ResultField, error) {
+	exec, ok := c.Context.(sqlexec.RestrictedSQLExecutor)
+	if !ok {
+		return nil, nil, errors.New("restricted sql can't be executed in this context")
+	}
+	return exec.ExecRestrictedStmt(ctx, stmt, opts...)
+}
+
+// Execute implements the sqlexec.SQLExecutor interface.
+func (c *instancePlanCacheCtx) Execute(ctx context.Context, sql string) ([]sqlexec.RecordSet, error) {
+	exec, ok := c.Context.(sqlexec.SQLExecutor)
+	if !ok {
+		return nil, errors.New("sql can't be executed in this context")
+	}
+	return exec.Execute(ctx, sql)
+}
+
+// ExecuteInternal implements the sqlexec.SQLExecutor interface.
+func (c *instancePlanCacheCtx) ExecuteInternal(ctx context.Context, sql string, args ...interface{}) (sqlexec.RecordSet, error) {
+	exec, ok := c.Context.(sqlexec.SQLExecutor)
+	if !ok {
+		return nil, errors.New("sql can't be executed in this context")
+	}
+	return exec.ExecuteInternal(ctx, sql, args...)
+}
+
+// ExecuteStmt implements the sqlexec.SQLExecutor interface.
+func (c *instancePlanCacheCtx) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlexec.RecordSet, error) {
+	exec, ok := c.Context.(sqlexec.SQLExecutor)
+	if !ok {
+		return nil, errors.New("sql can't be executed in this context")
+	}
+	return exec.ExecuteStmt(ctx, stmtNode)
+}
+
+// SetDiskFullOpt implements the sqlexec.SQLExecutor interface.
+func (c *instancePlanCacheCtx) SetDiskFullOpt(level kvrpcpb.DiskFullOpt) {
+	if exec, ok := c.Context.(sqlexec.SQLExecutor); ok {
+		exec.SetDiskFullOpt(level)
+	}
+}
+
+// ClearDiskFullOpt implements the sqlexec.SQLExecutor interface.
+func (c *instancePlanCacheCtx) ClearDiskFullOpt() {
+	if exec, ok := c.Context.(sqlexec.SQLExecutor); ok {
+		exec.ClearDiskFullOpt()
+	}
+}
+
+// ParseSQL implements the sqlexec.SQLParser interface.
+func (c *instancePlanCacheCtx) ParseSQL(ctx context.Context, sql, charset, collation string) ([]ast.StmtNode, []error, error) {
+	p, ok := c.Context.(sqlexec.SQLParser)
+	if !ok {
+		return nil, nil, errors.New("sql can't be parsed in this context")
+	}
+	return p.ParseSQL(ctx, sql, charset, collation)
+}
+
+// instancePlanCacheKey is the key of the instance plan cache. It's the key of the prepared plan cache without the
+// connection ID and the statement ID, the statement is identified by its SQL text instead.
+type instancePlanCacheKey struct {
+	*pstmtPlanCacheKey
+	// collation is the connection collation, the sessions sharing a plan may use different collations.
+	collation     string
+	normalizedSQL string
+	digest        *parser.Digest
+
+	hash []byte
+}
+
+// Hash implements Key interface.
+func (key *instancePlanCacheKey) Hash() []byte {
+	if len(key.hash) == 0 {
+		key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(key.collation))
+		key.hash = append(key.hash, key.pstmtPlanCacheKey.Hash()...)
+	}
+	return key.hash
+}
+
+// newInstancePlanCacheKey creates the instance plan cache key of the statement.
+func newInstancePlanCacheKey(sessVars *variable.SessionVars, preparedStmt *CachedPrepareStmt) *instancePlanCacheKey {
+	key := NewPSTMTPlanCacheKey(sessVars, 0, preparedStmt.PreparedAst.SchemaVersion).(*pstmtPlanCacheKey)
+	key.connID = 0
+	key.stmtText = preparedStmt.PreparedAst.Stmt.Text()
+	_, collation := sessVars.GetCharsetInfo()
+	return &instancePlanCacheKey{
+		pstmtPlanCacheKey: key,
+		collation:         collation,
+		normalizedSQL:     preparedStmt.NormalizedSQL,
+		digest:            preparedStmt.SQLDigest,
+	}
+}
+
+// instancePlanCacheValue is a plan in the instance plan cache.
+type instancePlanCacheValue struct {
+	*PSTMTPlanCacheValue
+	sctx           *instancePlanCacheCtx
+	normalizedPlan string
+	planDigest     *parser.Digest
+	memUsage       int64
+}
+
+// instancePlanCacheCheckout is a plan taken from the instance plan cache, or built to be put into it.
+type instancePlanCacheCheckout struct {
+	key   *instancePlanCacheKey
+	value *instancePlanCacheValue
+}
+
+// CheckedOutPlans are the plans used by a statement which belong to the instance plan cache. A plan is used by only
+// one statement at a time, the plans are put back to the cache after the statement finishes.
+type CheckedOutPlans []instancePlanCacheCheckout
+
+// useInstancePlanCache checks whether the plans of the session can be shared with the other sessions.
+func useInstancePlanCache(sctx sessionctx.Context) bool {
+	// The local temporary tables are only visible to the session.
+	return variable.EnableInstancePlanCache.Load() && sctx.GetSessionVars().LocalTemporaryTables == nil
+}
+
+// takeInstancePlan takes a plan from the instance plan cache and binds it to the session, it returns nil if there's
+// no plan for the parameter types.
+func takeInstancePlan(sctx sessionctx.Context, key *instancePlanCacheKey, tps []*types.FieldType) *instancePlanCacheValue {
+	val, ok := kvcache.InstancePlanCache.Take(key, func(v kvcache.Value) bool {
+		return v.(*instancePlanCacheValue).UserVarTypes.Equal(tps)
+	})
+	instancePlanCacheMemory.Set(float64(kvcache.InstancePlanCache.MemTracker().BytesConsumed()))
+	if !ok {
+		return nil
+	}
+	cachedVal := val.(*instancePlanCacheValue)
+	cachedVal.sctx.Context = sctx
+	checkOutInstancePlan(sctx, key, cachedVal)
+	return cachedVal
+}
+
+// checkOutInstancePlan records that the plan is used by the current statement, so it's put back after the
+// statement finishes.
+func checkOutInstancePlan(sctx sessionctx.Context, key *instancePlanCacheKey, value *instancePlanCacheValue) {
+	plans, _ := sctx.Value(instancePlanCacheCheckoutKey).(CheckedOutPlans)
+	sctx.SetValue(instancePlanCacheCheckoutKey, append(plans, instancePlanCacheCheckout{key: key, value: value}))
+}
+
+// TakeCheckedOutPlans returns the plans of the instance plan cache used by the current statement, the caller is
+// responsible for releasing them after the statement finishes.
+func TakeCheckedOutPlans(sctx sessionctx.Context) CheckedOutPlans {
+	plans, _ := sctx.Value(instancePlanCacheCheckoutKey).(CheckedOutPlans)
+	if plans != nil {
+		sctx.ClearValue(instancePlanCacheCheckoutKey)
+	}
+	return plans
+}
+
+// Release puts the plans back to the instance plan cache.
+func (plans CheckedOutPlans) Release() {
+	if len(plans) == 0 || !variable.EnableInstancePlanCache.Load() {
+		return
+	}
+	for _, plan := range plans {
+		kvcache.InstancePlanCache.Put(plan.key, plan.value, plan.value.memUsage)
+	}
+	instancePlanCacheMemory.Set(float64(kvcache.InstancePlanCache.MemTracker().BytesConsumed()))
+}
+
+// estimatePlanMemUsage estimates the memory usage of a plan by the number of its operators.
+func estimatePlanMemUsage(p Plan) int64 {
+	if p == nil {
+		return 0
+	}
+	size := int64(estimatedPlanNodeSize)
+	switch x := p.(type) {
+	case *Insert:
+		size += estimatePlanMemUsage(x.SelectPlan)
+	case *Update:
+		size += estimatePlanMemUsage(x.SelectPlan)
+	case *Delete:
+		size += estimatePlanMemUsage(x.SelectPlan)
+	case PhysicalPlan:
+		var pushedDown []PhysicalPlan
+		switch reader := x.(type) {
+		case *PhysicalTableReader:
+			pushedDown = reader.TablePlans
+		case *PhysicalIndexReader:
+			pushedDown = reader.IndexPlans
+		case *PhysicalIndexLookUpReader:
+			pushedDown = append(pushedDown, reader.IndexPlans...)
+			pushedDown = append(pushedDown, reader.TablePlans...)
+		case *PhysicalIndexMergeReader:
+			for _, partialPlans := range reader.PartialPlans {
+				pushedDown = append(pushedDown, partialPlans...)
+			}
+			pushedDown = append(pushedDown, reader.TablePlans...)
+		}
+		size += int64(len(pushedDown)) * estimatedPlanNodeSize
+		for _, child := range x.Children() {
+			size += estimatePlanMemUsage(child)
+		}
+	}
+	return size
+}
+
+// InstancePlanCacheEntry is the information of the plans cached for a statement in the instance plan cache.
+type InstancePlanCacheEntry struct {
+	SchemaName    string
+	Digest        string
+	NormalizedSQL string
+	// PlanCount is the number of the plans which are not being used.
+	PlanCount  int
+	Hits       uint64
+	MemUsage   int64
+	LastAccess time.Time
+}
+
+// InstancePlanCacheEntries returns the information of all the statements in the instance plan cache.
+func InstancePlanCacheEntries() []InstancePlanCacheEntry {
+	items := kvcache.InstancePlanCache.Items()
+	entries := make([]InstancePlanCacheEntry, 0, len(items))
+	for _, item := range items {
+		key, ok := item.Key.(*instancePlanCacheKey)
+		if !ok {
+			continue
+		}
+		entry := InstancePlanCacheEntry{
+			SchemaName:    key.database,
+			NormalizedSQL: key.normalizedSQL,
+			PlanCount:     item.Values,
+			Hits:          item.Hits,
+			MemUsage:      item.MemUsage,
+			LastAccess:    item.LastUsed,
+		}
+		if key.digest != nil {
+			entry.Digest = key.digest.String()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/ranger"
+	"go.uber.org/zap"
+)
+
+// generateMultiValuedIndexMergePaths generates the IndexMerge paths which read the multi-valued indexes.
+// A multi-valued index has an entry for each element of the array, so the rows satisfying
+// `c MEMBER OF (expr)`, `JSON_OVERLAPS(expr, c)` and `JSON_CONTAINS(expr, c)` can be read by the union of
+// the point ranges of the elements in `c`. The original conditions are always kept as filters.
+// Only the multi-valued indexes whose first key part is the multi-valued one are considered now.
+func (ds *DataSource) generateMultiValuedIndexMergePaths() {
+	useInvisibleIndexes := ds.ctx.GetSessionVars().OptimizerUseInvisibleIndexes
+	for _, idxInfo := range ds.tableInfo.Indices {
+		if idxInfo.State != model.StatePublic || (idxInfo.Invisible && !useInvisibleIndexes) {
+			continue
+		}
+		if table.FindMultiValuedColumn(ds.tableInfo, idxInfo) != 0 || !ds.isInIndexMergeHints(idxInfo.Name.L) {
+			continue
+		}
+		colInfo := ds.tableInfo.Columns[idxInfo.Columns[0].Offset]
+		var virtualExpr expression.Expression
+		for _, col := range ds.TblCols {
+			if col.ID == colInfo.ID {
+				virtualExpr = col.VirtualExpr
+				break
+			}
+		}
+		if virtualExpr == nil {
+			continue
+		}
+		elemTp, err := table.MultiValuedElemType(colInfo)
+		if err != nil {
+			logutil.BgLogger().Debug("invalid multi-valued index", zap.String("index", idxInfo.Name.O), zap.Error(err))
+			continue
+		}
+		for _, cond := range ds.allConds {
+			elems := ds.multiValuedIndexElems(cond, virtualExpr, elemTp, colInfo.GeneratedExprString)
+			if len(elems) == 0 {
+				continue
+			}
+			ds.possibleAccessPaths = append(ds.possibleAccessPaths, ds.buildMultiValuedIndexMergePath(idxInfo, elems))
+			break
+		}
+	}
+}
+
+// multiValuedIndexElems returns the elements which the rows satisfying cond must have in the array of virtualExpr,
+// a row satisfies cond only if its array contains any of them. It returns nil if the multi-valued index can't be
+// used for cond.
+func (ds *DataSource) multiValuedIndexElems(cond, virtualExpr expression.Expression, elemTp *types.FieldType, colName string) []types.Datum {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok {
+		return nil
+	}
+	args := sf.GetArgs()
+	var (
+		constArg     expression.Expression
+		allRequired  bool
+		scalarNeeded bool
+	)
+	switch sf.FuncName.L {
+	case expression.JSONMemberOf:
+		// c MEMBER OF (expr)
+		if !args[1].Equal(ds.ctx, virtualExpr) {
+			return nil
+		}
+		constArg, scalarNeeded = args[0], true
+	case expression.JSONOverlaps:
+		// JSON_OVERLAPS(expr, c) or JSON_OVERLAPS(c, expr)
+		if args[0].Equal(ds.ctx, virtualExpr) {
+			constArg = args[1]
+		} else if args[1].Equal(ds.ctx, virtualExpr) {
+			constArg = args[0]
+		} else {
+			return nil
+		}
+	case ast.JSONContains:
+		// JSON_CONTAINS(expr, c)
+		if len(args) != 2 || !args[0].Equal(ds.ctx, virtualExpr) {
+			return nil
+		}
+		constArg, allRequired = args[1], true
+	default:
+		return nil
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	if !constArg.ConstItem(sc) || constArg.GetType().EvalType() != types.ETJson {
+		return nil
+	}
+	bj, isNull, err := constArg.EvalJSON(ds.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return nil
+	}
+	var jsonElems []json.BinaryJSON
+	switch bj.TypeCode {
+	case json.TypeCodeObject:
+		return nil
+	case json.TypeCodeArray:
+		if scalarNeeded {
+			return nil
+		}
+		for i := 0; i < bj.GetElemCount(); i++ {
+			jsonElems = append(jsonElems, bj.ArrayGetElem(i))
+		}
+	default:
+		jsonElems = append(jsonElems, bj)
+	}
+	elems := make([]types.Datum, 0, len(jsonElems))
+	for _, jsonElem := range jsonElems {
+		elem, err := table.ConvertMultiValuedElem(sc, jsonElem, elemTp, colName)
+		if err != nil {
+			// The element can't be stored in the index, so no row has it.
+			if allRequired {
+				return nil
+			}
+			continue
+		}
+		if allRequired {
+			// Reading the rows having any one of the required elements is enough.
+			return []types.Datum{elem}
+		}
+		duplicated := false
+		for i := range elems {
+			if cmp, err := elems[i].CompareDatum(sc, &elem); err == nil && cmp == 0 {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+// buildMultiValuedIndexMergePath builds the IndexMerge path which reads the point range of each element.
+func (ds *DataSource) buildMultiValuedIndexMergePath(idxInfo *model.IndexInfo, elems []types.Datum) *util.AccessPath {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	fullIdxCols, fullIdxColLens := expression.IndexInfo2Cols(ds.Columns, ds.schema.Columns, idxInfo)
+	partialPaths := make([]*util.AccessPath, 0, len(elems))
+	var totalCount float64
+	for _, elem := range elems {
+		path := &util.AccessPath{
+			Index:          idxInfo,
+			Ranges:         []*ranger.Range{{LowVal: []types.Datum{elem}, HighVal: []types.Datum{elem}}},
+			FullIdxCols:    fullIdxCols,
+			FullIdxColLens: fullIdxColLens,
+		}
+		count, err := ds.statisticTable.GetRowCountByIndexRanges(sc, idxInfo.ID, path.Ranges)
+		if err != nil {
+			logutil.BgLogger().Debug("can not estimate the row count of a path", zap.Error(err))
+			count = ds.tableStats.RowCount * SelectionFactor
+		}
+		path.CountAfterAccess, path.CountAfterIndex = count, count
+		totalCount += count
+		partialPaths = append(partialPaths, path)
+	}
+	indexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths}
+	indexMergePath.TableFilters = append(indexMergePath.TableFilters, ds.pushedDownConds...)
+	indexMergePath.CountAfterAccess = totalCount
+	if totalCount > ds.tableStats.RowCount {
+		indexMergePath.CountAfterAccess = ds.tableStats.RowCount
+	}
+	return indexMergePath
+}
@@ -0,0 +1,299 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/hint"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// NonPreparedPlanCacheable checks whether the plan of a statement sent by the text protocol can be cached.
+// Only the simple statements which read or write a single table are supported, e.g. point gets, single table
+// selects, and the insert, update and delete statements without subqueries.
+func NonPreparedPlanCacheable(node ast.StmtNode, is infoschema.InfoSchema) bool {
+	var tableRefs *ast.TableRefsClause
+	switch x := node.(type) {
+	case *ast.SelectStmt:
+		if x.Kind != ast.SelectStmtKindSelect || x.With != nil || x.SelectIntoOpt != nil {
+			return false
+		}
+		tableRefs = x.From
+	case *ast.UpdateStmt:
+		if x.MultipleTable || x.With != nil {
+			return false
+		}
+		tableRefs = x.TableRefs
+	case *ast.DeleteStmt:
+		if x.IsMultiTable || x.With != nil {
+			return false
+		}
+		tableRefs = x.TableRefs
+	case *ast.InsertStmt:
+		if x.Select != nil {
+			return false
+		}
+		tableRefs = x.Table
+	default:
+		return false
+	}
+	if tableRefs == nil || tableRefs.TableRefs == nil || tableRefs.TableRefs.Right != nil {
+		return false
+	}
+	tableSource, ok := tableRefs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return false
+	}
+	tableName, ok := tableSource.Source.(*ast.TableName)
+	if !ok || tableName.AsOf != nil || util.IsMemOrSysDB(tableName.Schema.L) {
+		return false
+	}
+	checker := nonPreparedCacheableChecker{cacheable: true}
+	node.Accept(&checker)
+	return checker.cacheable && Cacheable(node, is)
+}
+
+// nonPreparedCacheableChecker rejects the statements which already contain parameter markers, e.g. the statements
+// rebuilt from the cached non-prepared statements.
+type nonPreparedCacheableChecker struct {
+	cacheable bool
+}
+
+// Enter implements Visitor interface.
+func (checker *nonPreparedCacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	if _, ok := in.(*driver.ParamMarkerExpr); ok {
+		checker.cacheable = false
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (checker *nonPreparedCacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, checker.cacheable
+}
+
+// rewriteParamExprs applies fn to the literals of the statement which can be parameterized, in the order they
+// appear in the SQL text. Only the literals in the filters, the assignments and the inserted values are visited,
+// the ones in other places like the field list or the limit clause decide the shape of the plan.
+func rewriteParamExprs(node ast.StmtNode, fn func(ast.ExprNode) ast.ExprNode) {
+	switch x := node.(type) {
+	case *ast.SelectStmt:
+		x.Where = rewriteParamExpr(x.Where, fn)
+	case *ast.UpdateStmt:
+		for _, assign := range x.List {
+			assign.Expr = rewriteParamExpr(assign.Expr, fn)
+		}
+		x.Where = rewriteParamExpr(x.Where, fn)
+	case *ast.DeleteStmt:
+		x.Where = rewriteParamExpr(x.Where, fn)
+	case *ast.InsertStmt:
+		for _, list := range x.Lists {
+			for i := range list {
+				list[i] = rewriteParamExpr(list[i], fn)
+			}
+		}
+		for _, assign := range x.Setlist {
+			assign.Expr = rewriteParamExpr(assign.Expr, fn)
+		}
+	}
+}
+
+func rewriteParamExpr(expr ast.ExprNode, fn func(ast.ExprNode) ast.ExprNode) ast.ExprNode {
+	switch x := expr.(type) {
+	case *driver.ValueExpr, *driver.ParamMarkerExpr:
+		return fn(x)
+	case *ast.BinaryOperationExpr:
+		x.L = rewriteParamExpr(x.L, fn)
+		x.R = rewriteParamExpr(x.R, fn)
+	case *ast.UnaryOperationExpr:
+		x.V = rewriteParamExpr(x.V, fn)
+	case *ast.ParenthesesExpr:
+		x.Expr = rewriteParamExpr(x.Expr, fn)
+	case *ast.BetweenExpr:
+		x.Expr = rewriteParamExpr(x.Expr, fn)
+		x.Left = rewriteParamExpr(x.Left, fn)
+		x.Right = rewriteParamExpr(x.Right, fn)
+	case *ast.PatternInExpr:
+		if x.Sel == nil {
+			x.Expr = rewriteParamExpr(x.Expr, fn)
+			for i := range x.List {
+				x.List[i] = rewriteParamExpr(x.List[i], fn)
+			}
+		}
+	case *ast.PatternLikeExpr:
+		x.Expr = rewriteParamExpr(x.Expr, fn)
+		x.Pattern = rewriteParamExpr(x.Pattern, fn)
+	case *ast.IsNullExpr:
+		x.Expr = rewriteParamExpr(x.Expr, fn)
+	case *ast.IsTruthExpr:
+		x.Expr = rewriteParamExpr(x.Expr, fn)
+	}
+	return expr
+}
+
+// parameterizable checks whether the literal can be replaced by a parameter marker without changing its type.
+func parameterizable(sctx sessionctx.Context, value *driver.ValueExpr) bool {
+	switch value.Kind() {
+	case types.KindInt64, types.KindUint64, types.KindFloat64, types.KindMysqlDecimal:
+		return true
+	case types.KindString:
+		// The string literals with a charset introducer keep their own charset.
+		chs, _ := sctx.GetSessionVars().GetCharsetInfo()
+		if chs == "" {
+			chs = mysql.DefaultCharset
+		}
+		return strings.EqualFold(value.Type.Charset, chs)
+	}
+	return false
+}
+
+// ParameterizeAST returns the parameterized SQL of the statement and the values of the parameters. The statement is
+// left unchanged.
+func ParameterizeAST(sctx sessionctx.Context, node ast.StmtNode) (string, []types.Datum, error) {
+	var (
+		params    []types.Datum
+		originals = make(map[*driver.ParamMarkerExpr]*driver.ValueExpr)
+	)
+	rewriteParamExprs(node, func(expr ast.ExprNode) ast.ExprNode {
+		value, ok := expr.(*driver.ValueExpr)
+		if !ok || !parameterizable(sctx, value) {
+			return expr
+		}
+		marker := &driver.ParamMarkerExpr{ValueExpr: *value}
+		originals[marker] = value
+		params = append(params, value.Datum)
+		return marker
+	})
+	var sb strings.Builder
+	err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb))
+	rewriteParamExprs(node, func(expr ast.ExprNode) ast.ExprNode {
+		if marker, ok := expr.(*driver.ParamMarkerExpr); ok {
+			if value, ok := originals[marker]; ok {
+				return value
+			}
+		}
+		return expr
+	})
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	return sb.String(), params, nil
+}
+
+// newNonPreparedStmt builds the CachedPrepareStmt of the parameterized SQL in the same way as the PREPARE statement.
+func newNonPreparedStmt(ctx context.Context, sctx sessionctx.Context, stmtText string) (*CachedPrepareStmt, error) {
+	vars := sctx.GetSessionVars()
+	p := parser.New()
+	p.SetParserConfig(vars.BuildParserConfig())
+	charset, collation := vars.GetCharsetInfo()
+	stmt, err := p.ParseOneStmt(stmtText, charset, collation)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret := &PreprocessorReturn{}
+	if err = Preprocess(sctx, stmt, InPrepare, WithPreprocessorReturn(ret)); err != nil {
+		return nil, err
+	}
+	var params []ast.ParamMarkerExpr
+	rewriteParamExprs(stmt, func(expr ast.ExprNode) ast.ExprNode {
+		if marker, ok := expr.(*driver.ParamMarkerExpr); ok {
+			marker.SetOrder(len(params))
+			params = append(params, marker)
+		}
+		return expr
+	})
+	prepared := &ast.Prepared{
+		Stmt:          stmt,
+		Params:        params,
+		SchemaVersion: ret.InfoSchema.SchemaMetaVersion(),
+		UseCache:      true,
+	}
+	// The plan is built only to collect the visit infos, restore the statement context it changes.
+	sc := vars.StmtCtx
+	warnCnt, dependOnMutableConst := int(sc.WarningCount()), sc.OptimDependOnMutableConst
+	defer func() {
+		sc.TruncateWarnings(warnCnt)
+		sc.OptimDependOnMutableConst = dependOnMutableConst
+	}()
+	vars.PlanID = 0
+	vars.PlanColumnID = 0
+	builder, _ := NewPlanBuilder().Init(sctx, ret.InfoSchema, &hint.BlockHintProcessor{})
+	if _, err = builder.Build(ctx, stmt); err != nil {
+		return nil, err
+	}
+	normalizedSQL, digest := parser.NormalizeDigest(stmtText)
+	return &CachedPrepareStmt{
+		PreparedAst:   prepared,
+		VisitInfos:    builder.GetVisitInfo(),
+		NormalizedSQL: normalizedSQL,
+		SQLDigest:     digest,
+		ForUpdateRead: builder.GetIsForUpdateRead(),
+		StmtText:      stmtText,
+	}, nil
+}
+
+// GetPlanFromNonPreparedPlanCache parameterizes the literals of a statement sent by the text protocol, and gets its
+// plan in the same way as the EXECUTE statement, so the plan is cached in the prepared plan cache and reused by the
+// statements which differ from it only in the literals. It returns false if the statement can't use the cache.
+func GetPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Context, node ast.StmtNode, is infoschema.InfoSchema) (Plan, types.NameSlice, bool, error) {
+	if !NonPreparedPlanCacheable(node, is) {
+		return nil, nil, false, nil
+	}
+	stmtText, params, err := ParameterizeAST(sctx, node)
+	if err != nil {
+		logutil.BgLogger().Debug("parameterize statement failed", zap.Error(err))
+		return nil, nil, false, nil
+	}
+	vars := sctx.GetSessionVars()
+	stmtKey := &nonPreparedStmtKey{database: vars.CurrentDB, stmtText: stmtText}
+	var preparedObj *CachedPrepareStmt
+	if val, ok := sctx.PreparedPlanCache().Get(stmtKey); ok {
+		preparedObj = val.(*CachedPrepareStmt)
+	} else {
+		preparedObj, err = newNonPreparedStmt(ctx, sctx, stmtText)
+		if err != nil {
+			// Fall back to the normal optimization, which reports the error if there is any.
+			logutil.BgLogger().Debug("build non-prepared statement failed", zap.String("sql", stmtText), zap.Error(err))
+			return nil, nil, false, nil
+		}
+		sctx.PreparedPlanCache().Put(stmtKey, preparedObj)
+	}
+	if len(preparedObj.PreparedAst.Params) != len(params) {
+		return nil, nil, false, nil
+	}
+	exec := &Execute{PrepareParams: params}
+	// The plan may come from the point plan cached in the statement, whose privileges are not checked again.
+	if err = exec.checkPreparedPriv(ctx, sctx, preparedObj, is); err != nil {
+		return nil, nil, false, err
+	}
+	if err = exec.optimizePreparedPlan(ctx, sctx, is, preparedObj); err != nil {
+		return nil, nil, false, err
+	}
+	return exec.Plan, exec.OutputNames(), true, nil
+}
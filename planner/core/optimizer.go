@@ -150,6 +150,9 @@ func DoOptimize(ctx context.Context, sctx sessionctx.Context, flag uint64, logic
 	if checkStableResultMode(sctx) {
 		flag |= flagStabilizeResults
 	}
+	if variable.EnableColumnTracking.Load() && !sctx.GetSessionVars().InRestrictedSQL {
+		collectPredicateColumns(sctx, logic)
+	}
 	logic, err := logicalOptimize(ctx, flag, logic)
 	if err != nil {
 		return nil, 0, err
@@ -308,7 +308,17 @@ func (p *PhysicalIndexScan) ToPB(ctx sessionctx.Context, _ kv.StoreType) (*tipb.
 		} else if col.ID == model.ExtraPidColID {
 			columns = append(columns, model.NewExtraPartitionIDColInfo())
 		} else {
-			columns = append(columns, findColumnInfoByID(tableColumns, col.ID))
+			colInfo := findColumnInfoByID(tableColumns, col.ID)
+			if table.IsMultiValuedColumn(colInfo) {
+				// The multi-valued index stores the elements of the array instead of the JSON value.
+				elemTp, err := table.MultiValuedElemType(colInfo)
+				if err != nil {
+					return nil, err
+				}
+				colInfo = colInfo.Clone()
+				colInfo.FieldType = *elemTp
+			}
+			columns = append(columns, colInfo)
 		}
 	}
 	var pkColIds []int64
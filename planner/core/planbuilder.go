@@ -1066,6 +1066,11 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// The multi-valued index has an entry for each element, it's only read by the IndexMerge paths
+			// generated in DataSource.DeriveStats.
+			if table.FindMultiValuedColumn(tblInfo, index) >= 0 {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1574,6 +1579,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if table.FindMultiValuedColumn(tblInfo, idxInfo) >= 0 {
+			// Skip the multi-valued index, it's checked by scanning the records since it has an entry for each element.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1671,6 +1680,15 @@ func (b *PlanBuilder) buildAdminCheckTable(ctx context.Context, as *ast.AdminStm
 	}
 	p.IndexInfos = indexInfos
 	p.IndexLookUpReaders = readers
+	for _, idx := range tbl.Indices() {
+		idxInfo := idx.Meta()
+		if as.Tp == ast.AdminCheckIndex && idxInfo.Name.L != strings.ToLower(as.Index) {
+			continue
+		}
+		if idxInfo.State == model.StatePublic && table.FindMultiValuedColumn(tableInfo, idxInfo) >= 0 {
+			p.MultiValuedIndexInfos = append(p.MultiValuedIndexInfos, idxInfo)
+		}
+	}
 	return p, nil
 }
 
@@ -1727,23 +1745,44 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		// The statistics of the multi-valued index aren't collected now.
+		if idx.State == model.StatePublic && table.FindMultiValuedColumn(tn.TableInfo, idx) < 0 {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
 	return
 }
 
+// getColOffsetForAnalyze returns the offset of the column in colsInfo.
+func getColOffsetForAnalyze(colsInfo []*model.ColumnInfo, colID int64) int {
+	for i, col := range colsInfo {
+		if colID == col.ID {
+			return i
+		}
+	}
+	return -1
+}
+
 // BuildHandleColsForAnalyze is exported for test.
 func BuildHandleColsForAnalyze(ctx sessionctx.Context, tblInfo *model.TableInfo) HandleCols {
+	return buildHandleColsForAnalyze(ctx, tblInfo, true, nil)
+}
+
+// buildHandleColsForAnalyze builds the handle columns of the table. If allColumns is true, the indexes of the handle
+// columns are their offsets in the table, otherwise they're the offsets in colsInfo.
+func buildHandleColsForAnalyze(ctx sessionctx.Context, tblInfo *model.TableInfo, allColumns bool, colsInfo []*model.ColumnInfo) HandleCols {
 	var handleCols HandleCols
 	switch {
 	case tblInfo.PKIsHandle:
 		pkCol := tblInfo.GetPkColInfo()
+		index := pkCol.Offset
+		if !allColumns {
+			index = getColOffsetForAnalyze(colsInfo, pkCol.ID)
+		}
 		handleCols = &IntHandleCols{col: &expression.Column{
 			ID:      pkCol.ID,
 			RetType: &pkCol.FieldType,
-			Index:   pkCol.Offset,
+			Index:   index,
 		}}
 	case tblInfo.IsCommonHandle:
 		pkIdx := tables.FindPrimaryIndex(tblInfo)
@@ -1751,10 +1790,14 @@ func BuildHandleColsForAnalyze(ctx sessionctx.Context, tblInfo *model.TableInfo)
 		columns := make([]*expression.Column, pkColLen)
 		for i := 0; i < pkColLen; i++ {
 			colInfo := tblInfo.Columns[pkIdx.Columns[i].Offset]
+			index := colInfo.Offset
+			if !allColumns {
+				index = getColOffsetForAnalyze(colsInfo, colInfo.ID)
+			}
 			columns[i] = &expression.Column{
 				ID:      colInfo.ID,
 				RetType: &colInfo.FieldType,
-				Index:   colInfo.Offset,
+				Index:   index,
 			}
 		}
 		handleCols = &CommonHandleCols{
@@ -1804,6 +1847,84 @@ func GetPhysicalIDsAndPartitionNames(tblInfo *model.TableInfo, partitionNames []
 	return ids, names, nil
 }
 
+// getPredicateColumnIDsForAnalyze returns the IDs of the columns which should be analyzed if only the predicate
+// columns are analyzed. Besides the predicate columns, the columns of the indexes and the handle, and the columns the
+// analyzed virtual generated columns depend on are always analyzed. It returns nil if all the columns are analyzed.
+func (b *PlanBuilder) getPredicateColumnIDsForAnalyze(tbl *ast.TableName) map[int64]struct{} {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.AnalyzePredicateColumns {
+		return nil
+	}
+	tblInfo := tbl.TableInfo
+	if sessVars.EnableFastAnalyze {
+		sessVars.StmtCtx.AppendWarning(errors.Errorf("Fast analyze doesn't support analyzing predicate columns only, all the columns of table %s.%s are analyzed", tbl.Schema.O, tbl.Name.O))
+		return nil
+	}
+	predicateCols, err := domain.GetDomain(b.ctx).StatsHandle().GetPredicateColumns(tblInfo.ID)
+	if err != nil {
+		sessVars.StmtCtx.AppendWarning(errors.Errorf("Failed to load the predicate columns of table %s.%s, all the columns are analyzed: %v", tbl.Schema.O, tbl.Name.O, err))
+		return nil
+	}
+	if len(predicateCols) == 0 {
+		sessVars.StmtCtx.AppendWarning(errors.Errorf("No predicate column has been collected yet for table %s.%s, all the columns are analyzed", tbl.Schema.O, tbl.Name.O))
+		return nil
+	}
+	colIDs := make(map[int64]struct{}, len(predicateCols))
+	for _, colID := range predicateCols {
+		colIDs[colID] = struct{}{}
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic {
+			continue
+		}
+		for _, idxCol := range idx.Columns {
+			colIDs[tblInfo.Columns[idxCol.Offset].ID] = struct{}{}
+		}
+	}
+	if tblInfo.PKIsHandle {
+		colIDs[tblInfo.GetPkColInfo().ID] = struct{}{}
+	}
+	// A generated column can only refer to the columns defined before it, so the dependencies are resolved by
+	// traversing the columns backwards.
+	for i := len(tblInfo.Columns) - 1; i >= 0; i-- {
+		col := tblInfo.Columns[i]
+		if _, ok := colIDs[col.ID]; !ok || !col.IsGenerated() || col.GeneratedStored {
+			continue
+		}
+		for name := range col.Dependences {
+			if depCol := model.FindColumnInfo(tblInfo.Columns, name); depCol != nil {
+				colIDs[depCol.ID] = struct{}{}
+			}
+		}
+	}
+	return colIDs
+}
+
+// filterColumnsForAnalyze returns the columns whose IDs are in colIDs, the order of the columns is kept.
+func filterColumnsForAnalyze(colsInfo []*model.ColumnInfo, colIDs map[int64]struct{}) []*model.ColumnInfo {
+	filtered := make([]*model.ColumnInfo, 0, len(colIDs))
+	for _, col := range colsInfo {
+		if _, ok := colIDs[col.ID]; ok {
+			filtered = append(filtered, col)
+		}
+	}
+	return filtered
+}
+
+// remapIndexOffsetsForAnalyze clones the indexes and sets the offsets of their columns to the offsets in colsInfo,
+// because the full sampling analyze locates the index columns by their offsets in the analyzed columns.
+func remapIndexOffsetsForAnalyze(tblInfo *model.TableInfo, idxInfos []*model.IndexInfo, colsInfo []*model.ColumnInfo) []*model.IndexInfo {
+	remapped := make([]*model.IndexInfo, 0, len(idxInfos))
+	for _, idx := range idxInfos {
+		newIdx := idx.Clone()
+		for _, idxCol := range newIdx.Columns {
+			idxCol.Offset = getColOffsetForAnalyze(colsInfo, tblInfo.Columns[idxCol.Offset].ID)
+		}
+		remapped = append(remapped, newIdx)
+	}
+	return remapped
+}
+
 func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 	as *ast.AnalyzeTableStmt,
 	taskSlice []AnalyzeColumnsTask,
@@ -1814,7 +1935,7 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 ) []AnalyzeColumnsTask {
 	idxInfos := make([]*model.IndexInfo, 0, len(tbl.TableInfo.Indices))
 	for _, idx := range tbl.TableInfo.Indices {
-		if idx.State != model.StatePublic {
+		if idx.State != model.StatePublic || table.FindMultiValuedColumn(tbl.TableInfo, idx) >= 0 {
 			continue
 		}
 		idxInfos = append(idxInfos, idx)
@@ -1822,6 +1943,11 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 	if as.Incremental {
 		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("The version 2 stats would ignore the INCREMENTAL keyword and do full sampling"))
 	}
+	colsInfo, allColumns := tbl.TableInfo.Columns, true
+	if predicateColIDs := b.getPredicateColumnIDsForAnalyze(tbl); predicateColIDs != nil {
+		colsInfo, allColumns = filterColumnsForAnalyze(tbl.TableInfo.Columns, predicateColIDs), false
+		idxInfos = remapIndexOffsetsForAnalyze(tbl.TableInfo, idxInfos, colsInfo)
+	}
 	for i, id := range physicalIDs {
 		if id == tbl.TableInfo.ID {
 			id = -1
@@ -1835,8 +1961,8 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 			StatsVersion:  version,
 		}
 		newTask := AnalyzeColumnsTask{
-			HandleCols:  BuildHandleColsForAnalyze(b.ctx, tbl.TableInfo),
-			ColsInfo:    tbl.TableInfo.Columns,
+			HandleCols:  buildHandleColsForAnalyze(b.ctx, tbl.TableInfo, allColumns, colsInfo),
+			ColsInfo:    colsInfo,
 			AnalyzeInfo: info,
 			TblInfo:     tbl.TableInfo,
 			Indexes:     idxInfos,
@@ -1844,7 +1970,7 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 		if newTask.HandleCols == nil {
 			extraCol := model.NewExtraHandleColInfo()
 			// Always place _tidb_rowid at the end of colsInfo, this is corresponding to logics in `analyzeColumnsPushdown`.
-			newTask.ColsInfo = append(newTask.ColsInfo, extraCol)
+			newTask.ColsInfo = append(newTask.ColsInfo[:len(newTask.ColsInfo):len(newTask.ColsInfo)], extraCol)
 			newTask.HandleCols = &IntHandleCols{col: colInfoToColumn(extraCol, len(newTask.ColsInfo)-1)}
 		}
 		taskSlice = append(taskSlice, newTask)
@@ -1882,6 +2008,9 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 			p.ColTasks = b.buildAnalyzeFullSamplingTask(as, p.ColTasks, physicalIDs, names, tbl, version)
 			continue
 		}
+		if predicateColIDs := b.getPredicateColumnIDsForAnalyze(tbl); predicateColIDs != nil {
+			colInfo = filterColumnsForAnalyze(colInfo, predicateColIDs)
+		}
 		for _, idx := range idxInfo {
 			// For prefix common handle. We don't use analyze mixed to handle it with columns. Because the full value
 			// is read by coprocessor, the prefix index would get wrong stats in this case.
@@ -1908,6 +2037,8 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 				})
 			}
 		}
+		// The version 1 analyze locates the handle columns by their offsets in the table rather than in colInfo,
+		// so the handle columns are built in the same way whether the predicate columns are filtered or not.
 		handleCols := BuildHandleColsForAnalyze(b.ctx, tbl.TableInfo)
 		if len(colInfo) > 0 || handleCols != nil {
 			for i, id := range physicalIDs {
@@ -1982,6 +2113,10 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 		if idx == nil || idx.State != model.StatePublic {
 			return nil, ErrAnalyzeMissIndex.GenWithStackByArgs(idxName.O, tblInfo.Name.O)
 		}
+		if table.FindMultiValuedColumn(tblInfo, idx) >= 0 {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("The statistics of the multi-valued index %s are not collected", idx.Name.O))
+			continue
+		}
 		for i, id := range physicalIDs {
 			if id == tblInfo.ID {
 				id = -1
@@ -2023,7 +2158,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 		return b.buildAnalyzeTable(as, opts, version)
 	}
 	for _, idx := range tblInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && table.FindMultiValuedColumn(tblInfo, idx) < 0 {
 			for i, id := range physicalIDs {
 				if id == tblInfo.ID {
 					id = -1
@@ -2139,6 +2274,13 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	if err != nil {
 		return nil, err
 	}
+	as, err = b.skipLockedTablesForAnalyze(as)
+	if err != nil {
+		return nil, err
+	}
+	if len(as.TableNames) == 0 {
+		return &Analyze{Opts: opts}, nil
+	}
 	if as.IndexFlag {
 		if len(as.IndexNames) == 0 {
 			return b.buildAnalyzeAllIndex(as, opts, statsVersion)
@@ -2148,6 +2290,33 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	return b.buildAnalyzeTable(as, opts, statsVersion)
 }
 
+// skipLockedTablesForAnalyze removes the tables whose stats are locked from the ANALYZE statement with warnings.
+func (b *PlanBuilder) skipLockedTablesForAnalyze(as *ast.AnalyzeTableStmt) (*ast.AnalyzeTableStmt, error) {
+	do := domain.GetDomain(b.ctx)
+	if do == nil || do.StatsHandle() == nil {
+		return as, nil
+	}
+	tableIDs := make([]int64, 0, len(as.TableNames))
+	for _, tbl := range as.TableNames {
+		tableIDs = append(tableIDs, tbl.TableInfo.ID)
+	}
+	lockedTables, err := do.StatsHandle().GetLockedTables(tableIDs...)
+	if err != nil || len(lockedTables) == 0 {
+		return as, err
+	}
+	tableNames := make([]*ast.TableName, 0, len(as.TableNames))
+	for _, tbl := range as.TableNames {
+		if _, ok := lockedTables[tbl.TableInfo.ID]; ok {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("skip analyze locked table: %s.%s", tbl.Schema.O, tbl.Name.O))
+			continue
+		}
+		tableNames = append(tableNames, tbl)
+	}
+	newAs := *as
+	newAs.TableNames = tableNames
+	return &newAs, nil
+}
+
 func buildShowNextRowID() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(4)
 	schema.Append(buildColumnWithName("", "DB_NAME", mysql.TypeVarchar, mysql.MaxDatabaseNameLength))
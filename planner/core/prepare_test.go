@@ -1516,3 +1516,184 @@ func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 		}
 	}
 }
+
+func (s *testPrepareSerialSuite) TestNonPreparedPlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.GetConnectionID()
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), key(b))")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t values(1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c')")
+
+	// The cache is disabled by default.
+	tk.MustQuery("select a from t where b = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where b = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustQuery("select a from t where b = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t where b = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where b in (1, 3) order by a").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select a from t where b in (2, 3) order by a").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	// The literals of different types don't share the plan.
+	tk.MustQuery("select a from t where b = '3'").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// Point get.
+	tk.MustQuery("select c from t where a = 1").Check(testkit.Rows("a"))
+	tk.MustQuery("select c from t where a = 3").Check(testkit.Rows("c"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select c from t where a = 4").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// Simple DML.
+	tk.MustExec("insert into t values (4, 4, 'd')")
+	tk.MustExec("insert into t values (5, 5, 'e')")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("update t set c = 'x' where b = 4")
+	tk.MustExec("update t set c = 'y' where b = 5")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("delete from t where a = 4")
+	tk.MustExec("delete from t where a = 1")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("2 2 b", "3 3 c", "5 5 y"))
+
+	// The cached plans are invalidated by the schema change.
+	tk.MustExec("alter table t add column d int")
+	tk.MustQuery("select a from t where b = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t where b = 3").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The statements which are not simple enough don't use the cache.
+	tk.MustQuery("select t.a from t, t2 where t.a = t2.a and t.b = 1").Check(testkit.Rows())
+	tk.MustQuery("select t.a from t, t2 where t.a = t2.a and t.b = 2").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t where b = (select max(b) from t2)").Check(testkit.Rows())
+	tk.MustQuery("select a from t where b = (select max(b) from t2)").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select user from mysql.user where user = 'u'").Check(testkit.Rows())
+	tk.MustQuery("select user from mysql.user where user = 'v'").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The privileges are checked for the cached plans.
+	tk.MustExec("create user 'u_npc'@'localhost'")
+	tk.MustExec("grant select on test.t to 'u_npc'@'localhost'")
+	userSess := newSession(c, store, "test")
+	c.Assert(userSess.Auth(&auth.UserIdentity{Username: "u_npc", Hostname: "localhost"}, nil, nil), IsTrue)
+	rootSe := tk.Se
+	tk.Se = userSess
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustQuery("select c from t where a = 2").Check(testkit.Rows("b"))
+	tk.MustQuery("select c from t where a = 3").Check(testkit.Rows("c"))
+	tk.Se = rootSe
+	tk.MustExec("revoke select on test.t from 'u_npc'@'localhost'")
+	tk.Se = userSess
+	_, err = tk.Exec("select c from t where a = 2")
+	c.Assert(err, NotNil)
+	tk.Se = rootSe
+	tk.MustExec("drop user 'u_npc'@'localhost'")
+
+	// The cache hits are reported by the statement summary.
+	tk.MustQuery("select b from t where a > 1 order by b").Check(testkit.Rows("2", "3", "5"))
+	tk.MustQuery("select b from t where a > 2 order by b").Check(testkit.Rows("3", "5"))
+	tk.MustQuery("select b from t where a > 3 order by b").Check(testkit.Rows("5"))
+	tk.MustQuery("select exec_count, plan_cache_hits, plan_in_cache from information_schema.statements_summary " +
+		"where digest_text = 'select `b` from `t` where `a` > ? order by `b`'").Check(testkit.Rows("3 2 1"))
+}
+
+func (s *testPrepareSerialSuite) TestInstancePlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	newTestKit := func() *testkit.TestKit {
+		tk := testkit.NewTestKit(c, store)
+		tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+			PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+		})
+		c.Assert(err, IsNil)
+		tk.GetConnectionID()
+		c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+		tk.MustExec("use test")
+		return tk
+	}
+	tk1, tk2 := newTestKit(), newTestKit()
+	defer tk1.MustExec("set global tidb_enable_instance_plan_cache = off")
+	tk1.MustExec("drop table if exists t")
+	tk1.MustExec("create table t(a int primary key, b int, key(b))")
+	tk1.MustExec("insert into t values(1, 1), (2, 2), (3, 3)")
+	tk1.MustExec("set global tidb_enable_instance_plan_cache = on")
+	tk1.MustQuery("select @@global.tidb_instance_plan_cache_max_mem_size").Check(testkit.Rows("104857600"))
+
+	// The plan built by a session is used by the other sessions.
+	tk1.MustExec("prepare stmt from 'select a from t where b = ?'")
+	tk1.MustExec("set @b = 1")
+	tk1.MustQuery("execute stmt using @b").Check(testkit.Rows("1"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("prepare stmt2 from 'select a from t where b = ?'")
+	tk2.MustExec("set @b = 2")
+	tk2.MustQuery("execute stmt2 using @b").Check(testkit.Rows("2"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk1.MustExec("set @b = 3")
+	tk1.MustQuery("execute stmt using @b").Check(testkit.Rows("3"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk1.MustQuery("select schema_name, digest_text, plan_count, hits from information_schema.instance_plan_cache").
+		Check(testkit.Rows("test select `a` from `t` where `b` = ? 1 2"))
+
+	// The sessions with different collations don't share the plan.
+	tk2.MustExec("set @@collation_connection = 'utf8mb4_general_ci'")
+	tk2.MustQuery("execute stmt2 using @b").Check(testkit.Rows("2"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk1.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("2"))
+
+	// The expressions executing SQL with the session still work in the cached plans.
+	tk1.MustExec("prepare stmt3 from 'select tidb_decode_sql_digests(?)'")
+	tk1.MustExec(`set @d = '["unknown"]'`)
+	tk1.MustQuery("execute stmt3 using @d").Check(testkit.Rows("[null]"))
+	tk1.MustQuery("execute stmt3 using @d").Check(testkit.Rows("[null]"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk1.MustQuery("show warnings").Check(testkit.Rows())
+
+	// The cached plans are purged when the cache is disabled.
+	tk1.MustExec("set global tidb_enable_instance_plan_cache = off")
+	tk1.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("0"))
+	tk1.MustExec("set global tidb_enable_instance_plan_cache = on")
+
+	// The plans are not kept if they exceed the memory quota.
+	tk1.MustExec("set global tidb_instance_plan_cache_max_mem_size = 1")
+	defer tk1.MustExec("set global tidb_instance_plan_cache_max_mem_size = default")
+	tk1.MustQuery("execute stmt using @b").Check(testkit.Rows("3"))
+	tk1.MustQuery("execute stmt using @b").Check(testkit.Rows("3"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk1.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("0"))
+}
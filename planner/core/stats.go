@@ -28,6 +28,8 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
@@ -217,6 +219,42 @@ func (ds *DataSource) getGroupNDVs(colGroups [][]*expression.Column) []property.
 			}
 		}
 	}
+	if ds.ctx.GetSessionVars().EnableExtendedStats {
+		ndvs = appendGroupNDVsByColGroupStats(ndvs, colGroups, tbl)
+	}
+	return ndvs
+}
+
+// appendGroupNDVsByColGroupStats appends the NDV of the column groups which have multi-column NDV statistics but are
+// not covered by any index.
+func appendGroupNDVsByColGroupStats(ndvs []property.GroupNDV, colGroups [][]*expression.Column, tbl *statistics.HistColl) []property.GroupNDV {
+	for _, item := range tbl.ColGroupStats {
+		if item.Tp != ast.StatsTypeCardinality || item.ScalarVals < 1 {
+			continue
+		}
+		cols := make([]int64, len(item.ColIDs))
+		copy(cols, item.ColIDs)
+		sort.Slice(cols, func(i, j int) bool {
+			return cols[i] < cols[j]
+		})
+		for _, g := range colGroups {
+			if len(g) != len(cols) {
+				continue
+			}
+			match := true
+			for i, col := range g {
+				// Both slices are sorted according to UniqueID.
+				if col.UniqueID != cols[i] {
+					match = false
+					break
+				}
+			}
+			if match && getGroupNDV4Cols(g, &property.StatsInfo{GroupNDVs: ndvs}) == nil {
+				ndvs = append(ndvs, property.GroupNDV{Cols: cols, NDV: item.ScalarVals})
+				break
+			}
+		}
+	}
 	return ndvs
 }
 
@@ -442,6 +480,10 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		ds.indexMergeHints = nil
 		ds.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("IndexMerge is inapplicable or disabled"))
 	}
+	// The multi-valued indexes can only be read by IndexMerge, so they are considered even if IndexMerge is disabled.
+	if len(ds.allConds) > 0 && isReadOnlyTxn && ds.tableInfo.TempTableType != model.TempTableLocal {
+		ds.generateMultiValuedIndexMergePaths()
+	}
 	return ds.stats, nil
 }
 
@@ -983,6 +1025,7 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	leftProfile, rightProfile := childStats[0], childStats[1]
 	leftJoinKeys, rightJoinKeys, _, _ := p.GetJoinKeys()
 	helper := &fullJoinRowCountHelper{
+		ctx:           p.ctx,
 		cartesian:     0 == len(p.EqualConditions),
 		leftProfile:   leftProfile,
 		rightProfile:  rightProfile,
@@ -1031,10 +1074,35 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		RowCount: count,
 		ColNDVs:  colNDVs,
 	}
+	if p.JoinType == InnerJoin && p.ctx.GetSessionVars().EnableJoinHistEstimation {
+		p.stats.HistColl = joinHistColl(leftProfile.HistColl, rightProfile.HistColl)
+	}
 	p.stats.GroupNDVs = p.getGroupNDVs(colGroups, childStats)
 	return p.stats, nil
 }
 
+// joinHistColl collects the column stats of the join inputs, so that the upper joins can estimate their row counts by
+// the stats of the join keys coming from the lower joins as well.
+func joinHistColl(colls ...*statistics.HistColl) *statistics.HistColl {
+	var newColl *statistics.HistColl
+	for _, coll := range colls {
+		if coll == nil || coll.Pseudo || len(coll.Columns) == 0 {
+			continue
+		}
+		if newColl == nil {
+			// The Count is left 0 since the columns come from different tables.
+			newColl = &statistics.HistColl{
+				Columns: make(map[int64]*statistics.Column),
+				Indices: make(map[int64]*statistics.Index),
+			}
+		}
+		for id, col := range coll.Columns {
+			newColl.Columns[id] = col
+		}
+	}
+	return newColl
+}
+
 // ExtractColGroups implements LogicalPlan ExtractColGroups interface.
 func (p *LogicalJoin) ExtractColGroups(colGroups [][]*expression.Column) [][]*expression.Column {
 	leftJoinKeys, rightJoinKeys, _, _ := p.GetJoinKeys()
@@ -1062,6 +1130,7 @@ func (p *LogicalJoin) ExtractColGroups(colGroups [][]*expression.Column) [][]*ex
 }
 
 type fullJoinRowCountHelper struct {
+	ctx           sessionctx.Context
 	cartesian     bool
 	leftProfile   *property.StatsInfo
 	rightProfile  *property.StatsInfo
@@ -1075,12 +1144,40 @@ func (h *fullJoinRowCountHelper) estimate() float64 {
 	if h.cartesian {
 		return h.leftProfile.RowCount * h.rightProfile.RowCount
 	}
+	if h.ctx != nil && h.ctx.GetSessionVars().EnableJoinHistEstimation && len(h.leftJoinKeys) == 1 {
+		if count, ok := h.estimateByHistograms(); ok {
+			return count
+		}
+	}
 	leftKeyNDV := getColsNDV(h.leftJoinKeys, h.leftSchema, h.leftProfile)
 	rightKeyNDV := getColsNDV(h.rightJoinKeys, h.rightSchema, h.rightProfile)
 	count := h.leftProfile.RowCount * h.rightProfile.RowCount / math.Max(leftKeyNDV, rightKeyNDV)
 	return count
 }
 
+// estimateByHistograms estimates the row count of the equal join on a single pair of columns by aligning the
+// histograms and TopN of the join keys.
+func (h *fullJoinRowCountHelper) estimateByHistograms() (float64, bool) {
+	sc := h.ctx.GetSessionVars().StmtCtx
+	leftCol := getJoinKeyStats(sc, h.leftProfile, h.leftJoinKeys[0])
+	rightCol := getJoinKeyStats(sc, h.rightProfile, h.rightJoinKeys[0])
+	if leftCol == nil || rightCol == nil {
+		return 0, false
+	}
+	return statistics.EqualJoinRowCount(sc, leftCol, rightCol, h.leftProfile.RowCount, h.rightProfile.RowCount)
+}
+
+func getJoinKeyStats(sc *stmtctx.StatementContext, profile *property.StatsInfo, key *expression.Column) *statistics.Column {
+	if profile.HistColl == nil || profile.HistColl.Pseudo {
+		return nil
+	}
+	col, ok := profile.HistColl.Columns[key.UniqueID]
+	if !ok || col.IsInvalid(sc, false) {
+		return nil
+	}
+	return col
+}
+
 func (la *LogicalApply) getGroupNDVs(colGroups [][]*expression.Column, childStats []*property.StatsInfo) []property.GroupNDV {
 	if len(colGroups) > 0 && (la.JoinType == LeftOuterSemiJoin || la.JoinType == AntiLeftOuterSemiJoin || la.JoinType == LeftOuterJoin) {
 		return childStats[0].GroupNDVs
@@ -124,6 +124,10 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}
 	}
 
+	if p, names, ok, err := getPlanFromNonPreparedPlanCache(ctx, sctx, node, is); err != nil || ok {
+		return p, names, err
+	}
+
 	if _, isolationReadContainTiKV := sessVars.IsolationReadEngines[kv.TiKV]; isolationReadContainTiKV {
 		var fp plannercore.Plan
 		if fpv, ok := sctx.Value(plannercore.PointPlanKey).(plannercore.PointPlanVal); ok {
@@ -261,6 +265,33 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	return bestPlan, names, nil
 }
 
+// getPlanFromNonPreparedPlanCache tries to get the plan of a statement sent by the text protocol from the plan cache.
+// The statements which have bindings are not cached since their plans are decided by the bindings.
+func getPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, bool, error) {
+	sessVars := sctx.GetSessionVars()
+	stmtNode, ok := node.(ast.StmtNode)
+	if !ok || !sessVars.EnableNonPreparedPlanCache || !plannercore.PreparedPlanCacheEnabled() ||
+		sessVars.InRestrictedSQL || sessVars.StmtCtx.InExplainStmt || variable.RestrictedReadOnly.Load() ||
+		// The statement is being rebuilt from a cached statement.
+		sessVars.StmtCtx.UseCache {
+		return nil, nil, false, nil
+	}
+	if sessVars.UsePlanBaselines {
+		bindRecord, _, err := getBindRecord(sctx, stmtNode)
+		if err != nil || (bindRecord != nil && len(bindRecord.Bindings) > 0) {
+			return nil, nil, false, nil
+		}
+	}
+	p, names, ok, err := plannercore.GetPlanFromNonPreparedPlanCache(ctx, sctx, stmtNode, is)
+	if err != nil || !ok {
+		return nil, nil, ok, err
+	}
+	if !useMaxTS(sctx, p) {
+		sctx.PrepareTSFuture(ctx)
+	}
+	return p, names, true, nil
+}
+
 func allowInReadOnlyMode(sctx sessionctx.Context, node ast.Node) (bool, error) {
 	pm := privilege.GetPrivilegeManager(sctx)
 	if pm == nil {
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -61,6 +62,12 @@ type Manager interface {
 	// ConnectionVerification verifies user privilege for connection.
 	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) (string, string, bool)
 
+	// ConnectionVerificationWithError is the same as ConnectionVerification, except that it returns the error of
+	// the verification and the VerificationInfo of the account, which is returned even if the verification fails,
+	// as long as an account is matched.
+	// authConn is used by the authentication plugins which exchange more data with the client, it may be nil.
+	ConnectionVerificationWithError(user, host string, auth, salt []byte, authConn AuthConn, sessionVars *variable.SessionVars) (VerificationInfo, error)
+
 	// GetAuthWithoutVerification uses to get auth name without verification.
 	GetAuthWithoutVerification(user, host string) (string, string, bool)
 
@@ -90,6 +97,32 @@ type Manager interface {
 	GetAuthPlugin(user, host string) (string, error)
 }
 
+// VerificationInfo is the information of the account returned by ConnectionVerification.
+type VerificationInfo struct {
+	// AuthUser and AuthHost are the user and host of the account matched in mysql.user.
+	AuthUser string
+	AuthHost string
+	// PasswordExpired is true if the password of the account has expired, the session should be in sandbox mode.
+	PasswordExpired bool
+	// TrackFailedLogin is true if the failed logins of the account are tracked to lock the account temporarily,
+	// it's only set when the password has been checked.
+	TrackFailedLogin bool
+	// Roles are the roles granted by the external authentication, such as the roles mapped from the LDAP groups.
+	// They're activated in addition to the default roles.
+	Roles []*auth.RoleIdentity
+	// AuthConnUsed is true if the authentication has exchanged the messages with the client through the AuthConn.
+	// The exchange can't be restarted, so the authentication can't be retried with another host.
+	AuthConnUsed bool
+}
+
+// AuthConn is the client connection in the authentication phase.
+type AuthConn interface {
+	// WriteAuthMoreData sends the data to the client in an AuthMoreData packet.
+	WriteAuthMoreData(data []byte) error
+	// ReadPacket reads the response of the client.
+	ReadPacket() ([]byte, error)
+}
+
 const key keyType = 0
 
 // BindPrivilegeManager binds Manager to context.
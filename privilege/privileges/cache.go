@@ -19,8 +19,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"sort"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -64,7 +66,10 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked,plugin FROM mysql.user`
+	account_locked,plugin`
+	// sqlLoadUserPasswordPolicy is the columns of the password expiration and failed-login tracking policies.
+	sqlLoadUserPasswordPolicy = `,Password_expired,UNIX_TIMESTAMP(Password_last_changed) AS Password_last_changed,Password_lifetime,
+	Failed_login_attempts,Password_lock_time,UNIX_TIMESTAMP(Password_locked_time) AS Password_locked_time`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -98,6 +103,18 @@ type UserRecord struct {
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
 	AuthPlugin           string
+
+	// The fields below are the password policy of the account.
+	PasswordExpired     bool
+	PasswordLastChanged time.Time
+	// PasswordLifetime is the days before the password expires, -1 means default_password_lifetime is used.
+	PasswordLifetime int64
+	// The account is locked for PasswordLockTime days after FailedLoginAttempts consecutive failed logins,
+	// the failed logins are not tracked if either of them is 0, and PasswordLockTime -1 means locked until unlocked.
+	FailedLoginAttempts int64
+	PasswordLockTime    int64
+	// PasswordLockedTime is the time the account is locked by the failed logins, it's zero if not locked.
+	PasswordLockedTime time.Time
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -379,6 +396,16 @@ func noSuchTable(err error) bool {
 	return false
 }
 
+func noSuchColumn(err error) bool {
+	e1 := errors.Cause(err)
+	if e2, ok := e1.(*terror.Error); ok {
+		if terror.ErrCode(e2.Code()) == terror.ErrCode(mysql.ErrBadField) {
+			return true
+		}
+	}
+	return false
+}
+
 // LoadRoleGraph loads the mysql.role_edges table from database.
 func (p *MySQLPrivilege) LoadRoleGraph(ctx sessionctx.Context) error {
 	p.RoleGraph = make(map[string]roleGraphEdgesTable)
@@ -391,7 +418,12 @@ func (p *MySQLPrivilege) LoadRoleGraph(ctx sessionctx.Context) error {
 
 // LoadUserTable loads the mysql.user table from database.
 func (p *MySQLPrivilege) LoadUserTable(ctx sessionctx.Context) error {
-	err := p.loadTable(ctx, sqlLoadUserTable, p.decodeUserTableRow)
+	err := p.loadTable(ctx, sqlLoadUserTable+sqlLoadUserPasswordPolicy+" FROM mysql.user", p.decodeUserTableRow)
+	if noSuchColumn(err) {
+		// The mysql.user table which isn't created by TiDB may lack the password policy columns,
+		// load it without the policies in this case.
+		err = p.loadTable(ctx, sqlLoadUserTable+" FROM mysql.user", p.decodeUserTableRow)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -626,6 +658,7 @@ func (record *baseRecord) assignUserOrHost(row chunk.Row, i int, f *ast.ResultFi
 
 func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField) error {
 	var value UserRecord
+	value.PasswordLifetime = -1
 	for i, f := range fs {
 		switch {
 		case f.ColumnAsName.L == "authentication_string":
@@ -634,6 +667,20 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			if row.GetEnum(i).String() == "Y" {
 				value.AccountLocked = true
 			}
+		case f.ColumnAsName.L == "password_expired":
+			value.PasswordExpired = row.GetEnum(i).String() == "Y"
+		case f.ColumnAsName.L == "password_last_changed":
+			value.PasswordLastChanged = decodeUnixTimestamp(row, i)
+		case f.ColumnAsName.L == "password_lifetime":
+			if !row.IsNull(i) {
+				value.PasswordLifetime = row.GetInt64(i)
+			}
+		case f.ColumnAsName.L == "failed_login_attempts":
+			value.FailedLoginAttempts = row.GetInt64(i)
+		case f.ColumnAsName.L == "password_lock_time":
+			value.PasswordLockTime = row.GetInt64(i)
+		case f.ColumnAsName.L == "password_locked_time":
+			value.PasswordLockedTime = decodeUnixTimestamp(row, i)
 		case f.ColumnAsName.L == "plugin":
 			if row.GetString(i) != "" {
 				value.AuthPlugin = row.GetString(i)
@@ -657,6 +704,14 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 	return nil
 }
 
+// decodeUnixTimestamp decodes the result of UNIX_TIMESTAMP, it returns the zero time for NULL.
+func decodeUnixTimestamp(row chunk.Row, i int) time.Time {
+	if row.IsNull(i) {
+		return time.Time{}
+	}
+	return time.Unix(row.GetInt64(i), 0)
+}
+
 func (p *MySQLPrivilege) decodeGlobalPrivTableRow(row chunk.Row, fs []*ast.ResultField) error {
 	var value globalPrivRecord
 	for i, f := range fs {
@@ -901,6 +956,44 @@ func (p *MySQLPrivilege) connectionVerification(user, host string) *UserRecord {
 	return nil
 }
 
+// trackFailedLogin returns whether the failed logins of the account are tracked.
+func (record *UserRecord) trackFailedLogin() bool {
+	return record.FailedLoginAttempts > 0 && record.PasswordLockTime != 0
+}
+
+// checkPasswordLock returns an error if the account is locked by the failed logins at the time now.
+func (record *UserRecord) checkPasswordLock(now time.Time) error {
+	if !record.trackFailedLogin() || record.PasswordLockedTime.IsZero() {
+		return nil
+	}
+	lockDays, remainingDays := "unlimited", "unlimited"
+	if record.PasswordLockTime > 0 {
+		unlockTime := record.PasswordLockedTime.Add(time.Duration(record.PasswordLockTime) * 24 * time.Hour)
+		if !now.Before(unlockTime) {
+			return nil
+		}
+		lockDays = strconv.FormatInt(record.PasswordLockTime, 10)
+		remainingDays = strconv.FormatInt(int64(math.Ceil(unlockTime.Sub(now).Hours()/24)), 10)
+	}
+	return errAccountBlocked.FastGenByArgs(record.User, record.Host, lockDays, remainingDays, record.FailedLoginAttempts)
+}
+
+// isPasswordExpired returns whether the password of the account has expired at the time now, defaultLifetime is
+// the value of default_password_lifetime.
+func (record *UserRecord) isPasswordExpired(now time.Time, defaultLifetime int64) bool {
+	if record.PasswordExpired {
+		return true
+	}
+	lifetime := record.PasswordLifetime
+	if lifetime < 0 {
+		lifetime = defaultLifetime
+	}
+	if lifetime <= 0 || record.PasswordLastChanged.IsZero() {
+		return false
+	}
+	return !now.Before(record.PasswordLastChanged.Add(time.Duration(lifetime) * 24 * time.Hour))
+}
+
 func (p *MySQLPrivilege) matchGlobalPriv(user, host string) *globalPrivRecord {
 	uGlobal, exists := p.Global[user]
 	if !exists {
@@ -928,6 +1021,21 @@ func (p *MySQLPrivilege) matchUser(user, host string) *UserRecord {
 	return nil
 }
 
+// accountExists returns whether the account of exactly the user and host exists, roles are also accounts.
+func (p *MySQLPrivilege) accountExists(user, host string) bool {
+	for _, record := range p.UserMap[user] {
+		if record.Host == host {
+			return true
+		}
+	}
+	return false
+}
+
+// roleGranted checks whether the role is granted to the account of the user record.
+func (p *MySQLPrivilege) roleGranted(record *UserRecord, role *auth.RoleIdentity) bool {
+	return p.RoleGraph[record.User+"@"+record.Host].Find(role.Username, role.Hostname)
+}
+
 func (p *MySQLPrivilege) matchDB(user, host, db string) *dbRecord {
 	records, exists := p.DBMap[user]
 	if exists {
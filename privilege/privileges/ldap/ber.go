@@ -0,0 +1,272 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/pingcap/errors"
+)
+
+// The classes of the BER identifier octet.
+const (
+	ClassUniversal   byte = 0x00
+	ClassApplication byte = 0x40
+	ClassContext     byte = 0x80
+)
+
+// The universal tags used by LDAP.
+const (
+	TagBoolean     = 0x01
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x10
+	TagSet         = 0x11
+)
+
+const (
+	constructedBit = 0x20
+	// maxPacketSize limits the size of a packet read from the network.
+	maxPacketSize = 16 << 20
+)
+
+// Packet is a BER encoded element, it's either primitive with Value, or constructed with Children.
+// Only the subset of BER used by LDAP is supported, the tags must be less than 31.
+type Packet struct {
+	Class       byte
+	Constructed bool
+	Tag         int
+	Value       []byte
+	Children    []*Packet
+}
+
+// NewSequence creates a universal SEQUENCE.
+func NewSequence(children ...*Packet) *Packet {
+	return &Packet{Class: ClassUniversal, Constructed: true, Tag: TagSequence, Children: children}
+}
+
+// NewSet creates a universal SET.
+func NewSet(children ...*Packet) *Packet {
+	return &Packet{Class: ClassUniversal, Constructed: true, Tag: TagSet, Children: children}
+}
+
+// NewInteger creates a universal INTEGER.
+func NewInteger(v int64) *Packet {
+	return &Packet{Class: ClassUniversal, Tag: TagInteger, Value: encodeInteger(v)}
+}
+
+// NewEnumerated creates a universal ENUMERATED.
+func NewEnumerated(v int64) *Packet {
+	return &Packet{Class: ClassUniversal, Tag: TagEnumerated, Value: encodeInteger(v)}
+}
+
+// NewBoolean creates a universal BOOLEAN.
+func NewBoolean(v bool) *Packet {
+	b := byte(0)
+	if v {
+		b = 0xff
+	}
+	return &Packet{Class: ClassUniversal, Tag: TagBoolean, Value: []byte{b}}
+}
+
+// NewOctetString creates a universal OCTET STRING.
+func NewOctetString(v []byte) *Packet {
+	return &Packet{Class: ClassUniversal, Tag: TagOctetString, Value: v}
+}
+
+// NewPrimitive creates a primitive element of the class and tag.
+func NewPrimitive(class byte, tag int, v []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: v}
+}
+
+// NewConstructed creates a constructed element of the class and tag.
+func NewConstructed(class byte, tag int, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// Is returns whether the element is of the class and tag.
+func (p *Packet) Is(class byte, tag int) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+// Int returns the value of an INTEGER or ENUMERATED.
+func (p *Packet) Int() int64 {
+	var v int64
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+// Bool returns the value of a BOOLEAN.
+func (p *Packet) Bool() bool {
+	return len(p.Value) > 0 && p.Value[0] != 0
+}
+
+// String returns the value of a primitive element as a string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Child returns the i-th child, or nil if there are not enough children.
+func (p *Packet) Child(i int) *Packet {
+	if i < len(p.Children) {
+		return p.Children[i]
+	}
+	return nil
+}
+
+// Encode encodes the element into BER.
+func (p *Packet) Encode() []byte {
+	content := p.Value
+	if p.Constructed {
+		content = nil
+		for _, child := range p.Children {
+			content = append(content, child.Encode()...)
+		}
+	}
+	identifier := p.Class | byte(p.Tag)
+	if p.Constructed {
+		identifier |= constructedBit
+	}
+	buf := make([]byte, 0, len(content)+6)
+	buf = append(buf, identifier)
+	buf = appendLength(buf, len(content))
+	return append(buf, content...)
+}
+
+func encodeInteger(v int64) []byte {
+	n := 1
+	for i := v; i > 127 || i < -128; i >>= 8 {
+		n++
+	}
+	buf := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		buf[i] = byte(v)
+		v >>= 8
+	}
+	return buf
+}
+
+func appendLength(buf []byte, length int) []byte {
+	if length < 0x80 {
+		return append(buf, byte(length))
+	}
+	n := 0
+	for l := length; l > 0; l >>= 8 {
+		n++
+	}
+	buf = append(buf, 0x80|byte(n))
+	for i := n - 1; i >= 0; i-- {
+		buf = append(buf, byte(length>>(8*i)))
+	}
+	return buf
+}
+
+// ReadPacket reads an element from the stream.
+func ReadPacket(r *bufio.Reader) (*Packet, error) {
+	identifier, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	header := []byte{identifier, first}
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errors.Errorf("ldap: unsupported BER length of %d bytes", n)
+		}
+		lengthBytes := make([]byte, n)
+		if _, err = io.ReadFull(r, lengthBytes); err != nil {
+			return nil, err
+		}
+		header = append(header, lengthBytes...)
+	}
+	_, length, err := decodeHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxPacketSize {
+		return nil, errors.Errorf("ldap: packet of %d bytes is too large", length)
+	}
+	content := make([]byte, length)
+	if _, err = io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	p, _, err := DecodePacket(append(header, content...))
+	return p, err
+}
+
+// decodeHeader decodes the identifier and length octets, it returns the size of the header and the content length.
+func decodeHeader(data []byte) (int, int, error) {
+	if len(data) < 2 {
+		return 0, 0, errors.New("ldap: truncated BER header")
+	}
+	if data[0]&0x1f == 0x1f {
+		return 0, 0, errors.New("ldap: unsupported BER high tag number")
+	}
+	first := data[1]
+	if first&0x80 == 0 {
+		return 2, int(first), nil
+	}
+	n := int(first & 0x7f)
+	if n == 0 || n > 4 || len(data) < 2+n {
+		return 0, 0, errors.New("ldap: invalid BER length")
+	}
+	length := 0
+	for _, b := range data[2 : 2+n] {
+		length = length<<8 | int(b)
+	}
+	return 2 + n, length, nil
+}
+
+// DecodePacket decodes an element from the data, it returns the element and the number of the bytes consumed.
+func DecodePacket(data []byte) (*Packet, int, error) {
+	headerSize, length, err := decodeHeader(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if length < 0 || len(data)-headerSize < length {
+		return nil, 0, errors.New("ldap: truncated BER content")
+	}
+	p := &Packet{
+		Class:       data[0] & 0xc0,
+		Constructed: data[0]&constructedBit != 0,
+		Tag:         int(data[0] & 0x1f),
+	}
+	content := data[headerSize : headerSize+length]
+	if !p.Constructed {
+		p.Value = content
+		return p, headerSize + length, nil
+	}
+	for len(content) > 0 {
+		child, n, err := DecodePacket(content)
+		if err != nil {
+			return nil, 0, err
+		}
+		p.Children = append(p.Children, child)
+		content = content[n:]
+	}
+	return p, headerSize + length, nil
+}
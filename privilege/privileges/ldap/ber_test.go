@@ -0,0 +1,94 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129, 1 << 40} {
+		p, n, err := DecodePacket(NewInteger(v).Encode())
+		require.NoError(t, err)
+		require.Equal(t, len(NewInteger(v).Encode()), n)
+		require.Equal(t, v, p.Int())
+	}
+
+	long := []byte(strings.Repeat("a", 300))
+	msg := NewSequence(
+		NewInteger(7),
+		NewConstructed(ClassApplication, ApplicationBindRequest,
+			NewInteger(3), NewOctetString(long), NewPrimitive(ClassContext, 0, []byte("pwd"))),
+		NewBoolean(true),
+	)
+	data := msg.Encode()
+	// The length of the sequence takes 2 bytes.
+	require.Equal(t, byte(0x82), data[1])
+
+	p, err := ReadPacket(bufio.NewReader(bytes.NewReader(data)))
+	require.NoError(t, err)
+	require.True(t, p.Is(ClassUniversal, TagSequence))
+	require.Len(t, p.Children, 3)
+	require.Equal(t, int64(7), p.Child(0).Int())
+	bind := p.Child(1)
+	require.True(t, bind.Is(ClassApplication, ApplicationBindRequest))
+	require.True(t, bind.Constructed)
+	require.Equal(t, string(long), bind.Child(1).String())
+	require.True(t, bind.Child(2).Is(ClassContext, 0))
+	require.Equal(t, "pwd", bind.Child(2).String())
+	require.True(t, p.Child(2).Bool())
+	require.Nil(t, p.Child(3))
+	require.Equal(t, data, p.Encode())
+
+	_, _, err = DecodePacket(data[:len(data)-1])
+	require.Error(t, err)
+	_, err = ReadPacket(bufio.NewReader(bytes.NewReader([]byte{0x30, 0x84, 0x7f, 0xff, 0xff, 0xff})))
+	require.Error(t, err)
+}
+
+func TestCompileFilter(t *testing.T) {
+	t.Parallel()
+
+	p, err := compileFilter("(|(&(objectClass=posixGroup)(memberUid=u\\2a))(!(member=*)))")
+	require.NoError(t, err)
+	require.True(t, p.Is(ClassContext, FilterOr))
+	require.Len(t, p.Children, 2)
+	and := p.Child(0)
+	require.True(t, and.Is(ClassContext, FilterAnd))
+	require.Equal(t, "objectClass", and.Child(0).Child(0).String())
+	require.Equal(t, "posixGroup", and.Child(0).Child(1).String())
+	require.Equal(t, "u*", and.Child(1).Child(1).String())
+	not := p.Child(1)
+	require.True(t, not.Is(ClassContext, FilterNot))
+	require.True(t, not.Child(0).Is(ClassContext, FilterPresent))
+	require.Equal(t, "member", not.Child(0).String())
+
+	for _, filter := range []string{"", "uid=a", "(uid=a", "(uid=a))", "(uid=a*)", "(uid>=a)", "(=a)", "(uid=\\2)", "(uid=\\zz)"} {
+		_, err = compileFilter(filter)
+		require.Error(t, err, filter)
+	}
+
+	require.Equal(t, "a\\2a\\28b\\29\\5c\\00", EscapeFilterValue("a*(b)\\\x00"))
+	p, err = compileFilter("(cn=" + EscapeFilterValue("a*(b)\\") + ")")
+	require.NoError(t, err)
+	require.Equal(t, "a*(b)\\", p.Child(1).String())
+}
@@ -0,0 +1,312 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// The application tags of the LDAP protocol operations, see RFC 4511.
+const (
+	ApplicationBindRequest           = 0
+	ApplicationBindResponse          = 1
+	ApplicationUnbindRequest         = 2
+	ApplicationSearchRequest         = 3
+	ApplicationSearchResultEntry     = 4
+	ApplicationSearchResultDone      = 5
+	ApplicationSearchResultReference = 19
+	ApplicationExtendedRequest       = 23
+	ApplicationExtendedResponse      = 24
+)
+
+// The result codes of the LDAP operations used by the authentication.
+const (
+	ResultSuccess            = 0
+	ResultSaslBindInProgress = 14
+	ResultNoSuchObject       = 32
+	ResultInvalidCredentials = 49
+	ResultUnwillingToPerform = 53
+)
+
+// The context tags in the bind requests and responses.
+const (
+	authSimple      = 0
+	authSASL        = 3
+	serverSaslCreds = 7
+)
+
+const (
+	ldapVersion   = 3
+	scopeSubtree  = 2
+	startTLSOID   = "1.3.6.1.4.1.1466.20037"
+	noAttributes  = "1.1"
+	dialTimeout   = 5 * time.Second
+	opTimeout     = 10 * time.Second
+	maxSearchSize = 1000
+)
+
+// Error is the failed result of an LDAP operation.
+type Error struct {
+	ResultCode int64
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("ldap: result code %d: %s", e.ResultCode, e.Message)
+}
+
+// Entry is an entry returned by a search, the attribute names are in lower case.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// conn is a connection to the LDAP server which supports the operations required by the authentication.
+type conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	msgID  int64
+}
+
+// dial connects to the LDAP server of the config, the connection is upgraded by StartTLS if TLS is enabled.
+func dial(cfg *Config) (*conn, error) {
+	if cfg.ServerHost == "" {
+		return nil, errors.New("ldap: the server host is not configured")
+	}
+	addr := net.JoinHostPort(cfg.ServerHost, strconv.Itoa(cfg.ServerPort))
+	nc, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := &conn{conn: nc, reader: bufio.NewReader(nc)}
+	if cfg.TLS {
+		if err = c.startTLS(cfg); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *conn) startTLS(cfg *Config) error {
+	op := NewConstructed(ClassApplication, ApplicationExtendedRequest,
+		NewPrimitive(ClassContext, 0, []byte(startTLSOID)))
+	resp, err := c.request(op, ApplicationExtendedResponse)
+	if err != nil {
+		return err
+	}
+	if err = resultError(resp); err != nil {
+		return err
+	}
+	tlsConfig := &tls.Config{ServerName: cfg.ServerHost, MinVersion: tls.VersionTLS12}
+	if cfg.CAPath != "" {
+		pem, err := os.ReadFile(cfg.CAPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.Errorf("ldap: no certificate is found in %s", cfg.CAPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	if err = tlsConn.Handshake(); err != nil {
+		return errors.Trace(err)
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+func (c *conn) close() {
+	c.msgID++
+	msg := NewSequence(NewInteger(c.msgID), NewPrimitive(ClassApplication, ApplicationUnbindRequest, nil))
+	_ = c.conn.SetDeadline(time.Now().Add(time.Second))
+	_, _ = c.conn.Write(msg.Encode())
+	_ = c.conn.Close()
+}
+
+// send sends the operation in a new message, and returns the message ID.
+func (c *conn) send(op *Packet) (int64, error) {
+	c.msgID++
+	msg := NewSequence(NewInteger(c.msgID), op)
+	if err := c.conn.SetDeadline(time.Now().Add(opTimeout)); err != nil {
+		return 0, errors.Trace(err)
+	}
+	_, err := c.conn.Write(msg.Encode())
+	return c.msgID, errors.Trace(err)
+}
+
+// receive reads the next operation of the message ID.
+func (c *conn) receive(msgID int64) (*Packet, error) {
+	for {
+		msg, err := ReadPacket(c.reader)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(msg.Children) < 2 || !msg.Children[0].Is(ClassUniversal, TagInteger) {
+			return nil, errors.New("ldap: malformed message")
+		}
+		// Skip the unsolicited notifications.
+		if msg.Children[0].Int() == msgID {
+			return msg.Children[1], nil
+		}
+	}
+}
+
+func (c *conn) request(op *Packet, responseTag int) (*Packet, error) {
+	msgID, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.receive(msgID)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Is(ClassApplication, responseTag) || len(resp.Children) < 3 {
+		return nil, errors.Errorf("ldap: unexpected response with tag %d", resp.Tag)
+	}
+	return resp, nil
+}
+
+// resultError returns the error of an LDAPResult, or nil if it succeeds.
+func resultError(resp *Packet) error {
+	code := resp.Child(0).Int()
+	if code == ResultSuccess {
+		return nil
+	}
+	return &Error{ResultCode: code, Message: resp.Child(2).String()}
+}
+
+// bind authenticates the connection by a simple bind.
+func (c *conn) bind(dn string, password []byte) error {
+	op := NewConstructed(ClassApplication, ApplicationBindRequest,
+		NewInteger(ldapVersion),
+		NewOctetString([]byte(dn)),
+		NewPrimitive(ClassContext, authSimple, password))
+	resp, err := c.request(op, ApplicationBindResponse)
+	if err != nil {
+		return err
+	}
+	return resultError(resp)
+}
+
+// saslBind sends a step of the SASL bind, it returns the result code and the credentials of the server.
+func (c *conn) saslBind(mechanism string, credentials []byte) (int64, []byte, error) {
+	sasl := NewConstructed(ClassContext, authSASL, NewOctetString([]byte(mechanism)))
+	if credentials != nil {
+		sasl.Children = append(sasl.Children, NewOctetString(credentials))
+	}
+	op := NewConstructed(ClassApplication, ApplicationBindRequest,
+		NewInteger(ldapVersion),
+		NewOctetString(nil),
+		sasl)
+	resp, err := c.request(op, ApplicationBindResponse)
+	if err != nil {
+		return 0, nil, err
+	}
+	var creds []byte
+	for _, child := range resp.Children[3:] {
+		if child.Is(ClassContext, serverSaslCreds) {
+			creds = child.Value
+		}
+	}
+	code := resp.Child(0).Int()
+	if code != ResultSuccess && code != ResultSaslBindInProgress {
+		return code, creds, resultError(resp)
+	}
+	return code, creds, nil
+}
+
+// search searches the subtree of the base DN with the filter, and returns the entries with the attributes.
+func (c *conn) search(baseDN, filter string, attributes []string) ([]*Entry, error) {
+	compiled, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	attrs := NewSequence()
+	if len(attributes) == 0 {
+		attrs.Children = append(attrs.Children, NewOctetString([]byte(noAttributes)))
+	}
+	for _, attr := range attributes {
+		attrs.Children = append(attrs.Children, NewOctetString([]byte(attr)))
+	}
+	op := NewConstructed(ClassApplication, ApplicationSearchRequest,
+		NewOctetString([]byte(baseDN)),
+		NewEnumerated(scopeSubtree),
+		NewEnumerated(0), // neverDerefAliases
+		NewInteger(maxSearchSize),
+		NewInteger(int64(opTimeout/time.Second)),
+		NewBoolean(false),
+		compiled,
+		attrs)
+	msgID, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for {
+		resp, err := c.receive(msgID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case resp.Is(ClassApplication, ApplicationSearchResultEntry):
+			entries = append(entries, decodeEntry(resp))
+		case resp.Is(ClassApplication, ApplicationSearchResultReference):
+			// The referrals are not followed.
+		case resp.Is(ClassApplication, ApplicationSearchResultDone):
+			if len(resp.Children) < 3 {
+				return nil, errors.New("ldap: malformed search result")
+			}
+			return entries, resultError(resp)
+		default:
+			return nil, errors.Errorf("ldap: unexpected search response with tag %d", resp.Tag)
+		}
+	}
+}
+
+func decodeEntry(p *Packet) *Entry {
+	entry := &Entry{Attributes: make(map[string][]string)}
+	if dn := p.Child(0); dn != nil {
+		entry.DN = dn.String()
+	}
+	if attrs := p.Child(1); attrs != nil {
+		for _, attr := range attrs.Children {
+			name := attr.Child(0)
+			values := attr.Child(1)
+			if name == nil || values == nil {
+				continue
+			}
+			key := strings.ToLower(name.String())
+			for _, v := range values.Children {
+				entry.Attributes[key] = append(entry.Attributes[key], v.String())
+			}
+		}
+	}
+	return entry
+}
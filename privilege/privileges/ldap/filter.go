@@ -0,0 +1,152 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// The context tags of the search filter choices, see RFC 4511 section 4.5.1.
+const (
+	FilterAnd           = 0
+	FilterOr            = 1
+	FilterNot           = 2
+	FilterEqualityMatch = 3
+	FilterPresent       = 7
+)
+
+// compileFilter compiles the string representation of a search filter defined in RFC 4515.
+// Only the AND, OR, NOT, equality and presence filters are supported.
+func compileFilter(filter string) (*Packet, error) {
+	p, n, err := parseFilter(filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(filter) {
+		return nil, errors.Errorf("ldap: unexpected trailing characters in filter %q", filter)
+	}
+	return p, nil
+}
+
+func parseFilter(filter string, pos int) (*Packet, int, error) {
+	if pos >= len(filter) || filter[pos] != '(' {
+		return nil, 0, errors.Errorf("ldap: filter %q must start with '(' at %d", filter, pos)
+	}
+	pos++
+	if pos >= len(filter) {
+		return nil, 0, errors.Errorf("ldap: unexpected end of filter %q", filter)
+	}
+	var p *Packet
+	switch filter[pos] {
+	case '&', '|':
+		tag := FilterAnd
+		if filter[pos] == '|' {
+			tag = FilterOr
+		}
+		p = NewConstructed(ClassContext, tag)
+		pos++
+		for pos < len(filter) && filter[pos] == '(' {
+			child, next, err := parseFilter(filter, pos)
+			if err != nil {
+				return nil, 0, err
+			}
+			p.Children = append(p.Children, child)
+			pos = next
+		}
+	case '!':
+		child, next, err := parseFilter(filter, pos+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		p = NewConstructed(ClassContext, FilterNot, child)
+		pos = next
+	default:
+		end := strings.IndexByte(filter[pos:], ')')
+		if end < 0 {
+			return nil, 0, errors.Errorf("ldap: unexpected end of filter %q", filter)
+		}
+		item := filter[pos : pos+end]
+		pos += end
+		var err error
+		if p, err = parseItem(item); err != nil {
+			return nil, 0, err
+		}
+	}
+	if pos >= len(filter) || filter[pos] != ')' {
+		return nil, 0, errors.Errorf("ldap: filter %q must end with ')' at %d", filter, pos)
+	}
+	return p, pos + 1, nil
+}
+
+func parseItem(item string) (*Packet, error) {
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, errors.Errorf("ldap: invalid filter item %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+	if strings.ContainsAny(attr, "~<>:") {
+		return nil, errors.Errorf("ldap: unsupported filter item %q", item)
+	}
+	if value == "*" {
+		return NewPrimitive(ClassContext, FilterPresent, []byte(attr)), nil
+	}
+	if strings.IndexByte(value, '*') >= 0 {
+		return nil, errors.Errorf("ldap: unsupported substring filter %q", item)
+	}
+	unescaped, err := unescapeFilterValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return NewConstructed(ClassContext, FilterEqualityMatch,
+		NewOctetString([]byte(attr)), NewOctetString(unescaped)), nil
+}
+
+func unescapeFilterValue(value string) ([]byte, error) {
+	buf := make([]byte, 0, len(value))
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			buf = append(buf, value[i])
+			continue
+		}
+		if i+2 >= len(value) {
+			return nil, errors.Errorf("ldap: invalid escape in filter value %q", value)
+		}
+		b, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return nil, errors.Errorf("ldap: invalid escape in filter value %q", value)
+		}
+		buf = append(buf, b[0])
+		i += 2
+	}
+	return buf, nil
+}
+
+// EscapeFilterValue escapes the special characters of a value substituted into a search filter.
+func EscapeFilterValue(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '*', '(', ')', '\\', 0:
+			sb.WriteByte('\\')
+			sb.WriteString(hex.EncodeToString([]byte{c}))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,252 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ldap implements the authentication_ldap_simple and authentication_ldap_sasl plugins,
+// which delegate the verification of the users to an LDAP server.
+package ldap
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/auth"
+)
+
+// The authentication plugins implemented by this package, and the client plugins they require.
+const (
+	AuthLDAPSimple = "authentication_ldap_simple"
+	AuthLDAPSASL   = "authentication_ldap_sasl"
+
+	ClientPluginClearPassword = "mysql_clear_password"
+	ClientPluginLDAPSASL      = "authentication_ldap_sasl_client"
+)
+
+// The placeholders in the group search filter, which are replaced by the user name and the user DN.
+const (
+	placeholderUserName = "{UA}"
+	placeholderUserDN   = "{UD}"
+)
+
+// maxSASLRounds limits the round trips of a SASL exchange.
+const maxSASLRounds = 10
+
+// IsAuthPlugin returns whether the plugin is implemented by this package.
+func IsAuthPlugin(plugin string) bool {
+	return plugin == AuthLDAPSimple || plugin == AuthLDAPSASL
+}
+
+// ClientPlugin returns the client plugin required by the authentication plugin.
+func ClientPlugin(plugin string) string {
+	if plugin == AuthLDAPSASL {
+		return ClientPluginLDAPSASL
+	}
+	return ClientPluginClearPassword
+}
+
+// Config is the configuration of a plugin, it's loaded from the system variables with the prefix of the plugin name.
+type Config struct {
+	ServerHost  string
+	ServerPort  int
+	TLS         bool
+	CAPath      string
+	BindBaseDN  string
+	BindRootDN  string
+	BindRootPwd string
+	// UserSearchAttr is the attribute of the user name, it's used to find the user DN when the
+	// authentication string of the account is empty.
+	UserSearchAttr string
+	// GroupSearchAttr is the attribute of the group names. The groups are not searched if it's empty.
+	GroupSearchAttr string
+	// GroupSearchFilter is the filter to search the groups of the user, {UA} and {UD} are replaced
+	// by the user name and the user DN.
+	GroupSearchFilter string
+	// GroupRoleMapping maps the groups to the roles in the form of "group=role[@host],...".
+	// The groups which are not mapped are granted the roles of the same names.
+	GroupRoleMapping string
+	// AuthMethodName is the SASL mechanism, it's only used by authentication_ldap_sasl.
+	AuthMethodName string
+}
+
+// Result is the result of a successful authentication.
+type Result struct {
+	// UserDN is the DN which the user is authenticated as.
+	UserDN string
+	// Groups are the names of the groups which the user belongs to.
+	Groups []string
+}
+
+// AuthenticateSimple verifies the cleartext password by binding as the user DN. The user DN is
+// the authentication string of the account, or searched by the user name if it's empty.
+func AuthenticateSimple(cfg *Config, userName, authString string, password []byte) (*Result, error) {
+	if len(password) == 0 {
+		// An empty password is an unauthenticated bind, which always succeeds, see RFC 4513 section 5.1.2.
+		return nil, errors.New("ldap: empty password is not allowed")
+	}
+	c, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	userDN := authString
+	if userDN == "" {
+		if userDN, err = c.searchUserDN(cfg, userName); err != nil {
+			return nil, err
+		}
+	}
+	if err = c.bind(userDN, password); err != nil {
+		return nil, err
+	}
+	groups, err := c.searchGroups(cfg, userName, userDN)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{UserDN: userDN, Groups: groups}, nil
+}
+
+// AuthenticateSASL relays the SASL exchange between the client and the LDAP server. The first
+// message of the client is initial, and exchange sends the challenge of the server to the client
+// and returns the response of the client.
+func AuthenticateSASL(cfg *Config, userName, authString string, initial []byte, exchange func([]byte) ([]byte, error)) (*Result, error) {
+	c, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	mechanism := cfg.AuthMethodName
+	if err = checkSCRAMUser(initial, userName); err != nil {
+		return nil, err
+	}
+	credentials := initial
+	for round := 0; ; round++ {
+		if round >= maxSASLRounds {
+			return nil, errors.Errorf("ldap: too many rounds of SASL %s exchange", mechanism)
+		}
+		code, serverCreds, err := c.saslBind(mechanism, credentials)
+		if err != nil {
+			return nil, err
+		}
+		if code == ResultSuccess {
+			break
+		}
+		if credentials, err = exchange(serverCreds); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	userDN := authString
+	if userDN == "" {
+		if userDN, err = c.searchUserDN(cfg, userName); err != nil {
+			return nil, err
+		}
+	}
+	groups, err := c.searchGroups(cfg, userName, userDN)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{UserDN: userDN, Groups: groups}, nil
+}
+
+// checkSCRAMUser checks the user name in the client-first-message of SCRAM is the user who logs in,
+// otherwise the client may log in with the credentials of another user in the directory.
+func checkSCRAMUser(clientFirst []byte, userName string) error {
+	msg := string(clientFirst)
+	// The GS2 header without the authorization identity, see RFC 5802 section 7.
+	if !strings.HasPrefix(msg, "n,,") && !strings.HasPrefix(msg, "y,,") {
+		return errors.New("ldap: unsupported SCRAM GS2 header")
+	}
+	for _, attr := range strings.Split(msg[3:], ",") {
+		if strings.HasPrefix(attr, "n=") {
+			name := strings.NewReplacer("=2C", ",", "=3D", "=").Replace(attr[2:])
+			if name != userName {
+				return errors.Errorf("ldap: SCRAM user %s doesn't match the login user %s", name, userName)
+			}
+			return nil
+		}
+	}
+	return errors.New("ldap: SCRAM user name is missing")
+}
+
+// bindRoot binds as the root DN if it's configured, otherwise the operations are anonymous.
+func (c *conn) bindRoot(cfg *Config) error {
+	if cfg.BindRootDN == "" {
+		return nil
+	}
+	return c.bind(cfg.BindRootDN, []byte(cfg.BindRootPwd))
+}
+
+func (c *conn) searchUserDN(cfg *Config, userName string) (string, error) {
+	if err := c.bindRoot(cfg); err != nil {
+		return "", err
+	}
+	filter := "(" + cfg.UserSearchAttr + "=" + EscapeFilterValue(userName) + ")"
+	entries, err := c.search(cfg.BindBaseDN, filter, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 {
+		return "", errors.Errorf("ldap: %d entries are found for user %s", len(entries), userName)
+	}
+	return entries[0].DN, nil
+}
+
+func (c *conn) searchGroups(cfg *Config, userName, userDN string) ([]string, error) {
+	if cfg.GroupSearchAttr == "" || cfg.GroupSearchFilter == "" {
+		return nil, nil
+	}
+	if err := c.bindRoot(cfg); err != nil {
+		return nil, err
+	}
+	filter := strings.ReplaceAll(cfg.GroupSearchFilter, placeholderUserName, EscapeFilterValue(userName))
+	filter = strings.ReplaceAll(filter, placeholderUserDN, EscapeFilterValue(userDN))
+	entries, err := c.search(cfg.BindBaseDN, filter, []string{cfg.GroupSearchAttr})
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	attr := strings.ToLower(cfg.GroupSearchAttr)
+	for _, entry := range entries {
+		groups = append(groups, entry.Attributes[attr]...)
+	}
+	return groups, nil
+}
+
+// MapGroupsToRoles maps the groups to the roles by GroupRoleMapping. The group names are case-insensitive,
+// and the groups which are not in the mapping are ignored.
+func (cfg *Config) MapGroupsToRoles(groups []string) []*auth.RoleIdentity {
+	mapping := make(map[string][]*auth.RoleIdentity)
+	for _, item := range strings.Split(cfg.GroupRoleMapping, ",") {
+		eq := strings.IndexByte(item, '=')
+		if eq < 0 {
+			continue
+		}
+		group := strings.ToLower(strings.TrimSpace(item[:eq]))
+		mapping[group] = append(mapping[group], parseRole(strings.TrimSpace(item[eq+1:])))
+	}
+	roles := make([]*auth.RoleIdentity, 0, len(groups))
+	for _, group := range groups {
+		if mapped, ok := mapping[strings.ToLower(group)]; ok {
+			roles = append(roles, mapped...)
+		}
+	}
+	return roles
+}
+
+func parseRole(s string) *auth.RoleIdentity {
+	if at := strings.LastIndexByte(s, '@'); at >= 0 {
+		return &auth.RoleIdentity{Username: s[:at], Hostname: s[at+1:]}
+	}
+	return &auth.RoleIdentity{Username: s, Hostname: "%"}
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
+	"github.com/pingcap/tidb/privilege/privileges/ldap/ldaptest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	baseDN  = "dc=example,dc=com"
+	aliceDN = "uid=alice,ou=people,dc=example,dc=com"
+	rootDN  = "cn=admin,dc=example,dc=com"
+)
+
+func newServer(t *testing.T) *ldaptest.Server {
+	s, err := ldaptest.NewServer(
+		&ldaptest.Entry{DN: rootDN, Attributes: map[string][]string{"userPassword": {"rootpwd"}}},
+		&ldaptest.Entry{DN: aliceDN, Attributes: map[string][]string{"uid": {"alice"}, "userPassword": {"secret"}}},
+		&ldaptest.Entry{DN: "cn=dba,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"posixGroup"}, "cn": {"dba"}, "memberUid": {"alice"}}},
+		&ldaptest.Entry{DN: "cn=dev,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"group"}, "cn": {"dev"}, "member": {aliceDN}}},
+		&ldaptest.Entry{DN: "cn=ops,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"group"}, "cn": {"ops"}, "member": {"uid=bob,ou=people,dc=example,dc=com"}}},
+	)
+	require.NoError(t, err)
+	return s
+}
+
+func newConfig(s *ldaptest.Server) *ldap.Config {
+	return &ldap.Config{
+		ServerHost:        s.Host(),
+		ServerPort:        s.Port(),
+		BindBaseDN:        baseDN,
+		UserSearchAttr:    "uid",
+		GroupSearchAttr:   "cn",
+		GroupSearchFilter: "(|(&(objectClass=posixGroup)(memberUid={UA}))(&(objectClass=group)(member={UD})))",
+		AuthMethodName:    "SCRAM-SHA-256",
+	}
+}
+
+func TestAuthenticateSimple(t *testing.T) {
+	t.Parallel()
+	s := newServer(t)
+	defer s.Close()
+	cfg := newConfig(s)
+
+	// The user DN is the authentication string.
+	result, err := ldap.AuthenticateSimple(cfg, "alice", aliceDN, []byte("secret"))
+	require.NoError(t, err)
+	require.Equal(t, aliceDN, result.UserDN)
+	require.ElementsMatch(t, []string{"dba", "dev"}, result.Groups)
+
+	_, err = ldap.AuthenticateSimple(cfg, "alice", aliceDN, []byte("wrong"))
+	require.Error(t, err)
+	require.Equal(t, int64(ldap.ResultInvalidCredentials), err.(*ldap.Error).ResultCode)
+	_, err = ldap.AuthenticateSimple(cfg, "alice", aliceDN, nil)
+	require.Error(t, err)
+
+	// The user DN is searched by the user name, with the root DN or anonymously.
+	result, err = ldap.AuthenticateSimple(cfg, "alice", "", []byte("secret"))
+	require.NoError(t, err)
+	require.Equal(t, aliceDN, result.UserDN)
+	cfg.BindRootDN, cfg.BindRootPwd = rootDN, "rootpwd"
+	result, err = ldap.AuthenticateSimple(cfg, "alice", "", []byte("secret"))
+	require.NoError(t, err)
+	require.Equal(t, aliceDN, result.UserDN)
+	require.ElementsMatch(t, []string{"dba", "dev"}, result.Groups)
+	_, err = ldap.AuthenticateSimple(cfg, "bob", "", []byte("secret"))
+	require.Error(t, err)
+	// The user name is escaped in the search filter.
+	_, err = ldap.AuthenticateSimple(cfg, "*", "", []byte("secret"))
+	require.Error(t, err)
+	cfg.BindRootPwd = "wrong"
+	_, err = ldap.AuthenticateSimple(cfg, "alice", "", []byte("secret"))
+	require.Error(t, err)
+	cfg.BindRootDN = ""
+
+	// The groups are not searched without the group search attribute.
+	cfg.GroupSearchAttr = ""
+	result, err = ldap.AuthenticateSimple(cfg, "alice", aliceDN, []byte("secret"))
+	require.NoError(t, err)
+	require.Empty(t, result.Groups)
+
+	// The server is unavailable.
+	cfg.ServerHost = ""
+	_, err = ldap.AuthenticateSimple(cfg, "alice", aliceDN, []byte("secret"))
+	require.Error(t, err)
+}
+
+func TestAuthenticateSASL(t *testing.T) {
+	t.Parallel()
+	s := newServer(t)
+	defer s.Close()
+	cfg := newConfig(s)
+
+	for _, mechanism := range []string{"SCRAM-SHA-1", "SCRAM-SHA-256"} {
+		cfg.AuthMethodName = mechanism
+		client, err := ldaptest.NewSCRAMClient(mechanism, "alice", "secret", "client-nonce")
+		require.NoError(t, err)
+		rounds := 0
+		result, err := ldap.AuthenticateSASL(cfg, "alice", aliceDN, client.First(), func(challenge []byte) ([]byte, error) {
+			rounds++
+			return client.Final(challenge)
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, rounds)
+		require.Equal(t, aliceDN, result.UserDN)
+		require.ElementsMatch(t, []string{"dba", "dev"}, result.Groups)
+
+		client, err = ldaptest.NewSCRAMClient(mechanism, "alice", "wrong", "client-nonce")
+		require.NoError(t, err)
+		_, err = ldap.AuthenticateSASL(cfg, "alice", aliceDN, client.First(), client.Final)
+		require.Error(t, err)
+	}
+
+	// The SCRAM user must be the login user.
+	client, err := ldaptest.NewSCRAMClient("SCRAM-SHA-256", "alice", "secret", "client-nonce")
+	require.NoError(t, err)
+	_, err = ldap.AuthenticateSASL(cfg, "bob", "", client.First(), client.Final)
+	require.Error(t, err)
+	_, err = ldap.AuthenticateSASL(cfg, "alice", aliceDN, []byte("n,a=bob,n=alice,r=x"), client.Final)
+	require.Error(t, err)
+
+	// A malformed exchange fails.
+	_, err = ldap.AuthenticateSASL(cfg, "alice", aliceDN, []byte("n,,n=alice,r=x"), func(challenge []byte) ([]byte, error) {
+		return []byte("n,,n=alice,r=x"), nil
+	})
+	require.Error(t, err)
+}
+
+func TestMapGroupsToRoles(t *testing.T) {
+	t.Parallel()
+	cfg := &ldap.Config{GroupRoleMapping: "DBA=admin@localhost, dba=auditor ,dev=developer,invalid"}
+	roles := cfg.MapGroupsToRoles([]string{"dba", "dev", "ops"})
+	require.Equal(t, []*auth.RoleIdentity{
+		{Username: "admin", Hostname: "localhost"},
+		{Username: "auditor", Hostname: "%"},
+		{Username: "developer", Hostname: "%"},
+	}, roles)
+	require.Empty(t, cfg.MapGroupsToRoles(nil))
+}
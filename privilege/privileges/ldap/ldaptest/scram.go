@@ -0,0 +1,195 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldaptest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const scramIterations = 4096
+
+func scramHash(mechanism string) (func() hash.Hash, error) {
+	switch mechanism {
+	case "SCRAM-SHA-1":
+		return sha1.New, nil
+	case "SCRAM-SHA-256":
+		return sha256.New, nil
+	}
+	return nil, errors.Errorf("unsupported SASL mechanism %s", mechanism)
+}
+
+func hmacSum(h func() hash.Hash, key, data []byte) []byte {
+	mac := hmac.New(h, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// pbkdf2 derives the key of one block, which is enough for SCRAM.
+func pbkdf2(h func() hash.Hash, password, salt []byte, iterations int) []byte {
+	block := make([]byte, 4)
+	binary.BigEndian.PutUint32(block, 1)
+	u := hmacSum(h, password, append(append([]byte{}, salt...), block...))
+	result := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		u = hmacSum(h, password, u)
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
+
+func xorBytes(a, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+	return result
+}
+
+// scramKeys computes the client key, stored key and server key of the password.
+func scramKeys(h func() hash.Hash, password string, salt []byte) (clientKey, storedKey, serverKey []byte) {
+	salted := pbkdf2(h, []byte(password), salt, scramIterations)
+	clientKey = hmacSum(h, salted, []byte("Client Key"))
+	sum := h()
+	sum.Write(clientKey)
+	storedKey = sum.Sum(nil)
+	serverKey = hmacSum(h, salted, []byte("Server Key"))
+	return
+}
+
+// parseSCRAMAttributes parses the "k=v,..." attributes of a SCRAM message.
+func parseSCRAMAttributes(msg string) map[string]string {
+	attrs := make(map[string]string)
+	for _, item := range strings.Split(msg, ",") {
+		if len(item) >= 2 && item[1] == '=' {
+			attrs[item[:1]] = item[2:]
+		}
+	}
+	return attrs
+}
+
+// SCRAMClient is the client side of the SCRAM exchange defined in RFC 5802, without channel binding.
+type SCRAMClient struct {
+	hash        func() hash.Hash
+	user        string
+	password    string
+	nonce       string
+	clientFirst string
+	serverSig   []byte
+}
+
+// NewSCRAMClient creates a SCRAM client of the mechanism.
+func NewSCRAMClient(mechanism, user, password, nonce string) (*SCRAMClient, error) {
+	h, err := scramHash(mechanism)
+	if err != nil {
+		return nil, err
+	}
+	return &SCRAMClient{hash: h, user: user, password: password, nonce: nonce}, nil
+}
+
+// First returns the client-first-message.
+func (c *SCRAMClient) First() []byte {
+	c.clientFirst = "n=" + c.user + ",r=" + c.nonce
+	return []byte("n,," + c.clientFirst)
+}
+
+// Final returns the client-final-message for the server-first-message.
+func (c *SCRAMClient) Final(serverFirst []byte) ([]byte, error) {
+	attrs := parseSCRAMAttributes(string(serverFirst))
+	nonce := attrs["r"]
+	if !strings.HasPrefix(nonce, c.nonce) {
+		return nil, errors.New("the server nonce doesn't start with the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	clientKey, storedKey, serverKey := scramKeys(c.hash, c.password, salt)
+	withoutProof := "c=biws,r=" + nonce
+	authMessage := c.clientFirst + "," + string(serverFirst) + "," + withoutProof
+	proof := xorBytes(clientKey, hmacSum(c.hash, storedKey, []byte(authMessage)))
+	c.serverSig = hmacSum(c.hash, serverKey, []byte(authMessage))
+	return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof)), nil
+}
+
+// Verify verifies the server-final-message.
+func (c *SCRAMClient) Verify(serverFinal []byte) error {
+	v := parseSCRAMAttributes(string(serverFinal))["v"]
+	if v != base64.StdEncoding.EncodeToString(c.serverSig) {
+		return errors.New("the server signature doesn't match")
+	}
+	return nil
+}
+
+// scramServer is the server side of a SCRAM exchange.
+type scramServer struct {
+	hash        func() hash.Hash
+	lookup      func(user string) (dn, password string, ok bool)
+	salt        []byte
+	nonce       string
+	clientFirst string
+	serverFirst string
+	dn          string
+	password    string
+}
+
+// step handles a message of the client, it returns the challenge of the server and whether the exchange is done.
+func (s *scramServer) step(msg []byte) ([]byte, bool, error) {
+	if s.serverFirst == "" {
+		m := string(msg)
+		if !strings.HasPrefix(m, "n,,") {
+			return nil, false, errors.New("channel binding is not supported")
+		}
+		s.clientFirst = m[3:]
+		attrs := parseSCRAMAttributes(s.clientFirst)
+		dn, password, ok := s.lookup(attrs["n"])
+		if !ok {
+			return nil, false, errors.Errorf("unknown user %s", attrs["n"])
+		}
+		s.dn, s.password = dn, password
+		s.serverFirst = fmt.Sprintf("r=%s%s,s=%s,i=%d", attrs["r"], s.nonce,
+			base64.StdEncoding.EncodeToString(s.salt), scramIterations)
+		return []byte(s.serverFirst), false, nil
+	}
+	m := string(msg)
+	idx := strings.LastIndex(m, ",p=")
+	if idx < 0 {
+		return nil, false, errors.New("the client proof is missing")
+	}
+	withoutProof := m[:idx]
+	proof, err := base64.StdEncoding.DecodeString(m[idx+3:])
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	clientKey, storedKey, serverKey := scramKeys(s.hash, s.password, s.salt)
+	authMessage := s.clientFirst + "," + s.serverFirst + "," + withoutProof
+	expected := xorBytes(clientKey, hmacSum(s.hash, storedKey, []byte(authMessage)))
+	if !bytes.Equal(proof, expected) {
+		return nil, false, errors.New("invalid proof")
+	}
+	serverSig := hmacSum(s.hash, serverKey, []byte(authMessage))
+	return []byte("v=" + base64.StdEncoding.EncodeToString(serverSig)), true, nil
+}
@@ -0,0 +1,349 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ldaptest provides an in-process LDAP server for the tests of the LDAP authentication.
+package ldaptest
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
+)
+
+const (
+	resultProtocolError = 2
+	resultOperationsErr = 1
+)
+
+// Entry is an entry of the directory, the attribute names are case-insensitive.
+// The password of the entry for binding is the userPassword attribute.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+func (e *Entry) values(attr string) []string {
+	for name, values := range e.Attributes {
+		if strings.EqualFold(name, attr) {
+			return values
+		}
+	}
+	return nil
+}
+
+func (e *Entry) password() string {
+	if values := e.values("userPassword"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Server is an LDAP server supporting the simple bind, the SCRAM SASL bind and the search, which are
+// used by the authentication. The directory is shared by all the connections.
+type Server struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu struct {
+		sync.Mutex
+		entries []*Entry
+		binds   []string
+		conns   map[net.Conn]struct{}
+	}
+}
+
+// NewServer starts a server listening on a random port of the loopback interface.
+func NewServer(entries ...*Entry) (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{listener: l}
+	s.mu.entries = entries
+	s.mu.conns = make(map[net.Conn]struct{})
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Host returns the host of the server.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.listener.Addr().String())
+	return host
+}
+
+// Port returns the port of the server.
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// AddEntry adds an entry to the directory.
+func (s *Server) AddEntry(entry *Entry) {
+	s.mu.Lock()
+	s.mu.entries = append(s.mu.entries, entry)
+	s.mu.Unlock()
+}
+
+// Binds returns the DNs of the successful binds, the SASL binds are recorded as the DNs of the users.
+func (s *Server) Binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.mu.binds...)
+}
+
+// Close stops the server and closes all the connections.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	for c := range s.mu.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.mu.conns[c] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(c)
+			s.mu.Lock()
+			delete(s.mu.conns, c)
+			s.mu.Unlock()
+			_ = c.Close()
+		}()
+	}
+}
+
+func (s *Server) handleConn(c net.Conn) {
+	r := bufio.NewReader(c)
+	var sasl *scramServer
+	for {
+		msg, err := ldap.ReadPacket(r)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		msgID := msg.Children[0].Int()
+		op := msg.Children[1]
+		var responses []*ldap.Packet
+		switch {
+		case op.Is(ldap.ClassApplication, ldap.ApplicationBindRequest):
+			var resp *ldap.Packet
+			resp, sasl = s.handleBind(op, sasl)
+			responses = append(responses, resp)
+		case op.Is(ldap.ClassApplication, ldap.ApplicationSearchRequest):
+			responses = s.handleSearch(op)
+		case op.Is(ldap.ClassApplication, ldap.ApplicationUnbindRequest):
+			return
+		case op.Is(ldap.ClassApplication, ldap.ApplicationExtendedRequest):
+			responses = append(responses, result(ldap.ApplicationExtendedResponse, resultProtocolError, "unsupported extended operation"))
+		default:
+			return
+		}
+		for _, resp := range responses {
+			if _, err = c.Write(ldap.NewSequence(ldap.NewInteger(msgID), resp).Encode()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func result(tag int, code int64, message string, extra ...*ldap.Packet) *ldap.Packet {
+	p := ldap.NewConstructed(ldap.ClassApplication, tag,
+		ldap.NewEnumerated(code), ldap.NewOctetString(nil), ldap.NewOctetString([]byte(message)))
+	p.Children = append(p.Children, extra...)
+	return p
+}
+
+func (s *Server) findEntry(dn string) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.mu.entries {
+		if strings.EqualFold(e.DN, dn) {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *Server) recordBind(dn string) {
+	s.mu.Lock()
+	s.mu.binds = append(s.mu.binds, dn)
+	s.mu.Unlock()
+}
+
+func (s *Server) handleBind(op *ldap.Packet, sasl *scramServer) (*ldap.Packet, *scramServer) {
+	if len(op.Children) < 3 {
+		return result(ldap.ApplicationBindResponse, resultProtocolError, "malformed bind request"), nil
+	}
+	dn := op.Children[1].String()
+	authentication := op.Children[2]
+	if authentication.Is(ldap.ClassContext, 0) {
+		password := authentication.String()
+		if dn == "" && password == "" {
+			return result(ldap.ApplicationBindResponse, ldap.ResultSuccess, ""), nil
+		}
+		entry := s.findEntry(dn)
+		if password == "" {
+			return result(ldap.ApplicationBindResponse, ldap.ResultUnwillingToPerform, "unauthenticated bind is not allowed"), nil
+		}
+		if entry == nil || entry.password() != password {
+			return result(ldap.ApplicationBindResponse, ldap.ResultInvalidCredentials, "invalid credentials"), nil
+		}
+		s.recordBind(entry.DN)
+		return result(ldap.ApplicationBindResponse, ldap.ResultSuccess, ""), nil
+	}
+
+	// SASL bind.
+	mechanism := authentication.Child(0).String()
+	var credentials []byte
+	if creds := authentication.Child(1); creds != nil {
+		credentials = creds.Value
+	}
+	if sasl == nil {
+		h, err := scramHash(mechanism)
+		if err != nil {
+			return result(ldap.ApplicationBindResponse, ldap.ResultUnwillingToPerform, err.Error()), nil
+		}
+		sasl = &scramServer{hash: h, lookup: s.lookupUser, salt: []byte("ldaptest-salt"), nonce: "ldaptest-nonce"}
+	}
+	challenge, done, err := sasl.step(credentials)
+	if err != nil {
+		return result(ldap.ApplicationBindResponse, ldap.ResultInvalidCredentials, err.Error()), nil
+	}
+	// The serverSaslCreds of the bind response.
+	creds := ldap.NewPrimitive(ldap.ClassContext, 7, challenge)
+	if !done {
+		return result(ldap.ApplicationBindResponse, ldap.ResultSaslBindInProgress, "", creds), sasl
+	}
+	s.recordBind(sasl.dn)
+	return result(ldap.ApplicationBindResponse, ldap.ResultSuccess, "", creds), nil
+}
+
+// lookupUser finds the entry of the SASL user by the uid attribute.
+func (s *Server) lookupUser(user string) (string, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.mu.entries {
+		for _, uid := range e.values("uid") {
+			if uid == user {
+				return e.DN, e.password(), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func (s *Server) handleSearch(op *ldap.Packet) []*ldap.Packet {
+	if len(op.Children) < 8 {
+		return []*ldap.Packet{result(ldap.ApplicationSearchResultDone, resultProtocolError, "malformed search request")}
+	}
+	baseDN := strings.ToLower(op.Children[0].String())
+	filter := op.Children[6]
+	var attrs []string
+	for _, attr := range op.Children[7].Children {
+		attrs = append(attrs, attr.String())
+	}
+
+	s.mu.Lock()
+	entries := append([]*Entry{}, s.mu.entries...)
+	s.mu.Unlock()
+
+	var responses []*ldap.Packet
+	for _, e := range entries {
+		dn := strings.ToLower(e.DN)
+		if baseDN != "" && dn != baseDN && !strings.HasSuffix(dn, ","+baseDN) {
+			continue
+		}
+		matched, ok := matchFilter(e, filter)
+		if !ok {
+			return []*ldap.Packet{result(ldap.ApplicationSearchResultDone, resultOperationsErr, "unsupported filter")}
+		}
+		if !matched {
+			continue
+		}
+		attributes := ldap.NewSequence()
+		for _, attr := range attrs {
+			values := e.values(attr)
+			if len(values) == 0 {
+				continue
+			}
+			set := ldap.NewSet()
+			for _, v := range values {
+				set.Children = append(set.Children, ldap.NewOctetString([]byte(v)))
+			}
+			attributes.Children = append(attributes.Children, ldap.NewSequence(ldap.NewOctetString([]byte(attr)), set))
+		}
+		responses = append(responses, ldap.NewConstructed(ldap.ClassApplication, ldap.ApplicationSearchResultEntry,
+			ldap.NewOctetString([]byte(e.DN)), attributes))
+	}
+	return append(responses, result(ldap.ApplicationSearchResultDone, ldap.ResultSuccess, ""))
+}
+
+// matchFilter evaluates the filter on the entry, the values are compared case-insensitively.
+// It returns false as the second value if the filter is not supported.
+func matchFilter(e *Entry, filter *ldap.Packet) (bool, bool) {
+	if filter.Class != ldap.ClassContext {
+		return false, false
+	}
+	switch filter.Tag {
+	case ldap.FilterAnd, ldap.FilterOr:
+		isAnd := filter.Tag == ldap.FilterAnd
+		for _, child := range filter.Children {
+			matched, ok := matchFilter(e, child)
+			if !ok {
+				return false, false
+			}
+			if matched != isAnd {
+				return !isAnd, true
+			}
+		}
+		return isAnd, true
+	case ldap.FilterNot:
+		if len(filter.Children) != 1 {
+			return false, false
+		}
+		matched, ok := matchFilter(e, filter.Children[0])
+		return !matched, ok
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false, false
+		}
+		value := filter.Children[1].String()
+		for _, v := range e.values(filter.Children[0].String()) {
+			if strings.EqualFold(v, value) {
+				return true, true
+			}
+		}
+		return false, true
+	case ldap.FilterPresent:
+		return len(e.values(filter.String())) > 0, true
+	}
+	return false, false
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// loadLDAPConfig loads the configuration of the LDAP plugin from the global system variables,
+// which are named with the plugin name as the prefix, for example, authentication_ldap_simple_server_host.
+func loadLDAPConfig(plugin string, sessionVars *variable.SessionVars) (*ldap.Config, error) {
+	if sessionVars == nil || sessionVars.GlobalVarsAccessor == nil {
+		return nil, errors.New("the global variables are not available")
+	}
+	get := func(name string) (string, error) {
+		return sessionVars.GlobalVarsAccessor.GetGlobalSysVar(plugin + name)
+	}
+	cfg := &ldap.Config{}
+	for name, dst := range map[string]*string{
+		"_server_host":         &cfg.ServerHost,
+		"_ca_path":             &cfg.CAPath,
+		"_bind_base_dn":        &cfg.BindBaseDN,
+		"_bind_root_dn":        &cfg.BindRootDN,
+		"_bind_root_pwd":       &cfg.BindRootPwd,
+		"_user_search_attr":    &cfg.UserSearchAttr,
+		"_group_search_attr":   &cfg.GroupSearchAttr,
+		"_group_search_filter": &cfg.GroupSearchFilter,
+		"_group_role_mapping":  &cfg.GroupRoleMapping,
+	} {
+		val, err := get(name)
+		if err != nil {
+			return nil, err
+		}
+		*dst = val
+	}
+	port, err := get("_server_port")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ServerPort, err = strconv.Atoi(port); err != nil {
+		return nil, errors.Trace(err)
+	}
+	useTLS, err := get("_tls")
+	if err != nil {
+		return nil, err
+	}
+	cfg.TLS = variable.TiDBOptOn(useTLS)
+	if plugin == ldap.AuthLDAPSASL {
+		if cfg.AuthMethodName, err = get("_auth_method_name"); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// ldapAuthenticate verifies the account by the LDAP server, and returns the roles mapped from the groups of
+// the user and granted to the account. The authentication string of the account is the user DN, or empty to search the DN by the user name.
+// For authentication_ldap_simple, the authentication data is the cleartext password. For authentication_ldap_sasl,
+// it's the first message of the client, and the following messages are exchanged through authConn.
+func (p *UserPrivileges) ldapAuthenticate(mysqlPriv *MySQLPrivilege, record *UserRecord, user string, authentication []byte,
+	authConn privilege.AuthConn, sessionVars *variable.SessionVars) ([]*auth.RoleIdentity, error) {
+	cfg, err := loadLDAPConfig(record.AuthPlugin, sessionVars)
+	if err != nil {
+		return nil, err
+	}
+	var result *ldap.Result
+	switch record.AuthPlugin {
+	case ldap.AuthLDAPSimple:
+		result, err = ldap.AuthenticateSimple(cfg, user, record.AuthenticationString, authentication)
+	case ldap.AuthLDAPSASL:
+		if authConn == nil {
+			return nil, errors.New("SASL authentication requires the client connection")
+		}
+		result, err = ldap.AuthenticateSASL(cfg, user, record.AuthenticationString, authentication,
+			func(challenge []byte) ([]byte, error) {
+				if err := authConn.WriteAuthMoreData(challenge); err != nil {
+					return nil, err
+				}
+				return authConn.ReadPacket()
+			})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Like SET ROLE, only the roles granted to the account can be activated, so that the directory can't
+	// grant any privilege which is not granted in TiDB.
+	var roles []*auth.RoleIdentity
+	for _, role := range cfg.MapGroupsToRoles(result.Groups) {
+		if mysqlPriv.accountExists(role.Username, role.Hostname) && mysqlPriv.roleGranted(record, role) {
+			roles = append(roles, role)
+			continue
+		}
+		logutil.BgLogger().Debug("skip the role mapped from the LDAP group which isn't granted to the user",
+			zap.String("user", user), zap.Stringer("role", role))
+	}
+	return roles, nil
+}
@@ -19,15 +19,19 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/infoschema/perfschema"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
@@ -185,6 +189,10 @@ func (p *UserPrivileges) isValidHash(record *UserRecord) bool {
 	if pwd == "" {
 		return true
 	}
+	// The authentication string of the LDAP plugins is the user DN.
+	if ldap.IsAuthPlugin(record.AuthPlugin) {
+		return true
+	}
 	if record.AuthPlugin == mysql.AuthNativePassword {
 		if len(pwd) == mysql.PWDHashLen+1 {
 			return true
@@ -227,7 +235,8 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	if record == nil {
 		return "", errors.New("Failed to get user record")
 	}
-	if len(record.AuthenticationString) == 0 {
+	// The LDAP plugins are used even if the authentication string is empty, the user DN is searched then.
+	if len(record.AuthenticationString) == 0 && !ldap.IsAuthPlugin(record.AuthPlugin) {
 		return "", nil
 	}
 	if p.isValidHash(record) {
@@ -263,10 +272,26 @@ func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string
 
 // ConnectionVerification implements the Manager interface.
 func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState) (u string, h string, success bool) {
+	info, err := p.verifyConnection(user, host, authentication, salt, tlsState, nil, nil)
+	return info.AuthUser, info.AuthHost, err == nil
+}
+
+// ConnectionVerificationWithError implements the Manager interface.
+func (p *UserPrivileges) ConnectionVerificationWithError(user, host string, authentication, salt []byte, authConn privilege.AuthConn, sessionVars *variable.SessionVars) (info privilege.VerificationInfo, err error) {
+	return p.verifyConnection(user, host, authentication, salt, sessionVars.TLSConnectionState, authConn, sessionVars)
+}
+
+// verifyConnection verifies the account for connection, sessionVars may be nil if the global variables are not
+// available, then the default_password_lifetime is ignored and the LDAP accounts fail to login.
+func (p *UserPrivileges) verifyConnection(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState,
+	authConn privilege.AuthConn, sessionVars *variable.SessionVars) (info privilege.VerificationInfo, err error) {
+	hasPassword := "YES"
+	if len(authentication) == 0 {
+		hasPassword = "NO"
+	}
 	if SkipWithGrant {
 		p.user = user
 		p.host = host
-		success = true
 		return
 	}
 
@@ -275,19 +300,18 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	if record == nil {
 		logutil.BgLogger().Error("get user privilege record fail",
 			zap.String("user", user), zap.String("host", host))
-		return
+		return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
 	}
 
-	u = record.User
-	h = record.Host
+	info.AuthUser = record.User
+	info.AuthHost = record.Host
 
 	globalPriv := mysqlPriv.matchGlobalPriv(user, host)
 	if globalPriv != nil {
 		if !p.checkSSL(globalPriv, tlsState) {
 			logutil.BgLogger().Error("global priv check ssl fail",
 				zap.String("user", user), zap.String("host", host))
-			success = false
-			return
+			return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
 		}
 	}
 
@@ -296,55 +320,85 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	if locked {
 		logutil.BgLogger().Error("try to login a locked account",
 			zap.String("user", user), zap.String("host", host))
-		success = false
-		return
+		return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
+	}
+
+	// Login an account locked by the failed logins is not allowed until the lock expires.
+	if err = record.checkPasswordLock(time.Now()); err != nil {
+		logutil.BgLogger().Error("try to login an account locked by the failed logins",
+			zap.String("user", user), zap.String("host", host))
+		return info, err
+	}
+
+	if ldap.IsAuthPlugin(record.AuthPlugin) {
+		info.AuthConnUsed = record.AuthPlugin == ldap.AuthLDAPSASL && authConn != nil
+		if info.Roles, err = p.ldapAuthenticate(mysqlPriv, record, user, authentication, authConn, sessionVars); err != nil {
+			logutil.BgLogger().Warn("LDAP authentication failed", zap.String("user", user),
+				zap.String("host", host), zap.String("plugin", record.AuthPlugin), zap.Error(err))
+			info.TrackFailedLogin = record.trackFailedLogin()
+			return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
+		}
+	} else if !p.checkPassword(record, authentication, salt) {
+		info.TrackFailedLogin = record.trackFailedLogin()
+		return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
 	}
 
+	info.TrackFailedLogin = record.trackFailedLogin()
+	info.PasswordExpired = record.isPasswordExpired(time.Now(), defaultPasswordLifetime(sessionVars))
+	p.user = user
+	p.host = record.Host
+	return
+}
+
+// checkPassword checks whether the authentication data matches the password of the account.
+func (p *UserPrivileges) checkPassword(record *UserRecord, authentication, salt []byte) bool {
 	pwd := record.AuthenticationString
 	if !p.isValidHash(record) {
-		return
+		return false
 	}
 
 	// empty password
 	if len(pwd) == 0 && len(authentication) == 0 {
-		p.user = user
-		p.host = h
-		success = true
-		return
+		return true
 	}
 
 	if len(pwd) == 0 || len(authentication) == 0 {
-		return
+		return false
 	}
 
 	if record.AuthPlugin == mysql.AuthNativePassword {
 		hpwd, err := auth.DecodePassword(pwd)
 		if err != nil {
 			logutil.BgLogger().Error("decode password string failed", zap.Error(err))
-			return
+			return false
 		}
 
-		if !auth.CheckScrambledPassword(salt, hpwd, authentication) {
-			return
-		}
+		return auth.CheckScrambledPassword(salt, hpwd, authentication)
 	} else if record.AuthPlugin == mysql.AuthCachingSha2Password {
 		authok, err := auth.CheckShaPassword([]byte(pwd), string(authentication))
 		if err != nil {
 			logutil.BgLogger().Error("Failed to check caching_sha2_password", zap.Error(err))
 		}
-
-		if !authok {
-			return
-		}
-	} else {
-		logutil.BgLogger().Error("unknown authentication plugin", zap.String("user", user), zap.String("plugin", record.AuthPlugin))
-		return
+		return authok
 	}
+	logutil.BgLogger().Error("unknown authentication plugin", zap.String("user", record.User), zap.String("plugin", record.AuthPlugin))
+	return false
+}
 
-	p.user = user
-	p.host = h
-	success = true
-	return
+// defaultPasswordLifetime returns the value of default_password_lifetime, 0 means the passwords never expire.
+func defaultPasswordLifetime(sessionVars *variable.SessionVars) int64 {
+	if sessionVars == nil || sessionVars.GlobalVarsAccessor == nil {
+		return 0
+	}
+	val, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.DefaultPasswordLifetime)
+	if err != nil {
+		return 0
+	}
+	lifetime, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return lifetime
 }
 
 type checkResult int
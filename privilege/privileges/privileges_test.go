@@ -29,10 +29,12 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/privilege/privileges/ldap/ldaptest"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/mockstore"
@@ -2085,3 +2087,209 @@ func TestGrantReferences(t *testing.T) {
 	tk.MustExec("DROP USER referencesUser")
 	tk.MustExec("DROP SCHEMA reftestdb")
 }
+
+func TestPasswordExpiration(t *testing.T) {
+	t.Parallel()
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'expired'@'localhost', 'lifetime'@'localhost', 'never'@'localhost'")
+	tk.MustExec("ALTER USER 'expired'@'localhost' PASSWORD EXPIRE")
+	tk.MustExec("ALTER USER 'lifetime'@'localhost' PASSWORD EXPIRE INTERVAL 10 DAY")
+	tk.MustExec("ALTER USER 'never'@'localhost' PASSWORD EXPIRE NEVER")
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil))
+	tk.MustQuery("SHOW CREATE USER 'expired'@'localhost'").Check(testkit.Rows(
+		"CREATE USER 'expired'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE ACCOUNT UNLOCK"))
+	tk.MustQuery("SHOW CREATE USER 'lifetime'@'localhost'").Check(testkit.Rows(
+		"CREATE USER 'lifetime'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE INTERVAL 10 DAY ACCOUNT UNLOCK"))
+	tk.MustQuery("SHOW CREATE USER 'never'@'localhost'").Check(testkit.Rows(
+		"CREATE USER 'never'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE NEVER ACCOUNT UNLOCK"))
+	_, err := tk.Exec("ALTER USER 'never'@'localhost' PASSWORD EXPIRE INTERVAL 0 DAY")
+	require.Error(t, err)
+
+	// The password which is expired manually can only be changed in the sandbox mode.
+	user := testkit.NewTestKit(t, store)
+	require.True(t, user.Session().Auth(&auth.UserIdentity{Username: "expired", Hostname: "localhost"}, nil, nil))
+	require.True(t, user.Session().GetSessionVars().InSandBoxMode)
+	_, err = user.Exec("SELECT 1")
+	require.True(t, session.ErrMustChangePassword.Equal(err))
+	user.MustExec("SET @a = 1")
+	user.MustExec("ALTER USER USER() IDENTIFIED BY 'newpassword'")
+	require.False(t, user.Session().GetSessionVars().InSandBoxMode)
+	user.MustQuery("SELECT 1").Check(testkit.Rows("1"))
+	tk.MustQuery("SELECT Password_expired FROM mysql.user WHERE User = 'expired'").Check(testkit.Rows("N"))
+
+	// The password expires after the lifetime of the account or the default_password_lifetime.
+	tk.MustExec("UPDATE mysql.user SET Password_last_changed = DATE_SUB(NOW(), INTERVAL 11 DAY) WHERE User IN ('lifetime', 'never')")
+	tk.MustExec("FLUSH PRIVILEGES")
+	require.True(t, user.Session().Auth(&auth.UserIdentity{Username: "lifetime", Hostname: "localhost"}, nil, nil))
+	require.True(t, user.Session().GetSessionVars().InSandBoxMode)
+	tk.MustExec("SET GLOBAL default_password_lifetime = 5")
+	require.True(t, user.Session().Auth(&auth.UserIdentity{Username: "never", Hostname: "localhost"}, nil, nil))
+	require.False(t, user.Session().GetSessionVars().InSandBoxMode)
+	tk.MustExec("ALTER USER 'never'@'localhost' PASSWORD EXPIRE DEFAULT")
+	require.True(t, user.Session().Auth(&auth.UserIdentity{Username: "never", Hostname: "localhost"}, nil, nil))
+	require.True(t, user.Session().GetSessionVars().InSandBoxMode)
+	user.MustExec("SET PASSWORD = 'newpassword'")
+	require.False(t, user.Session().GetSessionVars().InSandBoxMode)
+	tk.MustExec("SET GLOBAL default_password_lifetime = DEFAULT")
+}
+
+func TestFailedLoginTracking(t *testing.T) {
+	t.Parallel()
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'tracked'@'localhost' IDENTIFIED BY 'abc'")
+	tk.MustExec("UPDATE mysql.user SET Failed_login_attempts = 2, Password_lock_time = 1 WHERE User = 'tracked'")
+	tk.MustExec("FLUSH PRIVILEGES")
+
+	salt := []byte{85, 92, 45, 22, 58, 79, 107, 6, 122, 125, 58, 80, 12, 90, 103, 32, 90, 10, 74, 82}
+	authentication := []byte{24, 180, 183, 225, 166, 6, 81, 102, 70, 248, 199, 143, 91, 204, 169, 9, 161, 171, 203, 33}
+	user := &auth.UserIdentity{Username: "tracked", Hostname: "localhost"}
+	se := newSession(t, store, dbName)
+
+	// A successful login resets the counter of the consecutive failed logins.
+	require.False(t, se.Auth(user, nil, nil))
+	tk.MustQuery("SELECT Failed_login_count, Password_locked_time IS NULL FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows("1 1"))
+	require.True(t, se.Auth(user, authentication, salt))
+	tk.MustQuery("SELECT Failed_login_count, Password_locked_time IS NULL FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows("0 1"))
+
+	// The account is locked after the consecutive failed logins, even if the password is correct.
+	require.False(t, se.Auth(user, nil, nil))
+	require.False(t, se.Auth(user, nil, nil))
+	tk.MustQuery("SELECT Failed_login_count, Password_locked_time IS NULL FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows("0 0"))
+	err := se.AuthWithError(user, authentication, salt, nil)
+	require.EqualError(t, err, "[privilege:3955]Access denied for user 'tracked'@'localhost'. Account is blocked for 1 day(s) (1 day(s) remaining) due to 2 consecutive failed logins.")
+
+	// ACCOUNT UNLOCK unlocks the account which is locked temporarily.
+	tk.MustExec("ALTER USER 'tracked'@'localhost' ACCOUNT UNLOCK")
+	require.True(t, se.Auth(user, authentication, salt))
+
+	// ACCOUNT LOCK locks the account until it's unlocked.
+	tk.MustExec("ALTER USER 'tracked'@'localhost' ACCOUNT LOCK")
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil))
+	tk.MustQuery("SHOW CREATE USER 'tracked'@'localhost'").Check(testkit.Rows(
+		"CREATE USER 'tracked'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '*0D3CED9BEC10A777AEC23CCC353A8C08A633045E' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT LOCK"))
+	require.False(t, se.Auth(user, authentication, salt))
+	tk.MustExec("ALTER USER 'tracked'@'localhost' ACCOUNT UNLOCK")
+	require.True(t, se.Auth(user, authentication, salt))
+
+	// The failed login is counted once, although both the IP and the hostname of the client are tried.
+	tk.MustExec("CREATE USER 'tracked'@'%' IDENTIFIED BY 'abc'")
+	tk.MustExec("UPDATE mysql.user SET Failed_login_attempts = 3, Password_lock_time = 1 WHERE User = 'tracked' AND Host = '%'")
+	tk.MustExec("FLUSH PRIVILEGES")
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "tracked", Hostname: "127.0.0.1"}, nil, nil))
+	tk.MustQuery("SELECT Host, Failed_login_count FROM mysql.user WHERE User = 'tracked' ORDER BY Host").Check(testkit.Rows("% 1", "localhost 0"))
+}
+
+// saslAuthConn runs the SCRAM client of the authentication_ldap_sasl_client plugin.
+type saslAuthConn struct {
+	client   *ldaptest.SCRAMClient
+	response []byte
+	writes   int
+}
+
+func (c *saslAuthConn) WriteAuthMoreData(data []byte) (err error) {
+	c.writes++
+	c.response, err = c.client.Final(data)
+	return err
+}
+
+func (c *saslAuthConn) ReadPacket() ([]byte, error) {
+	return c.response, nil
+}
+
+func TestLDAPAuthentication(t *testing.T) {
+	t.Parallel()
+	store, clean := newStore(t)
+	defer clean()
+
+	const aliceDN = "uid=alice,ou=people,dc=example,dc=com"
+	s, err := ldaptest.NewServer(
+		&ldaptest.Entry{DN: aliceDN, Attributes: map[string][]string{"uid": {"alice"}, "userPassword": {"secret"}}},
+		&ldaptest.Entry{DN: "cn=dba,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"posixGroup"}, "cn": {"dba"}, "memberUid": {"alice"}}},
+		&ldaptest.Entry{DN: "cn=dev,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"group"}, "cn": {"dev"}, "member": {aliceDN}}},
+		&ldaptest.Entry{DN: "cn=guest,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"group"}, "cn": {"guest"}, "member": {aliceDN}}},
+		&ldaptest.Entry{DN: "cn=root,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"group"}, "cn": {"root"}, "member": {aliceDN}}},
+	)
+	require.NoError(t, err)
+	defer s.Close()
+
+	tk := testkit.NewTestKit(t, store)
+	for _, plugin := range []string{"authentication_ldap_simple", "authentication_ldap_sasl"} {
+		tk.MustExec(fmt.Sprintf("SET GLOBAL %s_server_host = '%s'", plugin, s.Host()))
+		tk.MustExec(fmt.Sprintf("SET GLOBAL %s_server_port = %d", plugin, s.Port()))
+		tk.MustExec(fmt.Sprintf("SET GLOBAL %s_bind_base_dn = 'dc=example,dc=com'", plugin))
+		tk.MustExec(fmt.Sprintf("SET GLOBAL %s_group_role_mapping = 'dba=ldap_admin@localhost,dev=dev,guest=ldap_guest'", plugin))
+	}
+	tk.MustExec("CREATE TABLE test.ldap (id INT)")
+	tk.MustExec("CREATE ROLE 'ldap_admin'@'localhost', 'dev', 'ldap_guest'")
+	tk.MustExec("GRANT SELECT ON mysql.* TO 'ldap_admin'@'localhost'")
+	tk.MustExec("GRANT SELECT ON test.* TO 'dev'")
+	tk.MustExec("CREATE USER 'alice'@'%' IDENTIFIED WITH 'authentication_ldap_simple' AS 'uid=alice,ou=people,dc=example,dc=com'")
+	tk.MustExec("CREATE USER 'alice'@'localhost' IDENTIFIED WITH 'authentication_ldap_simple'")
+	tk.MustExec("CREATE USER 'alice'@'127.0.0.1' IDENTIFIED WITH 'authentication_ldap_sasl'")
+	tk.MustExec("GRANT 'ldap_admin'@'localhost', 'dev' TO 'alice'@'%', 'alice'@'localhost', 'alice'@'127.0.0.1'")
+	tk.MustQuery("SHOW CREATE USER 'alice'@'%'").Check(testkit.Rows(
+		"CREATE USER 'alice'@'%' IDENTIFIED WITH 'authentication_ldap_simple' AS 'uid=alice,ou=people,dc=example,dc=com' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	pm := privilege.GetPrivilegeManager(tk.Session())
+	plugin, err := pm.GetAuthPlugin("alice", "localhost")
+	require.NoError(t, err)
+	require.Equal(t, "authentication_ldap_simple", plugin)
+
+	// The user DN is the authentication string, or searched by the user name. Only the mapped roles which are
+	// granted to the account are activated, the group named root doesn't grant the privileges of root.
+	for _, host := range []string{"192.168.0.1", "localhost"} {
+		user := testkit.NewTestKit(t, store)
+		require.True(t, user.Session().Auth(&auth.UserIdentity{Username: "alice", Hostname: host}, []byte("secret"), nil))
+		require.Equal(t, []*auth.RoleIdentity{{Username: "ldap_admin", Hostname: "localhost"}, {Username: "dev", Hostname: "%"}},
+			user.Session().GetSessionVars().ActiveRoles)
+		user.MustQuery("SELECT COUNT(*) > 0 FROM mysql.user").Check(testkit.Rows("1"))
+		user.MustQuery("SELECT COUNT(*) FROM test.ldap").Check(testkit.Rows("0"))
+		_, err = user.Exec("CREATE USER 'ldap_evil'")
+		require.Error(t, err)
+	}
+	user := testkit.NewTestKit(t, store)
+	err = user.Session().AuthWithError(&auth.UserIdentity{Username: "alice", Hostname: "localhost"}, []byte("wrong"), nil, nil)
+	require.True(t, privileges.ErrAccessDenied.Equal(err))
+	err = user.Session().AuthWithError(&auth.UserIdentity{Username: "alice", Hostname: "localhost"}, nil, nil, nil)
+	require.True(t, privileges.ErrAccessDenied.Equal(err))
+
+	// The SASL exchange is relayed through the connection.
+	tk.MustExec("SET GLOBAL authentication_ldap_sasl_auth_method_name = 'SCRAM-SHA-256'")
+	client, err := ldaptest.NewSCRAMClient("SCRAM-SHA-256", "alice", "secret", "nonce")
+	require.NoError(t, err)
+	require.NoError(t, user.Session().AuthWithError(&auth.UserIdentity{Username: "alice", Hostname: "127.0.0.1"}, client.First(), nil, &saslAuthConn{client: client}))
+	require.Len(t, user.Session().GetSessionVars().ActiveRoles, 2)
+	client, err = ldaptest.NewSCRAMClient("SCRAM-SHA-256", "alice", "wrong", "nonce")
+	require.NoError(t, err)
+	err = user.Session().AuthWithError(&auth.UserIdentity{Username: "alice", Hostname: "127.0.0.1"}, client.First(), nil, &saslAuthConn{client: client})
+	require.True(t, privileges.ErrAccessDenied.Equal(err))
+	err = user.Session().AuthWithError(&auth.UserIdentity{Username: "alice", Hostname: "127.0.0.1"}, client.First(), nil, nil)
+	require.True(t, privileges.ErrAccessDenied.Equal(err))
+
+	// The failed SASL exchange isn't restarted for the hostname of the client.
+	tk.MustExec("UPDATE mysql.user SET plugin = 'authentication_ldap_sasl' WHERE User = 'alice' AND Host = 'localhost'")
+	tk.MustExec("FLUSH PRIVILEGES")
+	client, err = ldaptest.NewSCRAMClient("SCRAM-SHA-256", "alice", "wrong", "nonce")
+	require.NoError(t, err)
+	conn := &saslAuthConn{client: client}
+	err = user.Session().AuthWithError(&auth.UserIdentity{Username: "alice", Hostname: "127.0.0.1"}, client.First(), nil, conn)
+	require.True(t, privileges.ErrAccessDenied.Equal(err))
+	require.Equal(t, 1, conn.writes)
+	tk.MustExec("UPDATE mysql.user SET plugin = 'authentication_ldap_simple' WHERE User = 'alice' AND Host = 'localhost'")
+	tk.MustExec("FLUSH PRIVILEGES")
+
+	// The password of the LDAP accounts is not stored.
+	_, err = tk.Exec("SET PASSWORD FOR 'alice'@'%' = 'secret'")
+	require.True(t, executor.ErrSetPasswordAuthPlugin.Equal(err))
+	tk.MustExec("ALTER USER 'alice'@'%' IDENTIFIED BY 'uid=alice,dc=example,dc=com'")
+	tk.MustQuery("SELECT authentication_string FROM mysql.user WHERE User = 'alice' AND Host = '%'").Check(testkit.Rows("uid=alice,dc=example,dc=com"))
+}
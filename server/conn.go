@@ -71,6 +71,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -179,6 +180,7 @@ type clientConn struct {
 	lastPacket   []byte            // latest sql query string, currently used for logging error.
 	ctx          *TiDBContext      // an interface to execute sql statements.
 	attrs        map[string]string // attributes parsed from client handshake response, not used for now.
+	zstdLevel    uint8             // zstd compression level requested by client.
 	peerHost     string            // peer host
 	peerPort     string            // peer port
 	status       int32             // dispatching/reading/shutdown/waitshutdown
@@ -209,12 +211,21 @@ func (cc *clientConn) String() string {
 // https://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::AuthSwitchRequest
 // https://bugs.mysql.com/bug.php?id=93044
 func (cc *clientConn) authSwitchRequest(ctx context.Context, plugin string) ([]byte, error) {
-	enclen := 1 + len(plugin) + 1 + len(cc.salt) + 1
+	pluginData := cc.salt
+	if plugin == ldap.ClientPluginLDAPSASL {
+		// The SASL client starts the exchange of the mechanism sent by the server.
+		mechanism, err := cc.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.AuthenticationLDAPSASLAuthMethodName)
+		if err != nil {
+			return nil, err
+		}
+		pluginData = []byte(mechanism)
+	}
+	enclen := 1 + len(plugin) + 1 + len(pluginData) + 1
 	data := cc.alloc.AllocWithLen(4, enclen)
 	data = append(data, mysql.AuthSwitchRequest) // switch request
 	data = append(data, []byte(plugin)...)
 	data = append(data, byte(0x00)) // requires null
-	data = append(data, cc.salt...)
+	data = append(data, pluginData...)
 	data = append(data, 0)
 	err := cc.writePacket(data)
 	if err != nil {
@@ -271,6 +282,11 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		return initErr
 	}
 
+	// The session state changed during the handshake, such as the initial schema, is known by the client.
+	if cc.ctx != nil {
+		cc.ctx.GetSessionVars().SessionTracker.Reset()
+	}
+
 	data := cc.alloc.AllocWithLen(4, 32)
 	data = append(data, mysql.OKHeader)
 	data = append(data, 0, 0)
@@ -293,9 +309,27 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
+
+	// The compressed protocol is used after the OK packet of the handshake.
+	cc.enableCompression()
 	return err
 }
 
+// enableCompression enables the compressed protocol negotiated in the handshake, zstd is preferred if the client
+// supports both.
+func (cc *clientConn) enableCompression() {
+	switch {
+	case cc.capability&clientZstdCompressionAlgorithm > 0:
+		level := int(cc.zstdLevel)
+		if level < 1 || level > 22 {
+			level = config.GetGlobalConfig().Performance.ZstdCompressionLevel
+		}
+		cc.pkt.setCompression(compressionZstd, level)
+	case cc.capability&mysql.ClientCompress > 0:
+		cc.pkt.setCompression(compressionZlib, 0)
+	}
+}
+
 func (cc *clientConn) Close() error {
 	cc.server.rwlock.Lock()
 	delete(cc.server.clients, cc.connectionID)
@@ -420,6 +454,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  uint8
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -566,6 +601,7 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 		if num, null, off := parseLengthEncodedInt(data[offset:]); !null {
 			offset += off
 			row := data[offset : offset+int(num)]
+			offset += int(num)
 			attrs, err := parseAttrs(row)
 			if err != nil {
 				logutil.Logger(ctx).Warn("parse attrs failed", zap.Error(err))
@@ -575,6 +611,14 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 		}
 	}
 
+	if packet.Capability&clientZstdCompressionAlgorithm > 0 {
+		if len(data[offset:]) == 0 {
+			// The level is optional, the default level will be used.
+			return nil
+		}
+		packet.ZstdLevel = data[offset]
+	}
+
 	return nil
 }
 
@@ -679,6 +723,7 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
 
 	newAuth, err := cc.checkAuthPlugin(ctx, &resp.AuthPlugin)
 	if err != nil {
@@ -695,6 +740,11 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 			return err
 		}
 	case mysql.AuthNativePassword:
+	case ldap.ClientPluginClearPassword:
+		// The cleartext password is terminated by NUL.
+		resp.Auth = bytes.TrimSuffix(resp.Auth, []byte{0})
+	case ldap.ClientPluginLDAPSASL:
+		// The first message of the SASL exchange, the rest is exchanged during the authentication.
 	default:
 		return errors.New("Unknown auth plugin")
 	}
@@ -734,6 +784,29 @@ func (cc *clientConn) authSha(ctx context.Context) ([]byte, error) {
 	return bytes.Trim(data, "\x00"), nil
 }
 
+// authConn exchanges the data of the authentication plugins with the client, it implements privilege.AuthConn.
+type authConn struct {
+	ctx context.Context
+	cc  *clientConn
+}
+
+// WriteAuthMoreData implements the privilege.AuthConn interface.
+func (c *authConn) WriteAuthMoreData(data []byte) error {
+	const authMoreData = 1
+	pkt := c.cc.alloc.AllocWithLen(4, 1+len(data))
+	pkt = append(pkt, authMoreData)
+	pkt = append(pkt, data...)
+	if err := c.cc.writePacket(pkt); err != nil {
+		return err
+	}
+	return c.cc.flush(c.ctx)
+}
+
+// ReadPacket implements the privilege.AuthConn interface.
+func (c *authConn) ReadPacket() ([]byte, error) {
+	return c.cc.readPacket()
+}
+
 func (cc *clientConn) SessionStatusToString() string {
 	status := cc.ctx.Status()
 	inTxn, autoCommit := 0, 0
@@ -784,8 +857,14 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
 	if err != nil {
 		return err
 	}
-	if !cc.ctx.Auth(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt) {
-		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+	ac := &authConn{ctx: context.Background(), cc: cc}
+	if err = cc.ctx.AuthWithError(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt, ac); err != nil {
+		return err
+	}
+	// The clients not supporting the sandbox mode are disconnected if the password has expired,
+	// the same as MySQL with disconnect_on_expired_password ON.
+	if cc.ctx.GetSessionVars().InSandBoxMode && cc.capability&clientCanHandleExpiredPasswords == 0 {
+		return errMustChangePasswordLogin.GenWithStackByArgs()
 	}
 	cc.ctx.SetPort(port)
 	if cc.dbname != "" {
@@ -822,12 +901,17 @@ func (cc *clientConn) checkAuthPlugin(ctx context.Context, authPlugin *string) (
 	// or if the authentication method send by the server doesn't match the authentication
 	// method send by the client (*authPlugin) then we need to switch the authentication
 	// method to match the one configured for that specific user.
-	if (cc.authPlugin != userplugin) || (cc.authPlugin != *authPlugin) {
-		authData, err := cc.authSwitchRequest(ctx, userplugin)
+	// The LDAP plugins are server side plugins, the client plugins they require are switched to.
+	clientPlugin := userplugin
+	if ldap.IsAuthPlugin(userplugin) {
+		clientPlugin = ldap.ClientPlugin(userplugin)
+	}
+	if (cc.authPlugin != clientPlugin) || (cc.authPlugin != *authPlugin) {
+		authData, err := cc.authSwitchRequest(ctx, clientPlugin)
 		if err != nil {
 			return nil, err
 		}
-		*authPlugin = userplugin
+		*authPlugin = clientPlugin
 		return authData, nil
 	}
 
@@ -1005,7 +1089,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 			terror.Log(err1)
 		}
 		cc.addMetrics(data[0], startTime, err)
-		cc.pkt.sequence = 0
+		cc.pkt.resetSequence()
 	}
 }
 
@@ -1182,6 +1266,14 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		cc.ctx.SetCommandValue(cmd)
 	}
 
+	switch cmd {
+	case mysql.ComSleep, mysql.ComQuit, mysql.ComPing, mysql.ComStmtFetch, mysql.ComStmtClose,
+		mysql.ComStmtSendLongData, mysql.ComStmtReset:
+	default:
+		// The executor of the streaming cursor can't stay open when other statements are executed.
+		cc.ctx.materializeStreamingCursor(ctx)
+	}
+
 	dataStr := string(hack.String(data))
 	switch cmd {
 	case mysql.ComPing, mysql.ComStmtClose, mysql.ComStmtSendLongData, mysql.ComStmtReset,
@@ -1316,7 +1408,15 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 		enclen = lengthEncodedIntSize(uint64(len(msg))) + len(msg)
 	}
 
-	data := cc.alloc.AllocWithLen(4, 32+enclen)
+	var sessionState []byte
+	if cc.capability&clientSessionTrack > 0 {
+		sessionState = dumpSessionStateChanges(cc.ctx.GetSessionVars().FetchSessionStateChanges())
+		if len(sessionState) > 0 {
+			status |= serverSessionStateChanged
+		}
+	}
+
+	data := cc.alloc.AllocWithLen(4, 32+enclen+len(sessionState))
 	data = append(data, mysql.OKHeader)
 	data = dumpLengthEncodedInt(data, affectedRows)
 	data = dumpLengthEncodedInt(data, lastInsertID)
@@ -1324,7 +1424,13 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 		data = dumpUint16(data, status)
 		data = dumpUint16(data, warnCnt)
 	}
-	if enclen > 0 {
+	if cc.capability&clientSessionTrack > 0 {
+		// The info message is always sent if the client supports CLIENT_SESSION_TRACK.
+		data = dumpLengthEncodedString(data, []byte(msg))
+		if len(sessionState) > 0 {
+			data = dumpLengthEncodedString(data, sessionState)
+		}
+	} else if enclen > 0 {
 		// although MySQL manual says the info message is string<EOF>(https://dev.mysql.com/doc/internals/en/packet-OK_Packet.html),
 		// it is actually string<lenenc>
 		data = dumpLengthEncodedString(data, []byte(msg))
@@ -15,11 +15,20 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/arena"
+	"github.com/pingcap/tidb/util/testkit"
 )
 
 func (ts *ConnTestSuite) TestParseExecArgs(c *C) {
@@ -223,3 +232,107 @@ func (ts *ConnTestSuite) TestParseStmtFetchCmd(c *C) {
 		c.Assert(err, Equals, t.err)
 	}
 }
+
+func (ts *ConnTestSuite) TestCursorMaterialize(c *C) {
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key)")
+	for i := 1; i <= 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d)", i))
+	}
+	tk.MustExec("set @@tidb_init_chunk_size = 32, @@tidb_max_chunk_size = 32")
+
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	defer server.Close()
+	cc := &clientConn{
+		server: server,
+		alloc:  arena.NewAllocator(1024),
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+		},
+		ctx: &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)},
+	}
+	ctx := context.Background()
+	dispatch := func(cmd byte, data []byte) {
+		c.Assert(cc.dispatch(ctx, append([]byte{cmd}, data...)), IsNil)
+	}
+	readRows := func(rs ResultSet) []string {
+		var rows []string
+		for _, row := range rs.GetFetchedRows() {
+			rows = append(rows, strconv.FormatInt(row.GetInt64(0), 10))
+		}
+		rs.StoreFetchedRows(nil)
+		chk := rs.NewChunk()
+		for {
+			c.Assert(rs.Next(ctx, chk), IsNil)
+			if chk.NumRows() == 0 {
+				return rows
+			}
+			for i := 0; i < chk.NumRows(); i++ {
+				rows = append(rows, strconv.FormatInt(chk.GetRow(i).GetInt64(0), 10))
+			}
+		}
+	}
+	expectedRows := func(from, to int) []string {
+		var rows []string
+		for i := from; i <= to; i++ {
+			rows = append(rows, strconv.Itoa(i))
+		}
+		return rows
+	}
+
+	dispatch(mysql.ComStmtPrepare, []byte("select a from t order by a"))
+	dispatch(mysql.ComStmtPrepare, []byte("select a from t where a > 50 order by a"))
+	tk.MustExec("begin")
+	// Open a cursor and fetch 3 rows, the executor stays open.
+	dispatch(mysql.ComStmtExecute, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0})
+	dispatch(mysql.ComStmtFetch, []byte{0x1, 0x0, 0x0, 0x0, 0x3, 0x0, 0x0, 0x0})
+	crs1 := cc.ctx.GetStatement(1).GetResultSet().(*cursorResultSet)
+	c.Assert(cc.ctx.streamingCursor, Equals, crs1)
+	c.Assert(crs1.rs, NotNil)
+
+	// Opening another cursor materializes the first one.
+	dispatch(mysql.ComStmtExecute, []byte{0x2, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0})
+	crs2 := cc.ctx.GetStatement(2).GetResultSet().(*cursorResultSet)
+	c.Assert(cc.ctx.streamingCursor, Equals, crs2)
+	c.Assert(crs1.rs, IsNil)
+	c.Assert(crs1.rowContainer.NumRow(), Equals, 68)
+	pi := cc.ctx.ShowProcess()
+	c.Assert(pi.CursorStats.OpenCursors, Equals, int64(2))
+	c.Assert(pi.CursorStats.MemTracker.BytesConsumed(), Greater, int64(0))
+
+	// The statements in the transaction don't affect the materialized cursors.
+	dispatch(mysql.ComQuery, []byte("delete from t"))
+	c.Assert(crs2.rs, IsNil)
+	dispatch(mysql.ComQuery, []byte("commit"))
+	c.Assert(readRows(crs1), DeepEquals, expectedRows(4, 100))
+	c.Assert(readRows(crs2), DeepEquals, expectedRows(51, 100))
+	dispatch(mysql.ComStmtClose, []byte{0x1, 0x0, 0x0, 0x0})
+	dispatch(mysql.ComStmtClose, []byte{0x2, 0x0, 0x0, 0x0})
+	c.Assert(pi.CursorStats.OpenCursors, Equals, int64(0))
+	c.Assert(pi.CursorStats.MemTracker.BytesConsumed(), Equals, int64(0))
+
+	// The materialized cursor is spilled to disk when exceeding tidb_mem_quota_query.
+	tk.MustExec("insert into t values (1), (2), (3)")
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	dispatch(mysql.ComStmtPrepare, []byte("select a from t order by a"))
+	dispatch(mysql.ComStmtExecute, []byte{0x3, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0})
+	crs3 := cc.ctx.GetStatement(3).GetResultSet().(*cursorResultSet)
+	dispatch(mysql.ComPing, nil)
+	c.Assert(crs3.rs, NotNil)
+	dispatch(mysql.ComQuery, []byte("select 1"))
+	c.Assert(crs3.rs, IsNil)
+	c.Assert(crs3.err, IsNil)
+	for i := 0; i < 100 && !crs3.rowContainer.AlreadySpilledSafeForTest(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(crs3.rowContainer.AlreadySpilledSafeForTest(), IsTrue)
+	c.Assert(pi.CursorStats.DiskTracker.BytesConsumed(), Greater, int64(0))
+	c.Assert(readRows(crs3), DeepEquals, expectedRows(1, 3))
+	dispatch(mysql.ComStmtClose, []byte{0x3, 0x0, 0x0, 0x0})
+	c.Assert(pi.CursorStats.DiskTracker.BytesConsumed(), Equals, int64(0))
+}
@@ -135,6 +135,18 @@ func (ts *ConnTestSuite) TestParseHandshakeResponse(c *C) {
 	c.Assert(p.User, Equals, "pam")
 	c.Assert(p.DBName, Equals, "test")
 
+	// Test for the zstd compression level at the end of the packet.
+	data = append(data, 0x07)
+	data[3] |= byte(clientZstdCompressionAlgorithm >> 24)
+	p = handshakeResponse41{}
+	offset, err = parseHandshakeResponseHeader(context.Background(), &p, data)
+	c.Assert(err, IsNil)
+	c.Assert(p.Capability&clientZstdCompressionAlgorithm, Equals, clientZstdCompressionAlgorithm)
+	err = parseHandshakeResponseBody(context.Background(), &p, data, offset)
+	c.Assert(err, IsNil)
+	c.Assert(p.DBName, Equals, "test")
+	c.Assert(p.ZstdLevel, Equals, uint8(7))
+
 	// Test for compatibility of Protocol::HandshakeResponse320
 	data = []byte{
 		0x00, 0x80, 0x00, 0x00, 0x01, 0x72, 0x6f, 0x6f, 0x74, 0x00, 0x00,
@@ -880,3 +892,77 @@ func (ts *ConnTestSuite) TestShowErrors(c *C) {
 	c.Assert(err, NotNil)
 	tk.MustQuery("show errors").Check(testkit.Rows("Error 1051 Unknown table 'test.idontexist'"))
 }
+
+func (ts *ConnTestSuite) TestSessionTrack(c *C) {
+	var outBuffer bytes.Buffer
+	cc := &clientConn{
+		alloc:      arena.NewAllocator(1024),
+		capability: mysql.ClientProtocol41 | clientSessionTrack,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+	}
+	ctx := context.Background()
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	tk.MustExec("create table if not exists t_session_track (a int)")
+	cc.ctx = &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)}
+
+	// sessionState runs the query and returns the session state information in the OK packet.
+	sessionState := func(sql string) []string {
+		outBuffer.Reset()
+		c.Assert(cc.handleQuery(ctx, sql), IsNil)
+		data := outBuffer.Bytes()[4:]
+		c.Assert(data[0], Equals, mysql.OKHeader)
+		data = data[3:]
+		status := binary.LittleEndian.Uint16(data)
+		data = data[4:]
+		// Skip the info message.
+		_, _, n, err := parseLengthEncodedBytes(data)
+		c.Assert(err, IsNil)
+		data = data[n:]
+		if status&serverSessionStateChanged == 0 {
+			c.Assert(data, HasLen, 0)
+			return nil
+		}
+		state, _, _, err := parseLengthEncodedBytes(data)
+		c.Assert(err, IsNil)
+		var changes []string
+		for len(state) > 0 {
+			tp := state[0]
+			item, _, n, err := parseLengthEncodedBytes(state[1:])
+			c.Assert(err, IsNil)
+			state = state[1+n:]
+			if variable.SessionStateType(tp) == variable.SessionStateSysVar {
+				name, _, n, err := parseLengthEncodedBytes(item)
+				c.Assert(err, IsNil)
+				value, _, _, err := parseLengthEncodedBytes(item[n:])
+				c.Assert(err, IsNil)
+				changes = append(changes, fmt.Sprintf("%d %s=%s", tp, name, value))
+			} else {
+				value, _, _, err := parseLengthEncodedBytes(item)
+				c.Assert(err, IsNil)
+				changes = append(changes, fmt.Sprintf("%d %s", tp, value))
+			}
+		}
+		return changes
+	}
+
+	c.Assert(sessionState("use test"), DeepEquals, []string{"1 test"})
+	c.Assert(sessionState("do 1"), IsNil)
+	c.Assert(sessionState("set @a = 1"), IsNil)
+	c.Assert(sessionState("set session_track_state_change = 1"), DeepEquals, []string{"2 1"})
+	c.Assert(sessionState("set @a = 2"), DeepEquals, []string{"2 1"})
+	c.Assert(sessionState("set time_zone = '+08:00', sql_mode = ''"), DeepEquals, []string{"0 time_zone=+08:00", "2 1"})
+	c.Assert(sessionState("set session_track_system_variables = 'sql_mode', session_track_state_change = 0"), IsNil)
+	c.Assert(sessionState("set sql_mode = 'ANSI_QUOTES'"), DeepEquals, []string{"0 sql_mode=ANSI_QUOTES"})
+	c.Assert(sessionState("set session_track_schema = 0"), IsNil)
+	c.Assert(sessionState("use mysql"), IsNil)
+
+	c.Assert(sessionState("set session_track_transaction_info = 'CHARACTERISTICS'"), IsNil)
+	c.Assert(sessionState("begin"), DeepEquals, []string{"4 START TRANSACTION;", "5 T_______"})
+	c.Assert(sessionState("insert into test.t_session_track values (1)"), DeepEquals, []string{"5 T___W___"})
+	c.Assert(sessionState("commit"), DeepEquals, []string{"4 ", "5 ________"})
+	c.Assert(sessionState("set transaction isolation level read committed"), DeepEquals, []string{"4 SET TRANSACTION ISOLATION LEVEL READ COMMITTED;"})
+	c.Assert(sessionState("begin"), DeepEquals, []string{"4 SET TRANSACTION ISOLATION LEVEL READ COMMITTED; START TRANSACTION;", "5 T_______"})
+	c.Assert(sessionState("rollback"), DeepEquals, []string{"4 ", "5 ________"})
+}
@@ -0,0 +1,255 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+// cursorResultSet is the ResultSet of a server-side cursor, which is opened by COM_STMT_EXECUTE
+// with the CURSOR_TYPE_READ_ONLY flag and read by COM_STMT_FETCH.
+//
+// The rows are streamed from the executor as long as it can stay open. The executor shares the
+// statement context and the transaction with the session, so at most one cursor of a connection
+// is streaming, and it's materialized before another command executes statements on the connection:
+// the remaining rows are read into a spillable chunk.RowContainer and the executor is closed.
+// The materialized cursors serve the fetches from their row containers.
+type cursorResultSet struct {
+	tc *TiDBContext
+	// rs is the result set of the executor, it's nil after the cursor is materialized.
+	rs         *tidbResultSet
+	columns    []*ColumnInfo
+	fieldTypes []*types.FieldType
+	rows       []chunk.Row
+
+	rowContainer *chunk.RowContainer
+	// chk is the chunk being read from rowContainer, chkIdx and rowIdx point to the next row to read.
+	chk    *chunk.Chunk
+	chkIdx int
+	rowIdx int
+	// err is the error met when materializing the cursor, it's returned by the next fetch.
+	err    error
+	closed bool
+}
+
+func newCursorResultSet(tc *TiDBContext, rs *tidbResultSet) *cursorResultSet {
+	crs := &cursorResultSet{
+		tc:         tc,
+		rs:         rs,
+		columns:    rs.Columns(),
+		fieldTypes: rs.fieldTypes(),
+	}
+	atomic.AddInt64(&tc.cursorStats.OpenCursors, 1)
+	return crs
+}
+
+// Columns implements ResultSet Columns method.
+func (crs *cursorResultSet) Columns() []*ColumnInfo {
+	return crs.columns
+}
+
+// NewChunk implements ResultSet NewChunk method.
+func (crs *cursorResultSet) NewChunk() *chunk.Chunk {
+	if crs.rs != nil {
+		return crs.rs.NewChunk()
+	}
+	maxChunkSize := crs.tc.GetSessionVars().MaxChunkSize
+	return chunk.New(crs.fieldTypes, maxChunkSize, maxChunkSize)
+}
+
+// Next implements ResultSet Next method.
+func (crs *cursorResultSet) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if crs.err != nil {
+		return crs.err
+	}
+	if crs.rs != nil {
+		return crs.rs.Next(ctx, req)
+	}
+	if crs.rowContainer == nil {
+		return nil
+	}
+	for !req.IsFull() && crs.chkIdx < crs.rowContainer.NumChunks() {
+		if crs.chk == nil {
+			chk, err := crs.rowContainer.GetChunk(crs.chkIdx)
+			if err != nil {
+				return err
+			}
+			crs.chk = chk
+		}
+		end := crs.chk.NumRows()
+		if remain := req.RequiredRows() - req.NumRows(); crs.rowIdx+remain < end {
+			end = crs.rowIdx + remain
+		}
+		req.Append(crs.chk, crs.rowIdx, end)
+		crs.rowIdx = end
+		if crs.rowIdx == crs.chk.NumRows() {
+			crs.chk = nil
+			crs.chkIdx++
+			crs.rowIdx = 0
+		}
+	}
+	return nil
+}
+
+// StoreFetchedRows implements ResultSet StoreFetchedRows method.
+func (crs *cursorResultSet) StoreFetchedRows(rows []chunk.Row) {
+	crs.rows = rows
+}
+
+// GetFetchedRows implements ResultSet GetFetchedRows method.
+func (crs *cursorResultSet) GetFetchedRows() []chunk.Row {
+	if crs.rows == nil {
+		crs.rows = make([]chunk.Row, 0, 1024)
+	}
+	return crs.rows
+}
+
+// OnFetchReturned implements fetchNotifier OnFetchReturned method.
+func (crs *cursorResultSet) OnFetchReturned() {
+	if crs.rs != nil {
+		crs.rs.OnFetchReturned()
+	}
+}
+
+// Close implements ResultSet Close method.
+func (crs *cursorResultSet) Close() error {
+	if crs.closed {
+		return nil
+	}
+	crs.closed = true
+	atomic.AddInt64(&crs.tc.cursorStats.OpenCursors, -1)
+	if crs.tc.streamingCursor == crs {
+		crs.tc.streamingCursor = nil
+	}
+	var err error
+	if crs.rs != nil {
+		err = crs.rs.Close()
+		crs.rs = nil
+	}
+	if crs.rowContainer != nil {
+		err1 := closeCursorRowContainer(crs.rowContainer)
+		if err == nil {
+			err = err1
+		}
+		crs.rowContainer, crs.chk = nil, nil
+	}
+	crs.rows = nil
+	return err
+}
+
+// materialize reads the remaining rows of the executor into a row container, and closes the executor.
+// The memory usage of the row container is limited by tidb_mem_quota_query, it's spilled to disk when
+// exceeding the quota if oom-use-tmp-storage is enabled.
+func (crs *cursorResultSet) materialize(ctx context.Context) (err error) {
+	vars := crs.tc.GetSessionVars()
+	rc := chunk.NewRowContainer(crs.fieldTypes, vars.MaxChunkSize)
+	memTracker := rc.GetMemTracker()
+	memTracker.SetLabel(memory.LabelForCursorFetch)
+	memTracker.SetBytesLimit(vars.MemQuotaQuery)
+	memTracker.AttachTo(crs.tc.cursorStats.MemTracker)
+	rc.GetDiskTracker().SetLabel(memory.LabelForCursorFetch)
+	rc.GetDiskTracker().AttachTo(crs.tc.cursorStats.DiskTracker)
+	cfg := config.GetGlobalConfig()
+	if cfg.OOMAction == config.OOMActionCancel {
+		memTracker.SetActionOnExceed(&memory.PanicOnExceed{ConnID: vars.ConnectionID})
+	} else {
+		memTracker.SetActionOnExceed(&memory.LogOnExceed{ConnID: vars.ConnectionID})
+	}
+	if cfg.OOMUseTmpStorage {
+		memTracker.FallbackOldAndSetNewAction(rc.ActionSpill())
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+		// Closing the executor finishes the statement, e.g. commits the transaction in the auto-commit mode.
+		if err1 := crs.rs.Close(); err == nil {
+			err = err1
+		}
+		crs.rs = nil
+		if err != nil {
+			terror.Call(func() error { return closeCursorRowContainer(rc) })
+			crs.err = err
+			return
+		}
+		crs.rowContainer = rc
+	}()
+	for {
+		chk := crs.rs.NewChunk()
+		if err = crs.rs.Next(ctx, chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+		if err = rc.Add(chk); err != nil {
+			return err
+		}
+	}
+}
+
+func closeCursorRowContainer(rc *chunk.RowContainer) error {
+	err := rc.Close()
+	rc.GetMemTracker().Detach()
+	rc.GetDiskTracker().Detach()
+	return err
+}
+
+// openCursor wraps the result set of a prepared statement into a streaming cursor.
+func (tc *TiDBContext) openCursor(rs ResultSet) ResultSet {
+	trs, ok := rs.(*tidbResultSet)
+	if !ok {
+		return rs
+	}
+	if tc.cursorStats == nil {
+		tc.cursorStats = util.NewCursorStats()
+		tc.cursorStats.MemTracker.AttachToGlobalTracker(executor.GlobalMemoryUsageTracker)
+		if config.GetGlobalConfig().OOMUseTmpStorage && executor.GlobalDiskUsageTracker != nil {
+			tc.cursorStats.DiskTracker.AttachToGlobalTracker(executor.GlobalDiskUsageTracker)
+		}
+		tc.GetSessionVars().CursorStats = tc.cursorStats
+	}
+	crs := newCursorResultSet(tc, trs)
+	tc.streamingCursor = crs
+	return crs
+}
+
+// materializeStreamingCursor materializes the streaming cursor of the connection, if any. It's called
+// before executing a statement, so that the executor of the cursor is not affected by the statement.
+// The error is kept by the cursor and returned to the client by the next fetch.
+func (tc *TiDBContext) materializeStreamingCursor(ctx context.Context) {
+	crs := tc.streamingCursor
+	if crs == nil {
+		return
+	}
+	tc.streamingCursor = nil
+	if err := crs.materialize(ctx); err != nil {
+		logutil.Logger(ctx).Warn("materialize cursor failed", zap.Error(err))
+	}
+}
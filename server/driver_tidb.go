@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/sqlexec"
 )
@@ -51,6 +52,10 @@ type TiDBContext struct {
 	session.Session
 	currentDB string
 	stmts     map[int]*TiDBStatement
+	// streamingCursor is the cursor whose executor is still open, see cursorResultSet.
+	streamingCursor *cursorResultSet
+	// cursorStats is initialized when the first cursor is opened.
+	cursorStats *util.CursorStats
 }
 
 // TiDBStatement implements PreparedStatement.
@@ -235,6 +240,10 @@ func (tc *TiDBContext) Close() error {
 	for _, v := range tc.stmts {
 		terror.Call(v.Close)
 	}
+	if tc.cursorStats != nil {
+		tc.cursorStats.MemTracker.DetachFromGlobalTracker()
+		tc.cursorStats.DiskTracker.DetachFromGlobalTracker()
+	}
 
 	tc.Session.Close()
 	return nil
@@ -306,6 +315,15 @@ func (trs *tidbResultSet) Next(ctx context.Context, req *chunk.Chunk) error {
 	return trs.recordSet.Next(ctx, req)
 }
 
+func (trs *tidbResultSet) fieldTypes() []*types.FieldType {
+	fields := trs.recordSet.Fields()
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	for _, f := range fields {
+		fieldTypes = append(fieldTypes, &f.Column.FieldType)
+	}
+	return fieldTypes
+}
+
 func (trs *tidbResultSet) StoreFetchedRows(rows []chunk.Row) {
 	trs.rows = rows
 }
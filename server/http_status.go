@@ -117,6 +117,11 @@ func (s *Server) startHTTPServer() {
 	// HTTP path for dump statistics.
 	router.Handle("/stats/dump/{db}/{table}", s.newStatsHandler()).Name("StatsDump")
 	router.Handle("/stats/dump/{db}/{table}/{snapshot}", s.newStatsHistoryHandler()).Name("StatsHistoryDump")
+	// HTTP path for locking statistics and restoring historical statistics.
+	router.Handle("/stats/lock/{db}/{table}", s.newStatsLockHandler(true)).Name("StatsLock")
+	router.Handle("/stats/unlock/{db}/{table}", s.newStatsLockHandler(false)).Name("StatsUnlock")
+	router.Handle("/stats/history/{db}/{table}", s.newStatsRestoreHandler()).Name("StatsHistory")
+	router.Handle("/stats/history/{db}/{table}/{snapshot}", s.newStatsRestoreHandler()).Name("StatsRestore")
 
 	tikvHandlerTool := s.newTikvHandlerTool()
 	router.Handle("/settings", settingsHandler{tikvHandlerTool}).Name("Settings")
@@ -37,9 +37,14 @@ package server
 
 import (
 	"bufio"
+	"bytes"
+	"compress/zlib"
 	"io"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
@@ -48,17 +53,103 @@ import (
 
 const defaultWriterSize = 16 * 1024
 
+const (
+	compressionNone = iota
+	compressionZlib
+	compressionZstd
+)
+
+const (
+	// compressedHeaderLen is the length of the header of a compressed packet, which consists of the length of the
+	// compressed payload, the compressed sequence and the length of the payload before compression.
+	compressedHeaderLen = 7
+	// minCompressLength is the minimum length of the payload to compress, the same as MySQL. The shorter payload is
+	// sent without compression.
+	minCompressLength = 50
+)
+
 var (
 	readPacketBytes  = metrics.PacketIOHistogram.WithLabelValues("read")
 	writePacketBytes = metrics.PacketIOHistogram.WithLabelValues("write")
+
+	readCompressedBytes    = metrics.CompressedPacketIOCounter.WithLabelValues("read_compressed")
+	readUncompressedBytes  = metrics.CompressedPacketIOCounter.WithLabelValues("read_uncompressed")
+	writeCompressedBytes   = metrics.CompressedPacketIOCounter.WithLabelValues("write_compressed")
+	writeUncompressedBytes = metrics.CompressedPacketIOCounter.WithLabelValues("write_uncompressed")
+)
+
+// The zstd encoders and decoder are shared by all the connections, EncodeAll and DecodeAll can be called concurrently.
+var (
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+	zstdDecoderOnce sync.Once
+	// zstdEncoders caches the encoders by the encoder level.
+	zstdEncoders sync.Map
 )
 
+func getZstdDecoder() (*zstd.Decoder, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+	})
+	return zstdDecoder, errors.Trace(zstdDecoderErr)
+}
+
+func getZstdEncoder(level int) (*zstd.Encoder, error) {
+	encLevel := zstd.EncoderLevelFromZstd(level)
+	if enc, ok := zstdEncoders.Load(encLevel); ok {
+		return enc.(*zstd.Encoder), nil
+	}
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encLevel))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	actual, loaded := zstdEncoders.LoadOrStore(encLevel, enc)
+	if loaded {
+		terror.Log(enc.Close())
+	}
+	return actual.(*zstd.Encoder), nil
+}
+
+// compressionStats records the bytes of the compressed packets of a connection. The uncompressed bytes are the
+// lengths of the payloads before compression, and the compressed bytes are the lengths actually sent or received.
+type compressionStats struct {
+	compressedRead    uint64
+	uncompressedRead  uint64
+	compressedWrite   uint64
+	uncompressedWrite uint64
+}
+
+func (s *compressionStats) addRead(compressed, uncompressed int) {
+	atomic.AddUint64(&s.compressedRead, uint64(compressed))
+	atomic.AddUint64(&s.uncompressedRead, uint64(uncompressed))
+	readCompressedBytes.Add(float64(compressed))
+	readUncompressedBytes.Add(float64(uncompressed))
+}
+
+func (s *compressionStats) addWrite(compressed, uncompressed int) {
+	atomic.AddUint64(&s.compressedWrite, uint64(compressed))
+	atomic.AddUint64(&s.uncompressedWrite, uint64(uncompressed))
+	writeCompressedBytes.Add(float64(compressed))
+	writeUncompressedBytes.Add(float64(uncompressed))
+}
+
 // packetIO is a helper to read and write data in packet format.
 type packetIO struct {
 	bufReadConn *bufferedReadConn
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+
+	// The fields below are used by the compressed protocol, the normal packets are transferred as a stream in the
+	// payloads of the compressed packets, so a normal packet may span multiple compressed packets.
+	compressionAlgorithm int
+	zstdLevel            int
+	compressedSequence   uint8
+	// compressedReadBuf is the uncompressed payload read but not consumed yet.
+	compressedReadBuf []byte
+	// compressedWriteBuf is the payload written but not compressed yet.
+	compressedWriteBuf bytes.Buffer
+	compressionStats   compressionStats
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -76,6 +167,34 @@ func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
 
+// setCompression enables the compressed protocol with the algorithm. It should be called after the handshake,
+// when the buffered packets have been flushed.
+func (p *packetIO) setCompression(algorithm int, zstdLevel int) {
+	p.compressionAlgorithm = algorithm
+	p.zstdLevel = zstdLevel
+	p.compressedSequence = 0
+}
+
+// resetSequence resets the sequences at the beginning of a command.
+func (p *packetIO) resetSequence() {
+	p.sequence = 0
+	p.compressedSequence = 0
+}
+
+func (p *packetIO) reader() io.Reader {
+	if p.compressionAlgorithm == compressionNone {
+		return p.bufReadConn
+	}
+	return compressedReader{p}
+}
+
+func (p *packetIO) writer() io.Writer {
+	if p.compressionAlgorithm == compressionNone {
+		return p.bufWriter
+	}
+	return compressedWriter{p}
+}
+
 func (p *packetIO) readOnePacket() ([]byte, error) {
 	var header [4]byte
 	if p.readTimeout > 0 {
@@ -83,16 +202,18 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	r := p.reader()
+	if _, err := io.ReadFull(r, header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
+	// The sequence of the compressed packet is checked instead if the compression is enabled, the same as MySQL.
 	sequence := header[3]
-	if sequence != p.sequence {
+	if sequence != p.sequence && p.compressionAlgorithm == compressionNone {
 		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
 	}
 
-	p.sequence++
+	p.sequence = sequence + 1
 
 	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
 
@@ -102,12 +223,178 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if _, err := io.ReadFull(r, data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
 }
 
+// readCompressedPacket reads a compressed packet and returns the payload after decompression.
+func (p *packetIO) readCompressedPacket() ([]byte, error) {
+	var header [compressedHeaderLen]byte
+	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	sequence := header[3]
+	if sequence != p.compressedSequence {
+		return nil, errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, p.compressedSequence)
+	}
+	p.compressedSequence++
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The payload is not compressed if the uncompressed length is 0.
+	if uncompressedLength == 0 {
+		p.compressionStats.addRead(length, length)
+		return data, nil
+	}
+
+	payload, err := p.decompress(data, uncompressedLength)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != uncompressedLength {
+		return nil, errors.Trace(mysql.ErrMalformPacket)
+	}
+	p.compressionStats.addRead(length, uncompressedLength)
+	return payload, nil
+}
+
+func (p *packetIO) decompress(data []byte, length int) ([]byte, error) {
+	switch p.compressionAlgorithm {
+	case compressionZlib:
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.Trace(mysql.ErrMalformPacket)
+		}
+		payload := make([]byte, length)
+		if _, err = io.ReadFull(r, payload); err != nil {
+			return nil, errors.Trace(mysql.ErrMalformPacket)
+		}
+		return payload, errors.Trace(r.Close())
+	case compressionZstd:
+		dec, err := getZstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := dec.DecodeAll(data, make([]byte, 0, length))
+		if err != nil {
+			return nil, errors.Trace(mysql.ErrMalformPacket)
+		}
+		return payload, nil
+	}
+	return nil, errors.Errorf("unknown compression algorithm %d", p.compressionAlgorithm)
+}
+
+// writeCompressedPacket compresses the payload and writes it as a compressed packet. The length of payload should
+// not be larger than mysql.MaxPayloadLen.
+func (p *packetIO) writeCompressedPacket(payload []byte) error {
+	data, uncompressedLength := payload, 0
+	if len(payload) >= minCompressLength {
+		compressed, err := p.compress(payload)
+		if err != nil {
+			return err
+		}
+		// Send the original payload if it can't be compressed.
+		if len(compressed) < len(payload) {
+			data, uncompressedLength = compressed, len(payload)
+		}
+	}
+
+	length := len(data)
+	header := [compressedHeaderLen]byte{
+		byte(length), byte(length >> 8), byte(length >> 16),
+		p.compressedSequence,
+		byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16),
+	}
+	if _, err := p.bufWriter.Write(header[:]); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	if _, err := p.bufWriter.Write(data); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	p.compressedSequence++
+	p.compressionStats.addWrite(length, len(payload))
+	return nil
+}
+
+func (p *packetIO) compress(payload []byte) ([]byte, error) {
+	switch p.compressionAlgorithm {
+	case compressionZlib:
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return buf.Bytes(), nil
+	case compressionZstd:
+		enc, err := getZstdEncoder(p.zstdLevel)
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(payload, nil), nil
+	}
+	return nil, errors.Errorf("unknown compression algorithm %d", p.compressionAlgorithm)
+}
+
+// flushCompressed compresses and writes all the buffered payload.
+func (p *packetIO) flushCompressed() error {
+	for p.compressedWriteBuf.Len() > 0 {
+		if err := p.writeCompressedPacket(p.compressedWriteBuf.Next(mysql.MaxPayloadLen)); err != nil {
+			return err
+		}
+	}
+	p.compressedWriteBuf.Reset()
+	return nil
+}
+
+// compressedReader reads the payloads of the compressed packets as a stream.
+type compressedReader struct {
+	p *packetIO
+}
+
+func (r compressedReader) Read(b []byte) (int, error) {
+	p := r.p
+	for len(p.compressedReadBuf) == 0 {
+		payload, err := p.readCompressedPacket()
+		if err != nil {
+			return 0, err
+		}
+		p.compressedReadBuf = payload
+	}
+	n := copy(b, p.compressedReadBuf)
+	p.compressedReadBuf = p.compressedReadBuf[n:]
+	return n, nil
+}
+
+// compressedWriter buffers the packets and writes them in compressed packets once the buffered payload is large
+// enough, the rest is written when flushing.
+type compressedWriter struct {
+	p *packetIO
+}
+
+func (w compressedWriter) Write(b []byte) (int, error) {
+	p := w.p
+	p.compressedWriteBuf.Write(b)
+	for p.compressedWriteBuf.Len() >= mysql.MaxPayloadLen {
+		if err := p.writeCompressedPacket(p.compressedWriteBuf.Next(mysql.MaxPayloadLen)); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
 func (p *packetIO) readPacket() ([]byte, error) {
 	if p.readTimeout == 0 {
 		if err := p.bufReadConn.SetReadDeadline(time.Time{}); err != nil {
@@ -147,6 +434,7 @@ func (p *packetIO) writePacket(data []byte) error {
 	length := len(data) - 4
 	writePacketBytes.Observe(float64(len(data)))
 
+	w := p.writer()
 	for length >= mysql.MaxPayloadLen {
 		data[0] = 0xff
 		data[1] = 0xff
@@ -154,7 +442,7 @@ func (p *packetIO) writePacket(data []byte) error {
 
 		data[3] = p.sequence
 
-		if n, err := p.bufWriter.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
+		if n, err := w.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
 			return errors.Trace(mysql.ErrBadConn)
 		} else if n != (4 + mysql.MaxPayloadLen) {
 			return errors.Trace(mysql.ErrBadConn)
@@ -170,7 +458,7 @@ func (p *packetIO) writePacket(data []byte) error {
 	data[2] = byte(length >> 16)
 	data[3] = p.sequence
 
-	if n, err := p.bufWriter.Write(data); err != nil {
+	if n, err := w.Write(data); err != nil {
 		terror.Log(errors.Trace(err))
 		return errors.Trace(mysql.ErrBadConn)
 	} else if n != len(data) {
@@ -182,6 +470,11 @@ func (p *packetIO) writePacket(data []byte) error {
 }
 
 func (p *packetIO) flush() error {
+	if p.compressionAlgorithm != compressionNone {
+		if err := p.flushCompressed(); err != nil {
+			return err
+		}
+	}
 	err := p.bufWriter.Flush()
 	if err != nil {
 		return errors.Trace(err)
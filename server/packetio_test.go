@@ -90,6 +90,56 @@ func (s *PacketIOTestSuite) TestRead(c *C) {
 	c.Assert(bytes[mysql.MaxPayloadLen], DeepEquals, byte(0x0a))
 }
 
+func (s *PacketIOTestSuite) TestCompressedReadWrite(c *C) {
+	for _, algorithm := range []int{compressionZlib, compressionZstd} {
+		var outBuffer bytes.Buffer
+		pkt := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		pkt.setCompression(algorithm, 3)
+		// A short packet which is sent without compression.
+		c.Assert(pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03}), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		c.Assert(outBuffer.Bytes(), DeepEquals, []byte{0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03})
+		c.Assert(pkt.compressedSequence, Equals, uint8(1))
+
+		// Packets buffered in one compressed packet, and a large packet spans multiple compressed packets.
+		outBuffer.Reset()
+		pkt.resetSequence()
+		small := append(make([]byte, 4), bytes.Repeat([]byte{0x0a}, 100)...)
+		large := append(make([]byte, 4), bytes.Repeat([]byte{0x0b}, mysql.MaxPayloadLen+10)...)
+		c.Assert(pkt.writePacket(small), IsNil)
+		c.Assert(pkt.writePacket(large), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		c.Assert(pkt.sequence, Equals, uint8(3))
+		c.Assert(pkt.compressedSequence, Equals, uint8(2))
+		c.Assert(outBuffer.Len() < 1024*1024, IsTrue)
+		c.Assert(pkt.compressionStats.compressedWrite, Equals, uint64(7+outBuffer.Len()-2*compressedHeaderLen))
+		c.Assert(pkt.compressionStats.uncompressedWrite, Equals, uint64(7+4+100+4+mysql.MaxPayloadLen+4+10))
+
+		brc := newBufferedReadConn(&bytesConn{outBuffer})
+		pkt = newPacketIO(brc)
+		pkt.setCompression(algorithm, 3)
+		data, err := pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, small[4:])
+		data, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+		// The header of the split packet is written in place, so the data to write can't be used to compare.
+		c.Assert(bytes.Equal(data, bytes.Repeat([]byte{0x0b}, mysql.MaxPayloadLen+10)), IsTrue)
+		c.Assert(pkt.compressedSequence, Equals, uint8(2))
+	}
+}
+
+func (s *PacketIOTestSuite) TestCompressedSequence(c *C) {
+	var inBuffer bytes.Buffer
+	_, err := inBuffer.Write([]byte{0x05, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01})
+	c.Assert(err, IsNil)
+	brc := newBufferedReadConn(&bytesConn{inBuffer})
+	pkt := newPacketIO(brc)
+	pkt.setCompression(compressionZlib, 0)
+	_, err = pkt.readPacket()
+	c.Assert(err, ErrorMatches, ".*invalid compressed sequence 1 != 0")
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
@@ -97,6 +97,7 @@ var (
 	errInvalidType             = dbterror.ClassServer.NewStd(errno.ErrInvalidType)
 	errNotAllowedCommand       = dbterror.ClassServer.NewStd(errno.ErrNotAllowedCommand)
 	errAccessDenied            = dbterror.ClassServer.NewStd(errno.ErrAccessDenied)
+	errMustChangePasswordLogin = dbterror.ClassServer.NewStd(errno.ErrMustChangePasswordLogin)
 	errConCount                = dbterror.ClassServer.NewStd(errno.ErrConCount)
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
@@ -109,7 +110,21 @@ const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	mysql.ClientCompress | clientZstdCompressionAlgorithm | clientSessionTrack |
+	clientCanHandleExpiredPasswords
+
+// The flags below are not defined in the parser.
+const (
+	// clientCanHandleExpiredPasswords is the capability flag of the clients supporting the sandbox mode.
+	clientCanHandleExpiredPasswords uint32 = 1 << 22
+	// clientSessionTrack is the capability flag of the session state information in the OK packets.
+	clientSessionTrack uint32 = 1 << 23
+	// clientZstdCompressionAlgorithm is the capability flag of the compressed protocol using zstd.
+	clientZstdCompressionAlgorithm uint32 = 1 << 26
+	// serverSessionStateChanged is the status flag indicating the OK packet contains the session state information.
+	serverSessionStateChanged uint16 = 0x4000
+)
 
 // Server is the MySQL protocol server
 type Server struct {
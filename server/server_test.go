@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/privilege/privileges/ldap/ldaptest"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/versioninfo"
@@ -1608,6 +1609,60 @@ func (cli *testServerClient) runTestAuth(c *C) {
 	})
 }
 
+func (cli *testServerClient) runTestLDAPAuth(c *C) {
+	ldapServer, err := ldaptest.NewServer(
+		&ldaptest.Entry{DN: "uid=ldapauth,dc=example,dc=com", Attributes: map[string][]string{
+			"uid": {"ldapauth"}, "userPassword": {"secret"}}},
+		&ldaptest.Entry{DN: "cn=ldapauth_r1,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass": {"posixGroup"}, "cn": {"ldapauth_r1"}, "memberUid": {"ldapauth"}}},
+	)
+	c.Assert(err, IsNil)
+	defer ldapServer.Close()
+
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(fmt.Sprintf("SET GLOBAL authentication_ldap_simple_server_host = '%s'", ldapServer.Host()))
+		dbt.mustExec(fmt.Sprintf("SET GLOBAL authentication_ldap_simple_server_port = %d", ldapServer.Port()))
+		dbt.mustExec("SET GLOBAL authentication_ldap_simple_bind_base_dn = 'dc=example,dc=com'")
+		dbt.mustExec("SET GLOBAL authentication_ldap_simple_group_role_mapping = 'ldapauth_r1=ldapauth_r1'")
+		dbt.mustExec("CREATE USER 'ldapauth'@'%' IDENTIFIED WITH 'authentication_ldap_simple'")
+		dbt.mustExec("CREATE ROLE 'ldapauth_r1'@'%'")
+		dbt.mustExec("GRANT ALL ON test.* TO 'ldapauth_r1'@'%'")
+		dbt.mustExec("GRANT 'ldapauth_r1'@'%' TO 'ldapauth'@'%'")
+	})
+	// The client switches to mysql_clear_password, and the roles are mapped from the groups.
+	cli.runTests(c, func(config *mysql.Config) {
+		config.User = "ldapauth"
+		config.Passwd = "secret"
+		config.AllowCleartextPasswords = true
+	}, func(dbt *DBTest) {
+		rows := dbt.mustQuery("SELECT CURRENT_USER(), CURRENT_ROLE()")
+		c.Assert(rows.Next(), IsTrue)
+		var user, role string
+		c.Assert(rows.Scan(&user, &role), IsNil)
+		c.Assert(user, Equals, "ldapauth@%")
+		c.Assert(role, Equals, "`ldapauth_r1`@`%`")
+		c.Assert(rows.Close(), IsNil)
+		dbt.mustExec("USE test")
+	})
+
+	for _, overrider := range []configOverrider{
+		func(config *mysql.Config) {
+			config.Passwd = "wrong"
+			config.AllowCleartextPasswords = true
+		},
+		func(config *mysql.Config) {
+			config.Passwd = "secret"
+		},
+	} {
+		db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+			config.User = "ldapauth"
+		}, overrider))
+		c.Assert(err, IsNil)
+		c.Assert(db.Ping(), NotNil)
+		c.Assert(db.Close(), IsNil)
+	}
+}
+
 func (cli *testServerClient) runTestIssue3662(c *C) {
 	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
 		config.DBName = "non_existing_schema"
@@ -16,6 +16,7 @@ package server
 
 import (
 	"crypto/x509"
+	"sync/atomic"
 
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/logutil"
@@ -25,11 +26,26 @@ import (
 var (
 	serverNotAfter  = "Ssl_server_not_after"
 	serverNotBefore = "Ssl_server_not_before"
+
+	compression               = "Compression"
+	compressionAlgorithm      = "Compression_algorithm"
+	compressionLevel          = "Compression_level"
+	compressedBytesReceived   = "Compressed_bytes_received"
+	compressedBytesSent       = "Compressed_bytes_sent"
+	uncompressedBytesReceived = "Uncompressed_bytes_received"
+	uncompressedBytesSent     = "Uncompressed_bytes_sent"
 )
 
 var defaultStatus = map[string]*variable.StatusVal{
-	serverNotAfter:  {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
-	serverNotBefore: {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	serverNotAfter:            {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	serverNotBefore:           {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	compression:               {Scope: variable.ScopeSession, Value: "OFF"},
+	compressionAlgorithm:      {Scope: variable.ScopeSession, Value: ""},
+	compressionLevel:          {Scope: variable.ScopeSession, Value: 0},
+	compressedBytesReceived:   {Scope: variable.ScopeSession, Value: uint64(0)},
+	compressedBytesSent:       {Scope: variable.ScopeSession, Value: uint64(0)},
+	uncompressedBytesReceived: {Scope: variable.ScopeSession, Value: uint64(0)},
+	uncompressedBytesSent:     {Scope: variable.ScopeSession, Value: uint64(0)},
 }
 
 // GetScope gets the status variables scope.
@@ -57,5 +73,36 @@ func (s *Server) Stats(vars *variable.SessionVars) (map[string]interface{}, erro
 			}
 		}
 	}
+
+	// `vars` may be nil in unit tests.
+	if vars != nil {
+		s.rwlock.RLock()
+		cc := s.clients[vars.ConnectionID]
+		s.rwlock.RUnlock()
+		if cc != nil {
+			fillCompressionStats(m, cc.pkt)
+		}
+	}
 	return m, nil
 }
+
+// fillCompressionStats fills the status of the compressed protocol of the connection.
+func fillCompressionStats(m map[string]interface{}, pkt *packetIO) {
+	switch pkt.compressionAlgorithm {
+	case compressionZlib:
+		m[compression] = "ON"
+		m[compressionAlgorithm] = "zlib"
+		m[compressionLevel] = 6
+	case compressionZstd:
+		m[compression] = "ON"
+		m[compressionAlgorithm] = "zstd"
+		m[compressionLevel] = pkt.zstdLevel
+	default:
+		return
+	}
+	stats := &pkt.compressionStats
+	m[compressedBytesReceived] = atomic.LoadUint64(&stats.compressedRead)
+	m[compressedBytesSent] = atomic.LoadUint64(&stats.compressedWrite)
+	m[uncompressedBytesReceived] = atomic.LoadUint64(&stats.uncompressedRead)
+	m[uncompressedBytesSent] = atomic.LoadUint64(&stats.uncompressedWrite)
+}
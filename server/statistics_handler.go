@@ -19,10 +19,12 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/tikv/client-go/v2/oracle"
@@ -131,3 +133,116 @@ func (sh StatsHistoryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		writeData(w, js)
 	}
 }
+
+// StatsLockHandler is the handler for locking and unlocking the statistics of a table.
+type StatsLockHandler struct {
+	do   *domain.Domain
+	lock bool
+}
+
+func (s *Server) newStatsLockHandler(lock bool) *StatsLockHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+	return &StatsLockHandler{do: do, lock: lock}
+}
+
+func (sh StatsLockHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	params := mux.Vars(req)
+	tbl, err := sh.do.InfoSchema().TableByName(model.NewCIStr(params[pDBName]), model.NewCIStr(params[pTableName]))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	h := sh.do.StatsHandle()
+	if sh.lock {
+		err = h.LockTableStats(tbl.Meta().ID)
+	} else {
+		err = h.UnlockTableStats(tbl.Meta().ID)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeData(w, "success!")
+}
+
+// StatsRestoreHandler is the handler for listing the historical statistics of a table and restoring the statistics
+// to a historical version.
+type StatsRestoreHandler struct {
+	do *domain.Domain
+}
+
+func (s *Server) newStatsRestoreHandler() *StatsRestoreHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+	return &StatsRestoreHandler{do}
+}
+
+type historicalStatsVersion struct {
+	Version    uint64 `json:"version"`
+	CreateTime string `json:"create_time,omitempty"`
+}
+
+func (sh StatsRestoreHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	is := sh.do.InfoSchema()
+	h := sh.do.StatsHandle()
+	tbl, err := is.TableByName(model.NewCIStr(params[pDBName]), model.NewCIStr(params[pTableName]))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// Without the time, it lists the historical versions of the statistics.
+	if _, ok := params[pSnapshot]; !ok {
+		versions, err := h.GetHistoricalStatsVersions(tbl.Meta().ID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		data := make([]historicalStatsVersion, 0, len(versions))
+		for _, v := range versions {
+			data = append(data, historicalStatsVersion{Version: v.Version, CreateTime: v.CreateTime.String()})
+		}
+		writeData(w, data)
+		return
+	}
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	t, err := types.ParseTime(sc, params[pSnapshot], mysql.TypeDatetime, 6)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	t1, err := t.GoTime(time.Local)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	version, err := h.RestoreHistoricalStats(is, tbl.Meta().ID, t1)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeData(w, historicalStatsVersion{Version: version})
+}
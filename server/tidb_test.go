@@ -240,6 +240,7 @@ func (ts *tidbTestSuite) TestAuth(c *C) {
 	c.Parallel()
 	ts.runTestAuth(c)
 	ts.runTestIssue3682(c)
+	ts.runTestLDAPAuth(c)
 }
 
 func (ts *tidbTestSuite) TestIssues(c *C) {
@@ -46,6 +46,7 @@ import (
 
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/hack"
@@ -139,6 +140,22 @@ func dumpLengthEncodedString(buffer []byte, bytes []byte) []byte {
 	return buffer
 }
 
+// dumpSessionStateChanges dumps the session state information of the OK packet, each piece of which consists of
+// the type and the data in string<lenenc>.
+func dumpSessionStateChanges(changes []variable.SessionStateChange) []byte {
+	var buffer, data []byte
+	for _, change := range changes {
+		data = data[:0]
+		if change.Type == variable.SessionStateSysVar {
+			data = dumpLengthEncodedString(data, hack.Slice(change.Name))
+		}
+		data = dumpLengthEncodedString(data, hack.Slice(change.Value))
+		buffer = append(buffer, byte(change.Type))
+		buffer = dumpLengthEncodedString(buffer, data)
+	}
+	return buffer
+}
+
 func dumpUint16(buffer []byte, n uint16) []byte {
 	buffer = append(buffer, byte(n))
 	buffer = append(buffer, byte(n>>8))
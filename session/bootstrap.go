@@ -90,6 +90,15 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Password_expired		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Password_last_changed	TIMESTAMP DEFAULT CURRENT_TIMESTAMP(),
+		Password_lifetime		SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_history	SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_time		SMALLINT UNSIGNED DEFAULT NULL,
+		Failed_login_attempts	SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+		Password_lock_time		SMALLINT NOT NULL DEFAULT 0,
+		Failed_login_count		SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+		Password_locked_time	TIMESTAMP NULL DEFAULT NULL,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -340,6 +349,65 @@ const (
 		WITH_GRANT_OPTION enum('N','Y') NOT NULL DEFAULT 'N',
 		PRIMARY KEY (USER,HOST,PRIV)
 	  );`
+	// CreateTTLTableStatusTable stores the progress of the TTL job of each table.
+	CreateTTLTableStatusTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_ttl_table_status (
+		table_id BIGINT(64) NOT NULL,
+		job_state VARCHAR(16) NOT NULL,
+		job_start_time DATETIME NOT NULL,
+		job_finish_time DATETIME NULL DEFAULT NULL,
+		expire_time DATETIME NOT NULL,
+		total_regions BIGINT(64) NOT NULL DEFAULT 0,
+		scanned_regions BIGINT(64) NOT NULL DEFAULT 0,
+		deleted_rows BIGINT(64) NOT NULL DEFAULT 0,
+		error_message TEXT,
+		PRIMARY KEY (table_id)
+	);`
+	// CreatePlanRegressionHistoryTable stores the decisions made for the plan regressions.
+	CreatePlanRegressionHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.plan_regression_history (
+		schema_name VARCHAR(64) NOT NULL,
+		digest VARCHAR(64) NOT NULL,
+		digest_text TEXT NOT NULL,
+		regressed_plan_digest VARCHAR(64) NOT NULL,
+		regressed_avg_latency BIGINT(64) UNSIGNED NOT NULL,
+		good_plan_digest VARCHAR(64) NOT NULL,
+		good_avg_latency BIGINT(64) UNSIGNED NOT NULL,
+		bind_sql TEXT,
+		action VARCHAR(16) NOT NULL,
+		reason TEXT,
+		create_time TIMESTAMP(3) NOT NULL,
+		INDEX digest_index(digest)
+	);`
+	// CreateColumnStatsUsageTable stores the last time when the columns of the tables are used in the predicates.
+	CreateColumnStatsUsageTable = `CREATE TABLE IF NOT EXISTS mysql.column_stats_usage (
+		table_id BIGINT(64) NOT NULL,
+		column_id BIGINT(64) NOT NULL,
+		last_used_at TIMESTAMP,
+		PRIMARY KEY (table_id, column_id) CLUSTERED
+	);`
+	// CreateStatsHistoryTable stores the historical versions of the stats of the tables.
+	CreateStatsHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.stats_history (
+		table_id BIGINT(64) NOT NULL,
+		stats_data LONGBLOB NOT NULL,
+		seq_no BIGINT(64) NOT NULL,
+		version BIGINT(64) UNSIGNED NOT NULL,
+		create_time DATETIME(6) NOT NULL,
+		UNIQUE INDEX table_version_seq (table_id, version, seq_no),
+		INDEX table_create_time (table_id, create_time, seq_no)
+	);`
+	// CreateStatsTableLockedTable stores the tables whose stats are locked.
+	CreateStatsTableLockedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_table_locked (
+		table_id BIGINT(64) NOT NULL,
+		create_time DATETIME(6) NOT NULL,
+		PRIMARY KEY (table_id)
+	);`
+	// CreatePasswordHistoryTable stores the history of the passwords to check the password reuse policy.
+	CreatePasswordHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.password_history (
+		Host				CHAR(255) NOT NULL DEFAULT '',
+		User				CHAR(32) NOT NULL DEFAULT '',
+		Password_timestamp	TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		Password			TEXT,
+		PRIMARY KEY (Host, User, Password_timestamp)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -499,11 +567,23 @@ const (
 	version71 = 71
 	// version72 adds snapshot column for mysql.stats_meta
 	version72 = 72
+	// version73 adds mysql.tidb_ttl_table_status for the TTL job
+	version73 = 73
+	// version74 adds mysql.plan_regression_history for the plan regression rollback
+	version74 = 74
+	// version75 adds mysql.column_stats_usage for collecting the predicate columns
+	version75 = 75
+	// version76 adds mysql.stats_history and mysql.stats_table_locked
+	version76 = 76
+	// version77 sets the default values of the session_track_* variables which were noop with empty values
+	version77 = 77
+	// version78 adds the columns of the password policy to mysql.user, and adds mysql.password_history
+	version78 = 78
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version72
+var currentBootstrapVersion int64 = version78
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -579,6 +659,12 @@ var (
 		upgradeToVer70,
 		upgradeToVer71,
 		upgradeToVer72,
+		upgradeToVer73,
+		upgradeToVer74,
+		upgradeToVer75,
+		upgradeToVer76,
+		upgradeToVer77,
+		upgradeToVer78,
 	}
 )
 
@@ -1528,6 +1614,69 @@ func upgradeToVer72(s Session, ver int64) {
 	doReentrantDDL(s, "ALTER TABLE mysql.stats_meta ADD COLUMN snapshot BIGINT(64) UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
 }
 
+func upgradeToVer73(s Session, ver int64) {
+	if ver >= version73 {
+		return
+	}
+	doReentrantDDL(s, CreateTTLTableStatusTable)
+}
+
+func upgradeToVer74(s Session, ver int64) {
+	if ver >= version74 {
+		return
+	}
+	doReentrantDDL(s, CreatePlanRegressionHistoryTable)
+}
+
+func upgradeToVer75(s Session, ver int64) {
+	if ver >= version75 {
+		return
+	}
+	doReentrantDDL(s, CreateColumnStatsUsageTable)
+}
+
+func upgradeToVer76(s Session, ver int64) {
+	if ver >= version76 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsHistoryTable)
+	doReentrantDDL(s, CreateStatsTableLockedTable)
+}
+
+func upgradeToVer77(s Session, ver int64) {
+	if ver >= version77 {
+		return
+	}
+	defaults := map[string]string{
+		variable.SessionTrackSchema:          variable.On,
+		variable.SessionTrackStateChange:     variable.Off,
+		variable.SessionTrackSystemVariables: variable.DefSessionTrackSystemVariables,
+	}
+	for name, val := range defaults {
+		mustExecute(s, "UPDATE HIGH_PRIORITY %n.%n SET variable_value = %? WHERE variable_name = %? AND variable_value = ''",
+			mysql.SystemDB, mysql.GlobalVariablesTable, val, name)
+	}
+}
+
+func upgradeToVer78(s Session, ver int64) {
+	if ver >= version78 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_expired` ENUM('N','Y') NOT NULL DEFAULT 'N'", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_last_changed` TIMESTAMP DEFAULT CURRENT_TIMESTAMP()", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lifetime` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_history` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_time` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Failed_login_attempts` SMALLINT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lock_time` SMALLINT NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Failed_login_count` SMALLINT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_locked_time` TIMESTAMP NULL DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreatePasswordHistoryTable)
+	// default_password_lifetime was a noop variable with an empty value.
+	mustExecute(s, "UPDATE HIGH_PRIORITY %n.%n SET variable_value = '0' WHERE variable_name = %? AND variable_value = ''",
+		mysql.SystemDB, mysql.GlobalVariablesTable, variable.DefaultPasswordLifetime)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1606,6 +1755,18 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateStatsFMSketchTable)
 	// Create global_grants
 	mustExecute(s, CreateGlobalGrantsTable)
+	// Create tidb_ttl_table_status
+	mustExecute(s, CreateTTLTableStatusTable)
+	// Create plan_regression_history
+	mustExecute(s, CreatePlanRegressionHistoryTable)
+	// Create column_stats_usage table.
+	mustExecute(s, CreateColumnStatsUsageTable)
+	// Create stats_history table.
+	mustExecute(s, CreateStatsHistoryTable)
+	// Create stats_table_locked table.
+	mustExecute(s, CreateStatsTableLockedTable)
+	// Create password_history table.
+	mustExecute(s, CreatePasswordHistoryTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1616,7 +1777,8 @@ func doDMLWorks(s Session) {
 
 	// Insert a default user with empty password.
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y",
+		"N", CURRENT_TIMESTAMP(), NULL, NULL, NULL, 0, 0, 0, NULL)`)
 
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.GetSysVars()))
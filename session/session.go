@@ -152,6 +152,9 @@ type Session interface {
 	SetSessionManager(util.SessionManager)
 	Close()
 	Auth(user *auth.UserIdentity, auth []byte, salt []byte) bool
+	// AuthWithError is the same as Auth, except that it returns the error of the verification, such as the account
+	// being locked, and the authentication plugins can exchange more data with the client by authConn.
+	AuthWithError(user *auth.UserIdentity, auth []byte, salt []byte, authConn privilege.AuthConn) error
 	AuthWithoutVerification(user *auth.UserIdentity) bool
 	AuthPluginForUser(user *auth.UserIdentity) (string, error)
 	ShowProcess() *util.ProcessInfo
@@ -427,6 +430,14 @@ func (s *session) StoreIndexUsage(tblID int64, idxID int64, rowsSelected int64)
 	s.idxUsageCollector.Update(tblID, idxID, &handle.IndexUsageInformation{QueryCount: 1, RowsSelected: rowsSelected})
 }
 
+// StoreColumnStatsUsage stores column stats usage information in statsCollector.
+func (s *session) StoreColumnStatsUsage(tblID int64, colID int64) {
+	if s.statsCollector == nil {
+		return
+	}
+	s.statsCollector.UpdateColStatsUsage(tblID, colID)
+}
+
 // FieldList returns fields list of a table.
 func (s *session) FieldList(tableName string) ([]*ast.ResultField, error) {
 	is := s.GetInfoSchema().(infoschema.InfoSchema)
@@ -1323,6 +1334,7 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 		CurTxnStartTS:    curTxnStartTS,
 		StmtCtx:          s.sessionVars.StmtCtx,
 		StatsInfo:        plannercore.GetStatsInfo,
+		CursorStats:      s.sessionVars.CursorStats,
 		MaxExecutionTime: maxExecutionTime,
 		RedactSQL:        s.sessionVars.EnableRedactLog,
 	}
@@ -1588,6 +1600,14 @@ func (s *session) ExecRestrictedStmt(ctx context.Context, stmtNode ast.StmtNode,
 		}()
 	}
 
+	if execOption.AnalyzePredicateColumns {
+		prevPredicateColumns := se.sessionVars.AnalyzePredicateColumns
+		se.sessionVars.AnalyzePredicateColumns = true
+		defer func() {
+			se.sessionVars.AnalyzePredicateColumns = prevPredicateColumns
+		}()
+	}
+
 	// for analyze stmt we need let worker session follow user session that executing stmt.
 	se.sessionVars.PartitionPruneMode.Store(s.sessionVars.PartitionPruneMode.Load())
 	metrics.SessionRestrictedSQLCounter.Inc()
@@ -1642,6 +1662,9 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	if err := s.validateStatementReadOnlyInStaleness(stmtNode); err != nil {
 		return nil, err
 	}
+	if err := s.validateStatementInSandBoxMode(stmtNode); err != nil {
+		return nil, err
+	}
 
 	// Uncorrelated subqueries will execute once when building plan, so we reset process info before building plan.
 	cmd32 := atomic.LoadUint32(&s.GetSessionVars().CommandValue)
@@ -1722,6 +1745,21 @@ func (s *session) validateStatementReadOnlyInStaleness(stmtNode ast.StmtNode) er
 	return nil
 }
 
+// validateStatementInSandBoxMode checks whether the statement is allowed when the password of the user has expired.
+// Only the statements to change the password and SET are allowed, USE is also allowed for the database specified in
+// the handshake. See https://dev.mysql.com/doc/refman/8.0/en/expired-password-handling.html.
+func (s *session) validateStatementInSandBoxMode(stmtNode ast.StmtNode) error {
+	vars := s.GetSessionVars()
+	if !vars.InSandBoxMode || vars.InRestrictedSQL {
+		return nil
+	}
+	switch stmtNode.(type) {
+	case *ast.SetPwdStmt, *ast.AlterUserStmt, *ast.SetStmt, *ast.UseStmt:
+		return nil
+	}
+	return ErrMustChangePassword.GenWithStackByArgs()
+}
+
 // querySpecialKeys contains the keys of special query, the special query will handled by handleQuerySpecial method.
 var querySpecialKeys = []fmt.Stringer{
 	executor.LoadDataVarKey,
@@ -1873,6 +1911,9 @@ func (s *session) rollbackOnError(ctx context.Context) {
 
 // PrepareStmt is used for executing prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.InSandBoxMode {
+		return 0, 0, nil, ErrMustChangePassword.GenWithStackByArgs()
+	}
 	if s.sessionVars.TxnCtx.InfoSchema == nil {
 		// We don't need to create a transaction for prepare statement, just get information schema will do.
 		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
@@ -2305,36 +2346,130 @@ func (s *session) AuthPluginForUser(user *auth.UserIdentity) (string, error) {
 }
 
 func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []byte) bool {
+	return s.AuthWithError(user, authentication, salt, nil) == nil
+}
+
+func (s *session) AuthWithError(user *auth.UserIdentity, authentication []byte, salt []byte, authConn privilege.AuthConn) error {
 	pm := privilege.GetPrivilegeManager(s)
 
 	// Check IP or localhost.
-	var success bool
-	user.AuthUsername, user.AuthHostname, success = pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars.TLSConnectionState)
-	if success {
+	info, err := pm.ConnectionVerificationWithError(user.Username, user.Hostname, authentication, salt, authConn, s.sessionVars)
+	user.AuthUsername, user.AuthHostname = info.AuthUser, info.AuthHost
+	if err == nil {
 		s.sessionVars.User = user
-		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
-		return true
-	} else if user.Hostname == variable.DefHostname {
-		return false
+		s.onAuthSucceeded(pm, info)
+		return nil
+	}
+	if user.Hostname == variable.DefHostname || !privileges.ErrAccessDenied.Equal(err) || info.AuthConnUsed {
+		s.onAuthFailed(info)
+		return err
 	}
 
 	// Check Hostname.
+	// The failed login is counted only once, for the account matched by the IP, or by the first hostname if no
+	// account is matched by the IP.
+	failedInfo := info
 	for _, addr := range s.getHostByIP(user.Hostname) {
-		u, h, success := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars.TLSConnectionState)
-		if success {
+		info, err1 := pm.ConnectionVerificationWithError(user.Username, addr, authentication, salt, authConn, s.sessionVars)
+		if err1 == nil {
 			s.sessionVars.User = &auth.UserIdentity{
 				Username:     user.Username,
 				Hostname:     addr,
-				AuthUsername: u,
-				AuthHostname: h,
+				AuthUsername: info.AuthUser,
+				AuthHostname: info.AuthHost,
 			}
-			s.sessionVars.ActiveRoles = pm.GetDefaultRoles(u, h)
+			s.onAuthSucceeded(pm, info)
+			return nil
+		}
+		if failedInfo.AuthUser == "" && failedInfo.AuthHost == "" {
+			failedInfo = info
+		}
+		if info.AuthConnUsed {
+			break
+		}
+	}
+	s.onAuthFailed(failedInfo)
+	return err
+}
+
+// onAuthSucceeded initializes the session for the authenticated user.
+func (s *session) onAuthSucceeded(pm privilege.Manager, info privilege.VerificationInfo) {
+	s.sessionVars.ActiveRoles = pm.GetDefaultRoles(info.AuthUser, info.AuthHost)
+	for _, role := range info.Roles {
+		if !containsRole(s.sessionVars.ActiveRoles, role) {
+			s.sessionVars.ActiveRoles = append(s.sessionVars.ActiveRoles, role)
+		}
+	}
+	s.sessionVars.InSandBoxMode = info.PasswordExpired
+	if !info.TrackFailedLogin {
+		return
+	}
+	// A successful login resets the failed login count.
+	stmt, err := s.ParseWithParams(context.TODO(), `UPDATE %n.%n SET Failed_login_count = 0, Password_locked_time = NULL
+		WHERE User = %? AND Host = %? AND (Failed_login_count > 0 OR Password_locked_time IS NOT NULL)`,
+		mysql.SystemDB, mysql.UserTable, info.AuthUser, info.AuthHost)
+	if err == nil {
+		_, _, err = s.ExecRestrictedStmt(context.TODO(), stmt)
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("reset failed login count failed", zap.String("user", info.AuthUser),
+			zap.String("host", info.AuthHost), zap.Error(err))
+	}
+}
+
+func containsRole(roles []*auth.RoleIdentity, role *auth.RoleIdentity) bool {
+	for _, r := range roles {
+		if r.Username == role.Username && r.Hostname == role.Hostname {
 			return true
 		}
 	}
 	return false
 }
 
+// onAuthFailed counts the failed login if the account tracks the failed logins, the account is locked when the count
+// reaches Failed_login_attempts, and the count is reset then. The lock is loaded by all the TiDB servers, while the
+// count is only kept in mysql.user.
+func (s *session) onAuthFailed(info privilege.VerificationInfo) {
+	if !info.TrackFailedLogin {
+		return
+	}
+	ctx := context.TODO()
+	logFailure := func(err error) {
+		logutil.BgLogger().Warn("track failed login failed", zap.String("user", info.AuthUser),
+			zap.String("host", info.AuthHost), zap.Error(err))
+	}
+	// Password_locked_time is assigned first, so both of the assignments use the old Failed_login_count.
+	stmt, err := s.ParseWithParams(ctx, `UPDATE %n.%n SET
+		Password_locked_time = IF(Failed_login_count + 1 >= Failed_login_attempts, NOW(), NULL),
+		Failed_login_count = IF(Failed_login_count + 1 >= Failed_login_attempts, 0, Failed_login_count + 1)
+		WHERE User = %? AND Host = %? AND Failed_login_attempts > 0 AND Password_lock_time != 0`,
+		mysql.SystemDB, mysql.UserTable, info.AuthUser, info.AuthHost)
+	if err != nil {
+		logFailure(err)
+		return
+	}
+	if _, _, err = s.ExecRestrictedStmt(ctx, stmt); err != nil {
+		logFailure(err)
+		return
+	}
+	stmt, err = s.ParseWithParams(ctx, `SELECT Password_locked_time IS NOT NULL FROM %n.%n WHERE User = %? AND Host = %?`,
+		mysql.SystemDB, mysql.UserTable, info.AuthUser, info.AuthHost)
+	if err != nil {
+		logFailure(err)
+		return
+	}
+	rows, _, err := s.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		logFailure(err)
+		return
+	}
+	if len(rows) > 0 && rows[0].GetInt64(0) == 1 {
+		logutil.BgLogger().Warn("account is locked by the failed logins", zap.String("user", info.AuthUser),
+			zap.String("host", info.AuthHost))
+		domain.GetDomain(s).NotifyUpdatePrivilege(s)
+	}
+}
+
 // AuthWithoutVerification is required by the ResetConnection RPC
 func (s *session) AuthWithoutVerification(user *auth.UserIdentity) bool {
 	pm := privilege.GetPrivilegeManager(s)
@@ -2647,6 +2782,12 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	se8, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.TTLJobLoop(se8)
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
 		if err != nil {
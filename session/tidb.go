@@ -354,4 +354,5 @@ func ResultSetToStringSlice(ctx context.Context, s Session, rs sqlexec.RecordSet
 // Session errors.
 var (
 	ErrForUpdateCantRetry = dbterror.ClassSession.NewStd(errno.ErrForUpdateCantRetry)
+	ErrMustChangePassword = dbterror.ClassSession.NewStd(errno.ErrMustChangePassword)
 )
@@ -44,6 +44,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/execdetails"
@@ -506,6 +507,10 @@ type SessionVars struct {
 	// User is the user identity with which the session login.
 	User *auth.UserIdentity
 
+	// InSandBoxMode indicates the password of the user has expired, the session only allows the statements to
+	// change the password until it is changed.
+	InSandBoxMode bool
+
 	// Port is the port of the connected socket
 	Port string
 
@@ -610,6 +615,9 @@ type SessionVars struct {
 	// EnableCorrelationAdjustment is used to indicate if correlation adjustment is enabled.
 	EnableCorrelationAdjustment bool
 
+	// EnableJoinHistEstimation indicates if the row count of equal joins is estimated by the histograms of join keys.
+	EnableJoinHistEstimation bool
+
 	// CorrelationExpFactor is used to control the heuristic approach of row count estimation when CorrelationThreshold is not met.
 	CorrelationExpFactor int
 
@@ -686,6 +694,10 @@ type SessionVars struct {
 	// EnableCascadesPlanner enables the cascades planner.
 	EnableCascadesPlanner bool
 
+	// ForeignKeyChecks indicates whether the FOREIGN KEY constraints are checked and
+	// the referential actions (CASCADE, SET NULL, ...) are applied on DML statements.
+	ForeignKeyChecks bool
+
 	// EnableWindowFunction enables the window function.
 	EnableWindowFunction bool
 
@@ -761,6 +773,9 @@ type SessionVars struct {
 	// ConnectionInfo indicates current connection info used by current session, only be lazy assigned by plugin.
 	ConnectionInfo *ConnectionInfo
 
+	// CursorStats is the statistics of the server-side cursors opened by current session, it's assigned by the server.
+	CursorStats *util.CursorStats
+
 	// use noop funcs or not
 	EnableNoopFuncs bool
 
@@ -840,6 +855,9 @@ type SessionVars struct {
 	// variable, and all public methods of SequenceState are currently-safe.
 	SequenceState *SequenceState
 
+	// SessionTracker tracks the changes of the session state reported to the client.
+	SessionTracker SessionTracker
+
 	// WindowingUseHighPrecision determines whether to compute window operations without loss of precision.
 	// see https://dev.mysql.com/doc/refman/8.0/en/window-function-optimization.html for more details.
 	WindowingUseHighPrecision bool
@@ -905,6 +923,10 @@ type SessionVars struct {
 	// AnalyzeVersion indicates how TiDB collect and use analyzed statistics.
 	AnalyzeVersion int
 
+	// AnalyzePredicateColumns indicates whether ANALYZE only collects the statistics of the predicate columns and the
+	// indexed columns.
+	AnalyzePredicateColumns bool
+
 	// EnableIndexMergeJoin indicates whether to enable index merge join.
 	EnableIndexMergeJoin bool
 
@@ -934,6 +956,9 @@ type SessionVars struct {
 	// EnableStableResultMode if stabilize query results.
 	EnableStableResultMode bool
 
+	// EnableNonPreparedPlanCache indicates whether to cache the plans of the statements sent by the text protocol.
+	EnableNonPreparedPlanCache bool
+
 	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
 	// It's nil if there is no local temporary table.
 	LocalTemporaryTables interface{}
@@ -1124,6 +1149,7 @@ func NewSessionVars() *SessionVars {
 		allowInSubqToJoinAndAgg:     DefOptInSubqToJoinAndAgg,
 		preferRangeScan:             DefOptPreferRangeScan,
 		EnableCorrelationAdjustment: DefOptEnableCorrelationAdjustment,
+		EnableJoinHistEstimation:    DefOptEnableJoinHistogramEstimation,
 		LimitPushDownThreshold:      DefOptLimitPushDownThreshold,
 		CorrelationThreshold:        DefOptCorrelationThreshold,
 		CorrelationExpFactor:        DefOptCorrelationExpFactor,
@@ -1155,6 +1181,7 @@ func NewSessionVars() *SessionVars {
 		MetricSchemaStep:            DefTiDBMetricSchemaStep,
 		MetricSchemaRangeDuration:   DefTiDBMetricSchemaRangeDuration,
 		SequenceState:               NewSequenceState(),
+		SessionTracker:              newSessionTracker(),
 		WindowingUseHighPrecision:   true,
 		PrevFoundInPlanCache:        DefTiDBFoundInPlanCache,
 		FoundInPlanCache:            DefTiDBFoundInPlanCache,
@@ -1176,6 +1203,7 @@ func NewSessionVars() *SessionVars {
 		Enable1PC:                   DefTiDBEnable1PC,
 		GuaranteeLinearizability:    DefTiDBGuaranteeLinearizability,
 		AnalyzeVersion:              DefTiDBAnalyzeVersion,
+		AnalyzePredicateColumns:     DefTiDBAnalyzePredicateColumns,
 		EnableIndexMergeJoin:        DefTiDBEnableIndexMergeJoin,
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
@@ -1502,6 +1530,7 @@ func (s *SessionVars) AddPreparedStmt(stmtID uint32, stmt interface{}) error {
 		metrics.PreparedStmtGauge.Set(float64(newPreparedStmtCount))
 	}
 	s.PreparedStmts[stmtID] = stmt
+	s.SessionTracker.MarkStateChanged()
 	return nil
 }
 
@@ -1512,6 +1541,7 @@ func (s *SessionVars) RemovePreparedStmt(stmtID uint32) {
 		return
 	}
 	delete(s.PreparedStmts, stmtID)
+	s.SessionTracker.MarkStateChanged()
 	afterMinus := atomic.AddInt64(&PreparedStmtCount, -1)
 	metrics.PreparedStmtGauge.Set(float64(afterMinus))
 }
@@ -0,0 +1,202 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"strings"
+)
+
+// SessionStateType is the type of the session state information sent to the client in the OK packets.
+type SessionStateType byte
+
+// The types of the session state information, see https://dev.mysql.com/doc/internals/en/packet-OK_Packet.html.
+const (
+	SessionStateSysVar             SessionStateType = 0x00
+	SessionStateSchema             SessionStateType = 0x01
+	SessionStateChanged            SessionStateType = 0x02
+	SessionStateTxnCharacteristics SessionStateType = 0x04
+	SessionStateTxnState           SessionStateType = 0x05
+)
+
+// The values of session_track_transaction_info.
+const (
+	// TrackTxnInfoState reports the state of the transaction.
+	TrackTxnInfoState = "STATE"
+	// TrackTxnInfoCharacteristics reports the characteristics of the transaction besides the state.
+	TrackTxnInfoCharacteristics = "CHARACTERISTICS"
+)
+
+// DefSessionTrackSystemVariables is the default value of session_track_system_variables, the same as MySQL.
+const DefSessionTrackSystemVariables = "time_zone,autocommit,character_set_client,character_set_results,character_set_connection"
+
+// noTxnState is the transaction state when there is no active transaction.
+const noTxnState = "________"
+
+// SessionStateChange is a piece of the session state information to report to the client.
+type SessionStateChange struct {
+	Type SessionStateType
+	// Name is only used by SessionStateSysVar.
+	Name  string
+	Value string
+}
+
+// SessionTracker tracks the changes of the session state, which are reported to the client in the OK packets if
+// the client supports CLIENT_SESSION_TRACK. The changes are kept until they are fetched, so a change made by a
+// statement returning a result set is reported in the next OK packet.
+type SessionTracker struct {
+	// The fields below are set by the session_track_* system variables.
+	trackAllSysVars  bool
+	trackSysVars     map[string]struct{}
+	trackSchema      bool
+	trackStateChange bool
+	trackTxnInfo     string
+
+	changedSysVars []string
+	schemaChanged  bool
+	stateChanged   bool
+	// The transaction state and characteristics reported last time, only the changes are reported.
+	lastTxnState           string
+	lastTxnCharacteristics string
+}
+
+func newSessionTracker() SessionTracker {
+	t := SessionTracker{
+		trackSchema:  true,
+		lastTxnState: noTxnState,
+	}
+	t.setTrackSysVars(DefSessionTrackSystemVariables)
+	return t
+}
+
+func (t *SessionTracker) setTrackSysVars(val string) {
+	t.trackAllSysVars = false
+	t.trackSysVars = make(map[string]struct{})
+	for _, name := range strings.Split(val, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "*" {
+			t.trackAllSysVars = true
+		} else if name != "" {
+			t.trackSysVars[name] = struct{}{}
+		}
+	}
+}
+
+// MarkSysVarChanged marks the session system variable changed by the client.
+func (t *SessionTracker) MarkSysVarChanged(name string) {
+	// The isolation level of the next transaction is reported as the transaction characteristics.
+	if name == TxnIsolationOneShot {
+		return
+	}
+	t.stateChanged = true
+	if _, ok := t.trackSysVars[name]; !ok && !t.trackAllSysVars {
+		return
+	}
+	for _, changed := range t.changedSysVars {
+		if changed == name {
+			return
+		}
+	}
+	t.changedSysVars = append(t.changedSysVars, name)
+}
+
+// MarkSchemaChanged marks the current database changed.
+func (t *SessionTracker) MarkSchemaChanged() {
+	t.schemaChanged = true
+	t.stateChanged = true
+}
+
+// MarkStateChanged marks the session state changed, such as the user variables and the prepared statements.
+func (t *SessionTracker) MarkStateChanged() {
+	t.stateChanged = true
+}
+
+// Reset discards the changes tracked.
+func (t *SessionTracker) Reset() {
+	t.changedSysVars = t.changedSysVars[:0]
+	t.schemaChanged = false
+	t.stateChanged = false
+}
+
+// FetchSessionStateChanges returns the session state information to report and resets the tracker.
+func (s *SessionVars) FetchSessionStateChanges() []SessionStateChange {
+	t := &s.SessionTracker
+	var changes []SessionStateChange
+	for _, name := range t.changedSysVars {
+		val, err := GetSessionOrGlobalSystemVar(s, name)
+		if err != nil {
+			continue
+		}
+		changes = append(changes, SessionStateChange{Type: SessionStateSysVar, Name: name, Value: val})
+	}
+	if t.trackSchema && t.schemaChanged {
+		changes = append(changes, SessionStateChange{Type: SessionStateSchema, Value: s.CurrentDB})
+	}
+	if t.trackStateChange && t.stateChanged {
+		changes = append(changes, SessionStateChange{Type: SessionStateChanged, Value: "1"})
+	}
+	if t.trackTxnInfo == TrackTxnInfoCharacteristics {
+		if characteristics := s.txnCharacteristics(); characteristics != t.lastTxnCharacteristics {
+			changes = append(changes, SessionStateChange{Type: SessionStateTxnCharacteristics, Value: characteristics})
+			t.lastTxnCharacteristics = characteristics
+		}
+	}
+	if t.trackTxnInfo == TrackTxnInfoState || t.trackTxnInfo == TrackTxnInfoCharacteristics {
+		if state := s.txnState(); state != t.lastTxnState {
+			changes = append(changes, SessionStateChange{Type: SessionStateTxnState, Value: state})
+			t.lastTxnState = state
+		}
+	}
+	t.Reset()
+	return changes
+}
+
+// txnState returns the state of the transaction in the format of MySQL. Only whether there is an active
+// transaction, and whether it has been written are tracked, the other flags are always '_'.
+func (s *SessionVars) txnState() string {
+	if !s.InTxn() {
+		return noTxnState
+	}
+	state := []byte(noTxnState)
+	if s.IsAutocommit() {
+		state[0] = 'T'
+	} else {
+		state[0] = 'I'
+	}
+	if len(s.TxnCtx.TableDeltaMap) > 0 {
+		state[4] = 'W'
+	}
+	return string(state)
+}
+
+// txnCharacteristics returns the statements to restart the current transaction with the same characteristics.
+func (s *SessionVars) txnCharacteristics() string {
+	var sb strings.Builder
+	if s.txnIsolationLevelOneShot.state != oneShotDef && s.txnIsolationLevelOneShot.value != "" {
+		sb.WriteString("SET TRANSACTION ISOLATION LEVEL ")
+		sb.WriteString(strings.ReplaceAll(s.txnIsolationLevelOneShot.value, "-", " "))
+		sb.WriteString(";")
+	}
+	if s.InTxn() && s.IsAutocommit() {
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		if s.TxnCtx.IsStaleness {
+			sb.WriteString("START TRANSACTION READ ONLY;")
+		} else {
+			sb.WriteString("START TRANSACTION;")
+		}
+	}
+	return sb.String()
+}
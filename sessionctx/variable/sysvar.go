@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/versioninfo"
@@ -660,16 +661,9 @@ var defaultSysVars = []*SysVar{
 		return nil
 	}},
 	{Scope: ScopeNone, Name: SystemTimeZone, Value: "CST"},
-	{Scope: ScopeGlobal | ScopeSession, Name: ForeignKeyChecks, Value: Off, Type: TypeBool, skipInit: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
-		if TiDBOptOn(normalizedValue) {
-			// TiDB does not yet support foreign keys.
-			// Return the original value in the warning, so that users are not confused.
-			vars.StmtCtx.AppendWarning(ErrUnsupportedValueForVar.GenWithStackByArgs(ForeignKeyChecks, originalValue))
-			return Off, nil
-		} else if !TiDBOptOn(normalizedValue) {
-			return Off, nil
-		}
-		return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(ForeignKeyChecks, originalValue)
+	{Scope: ScopeGlobal | ScopeSession, Name: ForeignKeyChecks, Value: Off, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.ForeignKeyChecks = TiDBOptOn(val)
+		return nil
 	}},
 	{Scope: ScopeNone, Name: Hostname, Value: DefHostname},
 	{Scope: ScopeSession, Name: Timestamp, Value: "", skipInit: true},
@@ -730,6 +724,28 @@ var defaultSysVars = []*SysVar{
 		}
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackSchema, Value: On, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.SessionTracker.trackSchema = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackStateChange, Value: Off, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.SessionTracker.trackStateChange = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackSystemVariables, Value: DefSessionTrackSystemVariables, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		names := strings.Split(normalizedValue, ",")
+		for i, name := range names {
+			names[i] = strings.ToLower(strings.TrimSpace(name))
+		}
+		return strings.Join(names, ","), nil
+	}, SetSession: func(s *SessionVars, val string) error {
+		s.SessionTracker.setTrackSysVars(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackTransactionInfo, Value: Off, Type: TypeEnum, PossibleValues: []string{Off, TrackTxnInfoState, TrackTxnInfoCharacteristics}, SetSession: func(s *SessionVars, val string) error {
+		s.SessionTracker.trackTxnInfo = val
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: CharsetDatabase, Value: mysql.DefaultCharset, skipInit: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		return checkCharacterSet(normalizedValue, CharsetDatabase)
 	}, SetSession: func(s *SessionVars, val string) error {
@@ -939,6 +955,10 @@ var defaultSysVars = []*SysVar{
 		s.EnableCorrelationAdjustment = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableJoinHistogramEstimation, Value: BoolToOnOff(DefOptEnableJoinHistogramEstimation), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableJoinHistEstimation = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationExpFactor, Value: strconv.Itoa(DefOptCorrelationExpFactor), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
 		s.CorrelationExpFactor = int(tidbOptInt64(val, DefOptCorrelationExpFactor))
 		return nil
@@ -1782,12 +1802,131 @@ var defaultSysVars = []*SysVar{
 		s.EnableGlobalTemporaryTable = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBTTLJobEnable, Value: BoolToOnOff(DefTiDBTTLJobEnable), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: TiDBTTLJobRunInterval, Value: DefTiDBTTLJobRunInterval, Type: TypeDuration, MinValue: int64(time.Minute), MaxValue: uint64(time.Hour * 24 * 365)},
+	{Scope: ScopeGlobal, Name: TiDBTTLScanBatchSize, Value: strconv.Itoa(DefTiDBTTLScanBatchSize), Type: TypeInt, MinValue: 1, MaxValue: 10240},
+	{Scope: ScopeGlobal, Name: TiDBTTLDeleteBatchSize, Value: strconv.Itoa(DefTiDBTTLDeleteBatchSize), Type: TypeInt, MinValue: 1, MaxValue: 10240},
+	{Scope: ScopeGlobal, Name: TiDBTTLDeleteRateLimit, Value: strconv.Itoa(DefTiDBTTLDeleteRateLimit), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64},
+	{Scope: ScopeGlobal, Name: TiDBEnableInstancePlanCache, Value: BoolToOnOff(DefTiDBEnableInstancePlanCache), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableInstancePlanCache.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		SetEnableInstancePlanCache(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBInstancePlanCacheMaxMemSize, Value: strconv.Itoa(DefTiDBInstancePlanCacheMaxMemSize), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(kvcache.InstancePlanCache.Quota(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		kvcache.InstancePlanCache.SetQuota(tidbOptInt64(val, DefTiDBInstancePlanCacheMaxMemSize))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnablePlanRegressionRollback, Value: BoolToOnOff(DefTiDBEnablePlanRegressionRollback), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnablePlanRegressionRollback.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnablePlanRegressionRollback.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableColumnTracking, Value: BoolToOnOff(DefTiDBEnableColumnTracking), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableColumnTracking.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnableColumnTracking.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzePredicateColumns, Value: BoolToOnOff(DefTiDBAnalyzePredicateColumns), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzePredicateColumns = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBPartitionStatsCacheMemQuota, Value: strconv.Itoa(DefTiDBPartitionStatsCacheMemQuota), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(PartitionStatsCacheMemQuota.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		PartitionStatsCacheMemQuota.Store(tidbOptInt64(val, DefTiDBPartitionStatsCacheMemQuota))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBStatsHistoryMaxVersions, Value: strconv.Itoa(DefTiDBStatsHistoryMaxVersions), Type: TypeUnsigned, MinValue: 0, MaxValue: 1024, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(StatsHistoryMaxVersions.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		StatsHistoryMaxVersions.Store(tidbOptInt64(val, DefTiDBStatsHistoryMaxVersions))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBServerMemoryLimit, Value: DefTiDBServerMemoryLimit, Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if _, err := ParseServerMemoryLimit(normalizedValue); err != nil {
+			return normalizedValue, err
+		}
+		return strings.TrimSpace(normalizedValue), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		limit, err := ParseServerMemoryLimit(val)
+		if err != nil {
+			return err
+		}
+		ServerMemoryLimit.Store(limit)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBServerMemoryLimitGCTrigger, Value: strconv.FormatFloat(DefTiDBServerMemoryLimitGCTrigger, 'f', -1, 64), Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		// The range is exclusive: GOGC would be tuned down all the time if the trigger was 0,
+		// and it would be tuned only after the limit is exceeded if the trigger was 1.
+		val, err := strconv.ParseFloat(normalizedValue, 64)
+		if err != nil {
+			return normalizedValue, ErrWrongTypeForVar.GenWithStackByArgs(TiDBServerMemoryLimitGCTrigger)
+		}
+		if val <= 0 || val >= 1 {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBServerMemoryLimitGCTrigger, originalValue)
+		}
+		return normalizedValue, nil
+	}, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatFloat(ServerMemoryLimitGCTrigger.Load(), 'f', -1, 64), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		ServerMemoryLimitGCTrigger.Store(tidbOptFloat64(val, DefTiDBServerMemoryLimitGCTrigger))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBServerMemoryLimitSessMinSize, Value: strconv.Itoa(DefTiDBServerMemoryLimitSessMinSize), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(ServerMemoryLimitSessMinSize.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		ServerMemoryLimitSessMinSize.Store(uint64(tidbOptInt64(val, DefTiDBServerMemoryLimitSessMinSize)))
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: SkipNameResolve, Value: Off, Type: TypeBool},
 	{Scope: ScopeGlobal, Name: DefaultAuthPlugin, Value: mysql.AuthNativePassword, Type: TypeEnum, PossibleValues: []string{mysql.AuthNativePassword, mysql.AuthCachingSha2Password}},
+	{Scope: ScopeGlobal, Name: DefaultPasswordLifetime, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint16, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: PasswordHistory, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: PasswordReuseInterval, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: ValidatePasswordEnable, Value: Off, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: ValidatePasswordPolicy, Value: "MEDIUM", Type: TypeEnum, PossibleValues: []string{"LOW", "MEDIUM", "STRONG"}},
+	{Scope: ScopeGlobal, Name: ValidatePasswordCheckUserName, Value: Off, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: ValidatePasswordLength, Value: "8", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: ValidatePasswordNumberCount, Value: "1", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: ValidatePasswordMixedCaseCount, Value: "1", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: ValidatePasswordSpecialCharCount, Value: "1", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, AutoConvertOutOfRange: true},
+	{Scope: ScopeGlobal, Name: ValidatePasswordDictionaryFile, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleServerHost, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleServerPort, Value: "389", Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxUint16},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleTLS, Value: Off, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleCAPath, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindBaseDN, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindRootDN, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindRootPwd, Value: "", Hidden: true},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleUserSearchAttr, Value: "uid"},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleGroupSearchAttr, Value: "cn"},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleGroupSearchFilter, Value: "(|(&(objectClass=posixGroup)(memberUid={UA}))(&(objectClass=group)(member={UD})))"},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleGroupRoleMapping, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLServerHost, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLServerPort, Value: "389", Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxUint16},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLTLS, Value: Off, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLCAPath, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLBindBaseDN, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLBindRootDN, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLBindRootPwd, Value: "", Hidden: true},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLUserSearchAttr, Value: "uid"},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLGroupSearchAttr, Value: "cn"},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLGroupSearchFilter, Value: "(|(&(objectClass=posixGroup)(memberUid={UA}))(&(objectClass=group)(member={UD})))"},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLGroupRoleMapping, Value: ""},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLAuthMethodName, Value: "SCRAM-SHA-1", Type: TypeEnum, PossibleValues: []string{"SCRAM-SHA-1", "SCRAM-SHA-256"}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableOrderedResultMode, Value: BoolToOnOff(DefTiDBEnableOrderedResultMode), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableStableResultMode = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableNonPreparedPlanCache, Value: BoolToOnOff(DefTiDBEnableNonPreparedPlanCache), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableNonPreparedPlanCache = TiDBOptOn(val)
+		return nil
+	}},
 }
 
 // FeedbackProbability points to the FeedbackProbability in statistics package.
@@ -1874,6 +2013,14 @@ const (
 	LowerCaseTableNames = "lower_case_table_names"
 	// SessionTrackGtids is the name for 'session_track_gtids' system variable.
 	SessionTrackGtids = "session_track_gtids"
+	// SessionTrackSchema is the name for 'session_track_schema' system variable.
+	SessionTrackSchema = "session_track_schema"
+	// SessionTrackStateChange is the name for 'session_track_state_change' system variable.
+	SessionTrackStateChange = "session_track_state_change"
+	// SessionTrackSystemVariables is the name for 'session_track_system_variables' system variable.
+	SessionTrackSystemVariables = "session_track_system_variables"
+	// SessionTrackTransactionInfo is the name for 'session_track_transaction_info' system variable.
+	SessionTrackTransactionInfo = "session_track_transaction_info"
 	// OldPasswords is the name for 'old_passwords' system variable.
 	OldPasswords = "old_passwords"
 	// MaxConnections is the name for 'max_connections' system variable.
@@ -1910,6 +2057,68 @@ const (
 	ValidatePasswordNumberCount = "validate_password_number_count"
 	// ValidatePasswordLength is the name of 'validate_password_length' system variable.
 	ValidatePasswordLength = "validate_password_length"
+	// ValidatePasswordEnable is the name of 'validate_password_enable' system variable.
+	ValidatePasswordEnable = "validate_password_enable"
+	// ValidatePasswordPolicy is the name of 'validate_password_policy' system variable.
+	ValidatePasswordPolicy = "validate_password_policy"
+	// ValidatePasswordMixedCaseCount is the name of 'validate_password_mixed_case_count' system variable.
+	ValidatePasswordMixedCaseCount = "validate_password_mixed_case_count"
+	// ValidatePasswordSpecialCharCount is the name of 'validate_password_special_char_count' system variable.
+	ValidatePasswordSpecialCharCount = "validate_password_special_char_count"
+	// ValidatePasswordDictionaryFile is the name of 'validate_password_dictionary_file' system variable.
+	ValidatePasswordDictionaryFile = "validate_password_dictionary_file"
+	// DefaultPasswordLifetime is the name of 'default_password_lifetime' system variable.
+	DefaultPasswordLifetime = "default_password_lifetime"
+	// PasswordHistory is the name of 'password_history' system variable.
+	PasswordHistory = "password_history"
+	// PasswordReuseInterval is the name of 'password_reuse_interval' system variable.
+	PasswordReuseInterval = "password_reuse_interval"
+	// AuthenticationLDAPSimpleServerHost is the name of 'authentication_ldap_simple_server_host' system variable.
+	AuthenticationLDAPSimpleServerHost = "authentication_ldap_simple_server_host"
+	// AuthenticationLDAPSimpleServerPort is the name of 'authentication_ldap_simple_server_port' system variable.
+	AuthenticationLDAPSimpleServerPort = "authentication_ldap_simple_server_port"
+	// AuthenticationLDAPSimpleTLS is the name of 'authentication_ldap_simple_tls' system variable.
+	AuthenticationLDAPSimpleTLS = "authentication_ldap_simple_tls"
+	// AuthenticationLDAPSimpleCAPath is the name of 'authentication_ldap_simple_ca_path' system variable.
+	AuthenticationLDAPSimpleCAPath = "authentication_ldap_simple_ca_path"
+	// AuthenticationLDAPSimpleBindBaseDN is the name of 'authentication_ldap_simple_bind_base_dn' system variable.
+	AuthenticationLDAPSimpleBindBaseDN = "authentication_ldap_simple_bind_base_dn"
+	// AuthenticationLDAPSimpleBindRootDN is the name of 'authentication_ldap_simple_bind_root_dn' system variable.
+	AuthenticationLDAPSimpleBindRootDN = "authentication_ldap_simple_bind_root_dn"
+	// AuthenticationLDAPSimpleBindRootPwd is the name of 'authentication_ldap_simple_bind_root_pwd' system variable.
+	AuthenticationLDAPSimpleBindRootPwd = "authentication_ldap_simple_bind_root_pwd"
+	// AuthenticationLDAPSimpleUserSearchAttr is the name of 'authentication_ldap_simple_user_search_attr' system variable.
+	AuthenticationLDAPSimpleUserSearchAttr = "authentication_ldap_simple_user_search_attr"
+	// AuthenticationLDAPSimpleGroupSearchAttr is the name of 'authentication_ldap_simple_group_search_attr' system variable.
+	AuthenticationLDAPSimpleGroupSearchAttr = "authentication_ldap_simple_group_search_attr"
+	// AuthenticationLDAPSimpleGroupSearchFilter is the name of 'authentication_ldap_simple_group_search_filter' system variable.
+	AuthenticationLDAPSimpleGroupSearchFilter = "authentication_ldap_simple_group_search_filter"
+	// AuthenticationLDAPSimpleGroupRoleMapping is the name of 'authentication_ldap_simple_group_role_mapping' system variable.
+	AuthenticationLDAPSimpleGroupRoleMapping = "authentication_ldap_simple_group_role_mapping"
+	// AuthenticationLDAPSASLServerHost is the name of 'authentication_ldap_sasl_server_host' system variable.
+	AuthenticationLDAPSASLServerHost = "authentication_ldap_sasl_server_host"
+	// AuthenticationLDAPSASLServerPort is the name of 'authentication_ldap_sasl_server_port' system variable.
+	AuthenticationLDAPSASLServerPort = "authentication_ldap_sasl_server_port"
+	// AuthenticationLDAPSASLTLS is the name of 'authentication_ldap_sasl_tls' system variable.
+	AuthenticationLDAPSASLTLS = "authentication_ldap_sasl_tls"
+	// AuthenticationLDAPSASLCAPath is the name of 'authentication_ldap_sasl_ca_path' system variable.
+	AuthenticationLDAPSASLCAPath = "authentication_ldap_sasl_ca_path"
+	// AuthenticationLDAPSASLBindBaseDN is the name of 'authentication_ldap_sasl_bind_base_dn' system variable.
+	AuthenticationLDAPSASLBindBaseDN = "authentication_ldap_sasl_bind_base_dn"
+	// AuthenticationLDAPSASLBindRootDN is the name of 'authentication_ldap_sasl_bind_root_dn' system variable.
+	AuthenticationLDAPSASLBindRootDN = "authentication_ldap_sasl_bind_root_dn"
+	// AuthenticationLDAPSASLBindRootPwd is the name of 'authentication_ldap_sasl_bind_root_pwd' system variable.
+	AuthenticationLDAPSASLBindRootPwd = "authentication_ldap_sasl_bind_root_pwd"
+	// AuthenticationLDAPSASLUserSearchAttr is the name of 'authentication_ldap_sasl_user_search_attr' system variable.
+	AuthenticationLDAPSASLUserSearchAttr = "authentication_ldap_sasl_user_search_attr"
+	// AuthenticationLDAPSASLGroupSearchAttr is the name of 'authentication_ldap_sasl_group_search_attr' system variable.
+	AuthenticationLDAPSASLGroupSearchAttr = "authentication_ldap_sasl_group_search_attr"
+	// AuthenticationLDAPSASLGroupSearchFilter is the name of 'authentication_ldap_sasl_group_search_filter' system variable.
+	AuthenticationLDAPSASLGroupSearchFilter = "authentication_ldap_sasl_group_search_filter"
+	// AuthenticationLDAPSASLGroupRoleMapping is the name of 'authentication_ldap_sasl_group_role_mapping' system variable.
+	AuthenticationLDAPSASLGroupRoleMapping = "authentication_ldap_sasl_group_role_mapping"
+	// AuthenticationLDAPSASLAuthMethodName is the name of 'authentication_ldap_sasl_auth_method_name' system variable.
+	AuthenticationLDAPSASLAuthMethodName = "authentication_ldap_sasl_auth_method_name"
 	// Version is the name of 'version' system variable.
 	Version = "version"
 	// VersionComment is the name of 'version_comment' system variable.
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 func TestT(t *testing.T) {
@@ -402,14 +403,19 @@ func (*testSysVarSuite) TestTimeZone(c *C) {
 func (*testSysVarSuite) TestForeignKeyChecks(c *C) {
 	sv := GetSysVar(ForeignKeyChecks)
 	vars := NewSessionVars()
+	c.Assert(vars.ForeignKeyChecks, IsFalse)
 
 	val, err := sv.Validate(vars, "on", ScopeSession)
 	c.Assert(err, IsNil)
-	c.Assert(val, Equals, "OFF") // warns and refuses to set ON.
-
-	warn := vars.StmtCtx.GetWarnings()[0].Err
-	c.Assert(warn.Error(), Equals, "[variable:8047]variable 'foreign_key_checks' does not yet support value: on")
+	c.Assert(val, Equals, "ON")
+	c.Assert(sv.SetSessionFromHook(vars, val), IsNil)
+	c.Assert(vars.ForeignKeyChecks, IsTrue)
 
+	val, err = sv.Validate(vars, "0", ScopeSession)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "OFF")
+	c.Assert(sv.SetSessionFromHook(vars, val), IsNil)
+	c.Assert(vars.ForeignKeyChecks, IsFalse)
 }
 
 func (*testSysVarSuite) TestTxnIsolation(c *C) {
@@ -661,3 +667,34 @@ func (*testSysVarSuite) TestValidateWithRelaxedValidation(c *C) {
 	val := sv.ValidateWithRelaxedValidation(vars, "1", ScopeGlobal)
 	c.Assert(val, Equals, "ON")
 }
+
+func (*testSysVarSuite) TestServerMemoryLimit(c *C) {
+	vars := NewSessionVars()
+	sv := GetSysVar(TiDBServerMemoryLimit)
+	for _, val := range []string{"abc", "-1", "100", "0%", "100%", "x%"} {
+		_, err := sv.Validate(vars, val, ScopeGlobal)
+		c.Assert(err, NotNil, Commentf("%s", val))
+	}
+	val, err := sv.Validate(vars, " 1073741824 ", ScopeGlobal)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "1073741824")
+	c.Assert(sv.SetGlobalFromHook(vars, val, false), IsNil)
+	c.Assert(ServerMemoryLimit.Load(), Equals, uint64(1<<30))
+
+	total, err := memory.MemTotal()
+	c.Assert(err, IsNil)
+	c.Assert(sv.SetGlobalFromHook(vars, "50%", false), IsNil)
+	c.Assert(ServerMemoryLimit.Load(), Equals, total/2)
+	c.Assert(sv.SetGlobalFromHook(vars, "0", false), IsNil)
+	c.Assert(ServerMemoryLimit.Load(), Equals, uint64(0))
+
+	sv = GetSysVar(TiDBServerMemoryLimitGCTrigger)
+	for _, val := range []string{"0", "1", "1.5", "-0.5", "abc"} {
+		_, err = sv.Validate(vars, val, ScopeGlobal)
+		c.Assert(err, NotNil, Commentf("%s", val))
+	}
+	c.Assert(sv.SetGlobalFromHook(vars, "0.8", false), IsNil)
+	c.Assert(ServerMemoryLimitGCTrigger.Load(), Equals, 0.8)
+	c.Assert(sv.SetGlobalFromHook(vars, sv.Value, false), IsNil)
+	c.Assert(ServerMemoryLimitGCTrigger.Load(), Equals, DefTiDBServerMemoryLimitGCTrigger)
+}
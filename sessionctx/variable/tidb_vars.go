@@ -231,6 +231,10 @@ const (
 	// tidb_opt_enable_correlation_adjustment is used to indicates if enable correlation adjustment.
 	TiDBOptEnableCorrelationAdjustment = "tidb_opt_enable_correlation_adjustment"
 
+	// tidb_opt_enable_join_histogram_estimation indicates if the row count of equal joins is estimated by aligning the
+	// histograms and TopN of the join keys.
+	TiDBOptEnableJoinHistogramEstimation = "tidb_opt_enable_join_histogram_estimation"
+
 	// tidb_opt_limit_push_down_threshold determines if push Limit or TopN down to TiKV forcibly.
 	TiDBOptLimitPushDownThreshold = "tidb_opt_limit_push_down_threshold"
 
@@ -584,6 +588,9 @@ const (
 
 	// TiDBEnableOrderedResultMode indicates if stabilize query results.
 	TiDBEnableOrderedResultMode = "tidb_enable_ordered_result_mode"
+
+	// TiDBEnableNonPreparedPlanCache indicates whether to cache the plans of the statements sent by the text protocol.
+	TiDBEnableNonPreparedPlanCache = "tidb_enable_non_prepared_plan_cache"
 )
 
 // TiDB vars that have only global scope
@@ -601,6 +608,45 @@ const (
 	TiDBGCScanLockMode = "tidb_gc_scan_lock_mode"
 	// TiDBEnableEnhancedSecurity restricts SUPER users from certain operations.
 	TiDBEnableEnhancedSecurity = "tidb_enable_enhanced_security"
+	// TiDBTTLJobEnable enables or disables the background job which deletes the expired rows of the TTL tables.
+	TiDBTTLJobEnable = "tidb_ttl_job_enable"
+	// TiDBTTLJobRunInterval sets the interval between two TTL jobs of a table.
+	TiDBTTLJobRunInterval = "tidb_ttl_job_run_interval"
+	// TiDBTTLScanBatchSize sets the max number of expired rows read by a scan statement of the TTL job.
+	TiDBTTLScanBatchSize = "tidb_ttl_scan_batch_size"
+	// TiDBTTLDeleteBatchSize sets the max number of rows deleted by a delete statement of the TTL job.
+	TiDBTTLDeleteBatchSize = "tidb_ttl_delete_batch_size"
+	// TiDBTTLDeleteRateLimit sets the max number of delete statements executed per second by the TTL job. 0 = no limit
+	TiDBTTLDeleteRateLimit = "tidb_ttl_delete_rate_limit"
+	// TiDBEnableInstancePlanCache indicates whether the plans are cached in the plan cache shared by all the sessions.
+	TiDBEnableInstancePlanCache = "tidb_enable_instance_plan_cache"
+	// TiDBInstancePlanCacheMaxMemSize sets the memory quota of the instance plan cache in bytes.
+	TiDBInstancePlanCacheMaxMemSize = "tidb_instance_plan_cache_max_mem_size"
+	// TiDBEnablePlanRegressionRollback indicates whether to create bindings for the previous plans automatically when
+	// the plans of the statements regress.
+	TiDBEnablePlanRegressionRollback = "tidb_enable_plan_regression_rollback"
+	// TiDBEnableColumnTracking indicates whether to record the columns used in the predicates to mysql.column_stats_usage.
+	TiDBEnableColumnTracking = "tidb_enable_column_tracking"
+	// TiDBAnalyzePredicateColumns indicates whether ANALYZE only collects the statistics of the predicate columns and
+	// the indexed columns. The global value is used by auto analyze.
+	TiDBAnalyzePredicateColumns = "tidb_analyze_predicate_columns"
+	// TiDBPartitionStatsCacheMemQuota sets the memory quota in bytes of the cached partition-level statistics, which
+	// are reused to merge the global-level statistics of the partitioned tables.
+	TiDBPartitionStatsCacheMemQuota = "tidb_partition_stats_cache_mem_quota"
+	// TiDBStatsHistoryMaxVersions sets the number of the versions of the stats of each table kept in
+	// mysql.stats_history after ANALYZE. 0 means the historical stats are not recorded.
+	TiDBStatsHistoryMaxVersions = "tidb_stats_history_max_versions"
+	// TiDBServerMemoryLimit sets the memory limit of the tidb-server instance, either in bytes or as a percentage
+	// of the total memory like "80%". When the limit is exceeded, the query using the most memory is canceled.
+	// 0 means no limit. A percentage is resolved when the variable is set or loaded, so later changes of the
+	// total memory, such as the cgroup limit, are ignored until the variable is set again.
+	TiDBServerMemoryLimit = "tidb_server_memory_limit"
+	// TiDBServerMemoryLimitGCTrigger sets the ratio of tidb_server_memory_limit from which GOGC is tuned down,
+	// so that the garbage collection is triggered before the heap reaches the limit.
+	TiDBServerMemoryLimitGCTrigger = "tidb_server_memory_limit_gc_trigger"
+	// TiDBServerMemoryLimitSessMinSize sets the minimum memory usage in bytes of the queries which can be canceled
+	// when tidb_server_memory_limit is exceeded.
+	TiDBServerMemoryLimitSessMinSize = "tidb_server_memory_limit_sess_min_size"
 )
 
 // Default TiDB system variable values.
@@ -627,6 +673,7 @@ const (
 	DefOptMPPOuterJoinFixedBuildSide      = false
 	DefOptWriteRowID                      = false
 	DefOptEnableCorrelationAdjustment     = true
+	DefOptEnableJoinHistogramEstimation   = false
 	DefOptLimitPushDownThreshold          = 100
 	DefOptCorrelationThreshold            = 0.9
 	DefOptCorrelationExpFactor            = 1
@@ -745,6 +792,22 @@ const (
 	DefTMPTableSize                       = 16777216
 	DefTiDBEnableLocalTxn                 = false
 	DefTiDBEnableOrderedResultMode        = false
+	DefTiDBEnableNonPreparedPlanCache     = false
+	DefTiDBTTLJobEnable                   = true
+	DefTiDBTTLJobRunInterval              = "1h0m0s"
+	DefTiDBTTLScanBatchSize               = 500
+	DefTiDBTTLDeleteBatchSize             = 100
+	DefTiDBTTLDeleteRateLimit             = 0
+	DefTiDBEnableInstancePlanCache        = false
+	DefTiDBInstancePlanCacheMaxMemSize    = 100 << 20
+	DefTiDBEnablePlanRegressionRollback   = false
+	DefTiDBEnableColumnTracking           = false
+	DefTiDBAnalyzePredicateColumns        = false
+	DefTiDBPartitionStatsCacheMemQuota    = 256 << 20
+	DefTiDBStatsHistoryMaxVersions        = 0
+	DefTiDBServerMemoryLimit              = "0"
+	DefTiDBServerMemoryLimitGCTrigger     = 0.7
+	DefTiDBServerMemoryLimitSessMinSize   = 128 << 20
 )
 
 // Process global variables.
@@ -779,6 +842,22 @@ var (
 	}
 	EnableLocalTxn     = atomic.NewBool(DefTiDBEnableLocalTxn)
 	RestrictedReadOnly = atomic.NewBool(DefTiDBRestrictedReadOnly)
+	// EnableInstancePlanCache indicates whether the instance plan cache is enabled.
+	EnableInstancePlanCache = atomic.NewBool(DefTiDBEnableInstancePlanCache)
+	// EnablePlanRegressionRollback indicates whether the plan regressions are rolled back by bindings.
+	EnablePlanRegressionRollback = atomic.NewBool(DefTiDBEnablePlanRegressionRollback)
+	// EnableColumnTracking indicates whether the columns used in the predicates are recorded.
+	EnableColumnTracking = atomic.NewBool(DefTiDBEnableColumnTracking)
+	// PartitionStatsCacheMemQuota is the memory quota of the cached partition-level statistics.
+	PartitionStatsCacheMemQuota = atomic.NewInt64(DefTiDBPartitionStatsCacheMemQuota)
+	// StatsHistoryMaxVersions is the number of the versions of the historical stats kept for each table.
+	StatsHistoryMaxVersions = atomic.NewInt64(DefTiDBStatsHistoryMaxVersions)
+	// ServerMemoryLimit is the memory limit of the tidb-server in bytes, 0 means no limit.
+	ServerMemoryLimit = atomic.NewUint64(0)
+	// ServerMemoryLimitGCTrigger is the ratio of ServerMemoryLimit from which GOGC is tuned down.
+	ServerMemoryLimitGCTrigger = atomic.NewFloat64(DefTiDBServerMemoryLimitGCTrigger)
+	// ServerMemoryLimitSessMinSize is the minimum memory usage of the queries canceled by ServerMemoryLimit.
+	ServerMemoryLimitSessMinSize = atomic.NewUint64(DefTiDBServerMemoryLimitSessMinSize)
 )
 
 // TopSQL is the variable for control top sql feature.
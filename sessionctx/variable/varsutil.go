@@ -26,6 +26,8 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/timeutil"
 	"github.com/tikv/client-go/v2/oracle"
 )
@@ -89,6 +91,41 @@ func SetMaxDeltaSchemaCount(cnt int64) {
 	atomic.StoreInt64(&maxDeltaSchemaCount, cnt)
 }
 
+// SetEnableInstancePlanCache enables or disables the instance plan cache, the cached plans are purged when it's
+// disabled.
+func SetEnableInstancePlanCache(enable bool) {
+	EnableInstancePlanCache.Store(enable)
+	if !enable {
+		kvcache.InstancePlanCache.DeleteAll()
+	}
+}
+
+// minServerMemoryLimit is the minimum value of tidb_server_memory_limit in bytes, a smaller limit makes
+// the queries canceled too easily.
+const minServerMemoryLimit = 512 << 20
+
+// ParseServerMemoryLimit parses the value of tidb_server_memory_limit into bytes. The value is either a
+// number of bytes, or a percentage of the total memory like "80%". 0 means no limit.
+func ParseServerMemoryLimit(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(s[:len(s)-1]), 64)
+		if err != nil || percent <= 0 || percent >= 100 {
+			return 0, ErrWrongValueForVar.GenWithStackByArgs(TiDBServerMemoryLimit, s)
+		}
+		total, err := memory.MemTotal()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		return uint64(float64(total) * percent / 100), nil
+	}
+	limit, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || (limit > 0 && limit < minServerMemoryLimit) {
+		return 0, ErrWrongValueForVar.GenWithStackByArgs(TiDBServerMemoryLimit, s)
+	}
+	return limit, nil
+}
+
 // GetMaxDeltaSchemaCount gets maxDeltaSchemaCount size.
 func GetMaxDeltaSchemaCount() int64 {
 	return atomic.LoadInt64(&maxDeltaSchemaCount)
@@ -211,7 +248,11 @@ func SetSessionSystemVar(vars *SessionVars, name string, value string) error {
 	if err != nil {
 		return err
 	}
-	return vars.SetSystemVar(name, sVal)
+	if err = vars.SetSystemVar(name, sVal); err != nil {
+		return err
+	}
+	vars.SessionTracker.MarkSysVarChanged(sysVar.Name)
+	return nil
 }
 
 // SetStmtVar sets system variable and updates SessionVars states.
@@ -137,12 +137,13 @@ func (s *testVarsutilSuite) TestVarsutil(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(val, Equals, "OFF")
 
-	// 1/ON is not supported (generates a warning and sets to OFF)
+	// 1 converts to ON
 	err = SetSessionSystemVar(v, "foreign_key_checks", "1")
 	c.Assert(err, IsNil)
 	val, err = GetSessionOrGlobalSystemVar(v, "foreign_key_checks")
 	c.Assert(err, IsNil)
-	c.Assert(val, Equals, "OFF")
+	c.Assert(val, Equals, "ON")
+	c.Assert(v.ForeignKeyChecks, IsTrue)
 
 	err = SetSessionSystemVar(v, "sql_mode", "strict_trans_tables")
 	c.Assert(err, IsNil)
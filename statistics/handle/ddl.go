@@ -46,7 +46,8 @@ func (h *Handle) HandleDDLEvent(t *util.Event) error {
 				return err
 			}
 		}
-	case model.ActionAddTablePartition, model.ActionTruncateTablePartition:
+	case model.ActionAddTablePartition, model.ActionTruncateTablePartition,
+		util.ActionReorganizePartition, util.ActionAlterTablePartitioning, util.ActionRemovePartitioning:
 		for _, def := range t.PartInfo.Definitions {
 			if err := h.insertTableStats2KV(t.TableInfo, def.ID); err != nil {
 				return err
@@ -196,7 +196,10 @@ func (h *Handle) LoadStatsFromJSON(is infoschema.InfoSchema, jsonTbl *JSONTable)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	tableInfo := table.Meta()
+	return h.loadTableStatsFromJSON(is, table.Meta(), jsonTbl)
+}
+
+func (h *Handle) loadTableStatsFromJSON(is infoschema.InfoSchema, tableInfo *model.TableInfo, jsonTbl *JSONTable) error {
 	pi := tableInfo.GetPartitionInfo()
 	if pi == nil || jsonTbl.Partitions == nil {
 		err := h.loadStatsFromJSON(tableInfo, tableInfo.ID, jsonTbl)
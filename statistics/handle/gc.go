@@ -72,6 +72,14 @@ func (h *Handle) gcTableStats(is infoschema.InfoSchema, physicalID int64) error
 	h.mu.Unlock()
 	if !ok {
 		logutil.BgLogger().Info("remove stats in GC due to dropped table", zap.Int64("table_id", physicalID))
+		// The historical stats and the lock are kept when the stats are dropped by DROP STATS, so they're only
+		// removed with the table.
+		if _, _, err = h.execRestrictedSQL(ctx, "delete from mysql.stats_history where table_id = %?", physicalID); err != nil {
+			return errors.Trace(err)
+		}
+		if _, _, err = h.execRestrictedSQL(ctx, "delete from mysql.stats_table_locked where table_id = %?", physicalID); err != nil {
+			return errors.Trace(err)
+		}
 		return errors.Trace(h.DeleteTableStatsFromKV([]int64{physicalID}))
 	}
 	tblInfo := tbl.Meta()
@@ -182,6 +190,9 @@ func (h *Handle) deleteHistStatsFromKV(physicalID int64, histID int64, isIndex i
 // DeleteTableStatsFromKV deletes table statistics from kv.
 // A statsID refers to statistic of a table or a partition.
 func (h *Handle) DeleteTableStatsFromKV(statsIDs []int64) (err error) {
+	for _, statsID := range statsIDs {
+		h.partitionStatsCache.remove(statsID)
+	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	exec := h.mu.ctx.(sqlexec.SQLExecutor)
@@ -221,6 +232,9 @@ func (h *Handle) DeleteTableStatsFromKV(statsIDs []int64) (err error) {
 		if _, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_fm_sketch where table_id = %?", statsID); err != nil {
 			return err
 		}
+		if _, err = exec.ExecuteInternal(ctx, "delete from mysql.column_stats_usage where table_id = %?", statsID); err != nil {
+			return err
+		}
 	}
 	return nil
 }
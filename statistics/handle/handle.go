@@ -18,8 +18,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -100,6 +103,10 @@ type Handle struct {
 	listHead *SessionStatsCollector
 	// globalMap contains all the delta map from collectors when we dump them to KV.
 	globalMap tableDeltaMap
+	// colMap contains all the column stats usage information from collectors when we dump them to KV.
+	colMap colStatsUsageMap
+	// partitionStatsCache caches the partition-level stats used to merge the global-level stats.
+	partitionStatsCache *partitionStatsCache
 	// feedback is used to store query feedback info.
 	feedback *statistics.QueryFeedbackMap
 
@@ -143,7 +150,11 @@ func (h *Handle) execRestrictedSQLWithStatsVer(ctx context.Context, statsVer int
 		if err != nil {
 			return nil, nil, errors.Trace(err)
 		}
-		return exec.ExecRestrictedStmt(ctx, stmt, execOptionForAnalyze[statsVer])
+		opts := []sqlexec.OptionFuncAlias{execOptionForAnalyze[statsVer]}
+		if h.mu.ctx.GetSessionVars().AnalyzePredicateColumns {
+			opts = append(opts, sqlexec.ExecOptionAnalyzePredicateColumns)
+		}
+		return exec.ExecRestrictedStmt(ctx, stmt, opts...)
 	})
 }
 
@@ -174,6 +185,8 @@ func (h *Handle) Clear() {
 	h.mu.ctx.GetSessionVars().SetProjectionConcurrency(0)
 	h.listHead = &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap)}
 	h.globalMap = make(tableDeltaMap)
+	h.colMap = make(colStatsUsageMap)
+	h.partitionStatsCache.clear()
 	h.mu.rateMap = make(errorRateDeltaMap)
 	h.mu.Unlock()
 }
@@ -186,12 +199,14 @@ type sessionPool interface {
 // NewHandle creates a Handle for update stats.
 func NewHandle(ctx sessionctx.Context, lease time.Duration, pool sessionPool) (*Handle, error) {
 	handle := &Handle{
-		ddlEventCh:       make(chan *util.Event, 100),
-		listHead:         &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap)},
-		globalMap:        make(tableDeltaMap),
-		feedback:         statistics.NewQueryFeedbackMap(),
-		idxUsageListHead: &SessionIndexUsageCollector{mapper: make(indexUsageMap)},
-		pool:             pool,
+		ddlEventCh:          make(chan *util.Event, 100),
+		listHead:            &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap)},
+		globalMap:           make(tableDeltaMap),
+		colMap:              make(colStatsUsageMap),
+		partitionStatsCache: newPartitionStatsCache(),
+		feedback:            statistics.NewQueryFeedbackMap(),
+		idxUsageListHead:    &SessionIndexUsageCollector{mapper: make(indexUsageMap)},
+		pool:                pool,
 	}
 	handle.lease.Store(lease)
 	handle.pool = pool
@@ -304,7 +319,12 @@ func (h *Handle) UpdateSessionVar() error {
 		return err
 	}
 	h.mu.ctx.GetSessionVars().AnalyzeVersion = int(ver)
-	return err
+	predicateColumns, err := h.mu.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAnalyzePredicateColumns)
+	if err != nil {
+		return err
+	}
+	h.mu.ctx.GetSessionVars().AnalyzePredicateColumns = variable.TiDBOptOn(predicateColumns)
+	return nil
 }
 
 // GlobalStats is used to store the statistics contained in the global-level stats
@@ -380,6 +400,12 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 		allFms[i] = make([]*statistics.FMSketch, 0, partitionNum)
 	}
 
+	// Only the partitions which have been changed since the last merging are loaded from the storage.
+	partitionVersions, err := h.getPartitionStatsVersions(partitionIDs)
+	if err != nil {
+		return
+	}
+
 	for _, partitionID := range partitionIDs {
 		h.mu.Lock()
 		partitionTable, ok := h.getTableByPhysicalID(is, partitionID)
@@ -390,7 +416,7 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 		}
 		tableInfo := partitionTable.Meta()
 		var partitionStats *statistics.Table
-		partitionStats, err = h.TableStatsFromStorage(tableInfo, partitionID, true, 0)
+		partitionStats, err = h.loadPartitionStatsForMerge(tableInfo, partitionID, partitionVersions[partitionID])
 		if err != nil {
 			return
 		}
@@ -418,6 +444,7 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 				// If the statistics is the column stats, we should use the column ID to replace the index ID.
 				ID = tableInfo.Columns[validColStatsIdx[i]].ID
 			}
+			// The partition-level stats may be cached, GetStatsInfo returns their copies which can be modified by merging.
 			count, hg, cms, topN, fms := partitionStats.GetStatsInfo(ID, isIndex == 1)
 			if i == 0 {
 				// In a partition, we will only update globalStats.Count once
@@ -691,6 +718,9 @@ func (h *Handle) FlushStats() {
 	if err := h.DumpStatsFeedbackToKV(); err != nil {
 		logutil.BgLogger().Error("[stats] dump stats feedback fail", zap.Error(err))
 	}
+	if err := h.DumpColStatsUsageToKV(); err != nil {
+		logutil.BgLogger().Error("[stats] dump column stats usage fail", zap.Error(err))
+	}
 }
 
 func (h *Handle) cmSketchAndTopNFromStorage(reader *statsReader, tblID int64, isIndex, histID int64) (_ *statistics.CMSketch, _ *statistics.TopN, err error) {
@@ -946,7 +976,7 @@ func (h *Handle) extendedStatsFromStorage(reader *statsReader, table *statistics
 				return nil, err
 			}
 			statsStr := row.GetString(4)
-			if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeCorrelation {
+			if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeCorrelation || item.Tp == ast.StatsTypeDependency {
 				if statsStr != "" {
 					item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
 					if err != nil {
@@ -1136,10 +1166,8 @@ func (h *Handle) SaveTableStatsToStorage(results *statistics.AnalyzeResults, nee
 		}
 		strColIDs := string(bytes)
 		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
+		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation, ast.StatsTypeDependency:
 			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
 		}
 		if _, err = exec.ExecuteInternal(ctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, StatsStatusAnalyzed); err != nil {
 			return err
@@ -1432,7 +1460,10 @@ const (
 
 // InsertExtendedStats inserts a record into mysql.stats_extended and update version in mysql.stats_meta.
 func (h *Handle) InsertExtendedStats(statsName string, colIDs []int64, tp int, tableID int64, ifNotExists bool) (err error) {
-	sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+	// The order of the columns matters for dependency statistics, in which the first column determines the second one.
+	if tp != int(ast.StatsTypeDependency) {
+		sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+	}
 	bytes, err := json.Marshal(colIDs)
 	if err != nil {
 		return errors.Trace(err)
@@ -1622,8 +1653,10 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 
 func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeCardinality:
+		return h.fillExtStatsCardinalityVals(item, cols, collectors)
+	case ast.StatsTypeDependency:
+		return h.fillExtStatsDependencyVals(item, cols, collectors)
 	case ast.StatsTypeCorrelation:
 		return h.fillExtStatsCorrVals(item, cols, collectors)
 	}
@@ -1698,6 +1731,127 @@ func (h *Handle) fillExtStatsCorrVals(item *statistics.ExtendedStatsItem, cols [
 	return item
 }
 
+// extStatsSampleRows returns the encoded values of the columns of the extended stats in each sampled row, the rows
+// having NULL in any of the columns are skipped. The samples of different columns are joined by their ordinals, i.e,
+// the positions of the rows in the sample. It also returns the estimated number of such rows in the table.
+func (h *Handle) extStatsSampleRows(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) ([][]string, float64, bool) {
+	colOffsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != len(item.ColIDs) || len(colOffsets) < 2 {
+		return nil, 0, false
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	ordinal2Row := make(map[int][]string, len(collectors[colOffsets[0]].Samples))
+	for i, offset := range colOffsets {
+		for _, sample := range collectors[offset].Samples {
+			row := ordinal2Row[sample.Ordinal]
+			if len(row) != i {
+				continue
+			}
+			key, err := codec.EncodeKey(sc, nil, sample.Value)
+			if err != nil {
+				return nil, 0, false
+			}
+			ordinal2Row[sample.Ordinal] = append(row, string(key))
+		}
+	}
+	rows := make([][]string, 0, len(ordinal2Row))
+	for _, row := range ordinal2Row {
+		if len(row) == len(colOffsets) {
+			rows = append(rows, row)
+		}
+	}
+	first := collectors[colOffsets[0]]
+	if len(first.Samples) == 0 {
+		return rows, 0, true
+	}
+	// The sample rate is the same for all the columns, so we can get it from any of them.
+	totalRows := float64(len(rows)) * float64(first.Count) / float64(len(first.Samples))
+	return rows, totalRows, true
+}
+
+// estimateNDVBySample estimates the NDV of the table from the NDV of the sample by the Duj1 estimator proposed by Haas
+// and Stokes: D = n*d / (n - f1 + f1*n/N), where n is the sample size, d is the NDV of the sample, f1 is the number of
+// values which appear exactly once in the sample, and N is the row count of the table.
+func estimateNDVBySample(counts map[string]int, sampleSize int, totalRows float64) float64 {
+	if sampleSize == 0 {
+		return 0
+	}
+	n, d := float64(sampleSize), float64(len(counts))
+	if totalRows <= n {
+		return d
+	}
+	f1 := 0.0
+	for _, cnt := range counts {
+		if cnt == 1 {
+			f1++
+		}
+	}
+	ndv := n * d / (n - f1 + f1*n/totalRows)
+	return math.Min(math.Max(ndv, d), totalRows)
+}
+
+// fillExtStatsCardinalityVals estimates the NDV of the column group.
+func (h *Handle) fillExtStatsCardinalityVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	rows, totalRows, ok := h.extStatsSampleRows(item, cols, collectors)
+	if !ok {
+		return nil
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		// The memcomparable encoding is self-delimiting, so the concatenation identifies the value group.
+		counts[strings.Join(row, "")]++
+	}
+	item.ScalarVals = estimateNDVBySample(counts, len(rows), totalRows)
+	return item
+}
+
+// fillExtStatsDependencyVals computes the degree of the functional dependency from the first column to the second
+// one, i.e, the fraction of the sampled rows whose value of the first column determines the value of the second
+// column. A value determines the second column if all the sampled rows having it agree on the second column.
+func (h *Handle) fillExtStatsDependencyVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	rows, _, ok := h.extStatsSampleRows(item, cols, collectors)
+	if !ok || len(item.ColIDs) != 2 {
+		return nil
+	}
+	if len(rows) == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	type valueGroup struct {
+		count      int
+		determined string
+		consistent bool
+	}
+	groups := make(map[string]*valueGroup)
+	for _, row := range rows {
+		g, ok := groups[row[0]]
+		if !ok {
+			groups[row[0]] = &valueGroup{count: 1, determined: row[1], consistent: true}
+			continue
+		}
+		g.count++
+		g.consistent = g.consistent && g.determined == row[1]
+	}
+	supportRows := 0
+	for _, g := range groups {
+		if g.consistent {
+			supportRows += g.count
+		}
+	}
+	item.ScalarVals = float64(supportRows) / float64(len(rows))
+	return item
+}
+
 // SaveExtendedStatsToStorage writes extended stats of a table into mysql.stats_extended.
 func (h *Handle) SaveExtendedStatsToStorage(tableID int64, extStats *statistics.ExtendedStatsColl, isLoad bool) (err error) {
 	if extStats == nil || len(extStats.Stats) == 0 {
@@ -1727,10 +1881,8 @@ func (h *Handle) SaveExtendedStatsToStorage(tableID int64, extStats *statistics.
 		strColIDs := string(bytes)
 		var statsStr string
 		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
+		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation, ast.StatsTypeDependency:
 			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
 		}
 		// If isLoad is true, it's INSERT; otherwise, it's UPDATE.
 		if _, err := exec.ExecuteInternal(ctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, StatsStatusAnalyzed); err != nil {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -61,6 +62,9 @@ func cleanEnv(c *C, store kv.Storage, do *domain.Domain) {
 	tk.MustExec("delete from mysql.stats_extended")
 	tk.MustExec("delete from mysql.stats_fm_sketch")
 	tk.MustExec("delete from mysql.schema_index_usage")
+	tk.MustExec("delete from mysql.column_stats_usage")
+	tk.MustExec("delete from mysql.stats_history")
+	tk.MustExec("delete from mysql.stats_table_locked")
 	do.StatsHandle().Clear()
 }
 
@@ -1100,6 +1104,54 @@ partition by range (a) (
 			"test t p1 a 1 1 10 2 17 19 0"))
 }
 
+func (s *testStatsSuite) TestIncrementalGlobalStats(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, key(a)) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("set @@tidb_analyze_version=2")
+	tk.MustExec("set @@tidb_partition_prune_mode='dynamic'")
+	tk.MustExec("insert into t values (1), (2), (3), (4), (5), (11), (12), (13), (14), (15)")
+	c.Assert(s.do.StatsHandle().DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select count, distinct_count from mysql.stats_meta m, mysql.stats_histograms h where m.table_id = h.table_id and h.is_index = 0 order by m.table_id").Check(
+		testkit.Rows("10 10", "5 5", "5 5"))
+
+	// Only p0 is analyzed again, the cached stats of p1 are reused to merge the global-level stats.
+	tk.MustExec("insert into t values (6), (7)")
+	c.Assert(s.do.StatsHandle().DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table t partition p0")
+	tk.MustQuery("select distinct_count from mysql.stats_histograms where is_index = 0 order by table_id").Check(
+		testkit.Rows("12", "7", "5"))
+	tk.MustQuery("select distinct_count from mysql.stats_histograms where is_index = 1 order by table_id").Check(
+		testkit.Rows("12", "7", "5"))
+
+	// The global-level stats are the same as the ones merged from the partition-level stats loaded from the storage.
+	globalBuckets := tk.MustQuery("show stats_buckets where partition_name = 'global'").Rows()
+	s.do.StatsHandle().Clear()
+	tk.MustExec("analyze table t partition p0")
+	tk.MustQuery("show stats_buckets where partition_name = 'global'").Check(globalBuckets)
+
+	// The global TopN value 1 is in the histogram of p1, so merging TopN removes it from the histogram. The cached
+	// stats of p1 must not be changed by the merging, otherwise the next merging gets different results.
+	tk.MustExec("create table t2 (a int, b int, key(b)) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t2 values (1, 1), (2, 1), (3, 1), (4, 3), (5, 4), (6, 5), " +
+		"(11, 1), (12, 1), (13, 7), (14, 7), (15, 7), (16, 8), (17, 9), (18, 6)")
+	c.Assert(s.do.StatsHandle().DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table t2 with 1 topn, 2 buckets")
+	tk.MustExec("analyze table t2 partition p0 with 1 topn, 2 buckets")
+	globalTopN := tk.MustQuery("show stats_topn where table_name = 't2' and partition_name = 'global'").Rows()
+	globalBuckets = tk.MustQuery("show stats_buckets where table_name = 't2' and partition_name = 'global'").Rows()
+	c.Assert(len(globalBuckets) > 0, IsTrue)
+	tk.MustExec("analyze table t2 partition p0 with 1 topn, 2 buckets")
+	tk.MustQuery("show stats_topn where table_name = 't2' and partition_name = 'global'").Check(globalTopN)
+	tk.MustQuery("show stats_buckets where table_name = 't2' and partition_name = 'global'").Check(globalBuckets)
+	s.do.StatsHandle().Clear()
+	tk.MustExec("analyze table t2 partition p0 with 1 topn, 2 buckets")
+	tk.MustQuery("show stats_topn where table_name = 't2' and partition_name = 'global'").Check(globalTopN)
+	tk.MustQuery("show stats_buckets where table_name = 't2' and partition_name = 'global'").Check(globalBuckets)
+}
+
 func (s *testStatsSuite) TestGlobalStatsData2(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
@@ -2003,6 +2055,30 @@ func (s *testStatsSuite) TestCorrelationStatsCompute(c *C) {
 	))
 }
 
+func (s *testStatsSuite) TestColumnGroupStatsCompute(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values(1,10,1),(1,10,2),(2,20,1),(2,20,1),(3,30,2),(3,31,null)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	c.Assert(err.Error(), Equals, "Only support Cardinality statistics type on at least 2 columns")
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b,c)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(a,b)")
+	tk.MustExec("alter table t add stats_extended s3 dependency(b,a)")
+	for _, ver := range []int{1, 2} {
+		tk.MustExec(fmt.Sprintf("set @@session.tidb_analyze_version=%d", ver))
+		tk.MustExec("analyze table t")
+		// The rows having NULL are skipped, and the value 3 of column a doesn't determine column b.
+		tk.MustQuery("select name, type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+			"s1 0 [1,2,3] 4.000000 1",
+			"s2 1 [1,2] 0.666667 1",
+			"s3 1 [2,1] 1.000000 1",
+		))
+	}
+}
+
 func (s *testStatsSuite) TestSyncStatsExtendedRemoval(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
@@ -2253,6 +2329,95 @@ func (s *statsSerialSuite) TestGCIndexUsageInformation(c *C) {
 	tk.MustQuery(querySQL).Check(testkit.Rows("0"))
 }
 
+func (s *statsSerialSuite) TestAnalyzePredicateColumns(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @@global.tidb_enable_column_tracking = 1")
+	defer tk.MustExec("set @@global.tidb_enable_column_tracking = 0")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, d int, index idx_d(d))")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 2, 2, 2), (3, 3, 3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+	tk.MustQuery("select * from t where a > 1").Check(testkit.Rows("2 2 2 2", "3 3 3 3"))
+	tk.MustQuery("select count(*) from (select b + 1 as x, c from t) s group by x").Check(testkit.Rows("1", "1", "1"))
+	tk.MustQuery("select * from mysql.column_stats_usage").Check(testkit.Rows())
+
+	h := s.do.StatsHandle()
+	c.Assert(h.DumpColStatsUsageToKV(), IsNil)
+	tbl, err := s.do.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tblInfo := tbl.Meta()
+	colIDs, err := h.GetPredicateColumns(tblInfo.ID)
+	c.Assert(err, IsNil)
+	sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+	c.Assert(colIDs, DeepEquals, []int64{tblInfo.Columns[0].ID, tblInfo.Columns[1].ID})
+
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_analyze_predicate_columns = 1")
+	tk.MustExec("analyze table t")
+	c.Assert(h.Update(s.do.InfoSchema()), IsNil)
+	statsTbl := h.GetTableStats(tblInfo)
+	c.Assert(statsTbl.Pseudo, IsFalse)
+	for _, col := range tblInfo.Columns {
+		analyzed := statsTbl.Columns[col.ID] != nil && statsTbl.Columns[col.ID].StatsVer == statistics.Version2
+		// The column c is neither a predicate column nor an index column.
+		c.Assert(analyzed, Equals, col.Name.L != "c", Commentf("column %s", col.Name.L))
+	}
+	c.Assert(statsTbl.Indices[tblInfo.Indices[0].ID], NotNil)
+
+	// All the columns are analyzed if no predicate column has been collected.
+	tk.MustExec("analyze table t2")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 No predicate column has been collected yet for table test.t2, all the columns are analyzed"))
+
+	// The usage information is deleted with the statistics of the table.
+	c.Assert(h.DeleteTableStatsFromKV([]int64{tblInfo.ID}), IsNil)
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.column_stats_usage where table_id = %d", tblInfo.ID)).Check(testkit.Rows("0"))
+}
+
+func (s *statsSerialSuite) TestAnalyzePredicateColumnsVersion1(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @@global.tidb_enable_column_tracking = 1")
+	defer tk.MustExec("set @@global.tidb_enable_column_tracking = 0")
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_clustered_index = 'ON'")
+	// The primary keys are not the leading columns of the tables.
+	tk.MustExec("create table t(a int, b int, c int, d int, e varchar(10), primary key(c))")
+	tk.MustExec("create table t2(a int, b int, c varchar(10), d int, primary key(c, b))")
+	tk.MustExec("insert into t values (1, 1, 1, 1, 'a'), (2, 2, 2, 2, 'b'), (3, 3, 3, 3, 'c')")
+	tk.MustExec("insert into t2 values (1, 1, 'a', 1), (2, 2, 'b', 2), (3, 3, 'c', 3)")
+	tk.MustQuery("select * from t where d > 1").Check(testkit.Rows("2 2 2 2 b", "3 3 3 3 c"))
+	tk.MustQuery("select * from t2 where d > 1").Check(testkit.Rows("2 2 b 2", "3 3 c 3"))
+	c.Assert(s.do.StatsHandle().DumpColStatsUsageToKV(), IsNil)
+
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustExec("set @@tidb_analyze_predicate_columns = 1")
+	tk.MustExec("analyze table t, t2")
+	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
+		"test t  c 0 0 1 1 1 1 0",
+		"test t  c 0 1 2 1 2 2 0",
+		"test t  c 0 2 3 1 3 3 0",
+		"test t  d 0 0 1 1 1 1 0",
+		"test t  d 0 1 2 1 2 2 0",
+		"test t  d 0 2 3 1 3 3 0",
+	))
+	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't2'").Sort().Check(testkit.Rows(
+		"test t2  PRIMARY 1 0 1 1 (a, 1) (a, 1) 0",
+		"test t2  PRIMARY 1 1 2 1 (b, 2) (b, 2) 0",
+		"test t2  PRIMARY 1 2 3 1 (c, 3) (c, 3) 0",
+		"test t2  b 0 0 1 1 1 1 0",
+		"test t2  b 0 1 2 1 2 2 0",
+		"test t2  b 0 2 3 1 3 3 0",
+		"test t2  c 0 0 1 1 a a 0",
+		"test t2  c 0 1 2 1 b b 0",
+		"test t2  c 0 2 3 1 c c 0",
+		"test t2  d 0 0 1 1 1 1 0",
+		"test t2  d 0 1 2 1 2 2 0",
+		"test t2  d 0 2 3 1 3 3 0",
+	))
+}
+
 func (s *statsSerialSuite) TestFeedbackWithGlobalStats(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	testKit := testkit.NewTestKit(c, s.store)
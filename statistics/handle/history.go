@@ -0,0 +1,179 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// maxHistoricalStatsBlockSize is the max size of the compressed stats stored in one row of mysql.stats_history, the
+// stats of a table are split into several rows if they're larger than it.
+const maxHistoricalStatsBlockSize = 1 << 20
+
+// HistoricalStatsVersion is a version of the stats of a table kept in mysql.stats_history.
+type HistoricalStatsVersion struct {
+	Version    uint64
+	CreateTime types.Time
+}
+
+func gzipJSONTable(jsonTbl *JSONTable) ([]byte, error) {
+	data, err := json.Marshal(jsonTbl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err = zw.Write(data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = zw.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipJSONTable(data []byte) (*JSONTable, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer zr.Close()
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonTbl := &JSONTable{}
+	if err = json.Unmarshal(data, jsonTbl); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return jsonTbl, nil
+}
+
+// RecordHistoricalStatsToStorage saves the current stats of the table to mysql.stats_history as a new version, and
+// removes the oldest versions which exceed variable.StatsHistoryMaxVersions.
+func (h *Handle) RecordHistoricalStatsToStorage(dbName string, tableInfo *model.TableInfo) (version uint64, err error) {
+	jsonTbl, err := h.DumpStatsToJSON(dbName, tableInfo, nil)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	data, err := gzipJSONTable(jsonTbl)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.TODO()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin pessimistic")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(context.Background(), exec, err)
+	}()
+	txn, err := h.mu.ctx.Txn(true)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	version = txn.StartTS()
+	createTime := types.NewTime(types.FromGoTime(time.Now()), mysql.TypeDatetime, 6).String()
+	for seq := 0; len(data) > 0; seq++ {
+		block := data
+		if len(block) > maxHistoricalStatsBlockSize {
+			block = block[:maxHistoricalStatsBlockSize]
+		}
+		data = data[len(block):]
+		const sql = "insert into mysql.stats_history (table_id, stats_data, seq_no, version, create_time) values (%?, %?, %?, %?, %?)"
+		if _, err = exec.ExecuteInternal(ctx, sql, tableInfo.ID, block, seq, version, createTime); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+
+	// Only the latest versions are kept.
+	rs, err := exec.ExecuteInternal(ctx, "select distinct version from mysql.stats_history where table_id = %? order by version desc limit %?, 1", tableInfo.ID, variable.StatsHistoryMaxVersions.Load())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 1)
+	terror.Call(rs.Close)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		_, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_history where table_id = %? and version <= %?", tableInfo.ID, rows[0].GetUint64(0))
+	}
+	return version, errors.Trace(err)
+}
+
+// GetHistoricalStatsVersions returns the versions of the stats of the table kept in mysql.stats_history, the latest
+// version comes first.
+func (h *Handle) GetHistoricalStatsVersions(tableID int64) ([]HistoricalStatsVersion, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select version, create_time from mysql.stats_history where table_id = %? and seq_no = 0 order by version desc", tableID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	versions := make([]HistoricalStatsVersion, 0, len(rows))
+	for _, row := range rows {
+		versions = append(versions, HistoricalStatsVersion{Version: row.GetUint64(0), CreateTime: row.GetTime(1)})
+	}
+	return versions, nil
+}
+
+// RestoreHistoricalStats restores the stats of the table to the latest version in mysql.stats_history which was
+// created no later than the given time. It returns the restored version.
+func (h *Handle) RestoreHistoricalStats(is infoschema.InfoSchema, tableID int64, ts time.Time) (uint64, error) {
+	tbl, ok := is.TableByID(tableID)
+	if !ok {
+		return 0, errors.Errorf("unknown table ID %d", tableID)
+	}
+	ctx := context.Background()
+	tsStr := types.NewTime(types.FromGoTime(ts), mysql.TypeDatetime, 6).String()
+	rows, _, err := h.execRestrictedSQL(ctx, "select max(version) from mysql.stats_history where table_id = %? and create_time <= %?", tableID, tsStr)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return 0, errors.Errorf("no historical stats of table %s created before %s", tbl.Meta().Name.O, tsStr)
+	}
+	version := rows[0].GetUint64(0)
+	rows, _, err = h.execRestrictedSQL(ctx, "select stats_data from mysql.stats_history where table_id = %? and version = %? order by seq_no", tableID, version)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	var data []byte
+	for _, row := range rows {
+		data = append(data, row.GetBytes(0)...)
+	}
+	jsonTbl, err := gunzipJSONTable(data)
+	if err != nil {
+		return 0, err
+	}
+	return version, h.loadTableStatsFromJSON(is, tbl.Meta(), jsonTbl)
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// LockTableStats locks the stats of the tables. The stats of a locked table are kept unchanged, both ANALYZE and auto
+// analyze skip it until it's unlocked.
+func (h *Handle) LockTableStats(tableIDs ...int64) error {
+	ctx := context.Background()
+	createTime := types.NewTime(types.FromGoTime(time.Now()), mysql.TypeDatetime, 6).String()
+	for _, tableID := range tableIDs {
+		if _, _, err := h.execRestrictedSQL(ctx, "insert ignore into mysql.stats_table_locked (table_id, create_time) values (%?, %?)", tableID, createTime); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// UnlockTableStats unlocks the stats of the tables.
+func (h *Handle) UnlockTableStats(tableIDs ...int64) error {
+	ctx := context.Background()
+	for _, tableID := range tableIDs {
+		if _, _, err := h.execRestrictedSQL(ctx, "delete from mysql.stats_table_locked where table_id = %?", tableID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// GetLockedTables returns the tables whose stats are locked among the given tables, or all the locked tables if no
+// table is given.
+func (h *Handle) GetLockedTables(tableIDs ...int64) (map[int64]struct{}, error) {
+	var sqlBuilder strings.Builder
+	sqlBuilder.WriteString("select table_id from mysql.stats_table_locked")
+	params := make([]interface{}, 0, len(tableIDs))
+	for i, id := range tableIDs {
+		if i == 0 {
+			sqlBuilder.WriteString(" where table_id in (%?")
+		} else {
+			sqlBuilder.WriteString(", %?")
+		}
+		params = append(params, id)
+	}
+	if len(tableIDs) > 0 {
+		sqlBuilder.WriteString(")")
+	}
+	rows, _, err := h.execRestrictedSQL(context.Background(), sqlBuilder.String(), params...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	locked := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		locked[row.GetInt64(0)] = struct{}{}
+	}
+	return locked, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
+)
+
+// partitionStatsCache caches the partition-level stats loaded to merge the global-level stats. Merging the global-level
+// stats needs the histograms, TopN and FMSketch of all the partitions, but usually only a few partitions are analyzed
+// between two merges, so the stats of the other partitions are reused instead of being loaded from the storage again.
+// The memory usage of the cached stats is limited by variable.PartitionStatsCacheMemQuota, and the least recently used
+// partitions are evicted once it's exceeded.
+type partitionStatsCache struct {
+	sync.Mutex
+	elements map[int64]*list.Element
+	lru      *list.List
+	memUsage int64
+}
+
+// partitionStatsCacheEntry is the value of list.Element.
+type partitionStatsCacheEntry struct {
+	physicalID int64
+	// version is the max version of the histograms of the partition when the stats are loaded.
+	version  uint64
+	tbl      *statistics.Table
+	memUsage int64
+}
+
+func newPartitionStatsCache() *partitionStatsCache {
+	return &partitionStatsCache{
+		elements: make(map[int64]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// get returns the cached stats of the partition if they're loaded at the version.
+func (c *partitionStatsCache) get(physicalID int64, version uint64) *statistics.Table {
+	c.Lock()
+	defer c.Unlock()
+	element, ok := c.elements[physicalID]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*partitionStatsCacheEntry)
+	if entry.version != version {
+		c.removeElement(element)
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return entry.tbl
+}
+
+func (c *partitionStatsCache) put(physicalID int64, version uint64, tbl *statistics.Table) {
+	c.Lock()
+	defer c.Unlock()
+	if element, ok := c.elements[physicalID]; ok {
+		c.removeElement(element)
+	}
+	entry := &partitionStatsCacheEntry{
+		physicalID: physicalID,
+		version:    version,
+		tbl:        tbl,
+		memUsage:   tbl.MemoryUsage(),
+	}
+	quota := variable.PartitionStatsCacheMemQuota.Load()
+	if entry.memUsage > quota {
+		return
+	}
+	c.elements[physicalID] = c.lru.PushFront(entry)
+	c.memUsage += entry.memUsage
+	for c.memUsage > quota {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *partitionStatsCache) remove(physicalID int64) {
+	c.Lock()
+	defer c.Unlock()
+	if element, ok := c.elements[physicalID]; ok {
+		c.removeElement(element)
+	}
+}
+
+func (c *partitionStatsCache) removeElement(element *list.Element) {
+	entry := element.Value.(*partitionStatsCacheEntry)
+	c.lru.Remove(element)
+	delete(c.elements, entry.physicalID)
+	c.memUsage -= entry.memUsage
+}
+
+func (c *partitionStatsCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.elements = make(map[int64]*list.Element)
+	c.lru.Init()
+	c.memUsage = 0
+}
+
+// getPartitionStatsVersions returns the max version of the histograms of each partition. The version changes whenever
+// the partition is analyzed or its columns and indexes are changed, so it tells whether the cached stats are stale.
+func (h *Handle) getPartitionStatsVersions(partitionIDs []int64) (map[int64]uint64, error) {
+	var sqlBuilder strings.Builder
+	sqlBuilder.WriteString("select table_id, max(version) from mysql.stats_histograms where table_id in (")
+	params := make([]interface{}, 0, len(partitionIDs))
+	for i, id := range partitionIDs {
+		if i != 0 {
+			sqlBuilder.WriteString(", ")
+		}
+		sqlBuilder.WriteString("%?")
+		params = append(params, id)
+	}
+	sqlBuilder.WriteString(") group by table_id")
+	rows, _, err := h.execRestrictedSQL(context.Background(), sqlBuilder.String(), params...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	versions := make(map[int64]uint64, len(rows))
+	for _, row := range rows {
+		versions[row.GetInt64(0)] = row.GetUint64(1)
+	}
+	return versions, nil
+}
+
+// loadPartitionStatsForMerge returns the stats of the partition for merging the global-level stats. The cached stats
+// are used if the partition hasn't been changed since they were loaded, otherwise the stats are loaded from the storage.
+// The returned stats may be shared by the concurrent mergings, so they must not be modified. Merging TopN removes the
+// values from the histograms, so the deep copies returned by statistics.Table.GetStatsInfo are merged instead.
+func (h *Handle) loadPartitionStatsForMerge(tableInfo *model.TableInfo, partitionID int64, version uint64) (*statistics.Table, error) {
+	if version > 0 {
+		if tbl := h.partitionStatsCache.get(partitionID, version); tbl != nil {
+			return tbl, nil
+		}
+	}
+	tbl, err := h.TableStatsFromStorage(tableInfo, partitionID, true, 0)
+	if err != nil || tbl == nil {
+		return tbl, err
+	}
+	if version > 0 {
+		h.partitionStatsCache.put(partitionID, version, tbl)
+	}
+	return tbl, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/types"
+)
+
+var _ = SerialSuites(&testPartitionStatsCacheSuite{})
+
+type testPartitionStatsCacheSuite struct {
+}
+
+func newPartitionStatsForTest(physicalID int64) *statistics.Table {
+	tbl := &statistics.Table{
+		HistColl: statistics.HistColl{
+			PhysicalID: physicalID,
+			Columns:    make(map[int64]*statistics.Column),
+			Indices:    make(map[int64]*statistics.Index),
+		},
+	}
+	hist := statistics.NewHistogram(1, 10, 0, 0, types.NewFieldType(mysql.TypeLonglong), 4, 0)
+	for i := int64(0); i < 4; i++ {
+		d := types.NewIntDatum(i)
+		hist.AppendBucket(&d, &d, i+1, 1)
+	}
+	tbl.Columns[1] = &statistics.Column{Histogram: *hist}
+	return tbl
+}
+
+func (s *testPartitionStatsCacheSuite) TestPartitionStatsCache(c *C) {
+	origQuota := variable.PartitionStatsCacheMemQuota.Load()
+	defer variable.PartitionStatsCacheMemQuota.Store(origQuota)
+
+	cache := newPartitionStatsCache()
+	tbl1, tbl2, tbl3 := newPartitionStatsForTest(1), newPartitionStatsForTest(2), newPartitionStatsForTest(3)
+	variable.PartitionStatsCacheMemQuota.Store(tbl1.MemoryUsage() * 2)
+
+	cache.put(1, 10, tbl1)
+	c.Assert(cache.get(1, 10), Equals, tbl1)
+	// The cached stats are stale if the partition has been analyzed again.
+	c.Assert(cache.get(1, 11), IsNil)
+	c.Assert(cache.get(1, 10), IsNil)
+
+	cache.put(1, 11, tbl1)
+	cache.put(2, 10, tbl2)
+	c.Assert(cache.get(1, 11), Equals, tbl1)
+	// The least recently used partition is evicted when the quota is exceeded.
+	cache.put(3, 10, tbl3)
+	c.Assert(cache.get(2, 10), IsNil)
+	c.Assert(cache.get(1, 11), Equals, tbl1)
+	c.Assert(cache.get(3, 10), Equals, tbl3)
+	c.Assert(cache.memUsage, Equals, tbl1.MemoryUsage()+tbl3.MemoryUsage())
+
+	cache.remove(1)
+	c.Assert(cache.get(1, 11), IsNil)
+	c.Assert(cache.memUsage, Equals, tbl3.MemoryUsage())
+
+	// The stats are not cached if they exceed the quota alone.
+	variable.PartitionStatsCacheMemQuota.Store(tbl1.MemoryUsage() - 1)
+	cache.put(1, 12, tbl1)
+	c.Assert(cache.get(1, 12), IsNil)
+
+	cache.clear()
+	c.Assert(cache.get(3, 10), IsNil)
+	c.Assert(cache.memUsage, Equals, int64(0))
+}
@@ -134,6 +134,8 @@ func (h *Handle) merge(s *SessionStatsCollector, rateMap errorRateDeltaMap) {
 	s.rateMap = make(errorRateDeltaMap)
 	h.feedback.Merge(s.feedback)
 	s.feedback = statistics.NewQueryFeedbackMap()
+	h.colMap.merge(s.colMap)
+	s.colMap = make(colStatsUsageMap)
 }
 
 // SessionStatsCollector is a list item that holds the delta mapper. If you want to write or read mapper, you must lock it.
@@ -143,6 +145,7 @@ type SessionStatsCollector struct {
 	mapper   tableDeltaMap
 	feedback *statistics.QueryFeedbackMap
 	rateMap  errorRateDeltaMap
+	colMap   colStatsUsageMap
 	next     *SessionStatsCollector
 	// deleted is set to true when a session is closed. Every time we sweep the list, we will remove the useless collector.
 	deleted bool
@@ -162,6 +165,14 @@ func (s *SessionStatsCollector) Update(id int64, delta int64, count int64, colSi
 	s.mapper.update(id, delta, count, colSize)
 }
 
+// UpdateColStatsUsage updates the last time when the column of the table is used in the predicates.
+func (s *SessionStatsCollector) UpdateColStatsUsage(tableID int64, colID int64) {
+	now := time.Now()
+	s.Lock()
+	defer s.Unlock()
+	s.colMap[tableColumnID{TableID: tableID, ColumnID: colID}] = now
+}
+
 var (
 	// MinLogScanCount is the minimum scan count for a feedback to be logged.
 	MinLogScanCount = atomic.NewInt64(1000)
@@ -205,6 +216,7 @@ func (h *Handle) NewSessionStatsCollector() *SessionStatsCollector {
 	newCollector := &SessionStatsCollector{
 		mapper:   make(tableDeltaMap),
 		rateMap:  make(errorRateDeltaMap),
+		colMap:   make(colStatsUsageMap),
 		next:     h.listHead.next,
 		feedback: statistics.NewQueryFeedbackMap(),
 	}
@@ -333,6 +345,51 @@ func (h *Handle) GCIndexUsage() error {
 	return err
 }
 
+// tableColumnID is the key type of colStatsUsageMap.
+type tableColumnID struct {
+	TableID  int64
+	ColumnID int64
+}
+
+// colStatsUsageMap records the last time when the columns are used in the predicates.
+type colStatsUsageMap map[tableColumnID]time.Time
+
+func (m colStatsUsageMap) merge(other colStatsUsageMap) {
+	for id, t := range other {
+		if t.After(m[id]) {
+			m[id] = t
+		}
+	}
+}
+
+// DumpColStatsUsageToKV sweeps the whole list and dumps the column stats usage information to KV.
+func (h *Handle) DumpColStatsUsageToKV() error {
+	h.sweepList()
+	ctx := context.Background()
+	for id, lastUsedAt := range h.colMap {
+		lastUsedAtStr := lastUsedAt.Format(types.TimeFormat)
+		const sql = `insert into mysql.column_stats_usage (table_id, column_id, last_used_at) values (%?, %?, %?) on duplicate key update last_used_at = greatest(last_used_at, %?)`
+		if _, _, err := h.execRestrictedSQL(ctx, sql, id.TableID, id.ColumnID, lastUsedAtStr, lastUsedAtStr); err != nil {
+			return errors.Trace(err)
+		}
+		delete(h.colMap, id)
+	}
+	return nil
+}
+
+// GetPredicateColumns returns the IDs of the columns of the table which have been used in the predicates.
+func (h *Handle) GetPredicateColumns(tableID int64) ([]int64, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select column_id from mysql.column_stats_usage where table_id = %? and last_used_at is not null", tableID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	colIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		colIDs = append(colIDs, row.GetInt64(0))
+	}
+	return colIDs, nil
+}
+
 var (
 	// DumpStatsDeltaRatio is the lower bound of `Modify Count / Table Count` for stats delta to be dumped.
 	DumpStatsDeltaRatio = 1 / 10000.0
@@ -942,11 +999,19 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 		logutil.BgLogger().Error("[stats] parse auto analyze period failed", zap.Error(err))
 		return false
 	}
+	lockedTables, err := h.GetLockedTables()
+	if err != nil {
+		logutil.BgLogger().Error("[stats] load locked tables for auto analyze failed", zap.Error(err))
+		return false
+	}
 	pruneMode := h.CurrentPruneMode()
 	for _, db := range dbs {
 		tbls := is.SchemaTables(model.NewCIStr(db))
 		for _, tbl := range tbls {
 			tblInfo := tbl.Meta()
+			if _, ok := lockedTables[tblInfo.ID]; ok {
+				continue
+			}
 			pi := tblInfo.GetPartitionInfo()
 			if pi == nil {
 				statsTbl := h.GetTableStats(tblInfo)
@@ -584,6 +584,76 @@ func (s *testSerialStatsSuite) TestAutoAnalyzeOnEmptyTable(c *C) {
 	c.Assert(s.do.StatsHandle().HandleAutoAnalyze(s.do.InfoSchema()), IsTrue)
 }
 
+func (s *testSerialStatsSuite) TestHistoricalStats(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @@global.tidb_stats_history_max_versions = 2")
+	defer tk.MustExec("set @@global.tidb_stats_history_max_versions = 0")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index ia(a))")
+	tbl, err := s.do.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+	h := s.do.StatsHandle()
+	getCount := func() int64 {
+		c.Assert(h.Update(s.do.InfoSchema()), IsNil)
+		return h.GetTableStats(tableInfo).Count
+	}
+
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustExec("analyze table t")
+	ts1 := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	tk.MustExec("insert into t values (2, 2)")
+	tk.MustExec("analyze table t")
+	ts2 := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustExec("analyze table t")
+	c.Assert(getCount(), Equals, int64(3))
+
+	// Only the latest 2 versions are kept.
+	versions, err := h.GetHistoricalStatsVersions(tableInfo.ID)
+	c.Assert(err, IsNil)
+	c.Assert(versions, HasLen, 2)
+	c.Assert(versions[0].Version > versions[1].Version, IsTrue)
+	_, err = h.RestoreHistoricalStats(s.do.InfoSchema(), tableInfo.ID, ts1)
+	c.Assert(err, ErrorMatches, "no historical stats of table t created before .*")
+
+	version, err := h.RestoreHistoricalStats(s.do.InfoSchema(), tableInfo.ID, ts2)
+	c.Assert(err, IsNil)
+	c.Assert(version, Equals, versions[1].Version)
+	c.Assert(getCount(), Equals, int64(2))
+
+	// The locked stats are kept unchanged by ANALYZE.
+	c.Assert(h.LockTableStats(tableInfo.ID), IsNil)
+	locked, err := h.GetLockedTables(tableInfo.ID)
+	c.Assert(err, IsNil)
+	c.Assert(locked, HasKey, tableInfo.ID)
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip analyze locked table: test.t"))
+	c.Assert(getCount(), Equals, int64(2))
+
+	// The locked stats are kept unchanged by auto analyze.
+	handle.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.2")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		tk.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+	}()
+	tk.MustExec("insert into t values (4, 4), (5, 5), (6, 6)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(s.do.InfoSchema()), IsNil)
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsFalse)
+
+	c.Assert(h.UnlockTableStats(tableInfo.ID), IsNil)
+	locked, err = h.GetLockedTables()
+	c.Assert(err, IsNil)
+	c.Assert(locked, HasLen, 0)
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsTrue)
+	c.Assert(getCount(), Equals, int64(6))
+}
+
 func (s *testSerialStatsSuite) TestIssue25700(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
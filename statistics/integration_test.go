@@ -481,3 +481,115 @@ func (s *testSerialIntegrationSuite) TestOutdatedStatsCheck(c *C) {
 	c.Assert(h.Update(is), IsNil)
 	c.Assert(tk.HasPseudoStats("select * from t where a = 1"), IsTrue)
 }
+
+func (s *testIntegrationSuite) TestColumnGroupStatsEstimation(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int, b int, c int)")
+	// Column a determines column b, while columns a and c are independent.
+	var sb strings.Builder
+	sb.WriteString("insert into t values ")
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fmt.Sprintf("(%d, %d, %d)", i%10, i%10*10, i/25))
+	}
+	tk.MustExec(sb.String())
+	tk.MustExec("analyze table t")
+	tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 10").Check(testkit.Rows(
+		"TableReader 1.00 root  data:Selection",
+		"└─Selection 1.00 cop[tikv]  eq(test.t.a, 1), eq(test.t.b, 10)",
+		"  └─TableFullScan 100.00 cop[tikv] table:t keep order:false",
+	))
+	tk.MustQuery("explain format = 'brief' select a, c from t group by a, c").Check(testkit.Rows(
+		"HashAgg 10.00 root  group by:test.t.a, test.t.c, funcs:firstrow(test.t.a)->test.t.a, funcs:firstrow(test.t.c)->test.t.c",
+		"└─TableReader 10.00 root  data:HashAgg",
+		"  └─HashAgg 10.00 cop[tikv]  group by:test.t.a, test.t.c, ",
+		"    └─TableFullScan 100.00 cop[tikv] table:t keep order:false",
+	))
+
+	tk.MustExec("alter table t add stats_extended s1 dependency(a, b)")
+	tk.MustExec("alter table t add stats_extended s2 cardinality(a, c)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,3] 40.000000 1",
+		"1 [1,2] 1.000000 1",
+	))
+	rows := tk.MustQuery("show stats_extended where stats_name = 's1'").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][4], Equals, "dependency")
+	c.Assert(rows[0][5], Equals, "1.000000")
+	tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 10").Check(testkit.Rows(
+		"TableReader 10.00 root  data:Selection",
+		"└─Selection 10.00 cop[tikv]  eq(test.t.a, 1), eq(test.t.b, 10)",
+		"  └─TableFullScan 100.00 cop[tikv] table:t keep order:false",
+	))
+	tk.MustQuery("explain format = 'brief' select a, c from t group by a, c").Check(testkit.Rows(
+		"HashAgg 40.00 root  group by:test.t.a, test.t.c, funcs:firstrow(test.t.a)->test.t.a, funcs:firstrow(test.t.c)->test.t.c",
+		"└─TableReader 40.00 root  data:HashAgg",
+		"  └─HashAgg 40.00 cop[tikv]  group by:test.t.a, test.t.c, ",
+		"    └─TableFullScan 100.00 cop[tikv] table:t keep order:false",
+	))
+
+	// The statistics are not used when the extended stats are disabled.
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 10").Check(testkit.Rows(
+		"TableReader 1.00 root  data:Selection",
+		"└─Selection 1.00 cop[tikv]  eq(test.t.a, 1), eq(test.t.b, 10)",
+		"  └─TableFullScan 100.00 cop[tikv] table:t keep order:false",
+	))
+}
+
+func (s *testIntegrationSuite) TestJoinEstimationByHistograms(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("create table t2(a int)")
+	// The value 1 is skewed in both tables, the join returns 90 * 10 = 900 rows.
+	var sb1, sb2 strings.Builder
+	sb1.WriteString("insert into t1 values (1)" + strings.Repeat(", (1)", 89))
+	sb2.WriteString("insert into t2 values (1)" + strings.Repeat(", (1)", 9))
+	for i := 0; i < 10; i++ {
+		sb1.WriteString(fmt.Sprintf(", (%d)", i+2))
+	}
+	for i := 0; i < 90; i++ {
+		sb2.WriteString(fmt.Sprintf(", (%d)", i+100))
+	}
+	tk.MustExec(sb1.String())
+	tk.MustExec(sb2.String())
+	tk.MustExec("analyze table t1, t2 with 1 topn, 4 buckets")
+	tk.MustQuery("select count(*) from t1 join t2 on t1.a = t2.a").Check(testkit.Rows("900"))
+
+	getJoinEstRows := func() string {
+		rows := tk.MustQuery("explain format = 'brief' select * from t1 join t2 on t1.a = t2.a").Rows()
+		return rows[0][1].(string)
+	}
+	c.Assert(getJoinEstRows(), Equals, "109.89")
+	tk.MustExec("set @@session.tidb_opt_enable_join_histogram_estimation = on")
+	c.Assert(getJoinEstRows(), Equals, "900.00")
+
+	// The TopN values of the time types are decoded as time to be compared with the histogram of the other side.
+	// The value '2021-01-01' is in the TopN of t3 and in the histogram of t4, the join returns 90 * 5 = 450 rows.
+	tk.MustExec("create table t3(a date)")
+	tk.MustExec("create table t4(a datetime)")
+	sb1.Reset()
+	sb2.Reset()
+	sb1.WriteString("insert into t3 values ('2021-01-01')" + strings.Repeat(", ('2021-01-01')", 89))
+	sb2.WriteString("insert into t4 values ('2021-01-01')" + strings.Repeat(", ('2021-01-01')", 4) + strings.Repeat(", ('2022-01-01')", 20))
+	for i := 0; i < 10; i++ {
+		sb1.WriteString(fmt.Sprintf(", ('2020-01-%02d')", i+1))
+		sb2.WriteString(fmt.Sprintf(", ('2021-02-%02d 10:00:00')", i+1))
+	}
+	tk.MustExec(sb1.String())
+	tk.MustExec(sb2.String())
+	tk.MustExec("analyze table t3, t4 with 1 topn, 4 buckets")
+	tk.MustQuery("select count(*) from t3 join t4 on t3.a = t4.a").Check(testkit.Rows("450"))
+	rows := tk.MustQuery("explain format = 'brief' select * from t3 join t4 on t3.a = t4.a").Rows()
+	c.Assert(rows[0][1].(string), Equals, "450.00")
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// EqualJoinRowCount estimates the row count of the equal join on the two columns by aligning their TopN and
+// histograms, which takes the overlap and the skew of the values into account. The row counts from the stats are
+// scaled to leftRowCount and rightRowCount, which are the row counts of the join inputs.
+// It only supports the columns analyzed by version 2, and returns false if the columns can't be estimated this way.
+func EqualJoinRowCount(sc *stmtctx.StatementContext, left, right *Column, leftRowCount, rightRowCount float64) (float64, bool) {
+	if left.StatsVer < Version2 || right.StatsVer < Version2 || !isJoinKeyComparable(left.Histogram.Tp, right.Histogram.Tp) {
+		return 0, false
+	}
+	leftTotal, rightTotal := left.TotalRowCount(), right.TotalRowCount()
+	if leftTotal <= 0 || rightTotal <= 0 {
+		return 0, false
+	}
+	var count float64
+	// 1. The TopN values of the left side join with all the values of the right side.
+	for _, meta := range left.TopN.topNMetas() {
+		val, err := decodeTopNValue(sc, left.Histogram.Tp, meta.Encoded)
+		if err != nil {
+			return 0, false
+		}
+		rightCnt, ok := right.QueryTopN(meta.Encoded)
+		if ok {
+			count += float64(meta.Count) * float64(rightCnt)
+		} else {
+			count += float64(meta.Count) * right.histEqualRowCount(val)
+		}
+	}
+	// 2. The TopN values of the right side join with the histogram of the left side.
+	for _, meta := range right.TopN.topNMetas() {
+		if _, ok := left.QueryTopN(meta.Encoded); ok {
+			continue
+		}
+		val, err := decodeTopNValue(sc, right.Histogram.Tp, meta.Encoded)
+		if err != nil {
+			return 0, false
+		}
+		count += float64(meta.Count) * left.histEqualRowCount(val)
+	}
+	// 3. Each bucket of the left histogram joins with the part of the right histogram in the range of the bucket, in
+	// which the values are assumed to be evenly distributed.
+	lh, rh := &left.Histogram, &right.Histogram
+	leftNDV, rightNDV := float64(lh.NDV-int64(left.TopN.Num())), float64(rh.NDV-int64(right.TopN.Num()))
+	if lh.Len() > 0 && rh.Len() > 0 && leftNDV > 0 && rightNDV > 0 {
+		for i := 0; i < lh.Len(); i++ {
+			upper := *lh.GetUpper(i)
+			rightCnt := rh.BetweenRowCount(*lh.GetLower(i), upper) + right.histEqualRowCount(upper)
+			if rightCnt <= 0 {
+				continue
+			}
+			leftCnt := float64(lh.bucketCount(i))
+			leftBktNDV := float64(lh.Buckets[i].NDV)
+			if leftBktNDV <= 0 {
+				leftBktNDV = leftNDV * leftCnt / lh.notNullCount()
+			}
+			rightRangeNDV := rightNDV * rightCnt / rh.notNullCount()
+			count += leftCnt * rightCnt / math.Max(1, math.Max(leftBktNDV, rightRangeNDV))
+		}
+	}
+	return count * (leftRowCount / leftTotal) * (rightRowCount / rightTotal), true
+}
+
+// isJoinKeyComparable checks whether the values in the stats of the two columns can be compared directly.
+func isJoinKeyComparable(l, r *types.FieldType) bool {
+	if l == nil || r == nil || l.EvalType() != r.EvalType() {
+		return false
+	}
+	for _, tp := range []*types.FieldType{l, r} {
+		switch tp.Tp {
+		case mysql.TypeEnum, mysql.TypeSet, mysql.TypeBit, mysql.TypeJSON:
+			return false
+		}
+	}
+	switch l.EvalType() {
+	case types.ETInt:
+		return mysql.HasUnsignedFlag(l.Flag) == mysql.HasUnsignedFlag(r.Flag)
+	case types.ETString:
+		// The stats of strings are collation keys.
+		return l.Collate == r.Collate
+	}
+	return true
+}
+
+// decodeTopNValue decodes the value of the TopN to compare with the histogram.
+func decodeTopNValue(sc *stmtctx.StatementContext, tp *types.FieldType, encoded []byte) (types.Datum, error) {
+	var d types.Datum
+	var err error
+	if types.IsTypeTime(tp.Tp) {
+		// handle datetime values specially since they are encoded to int and we'll get int values if using DecodeOne.
+		_, d, err = codec.DecodeAsDateTime(encoded, tp.Tp, sc.TimeZone)
+	} else {
+		_, d, err = codec.DecodeOne(encoded)
+	}
+	return d, err
+}
+
+// histEqualRowCount estimates the row count of the value in the histogram, the TopN is not considered.
+func (c *Column) histEqualRowCount(val types.Datum) float64 {
+	hg := &c.Histogram
+	if hg.Len() == 0 || hg.outOfRange(val) {
+		return 0
+	}
+	if cnt, matched := hg.equalRowCount(val, true); matched {
+		return cnt
+	}
+	histNDV := float64(hg.NDV - int64(c.TopN.Num()))
+	if histNDV <= 0 {
+		return 0
+	}
+	return hg.notNullCount() / histNDV
+}
+
+func (c *TopN) topNMetas() []TopNMeta {
+	if c == nil {
+		return nil
+	}
+	return c.TopN
+}
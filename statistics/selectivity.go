@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	planutil "github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
@@ -292,6 +293,9 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 			ret *= selectionFactor
 		}
 	}
+	if ctx.GetSessionVars().EnableExtendedStats {
+		ret = coll.adjustSelectivityByColGroupStats(sc, usedSets, ret)
+	}
 
 	// Now we try to cover those still not covered DNF conditions using independence assumption,
 	// i.e., sel(condA or condB) = sel(condA) + sel(condB) - sel(condA) * sel(condB)
@@ -361,6 +365,73 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	return ret, nodes, nil
 }
 
+// adjustSelectivityByColGroupStats corrects the selectivity of the equal conditions on correlated columns, which is
+// calculated by multiplying the selectivity of each column under the independence assumption, using the multi-column
+// NDV and functional dependency statistics in coll.ColGroupStats.
+func (coll *HistColl) adjustSelectivityByColGroupStats(sc *stmtctx.StatementContext, usedSets []*StatsNode, sel float64) float64 {
+	if len(coll.ColGroupStats) == 0 {
+		return sel
+	}
+	// colSel maps the unique ID of a column to the selectivity of the equal condition on it.
+	colSel := make(map[int64]float64)
+	for _, set := range usedSets {
+		if (set.Tp != ColType && set.Tp != PkType) || set.partCover || len(set.Ranges) != 1 || !set.Ranges[0].IsPoint(sc) {
+			continue
+		}
+		colSel[set.ID] = set.Selectivity
+	}
+	if len(colSel) < 2 {
+		return sel
+	}
+	// The selectivity of a column is adjusted at most once.
+	adjusted := make(map[int64]struct{})
+	isUsable := func(colID int64) bool {
+		_, ok := adjusted[colID]
+		return !ok && colSel[colID] > 0
+	}
+	// The equal conditions on all the columns of a group select 1/NDV(group) of the rows on average, it can't be smaller
+	// than the product of the selectivity of each column, and can't be larger than the selectivity of any column.
+	for _, item := range coll.ColGroupStats {
+		if item.Tp != ast.StatsTypeCardinality || item.ScalarVals < 1 {
+			continue
+		}
+		product, minSel, usable := 1.0, 1.0, true
+		for _, id := range item.ColIDs {
+			if _, ok := colSel[id]; !ok || !isUsable(id) {
+				usable = false
+				break
+			}
+			product *= colSel[id]
+			minSel = math.Min(minSel, colSel[id])
+		}
+		if !usable {
+			continue
+		}
+		sel = sel / product * math.Min(minSel, math.Max(product, 1/item.ScalarVals))
+		for _, id := range item.ColIDs {
+			adjusted[id] = struct{}{}
+		}
+	}
+	// If column a determines column b with degree f, sel(a = x and b = y) = sel(a = x) * (f + (1 - f) * sel(b = y)).
+	for _, item := range coll.ColGroupStats {
+		if item.Tp != ast.StatsTypeDependency || len(item.ColIDs) != 2 {
+			continue
+		}
+		a, b := item.ColIDs[0], item.ColIDs[1]
+		// Column a may determine several columns, so only the selectivity of b is adjusted here.
+		if _, ok := colSel[a]; !ok {
+			continue
+		}
+		if _, ok := colSel[b]; !ok || !isUsable(b) {
+			continue
+		}
+		degree := math.Max(0, math.Min(1, item.ScalarVals))
+		sel = sel / colSel[b] * (degree + (1-degree)*colSel[b])
+		adjusted[b] = struct{}{}
+	}
+	return sel
+}
+
 func getMaskAndRanges(ctx sessionctx.Context, exprs []expression.Expression, rangeType ranger.RangeType, lengths []int, cachedPath *planutil.AccessPath, cols ...*expression.Column) (mask int64, ranges []*ranger.Range, partCover bool, err error) {
 	sc := ctx.GetSessionVars().StmtCtx
 	isDNF := false
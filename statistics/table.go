@@ -23,6 +23,7 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
@@ -106,6 +107,9 @@ type HistColl struct {
 	// The physical id is used when try to load column stats from storage.
 	HavePhysicalID bool
 	Pseudo         bool
+	// ColGroupStats are the multi-column NDV and functional dependency statistics of the table, whose column IDs are
+	// the unique IDs of the columns. It's filled by Table.GenerateHistCollFromColumnInfo to be used in planner.
+	ColGroupStats []*ExtendedStatsItem
 }
 
 // MemoryUsage returns the total memory usage of this Table.
@@ -205,7 +209,7 @@ func (t *Table) ColumnByName(colName string) *Column {
 	return nil
 }
 
-// GetStatsInfo returns their statistics according to the ID of the column or index, including histogram, CMSketch, TopN and FMSketch.
+// GetStatsInfo returns the deep copies of the statistics according to the ID of the column or index, including histogram, CMSketch, TopN and FMSketch.
 func (t *Table) GetStatsInfo(ID int64, isIndex bool) (int64, *Histogram, *CMSketch, *TopN, *FMSketch) {
 	if isIndex {
 		idxStatsInfo := t.Indices[ID]
@@ -471,6 +475,48 @@ func (coll *HistColl) GenerateHistCollFromColumnInfo(infos []*model.ColumnInfo,
 	return newColl
 }
 
+// GenerateHistCollFromColumnInfo generates a new HistColl like HistColl.GenerateHistCollFromColumnInfo, and fills its
+// ColGroupStats with the multi-column NDV and functional dependency statistics on the given columns.
+func (t *Table) GenerateHistCollFromColumnInfo(infos []*model.ColumnInfo, columns []*expression.Column) *HistColl {
+	newColl := t.HistColl.GenerateHistCollFromColumnInfo(infos, columns)
+	if t.ExtendedStats == nil || len(t.ExtendedStats.Stats) == 0 {
+		return newColl
+	}
+	colInfoID2UniqueID := make(map[int64]int64, len(columns))
+	for _, col := range columns {
+		colInfoID2UniqueID[col.ID] = col.UniqueID
+	}
+	names := make([]string, 0, len(t.ExtendedStats.Stats))
+	for name := range t.ExtendedStats.Stats {
+		names = append(names, name)
+	}
+	// Sort the names to make the estimation stable when several stats are applicable.
+	sort.Strings(names)
+	for _, name := range names {
+		item := t.ExtendedStats.Stats[name]
+		if item.Tp != ast.StatsTypeCardinality && item.Tp != ast.StatsTypeDependency {
+			continue
+		}
+		uniqueIDs := make([]int64, 0, len(item.ColIDs))
+		for _, id := range item.ColIDs {
+			uniqueID, ok := colInfoID2UniqueID[id]
+			if !ok {
+				break
+			}
+			uniqueIDs = append(uniqueIDs, uniqueID)
+		}
+		if len(uniqueIDs) != len(item.ColIDs) {
+			continue
+		}
+		newColl.ColGroupStats = append(newColl.ColGroupStats, &ExtendedStatsItem{
+			ColIDs:     uniqueIDs,
+			Tp:         item.Tp,
+			ScalarVals: item.ScalarVals,
+		})
+	}
+	return newColl
+}
+
 // isSingleColIdxNullRange checks if a range is [NULL, NULL] on a single-column index.
 func isSingleColIdxNullRange(idx *Index, ran *ranger.Range) bool {
 	if len(idx.Info.Columns) > 1 {
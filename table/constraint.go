@@ -0,0 +1,70 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides meta data describing a check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	// ConstraintExpr is the check expression, its columns refer to the row by the column offsets.
+	ConstraintExpr expression.Expression
+}
+
+// ToConstraint converts a *model.ConstraintInfo to *Constraint.
+// The expression is built with the session context, so it follows the sql_mode,
+// time_zone and collation of the session which writes the rows.
+func ToConstraint(ctx sessionctx.Context, constraintInfo *model.ConstraintInfo, tblInfo *model.TableInfo) (*Constraint, error) {
+	expr, err := expression.ParseSimpleExprWithTableInfo(ctx, constraintInfo.ExprString, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Constraint{
+		ConstraintInfo: constraintInfo,
+		ConstraintExpr: expr,
+	}, nil
+}
+
+// IsWritable checks whether the constraint must be enforced by the writes.
+func (c *Constraint) IsWritable() bool {
+	if !c.Enforced {
+		return false
+	}
+	switch c.State {
+	case model.StateWriteOnly, model.StateWriteReorganization, model.StatePublic:
+		return true
+	}
+	return false
+}
+
+// CheckRow checks whether the row satisfies the constraint. A constraint is
+// only violated when its expression is evaluated to false, NULL passes the check.
+func (c *Constraint) CheckRow(sctx sessionctx.Context, row []types.Datum) error {
+	val, isNull, err := c.ConstraintExpr.EvalInt(sctx, chunk.MutRowFromDatums(row).ToRow())
+	if err != nil {
+		return err
+	}
+	if !isNull && val == 0 {
+		return ErrCheckConstraintViolated.FastGenByArgs(c.Name.O)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"sync"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/generatedexpr"
+)
+
+// IsMultiValuedColumn returns whether the column is the hidden column of a multi-valued index part.
+func IsMultiValuedColumn(col *model.ColumnInfo) bool {
+	return col.Hidden && types.HasArrayFlag(col.Flag)
+}
+
+// FindMultiValuedColumn returns the offset of the multi-valued key part in the index columns, -1 if the index
+// isn't a multi-valued index.
+func FindMultiValuedColumn(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) int {
+	for i, idxCol := range idxInfo.Columns {
+		if IsMultiValuedColumn(tblInfo.Columns[idxCol.Offset]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexExprString returns the expression of the hidden column of an expression index part as it's written in the
+// index definition. The generated expression of a multi-valued column is stored as CAST(expr AS type), since
+// restoring the CAST doesn't write the ARRAY keyword, it's added back here.
+func IndexExprString(col *model.ColumnInfo) string {
+	expr := col.GeneratedExprString
+	if !IsMultiValuedColumn(col) || len(expr) == 0 {
+		return expr
+	}
+	return expr[:len(expr)-1] + " array)"
+}
+
+// multiValuedElemTypes caches the element types of the multi-valued index columns by their generated expressions,
+// so that each expression is parsed only once. The number of entries is bounded by the multi-valued indexes created.
+var multiValuedElemTypes sync.Map
+
+// MultiValuedElemType returns the type of the array elements of a multi-valued index column, which is the
+// target type of the CAST in the generated expression. The strings are compared in binary as MySQL does.
+func MultiValuedElemType(col *model.ColumnInfo) (*types.FieldType, error) {
+	if tp, ok := multiValuedElemTypes.Load(col.GeneratedExprString); ok {
+		return tp.(*types.FieldType).Clone(), nil
+	}
+	elemTp, err := parseMultiValuedElemType(col.GeneratedExprString)
+	if err != nil {
+		return nil, err
+	}
+	multiValuedElemTypes.Store(col.GeneratedExprString, elemTp)
+	return elemTp.Clone(), nil
+}
+
+func parseMultiValuedElemType(exprString string) (*types.FieldType, error) {
+	node, err := generatedexpr.ParseExpression(exprString)
+	if err != nil {
+		return nil, err
+	}
+	_, tp, err := generatedexpr.UnwrapArrayCast(node)
+	if err != nil {
+		return nil, err
+	}
+	elemTp := tp.Clone()
+	elemTp.Flag &= ^types.ArrayFlag
+	if types.IsString(elemTp.Tp) {
+		if elemTp.Charset == charset.CharsetBin {
+			elemTp.Collate = charset.CollationBin
+		} else {
+			elemTp.Charset, elemTp.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+		}
+	}
+	return elemTp, nil
+}
+
+// MultiValuedElems returns the elements of the value of a multi-valued index column converted to the element
+// type, a scalar is treated as an array with one element. The elements are neither sorted nor deduplicated.
+func MultiValuedElems(sc *stmtctx.StatementContext, value types.Datum, elemTp *types.FieldType, colName string) ([]types.Datum, error) {
+	bj := value.GetMysqlJSON()
+	if bj.TypeCode != json.TypeCodeArray {
+		d, err := ConvertMultiValuedElem(sc, bj, elemTp, colName)
+		if err != nil {
+			return nil, err
+		}
+		return []types.Datum{d}, nil
+	}
+	elems := make([]types.Datum, 0, bj.GetElemCount())
+	for i := 0; i < bj.GetElemCount(); i++ {
+		d, err := ConvertMultiValuedElem(sc, bj.ArrayGetElem(i), elemTp, colName)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, d)
+	}
+	return elems, nil
+}
+
+// ConvertMultiValuedElem converts an element of the JSON array to the element type of a multi-valued index column.
+// Numbers can only be stored as numeric types and strings can only be stored as string and temporal types.
+func ConvertMultiValuedElem(sc *stmtctx.StatementContext, elem json.BinaryJSON, elemTp *types.FieldType, colName string) (types.Datum, error) {
+	var d types.Datum
+	isStringTp := types.IsString(elemTp.Tp) || types.IsTypeTime(elemTp.Tp) || elemTp.Tp == mysql.TypeDuration
+	switch elem.TypeCode {
+	case json.TypeCodeInt64:
+		d.SetInt64(elem.GetInt64())
+	case json.TypeCodeUint64:
+		d.SetUint64(elem.GetUint64())
+	case json.TypeCodeFloat64:
+		d.SetFloat64(elem.GetFloat64())
+	case json.TypeCodeString:
+		d.SetString(string(elem.GetString()), elemTp.Collate)
+	default:
+		return d, ErrInvalidJSONValueForFuncIndex.GenWithStackByArgs(colName)
+	}
+	if isStringTp != (elem.TypeCode == json.TypeCodeString) {
+		return d, ErrInvalidJSONValueForFuncIndex.GenWithStackByArgs(colName)
+	}
+	// The truncations and overflows are always errors, whatever the SQL mode is.
+	strictSC := &stmtctx.StatementContext{TimeZone: sc.TimeZone}
+	v, err := d.ConvertTo(strictSC, elemTp)
+	if err != nil {
+		if types.ErrOverflow.Equal(err) || types.ErrDataTooLong.Equal(err) {
+			return d, ErrJSONValueOutOfRangeForFuncIndex.GenWithStackByArgs(colName)
+		}
+		return d, ErrInvalidJSONValueForFuncIndex.GenWithStackByArgs(colName)
+	}
+	return v, nil
+}
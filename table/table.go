@@ -100,6 +100,12 @@ var (
 	ErrRowDoesNotMatchGivenPartitionSet = dbterror.ClassTable.NewStd(mysql.ErrRowDoesNotMatchGivenPartitionSet)
 	// ErrTempTableFull returns a table is full error, it's used by temporary table now.
 	ErrTempTableFull = dbterror.ClassTable.NewStd(mysql.ErrRecordFileFull)
+	// ErrCheckConstraintViolated returns when the row doesn't satisfy a check constraint.
+	ErrCheckConstraintViolated = dbterror.ClassTable.NewStd(mysql.ErrCheckConstraintViolated)
+	// ErrInvalidJSONValueForFuncIndex returns when an element of the array can't be stored in the multi-valued index.
+	ErrInvalidJSONValueForFuncIndex = dbterror.ClassTable.NewStd(mysql.ErrInvalidJSONValueForFuncIndex)
+	// ErrJSONValueOutOfRangeForFuncIndex returns when an element of the array is out of the range of the multi-valued index.
+	ErrJSONValueOutOfRangeForFuncIndex = dbterror.ClassTable.NewStd(mysql.ErrJSONValueOutOfRangeForFuncIndex)
 )
 
 // RecordIterFunc is used for low-level record iteration.
@@ -17,6 +17,9 @@ package table
 import (
 	"testing"
 
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	parsermysql "github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	mysql "github.com/pingcap/tidb/errno"
 	"github.com/stretchr/testify/require"
@@ -42,3 +45,22 @@ func TestErrorCode(t *testing.T) {
 	require.Equal(t, mysql.ErrNoPartitionForGivenValue, int(terror.ToSQLError(ErrNoPartitionForGivenValue).Code))
 	require.Equal(t, mysql.ErrLockOrActiveTransaction, int(terror.ToSQLError(ErrLockOrActiveTransaction).Code))
 }
+
+func TestMultiValuedElemType(t *testing.T) {
+	t.Parallel()
+	col := &model.ColumnInfo{GeneratedExprString: "cast(json_extract(`j`, _utf8mb4'$.a') as char(10))"}
+	elemTp, err := MultiValuedElemType(col)
+	require.NoError(t, err)
+	require.Equal(t, parsermysql.TypeVarString, elemTp.Tp)
+	require.Equal(t, 10, elemTp.Flen)
+	require.Equal(t, charset.CollationUTF8MB4, elemTp.Collate)
+
+	// The cached type isn't shared with the callers.
+	elemTp.Flen = 20
+	elemTp, err = MultiValuedElemType(col)
+	require.NoError(t, err)
+	require.Equal(t, 10, elemTp.Flen)
+
+	_, err = MultiValuedElemType(&model.ColumnInfo{GeneratedExprString: "json_extract(`j`, _utf8mb4'$.a')"})
+	require.Error(t, err)
+}
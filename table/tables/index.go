@@ -30,7 +30,9 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"go.uber.org/zap"
 )
 
 // indexIter is for KV store index iterator.
@@ -92,6 +94,14 @@ type index struct {
 	// the collation global variable is initialized *after* `NewIndex()`.
 	initNeedRestoreData sync.Once
 	needRestoredData    bool
+	// mvOffset is the offset of the multi-valued key part in the index columns, it's -1 if the index isn't
+	// a multi-valued index. For a multi-valued index, an entry is built for each element of the array.
+	mvOffset   int
+	mvElemType *types.FieldType
+	// colInfos and tps are the types of the index columns to decode the index entries, they're built once with the
+	// index, so the element type of the multi-valued key part isn't resolved again for each read.
+	colInfos []rowcodec.ColInfo
+	tps      []*types.FieldType
 }
 
 // NeedRestoredData checks whether the index columns needs restored data.
@@ -121,10 +131,45 @@ func NewIndex(physicalID int64, tblInfo *model.TableInfo, indexInfo *model.Index
 		tblInfo:  tblInfo,
 		prefix:   prefix,
 		phyTblID: physicalID,
+		mvOffset: table.FindMultiValuedColumn(tblInfo, indexInfo),
+	}
+	if index.mvOffset >= 0 {
+		elemType, err := table.MultiValuedElemType(tblInfo.Columns[indexInfo.Columns[index.mvOffset].Offset])
+		if err != nil {
+			logutil.BgLogger().Error("invalid multi-valued index", zap.String("index", indexInfo.Name.O), zap.Error(err))
+		}
+		index.mvElemType = elemType
 	}
+	index.colInfos = buildRowcodecColInfoForIndexColumns(indexInfo, tblInfo, index.mvElemType)
+	index.tps = buildFieldTypesForIndexColumns(indexInfo, tblInfo, index.mvElemType)
 	return index
 }
 
+// forEachMultiValuedEntry calls fn with the indexed values of each entry of the multi-valued index, the JSON array
+// in indexedValues is replaced by its elements. A NULL array has an entry for NULL and an empty array has no entry.
+func (c *index) forEachMultiValuedEntry(sc *stmtctx.StatementContext, indexedValues []types.Datum, fn func([]types.Datum) error) error {
+	if indexedValues[c.mvOffset].IsNull() {
+		return fn(indexedValues)
+	}
+	if c.mvElemType == nil {
+		return errors.Errorf("invalid multi-valued index %s", c.idxInfo.Name.O)
+	}
+	colName := c.tblInfo.Columns[c.idxInfo.Columns[c.mvOffset].Offset].GeneratedExprString
+	elems, err := table.MultiValuedElems(sc, indexedValues[c.mvOffset], c.mvElemType, colName)
+	if err != nil {
+		return err
+	}
+	vals := make([]types.Datum, len(indexedValues))
+	copy(vals, indexedValues)
+	for _, elem := range elems {
+		vals[c.mvOffset] = elem
+		if err := fn(vals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Meta returns index info.
 func (c *index) Meta() *model.IndexInfo {
 	return c.idxInfo
@@ -144,6 +189,18 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	if c.mvOffset >= 0 {
+		// The multi-valued index can't be unique, so there is no existing handle to return.
+		err := c.forEachMultiValuedEntry(sctx.GetSessionVars().StmtCtx, indexedValues, func(vals []types.Datum) error {
+			_, err := c.create(sctx, txn, vals, h, handleRestoreData, opts...)
+			return err
+		})
+		return nil, err
+	}
+	return c.create(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+}
+
+func (c *index) create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
 		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
 	}
@@ -229,6 +286,15 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
+	if c.mvOffset >= 0 {
+		return c.forEachMultiValuedEntry(sc, indexedValues, func(vals []types.Datum) error {
+			return c.delete(sc, txn, vals, h)
+		})
+	}
+	return c.delete(sc, txn, indexedValues, h)
+}
+
+func (c *index) delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return err
@@ -283,9 +349,7 @@ func (c *index) Seek(sc *stmtctx.StatementContext, r kv.Retriever, indexedValues
 	if it.Valid() && it.Key().Cmp(key) == 0 {
 		hit = true
 	}
-	colInfos := BuildRowcodecColInfoForIndexColumns(c.idxInfo, c.tblInfo)
-	tps := BuildFieldTypesForIndexColumns(c.idxInfo, c.tblInfo)
-	return &indexIter{it: it, idx: c, prefix: c.prefix, colInfos: colInfos, tps: tps}, hit, nil
+	return &indexIter{it: it, idx: c, prefix: c.prefix, colInfos: c.colInfos, tps: c.tps}, hit, nil
 }
 
 // SeekFirst returns an iterator which points to the first entry of the KV index.
@@ -295,12 +359,27 @@ func (c *index) SeekFirst(r kv.Retriever) (iter table.IndexIterator, err error)
 	if err != nil {
 		return nil, err
 	}
-	colInfos := BuildRowcodecColInfoForIndexColumns(c.idxInfo, c.tblInfo)
-	tps := BuildFieldTypesForIndexColumns(c.idxInfo, c.tblInfo)
-	return &indexIter{it: it, idx: c, prefix: c.prefix, colInfos: colInfos, tps: tps}, nil
+	return &indexIter{it: it, idx: c, prefix: c.prefix, colInfos: c.colInfos, tps: c.tps}, nil
 }
 
 func (c *index) Exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
+	if c.mvOffset >= 0 {
+		// The row exists in the multi-valued index only if all of its entries exist.
+		exist := true
+		err := c.forEachMultiValuedEntry(sc, indexedValues, func(vals []types.Datum) error {
+			entryExist, _, err := c.exist(sc, txn, vals, h)
+			exist = exist && entryExist
+			return err
+		})
+		if err != nil {
+			return false, nil, err
+		}
+		return exist, h, nil
+	}
+	return c.exist(sc, txn, indexedValues, h)
+}
+
+func (c *index) exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return false, nil, err
@@ -367,13 +446,17 @@ func IsIndexWritable(idx table.Index) bool {
 // BuildRowcodecColInfoForIndexColumns builds []rowcodec.ColInfo for the given index.
 // The result can be used for decoding index key-values.
 func BuildRowcodecColInfoForIndexColumns(idxInfo *model.IndexInfo, tblInfo *model.TableInfo) []rowcodec.ColInfo {
+	return buildRowcodecColInfoForIndexColumns(idxInfo, tblInfo, multiValuedElemType(idxInfo, tblInfo))
+}
+
+func buildRowcodecColInfoForIndexColumns(idxInfo *model.IndexInfo, tblInfo *model.TableInfo, mvElemType *types.FieldType) []rowcodec.ColInfo {
 	colInfo := make([]rowcodec.ColInfo, 0, len(idxInfo.Columns))
 	for _, idxCol := range idxInfo.Columns {
 		col := tblInfo.Columns[idxCol.Offset]
 		colInfo = append(colInfo, rowcodec.ColInfo{
 			ID:         col.ID,
 			IsPKHandle: tblInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag),
-			Ft:         rowcodec.FieldTypeFromModelColumn(col),
+			Ft:         fieldTypeForIndexColumn(col, mvElemType),
 		})
 	}
 	return colInfo
@@ -381,14 +464,41 @@ func BuildRowcodecColInfoForIndexColumns(idxInfo *model.IndexInfo, tblInfo *mode
 
 // BuildFieldTypesForIndexColumns builds the index columns field types.
 func BuildFieldTypesForIndexColumns(idxInfo *model.IndexInfo, tblInfo *model.TableInfo) []*types.FieldType {
+	return buildFieldTypesForIndexColumns(idxInfo, tblInfo, multiValuedElemType(idxInfo, tblInfo))
+}
+
+func buildFieldTypesForIndexColumns(idxInfo *model.IndexInfo, tblInfo *model.TableInfo, mvElemType *types.FieldType) []*types.FieldType {
 	tps := make([]*types.FieldType, 0, len(idxInfo.Columns))
 	for _, idxCol := range idxInfo.Columns {
 		col := tblInfo.Columns[idxCol.Offset]
-		tps = append(tps, rowcodec.FieldTypeFromModelColumn(col))
+		tps = append(tps, fieldTypeForIndexColumn(col, mvElemType))
 	}
 	return tps
 }
 
+// multiValuedElemType returns the element type of the multi-valued key part of the index, nil if the index isn't
+// a multi-valued index or the type is invalid.
+func multiValuedElemType(idxInfo *model.IndexInfo, tblInfo *model.TableInfo) *types.FieldType {
+	mvOffset := table.FindMultiValuedColumn(tblInfo, idxInfo)
+	if mvOffset < 0 {
+		return nil
+	}
+	elemTp, err := table.MultiValuedElemType(tblInfo.Columns[idxInfo.Columns[mvOffset].Offset])
+	if err != nil {
+		return nil
+	}
+	return elemTp
+}
+
+// fieldTypeForIndexColumn returns the field type of the column stored in the index, it's the element type for the
+// multi-valued key part.
+func fieldTypeForIndexColumn(col *model.ColumnInfo, mvElemType *types.FieldType) *types.FieldType {
+	if mvElemType != nil && table.IsMultiValuedColumn(col) {
+		return mvElemType
+	}
+	return rowcodec.FieldTypeFromModelColumn(col)
+}
+
 // TryAppendCommonHandleRowcodecColInfos tries to append common handle columns to `colInfo`.
 func TryAppendCommonHandleRowcodecColInfos(colInfo []rowcodec.ColInfo, tblInfo *model.TableInfo) []rowcodec.ColInfo {
 	if !tblInfo.IsCommonHandle || tblInfo.CommonHandleVersion == 0 {
@@ -1126,7 +1126,11 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil || !t.reorg.isReorganized(pid) {
+		return recordID, err
+	}
+	return recordID, t.reorg.addRecord(ctx, recordID, r)
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1170,7 +1174,10 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	if err = tbl.RemoveRecord(ctx, h, r); err != nil || !t.reorg.isReorganized(pid) {
+		return err
+	}
+	return t.reorg.removeRecord(ctx, h, r)
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1230,11 +1237,208 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
+	} else {
+		tbl := t.GetPartition(to)
+		if err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if t.reorg == nil {
+		return nil
+	}
+	return t.reorg.updateRecord(ctx, h, currData, newData, t.reorg.isReorganized(from), t.reorg.isReorganized(to))
+}
+
+// partitionReorg is the layout of the partitions that a table is being reorganized to or from by REORGANIZE
+// PARTITION, PARTITION BY or REMOVE PARTITIONING. The rows written to the reorganized partitions of the table are
+// also written to it, so that it's consistent with the table until the reorganization is done.
+//
+// The partitions being reorganized are kept in PartitionInfo: AddingDefinitions are the new ones and
+// DroppingDefinitions are the old ones. A definition without name stands for a non-partitioned table. Before the
+// reads are switched to the new partitions, the rows are written to the new partitions according to their schema
+// state in PartitionInfo.States; after the switch, the rows are written to the old partitions as well, for the
+// servers which are still reading them.
+type partitionReorg struct {
+	// tbl is the table of the other layout, it's a partitioned table or a physical table.
+	tbl table.Table
+	// ids are the physical IDs of the table whose rows are written to tbl as well.
+	// It's nil if the table isn't partitioned, all rows of the table are written to tbl then.
+	ids map[int64]struct{}
+	// deleteOnly means the rows are only removed from tbl.
+	deleteOnly bool
+}
+
+// IsPartitionReorganizing returns whether the partitions of the table are being reorganized, see partitionReorg.
+func IsPartitionReorganizing(tblInfo *model.TableInfo) bool {
+	pi := tblInfo.Partition
+	return pi != nil && len(pi.AddingDefinitions) > 0 && len(pi.DroppingDefinitions) > 0
+}
+
+// IsPartitionReorgSwitched returns whether the reads of the table have been switched to the new partitions of the
+// partition reorganization.
+func IsPartitionReorgSwitched(tblInfo *model.TableInfo) bool {
+	id := tblInfo.Partition.AddingDefinitions[0].ID
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return id == tblInfo.ID
+	}
+	for _, def := range pi.Definitions {
+		if def.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWholeTableDefinitions returns whether the partition definitions stand for a non-partitioned table.
+func IsWholeTableDefinitions(defs []model.PartitionDefinition) bool {
+	return len(defs) == 1 && defs[0].Name.L == ""
+}
+
+// ReplacePartitionDefinitions replaces the definitions of from in defs with to.
+// The definitions of from are expected to be adjacent in defs, to are put at the position of the first one.
+func ReplacePartitionDefinitions(defs, from, to []model.PartitionDefinition) []model.PartitionDefinition {
+	ret := make([]model.PartitionDefinition, 0, len(defs)-len(from)+len(to))
+	replaced := false
+	for _, def := range defs {
+		if !containsPartitionDefinition(from, def.ID) {
+			ret = append(ret, def)
+			continue
+		}
+		if !replaced {
+			ret = append(ret, to...)
+			replaced = true
+		}
+	}
+	return ret
+}
+
+func containsPartitionDefinition(defs []model.PartitionDefinition, id int64) bool {
+	for _, def := range defs {
+		if def.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPartitionReorgTable returns the table of the other partition layout of a table whose partitions are being
+// reorganized, it returns nil if the table isn't being reorganized.
+func GetPartitionReorgTable(t table.Table) table.Table {
+	var reorg *partitionReorg
+	switch tbl := t.(type) {
+	case *TableCommon:
+		reorg = tbl.reorg
+	case *partitionedTable:
+		reorg = tbl.reorg
+	}
+	if reorg == nil {
 		return nil
 	}
+	return reorg.tbl
+}
 
-	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+func newPartitionReorg(tbl *TableCommon, tblInfo *model.TableInfo) (*partitionReorg, error) {
+	if !IsPartitionReorganizing(tblInfo) {
+		return nil, nil
+	}
+	pi := tblInfo.Partition
+	// from are the reorganized partitions of the table, to are the ones which the rows are written to as well.
+	from, to := pi.DroppingDefinitions, pi.AddingDefinitions
+	switched := IsPartitionReorgSwitched(tblInfo)
+	if switched {
+		from, to = to, from
+	}
+	ret := &partitionReorg{deleteOnly: !switched && pi.GetStateByID(to[0].ID) == model.StateDeleteOnly}
+	if tblInfo.GetPartitionInfo() != nil {
+		ret.ids = make(map[int64]struct{}, len(from))
+		for _, def := range from {
+			ret.ids[def.ID] = struct{}{}
+		}
+	}
+
+	if IsWholeTableDefinitions(to) {
+		var t partition
+		if err := initTableCommonWithIndices(&t.TableCommon, tblInfo, to[0].ID, tbl.Columns, tbl.allocs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ret.tbl = &t
+		return ret, nil
+	}
+
+	defs := to
+	if tblInfo.GetPartitionInfo() != nil {
+		defs = ReplacePartitionDefinitions(pi.Definitions, from, to)
+	}
+	reorgedInfo := tblInfo.Clone()
+	reorgedInfo.Partition = &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Enable:      true,
+		Definitions: defs,
+		Num:         uint64(len(defs)),
+	}
+	var t TableCommon
+	initTableCommon(&t, reorgedInfo, reorgedInfo.ID, tbl.Columns, tbl.allocs)
+	reorgedTbl, err := newPartitionedTable(&t, reorgedInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret.tbl = reorgedTbl
+	return ret, nil
+}
+
+// isReorganized returns whether the rows of the physical table need to be written to the other layout.
+func (r *partitionReorg) isReorganized(pid int64) bool {
+	if r == nil {
+		return false
+	}
+	if r.ids == nil {
+		return true
+	}
+	_, ok := r.ids[pid]
+	return ok
+}
+
+func (r *partitionReorg) addRecord(ctx sessionctx.Context, h kv.Handle, row []types.Datum) error {
+	if r.deleteOnly {
+		return nil
+	}
+	tblInfo := r.tbl.Meta()
+	if !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle {
+		// Keep the same _tidb_rowid in the other layout.
+		cols := r.tbl.Cols()
+		if len(row) > len(cols) {
+			row = row[:len(cols)]
+		}
+		row = append(append(make([]types.Datum, 0, len(cols)+1), row...), types.NewIntDatum(h.IntValue()))
+	}
+	_, err := r.tbl.AddRecord(ctx, row)
+	return errors.Trace(err)
+}
+
+func (r *partitionReorg) removeRecord(ctx sessionctx.Context, h kv.Handle, row []types.Datum) error {
+	err := r.tbl.RemoveRecord(ctx, h, row)
+	if table.ErrNoPartitionForGivenValue.Equal(err) {
+		// The row can't be in the other layout.
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// updateRecord writes the update of a row to the other layout, removed and added mean whether the old row and the new
+// row are in the reorganized partitions.
+func (r *partitionReorg) updateRecord(ctx sessionctx.Context, h kv.Handle, currData, newData []types.Datum, removed, added bool) error {
+	if removed {
+		if err := r.removeRecord(ctx, h, currData); err != nil {
+			return err
+		}
+	}
+	if added {
+		return r.addRecord(ctx, h, newData)
+	}
+	return nil
 }
 
 // FindPartitionByName finds partition in table meta by name.
@@ -68,6 +68,8 @@ type TableCommon struct {
 	meta                            *model.TableInfo
 	allocs                          autoid.Allocators
 	sequence                        *sequenceCommon
+	// reorg is not nil when the partitions of the table are being reorganized.
+	reorg *partitionReorg
 
 	// recordPrefix and indexPrefix are generated using physicalTableID.
 	recordPrefix kv.Key
@@ -122,6 +124,13 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 			if err != nil {
 				return nil, err
 			}
+			if table.IsMultiValuedColumn(colInfo) {
+				// The column stores the JSON array, the elements are cast when the index entries are built.
+				expr, _, err = generatedexpr.UnwrapArrayCast(expr)
+				if err != nil {
+					return nil, err
+				}
+			}
 			expr, err = generatedexpr.SimpleResolveName(expr, tblInfo)
 			if err != nil {
 				return nil, err
@@ -141,6 +150,11 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, allocs)
+	reorg, err := newPartitionReorg(&t, tblInfo)
+	if err != nil {
+		return nil, err
+	}
+	t.reorg = reorg
 	if tblInfo.GetPartitionInfo() == nil {
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
@@ -320,6 +334,13 @@ func (t *TableCommon) RecordKey(h kv.Handle) kv.Key {
 // `touched` means which columns are really modified, used for secondary indices.
 // Length of `oldData` and `newData` equals to length of `t.WritableCols()`.
 func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, touched []bool) error {
+	if err := t.updateRecord(ctx, sctx, h, oldData, newData, touched); err != nil || t.reorg == nil {
+		return err
+	}
+	return t.reorg.updateRecord(sctx, h, oldData, newData, true, true)
+}
+
+func (t *TableCommon) updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, touched []bool) error {
 	txn, err := sctx.Txn(true)
 	if err != nil {
 		return err
@@ -632,6 +653,14 @@ func checkTempTableSize(ctx sessionctx.Context, tmpTable tableutil.TempTable, tb
 
 // AddRecord implements table.Table AddRecord interface.
 func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts ...table.AddRecordOption) (recordID kv.Handle, err error) {
+	recordID, err = t.addRecord(sctx, r, opts...)
+	if err != nil || t.reorg == nil {
+		return recordID, err
+	}
+	return recordID, t.reorg.addRecord(sctx, recordID, r)
+}
+
+func (t *TableCommon) addRecord(sctx sessionctx.Context, r []types.Datum, opts ...table.AddRecordOption) (recordID kv.Handle, err error) {
 	txn, err := sctx.Txn(true)
 	if err != nil {
 		return nil, err
@@ -1047,6 +1076,13 @@ func GetChangingColVal(ctx sessionctx.Context, cols []*table.Column, col *table.
 
 // RemoveRecord implements table.Table RemoveRecord interface.
 func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	if err := t.removeRecord(ctx, h, r); err != nil || t.reorg == nil {
+		return err
+	}
+	return t.reorg.removeRecord(ctx, h, r)
+}
+
+func (t *TableCommon) removeRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
 	err := t.removeRowData(ctx, h)
 	if err != nil {
 		return err
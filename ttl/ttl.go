@@ -0,0 +1,120 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+)
+
+const (
+	// AttributeTTL is the table attribute which sets the TTL of the rows,
+	// e.g. `ALTER TABLE t ATTRIBUTES="ttl=created_at + interval 1 day"`.
+	AttributeTTL = "ttl"
+	// AttributeTTLEnable is the table attribute which pauses or resumes the TTL job of the table,
+	// e.g. `ALTER TABLE t ATTRIBUTES="ttl=created_at + interval 1 day,ttl_enable=off"`.
+	AttributeTTLEnable = "ttl_enable"
+)
+
+// Info is the TTL attribute of a table. A row is expired when the value of its TTL column
+// is earlier than `NOW() - INTERVAL IntervalExpr IntervalUnit`.
+type Info struct {
+	// ColumnID is the ID of the TTL column, so that renaming the column keeps the TTL attribute.
+	ColumnID     int64  `json:"column_id"`
+	IntervalExpr string `json:"interval_expr"`
+	IntervalUnit string `json:"interval_unit"`
+	Enable       bool   `json:"enable"`
+}
+
+// Clone clones the TTL info.
+func (i *Info) Clone() *Info {
+	ni := *i
+	return &ni
+}
+
+// IntervalString returns the interval in the SQL form, e.g. "INTERVAL 1 DAY".
+func (i *Info) IntervalString() string {
+	return fmt.Sprintf("INTERVAL %s %s", i.IntervalExpr, i.IntervalUnit)
+}
+
+// ExpireTime returns the time before which the rows are expired. Like `NOW() - INTERVAL ...` in a
+// session whose time zone is loc, the interval is subtracted from the wall clock in loc.
+func (i *Info) ExpireTime(now time.Time, loc *time.Location) (time.Time, error) {
+	y, m, d, n, err := types.ParseDurationValue(i.IntervalUnit, i.IntervalExpr)
+	if err != nil {
+		return now, errors.Trace(err)
+	}
+	return now.In(loc).AddDate(-int(y), -int(m), -int(d)).Add(-time.Duration(n)), nil
+}
+
+// SplitAttribute splits an attribute like "ttl=created_at + interval 1 day" into its key and value.
+// ok is false if the attribute is not a TTL attribute.
+func SplitAttribute(attr string) (key, value string, ok bool) {
+	pos := strings.IndexByte(attr, '=')
+	if pos < 0 {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(attr[:pos]))
+	if key != AttributeTTL && key != AttributeTTLEnable {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(attr[pos+1:]), true
+}
+
+// ParseExpr parses the TTL expression like "created_at + interval 1 day", it returns
+// the name of the TTL column and the interval.
+func ParseExpr(expr string) (colName model.CIStr, intervalExpr, intervalUnit string, err error) {
+	stmt, err := parser.New().ParseOneStmt("SELECT "+expr, "", "")
+	if err != nil {
+		return colName, "", "", errors.Trace(err)
+	}
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.From != nil || sel.Where != nil || len(sel.Fields.Fields) != 1 {
+		return colName, "", "", errors.Errorf("invalid TTL expression '%s'", expr)
+	}
+	fn, ok := sel.Fields.Fields[0].Expr.(*ast.FuncCallExpr)
+	if !ok || fn.FnName.L != ast.DateAdd || len(fn.Args) != 3 {
+		return colName, "", "", errors.Errorf("TTL expression '%s' should be in the form of `column + INTERVAL expr unit`", expr)
+	}
+	col, ok := fn.Args[0].(*ast.ColumnNameExpr)
+	if !ok || col.Name.Schema.L != "" || col.Name.Table.L != "" {
+		return colName, "", "", errors.Errorf("TTL expression '%s' should be in the form of `column + INTERVAL expr unit`", expr)
+	}
+	val, ok := fn.Args[1].(*driver.ValueExpr)
+	if !ok {
+		return colName, "", "", errors.Errorf("the interval of TTL expression '%s' should be a literal", expr)
+	}
+	intervalExpr, err = val.ToString()
+	if err != nil {
+		return colName, "", "", errors.Trace(err)
+	}
+	intervalUnit = fn.Args[2].(*ast.TimeUnitExpr).Unit.String()
+	y, m, d, n, err := types.ParseDurationValue(intervalUnit, intervalExpr)
+	if err != nil {
+		return colName, "", "", errors.Trace(err)
+	}
+	if y < 0 || m < 0 || d < 0 || n < 0 {
+		return colName, "", "", errors.Errorf("the interval of TTL expression '%s' should not be negative", expr)
+	}
+	return col.Name.Name, intervalExpr, intervalUnit, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAttribute(t *testing.T) {
+	t.Parallel()
+
+	key, value, ok := SplitAttribute(" TTL = created_at + interval 1 day")
+	require.True(t, ok)
+	require.Equal(t, AttributeTTL, key)
+	require.Equal(t, "created_at + interval 1 day", value)
+
+	key, value, ok = SplitAttribute("ttl_enable=off")
+	require.True(t, ok)
+	require.Equal(t, AttributeTTLEnable, key)
+	require.Equal(t, "off", value)
+
+	_, _, ok = SplitAttribute("nomerge")
+	require.False(t, ok)
+	_, _, ok = SplitAttribute("ttl_unknown=1")
+	require.False(t, ok)
+}
+
+func TestParseExpr(t *testing.T) {
+	t.Parallel()
+
+	col, expr, unit, err := ParseExpr("created_at + interval 1 day")
+	require.NoError(t, err)
+	require.Equal(t, "created_at", col.O)
+	require.Equal(t, "1", expr)
+	require.Equal(t, "DAY", unit)
+
+	col, expr, unit, err = ParseExpr("`Create Time` + INTERVAL '1:30' hour_minute")
+	require.NoError(t, err)
+	require.Equal(t, "Create Time", col.O)
+	require.Equal(t, "1:30", expr)
+	require.Equal(t, "HOUR_MINUTE", unit)
+
+	for _, expr := range []string{
+		"created_at",
+		"created_at + 1",
+		"created_at - interval 1 day",
+		"t.created_at + interval 1 day",
+		"created_at + interval a day",
+		"created_at + interval -1 day",
+		"created_at + interval 'x' day",
+		"created_at + interval 1 day from t",
+		"created_at + interval 1 day, a",
+	} {
+		_, _, _, err = ParseExpr(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestExpireTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		expr   string
+		unit   string
+		expect time.Time
+	}{
+		{"1", "DAY", time.Date(2021, 3, 30, 12, 0, 0, 0, time.UTC)},
+		{"2", "HOUR", time.Date(2021, 3, 31, 10, 0, 0, 0, time.UTC)},
+		{"1:30", "HOUR_MINUTE", time.Date(2021, 3, 31, 10, 30, 0, 0, time.UTC)},
+		{"1", "YEAR", time.Date(2020, 3, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, ca := range cases {
+		info := &Info{IntervalExpr: ca.expr, IntervalUnit: ca.unit}
+		expire, err := info.ExpireTime(now, time.UTC)
+		require.NoError(t, err)
+		require.Equal(t, ca.expect, expire, ca.expr+" "+ca.unit)
+	}
+
+	// The expire time is in the given time zone.
+	loc := time.FixedZone("", 8*60*60)
+	expire, err := (&Info{IntervalExpr: "1", IntervalUnit: "DAY"}).ExpireTime(now, loc)
+	require.NoError(t, err)
+	require.Equal(t, loc, expire.Location())
+	require.Equal(t, "2021-03-30 20:00:00", expire.Format("2006-01-02 15:04:05"))
+	require.Equal(t, "INTERVAL 1:30 HOUR_MINUTE", (&Info{IntervalExpr: "1:30", IntervalUnit: "HOUR_MINUTE"}).IntervalString())
+}
@@ -0,0 +1,526 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttlworker
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/ttl"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/zap"
+)
+
+const (
+	// OwnerKey is the TTL owner path that is saved to etcd.
+	OwnerKey = "/tidb/ttl/owner"
+	// Prompt is the prompt for TTL owner manager.
+	Prompt = "ttl"
+)
+
+// CheckInterval is the interval to check whether there are TTL jobs to run.
+var CheckInterval = time.Minute
+
+// The states of a TTL job saved in mysql.tidb_ttl_table_status.
+const (
+	JobStateRunning  = "running"
+	JobStateFinished = "finished"
+	JobStateFailed   = "failed"
+)
+
+// JobManager runs the TTL jobs. A TTL job deletes the expired rows of a table, it scans the
+// table region by region, and deletes the expired rows in batches. The progress of the job is
+// saved in mysql.tidb_ttl_table_status.
+type JobManager struct {
+	sctx       sessionctx.Context
+	store      kv.Storage
+	infoSchema func() infoschema.InfoSchema
+
+	lastDeleteTime time.Time
+}
+
+// NewJobManager creates a JobManager. The sctx is used to execute the internal SQLs of the jobs.
+func NewJobManager(sctx sessionctx.Context, infoSchema func() infoschema.InfoSchema) *JobManager {
+	return &JobManager{
+		sctx:       sctx,
+		store:      sctx.GetStore(),
+		infoSchema: infoSchema,
+	}
+}
+
+// ttlTable is a table with the TTL attribute.
+type ttlTable struct {
+	schema  model.CIStr
+	tblInfo *model.TableInfo
+	ttlInfo *ttl.Info
+	column  *model.ColumnInfo
+}
+
+// scanRange is the handle range [start, end) of a region, nil means unbounded.
+type scanRange struct {
+	start *int64
+	end   *int64
+}
+
+// RunJobs runs the TTL jobs of the tables whose last job starts earlier than `now - tidb_ttl_job_run_interval`.
+// The jobs are run one by one, and the running job stops when keepRunning returns false. The stopped job
+// will be restarted by the next call.
+func (m *JobManager) RunJobs(ctx context.Context, now time.Time, keepRunning func() bool) error {
+	enable, err := m.getGlobalSysVar(variable.TiDBTTLJobEnable)
+	if err != nil || !variable.TiDBOptOn(enable) {
+		return errors.Trace(err)
+	}
+	intervalStr, err := m.getGlobalSysVar(variable.TiDBTTLJobRunInterval)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	runInterval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	ttlTables, err := m.loadTTLTables()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := m.execSQL(ctx, "SELECT table_id, job_state, job_start_time FROM mysql.tidb_ttl_table_status")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, row := range rows {
+		tableID := row.GetInt64(0)
+		tbl, ok := ttlTables[tableID]
+		if !ok {
+			// The table is dropped or its TTL attribute is removed.
+			if _, _, err = m.execSQL(ctx, "DELETE FROM mysql.tidb_ttl_table_status WHERE table_id = %?", tableID); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		// The running job may be left by the previous owner, restart it.
+		if row.GetString(1) == JobStateRunning {
+			continue
+		}
+		startTime, err := row.GetTime(2).GoTime(time.Local)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if now.Sub(startTime) < runInterval {
+			delete(ttlTables, tbl.tblInfo.ID)
+		}
+	}
+
+	for _, tbl := range ttlTables {
+		if !keepRunning() {
+			return nil
+		}
+		if !tbl.ttlInfo.Enable {
+			continue
+		}
+		if err = m.runTableJob(ctx, tbl, now, keepRunning); err != nil {
+			logutil.BgLogger().Warn("[ttl] run TTL job failed", zap.String("schema", tbl.schema.O),
+				zap.String("table", tbl.tblInfo.Name.O), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// loadTTLTables loads the tables with the TTL attribute from the meta, the key of the result is the table ID.
+func (m *JobManager) loadTTLTables() (map[int64]*ttlTable, error) {
+	is := m.infoSchema()
+	t := meta.NewSnapshotMeta(m.store.GetSnapshot(kv.MaxVersion))
+	ttlTables := make(map[int64]*ttlTable)
+	for _, db := range is.AllSchemas() {
+		ttlInfos, err := t.ListTableTTLs(db.ID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for tableID, ttlInfo := range ttlInfos {
+			tbl, ok := is.TableByID(tableID)
+			if !ok {
+				continue
+			}
+			tblInfo := tbl.Meta()
+			var column *model.ColumnInfo
+			for _, col := range tblInfo.Columns {
+				if col.ID == ttlInfo.ColumnID && col.State == model.StatePublic && types.IsTypeTime(col.Tp) {
+					column = col
+				}
+			}
+			ttlTables[tableID] = &ttlTable{
+				schema:  db.Name,
+				tblInfo: tblInfo,
+				ttlInfo: ttlInfo,
+				column:  column,
+			}
+		}
+	}
+	return ttlTables, nil
+}
+
+// runTableJob deletes the expired rows of the table, and saves the progress after each region is finished.
+func (m *JobManager) runTableJob(ctx context.Context, tbl *ttlTable, now time.Time, keepRunning func() bool) error {
+	if err := m.syncTimeZone(); err != nil {
+		return errors.Trace(err)
+	}
+	expireTime, err := tbl.ttlInfo.ExpireTime(now, m.sctx.GetSessionVars().Location())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	type physicalRange struct {
+		partition  string
+		physicalID int64
+		scanRange
+	}
+	physicalTables := []physicalRange{{physicalID: tbl.tblInfo.ID}}
+	if pi := tbl.tblInfo.GetPartitionInfo(); pi != nil {
+		physicalTables = physicalTables[:0]
+		for _, def := range pi.Definitions {
+			physicalTables = append(physicalTables, physicalRange{partition: def.Name.O, physicalID: def.ID})
+		}
+	}
+	var ranges []physicalRange
+	for _, pt := range physicalTables {
+		scanRanges, err := m.splitRangesByRegion(ctx, tbl.tblInfo, pt.physicalID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, r := range scanRanges {
+			ranges = append(ranges, physicalRange{partition: pt.partition, physicalID: pt.physicalID, scanRange: r})
+		}
+	}
+
+	_, _, err = m.execSQL(ctx, `REPLACE INTO mysql.tidb_ttl_table_status (table_id, job_state, job_start_time, job_finish_time,
+		expire_time, total_regions, scanned_regions, deleted_rows, error_message) VALUES (%?, %?, %?, NULL, %?, %?, 0, 0, NULL)`,
+		tbl.tblInfo.ID, JobStateRunning, now, expireTime, len(ranges))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var deletedRows int64
+	for i, r := range ranges {
+		if !keepRunning() {
+			return nil
+		}
+		var deleted int64
+		if tbl.column == nil {
+			err = errors.Errorf("the TTL column of table %s.%s doesn't exist or is not a time column", tbl.schema.O, tbl.tblInfo.Name.O)
+		} else {
+			deleted, err = m.deleteExpiredRows(ctx, tbl, r.partition, r.scanRange, expireTime, keepRunning)
+		}
+		deletedRows += deleted
+		if err != nil {
+			_, _, err1 := m.execSQL(ctx, `UPDATE mysql.tidb_ttl_table_status SET job_state = %?, job_finish_time = %?,
+				scanned_regions = %?, deleted_rows = %?, error_message = %? WHERE table_id = %?`,
+				JobStateFailed, time.Now(), i, deletedRows, err.Error(), tbl.tblInfo.ID)
+			if err1 != nil {
+				logutil.BgLogger().Warn("[ttl] update TTL job status failed", zap.Error(err1))
+			}
+			return errors.Trace(err)
+		}
+		_, _, err = m.execSQL(ctx, "UPDATE mysql.tidb_ttl_table_status SET scanned_regions = %?, deleted_rows = %? WHERE table_id = %?",
+			i+1, deletedRows, tbl.tblInfo.ID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, _, err = m.execSQL(ctx, "UPDATE mysql.tidb_ttl_table_status SET job_state = %?, job_finish_time = %? WHERE table_id = %?",
+		JobStateFinished, time.Now(), tbl.tblInfo.ID)
+	return errors.Trace(err)
+}
+
+// splitRangesByRegion splits the handle range of the physical table by the regions, so that the progress of
+// the job can be saved region by region. The whole table is scanned as a single range if the store has no
+// region cache, or the handle of the table is not a signed integer.
+func (m *JobManager) splitRangesByRegion(ctx context.Context, tblInfo *model.TableInfo, physicalID int64) ([]scanRange, error) {
+	s, ok := m.store.(interface {
+		GetRegionCache() *tikv.RegionCache
+	})
+	if !ok || tblInfo.IsCommonHandle {
+		return []scanRange{{}}, nil
+	}
+	if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && mysql.HasUnsignedFlag(pkCol.Flag) {
+		return []scanRange{{}}, nil
+	}
+	recordPrefix := tablecodec.GenTableRecordPrefix(physicalID)
+	regions, err := s.GetRegionCache().LoadRegionsInKeyRange(tikv.NewBackofferWithVars(ctx, 20000, nil), recordPrefix, recordPrefix.PrefixNext())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ranges := make([]scanRange, 0, len(regions))
+	for _, region := range regions {
+		ranges = append(ranges, scanRange{
+			start: decodeHandleBound(recordPrefix, region.StartKey()),
+			end:   decodeHandleBound(recordPrefix, region.EndKey()),
+		})
+	}
+	return ranges, nil
+}
+
+// decodeHandleBound decodes the region boundary key to the smallest handle whose record key is not
+// less than the key. It returns nil if the key is out of the record range of the table.
+func decodeHandleBound(recordPrefix kv.Key, key []byte) *int64 {
+	if bytes.Compare(key, recordPrefix) <= 0 || !bytes.HasPrefix(key, recordPrefix) {
+		return nil
+	}
+	rest := key[len(recordPrefix):]
+	if len(rest) < 8 {
+		rest = append(append([]byte{}, rest...), make([]byte, 8-len(rest))...)
+	}
+	_, handle, err := codec.DecodeInt(rest[:8])
+	if err != nil {
+		return nil
+	}
+	if len(rest) > 8 && handle < math.MaxInt64 {
+		handle++
+	}
+	return &handle
+}
+
+// deleteExpiredRows scans the expired rows in the range and deletes them in batches. It returns the number of deleted rows.
+func (m *JobManager) deleteExpiredRows(ctx context.Context, tbl *ttlTable, partition string, r scanRange, expireTime time.Time,
+	keepRunning func() bool) (int64, error) {
+	scanBatchSize, err := m.getGlobalSysVarInt(variable.TiDBTTLScanBatchSize)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	deleteBatchSize, err := m.getGlobalSysVarInt(variable.TiDBTTLDeleteBatchSize)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	rateLimit, err := m.getGlobalSysVarInt(variable.TiDBTTLDeleteRateLimit)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	keyCols := handleColumnNames(tbl.tblInfo)
+	intHandle := !tbl.tblInfo.IsCommonHandle
+	from := "%n.%n"
+	fromArgs := []interface{}{tbl.schema.O, tbl.tblInfo.Name.O}
+	if partition != "" {
+		from += " PARTITION (%n)"
+		fromArgs = append(fromArgs, partition)
+	}
+
+	var deletedRows int64
+	start := r.start
+	for keepRunning() {
+		var sb strings.Builder
+		args := make([]interface{}, 0, 8)
+		sb.WriteString("SELECT ")
+		for i, col := range keyCols {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("%n")
+			args = append(args, col)
+		}
+		sb.WriteString(" FROM " + from + " WHERE %n < %?")
+		args = append(append(args, fromArgs...), tbl.column.Name.O, expireTime)
+		if intHandle {
+			if start != nil {
+				sb.WriteString(" AND %n >= %?")
+				args = append(args, keyCols[0], *start)
+			}
+			if r.end != nil {
+				sb.WriteString(" AND %n < %?")
+				args = append(args, keyCols[0], *r.end)
+			}
+			sb.WriteString(" ORDER BY %n")
+			args = append(args, keyCols[0])
+		}
+		sb.WriteString(" LIMIT %?")
+		args = append(args, scanBatchSize)
+		rows, fields, err := m.execSQL(ctx, sb.String(), args...)
+		if err != nil {
+			return deletedRows, errors.Trace(err)
+		}
+
+		var scanDeleted int64
+		for i := 0; i < len(rows); i += int(deleteBatchSize) {
+			end := i + int(deleteBatchSize)
+			if end > len(rows) {
+				end = len(rows)
+			}
+			if err = m.throttle(ctx, rateLimit); err != nil {
+				return deletedRows, errors.Trace(err)
+			}
+			sql, args, err := buildDeleteSQL(from, fromArgs, keyCols, fields, rows[i:end], tbl.column.Name.O, expireTime)
+			if err != nil {
+				return deletedRows, errors.Trace(err)
+			}
+			if _, _, err = m.execSQL(ctx, sql, args...); err != nil {
+				return deletedRows, errors.Trace(err)
+			}
+			scanDeleted += int64(m.sctx.GetSessionVars().StmtCtx.AffectedRows())
+		}
+		deletedRows += scanDeleted
+
+		if int64(len(rows)) < scanBatchSize {
+			break
+		}
+		if intHandle {
+			last := rows[len(rows)-1].GetInt64(0)
+			if last == math.MaxInt64 {
+				break
+			}
+			last++
+			start = &last
+		} else if scanDeleted == 0 {
+			// The scanned rows are not expired anymore, they will be scanned again without a start handle.
+			break
+		}
+	}
+	return deletedRows, nil
+}
+
+// handleColumnNames returns the names of the columns which identify a row.
+func handleColumnNames(tblInfo *model.TableInfo) []string {
+	if tblInfo.IsCommonHandle {
+		pk := tables.FindPrimaryIndex(tblInfo)
+		names := make([]string, 0, len(pk.Columns))
+		for _, col := range pk.Columns {
+			names = append(names, col.Name.O)
+		}
+		return names
+	}
+	if pkCol := tblInfo.GetPkColInfo(); pkCol != nil {
+		return []string{pkCol.Name.O}
+	}
+	return []string{model.ExtraHandleName.O}
+}
+
+// buildDeleteSQL builds the statement which deletes the scanned rows if they are still expired.
+func buildDeleteSQL(from string, fromArgs []interface{}, keyCols []string, fields []*ast.ResultField, rows []chunk.Row,
+	ttlColumn string, expireTime time.Time) (string, []interface{}, error) {
+	var sb strings.Builder
+	args := make([]interface{}, 0, len(fromArgs)+len(keyCols)*(len(rows)+1)+2)
+	sb.WriteString("DELETE FROM " + from + " WHERE ")
+	args = append(args, fromArgs...)
+	if len(keyCols) > 1 {
+		sb.WriteString("(")
+	}
+	for i, col := range keyCols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("%n")
+		args = append(args, col)
+	}
+	if len(keyCols) > 1 {
+		sb.WriteString(")")
+	}
+	sb.WriteString(" IN (")
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if len(keyCols) > 1 {
+			sb.WriteString("(")
+		}
+		for j := range keyCols {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("%?")
+			if row.IsNull(j) {
+				args = append(args, nil)
+				continue
+			}
+			d := row.GetDatum(j, &fields[j].Column.FieldType)
+			s, err := d.ToString()
+			if err != nil {
+				return "", nil, errors.Trace(err)
+			}
+			args = append(args, s)
+		}
+		if len(keyCols) > 1 {
+			sb.WriteString(")")
+		}
+	}
+	sb.WriteString(") AND %n < %?")
+	args = append(args, ttlColumn, expireTime)
+	return sb.String(), args, nil
+}
+
+// throttle waits until the next delete statement is allowed by the rate limit.
+func (m *JobManager) throttle(ctx context.Context, rateLimit int64) error {
+	if rateLimit > 0 {
+		wait := time.Until(m.lastDeleteTime.Add(time.Second / time.Duration(rateLimit)))
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+	}
+	m.lastDeleteTime = time.Now()
+	return nil
+}
+
+// syncTimeZone sets the time zone of the job session to the global time_zone. The expire time is calculated in it,
+// and the internal SQLs compare the TTL column with the expire time in it: the DATETIME values, which don't have a
+// time zone, are regarded as the wall clock of the global time_zone, and the TIMESTAMP values are converted to it.
+func (m *JobManager) syncTimeZone() error {
+	tz, err := m.getGlobalSysVar(variable.TimeZone)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(m.sctx.GetSessionVars().SetSystemVar(variable.TimeZone, tz))
+}
+
+func (m *JobManager) getGlobalSysVar(name string) (string, error) {
+	return m.sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(name)
+}
+
+func (m *JobManager) getGlobalSysVarInt(name string) (int64, error) {
+	val, err := m.getGlobalSysVar(name)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+func (m *JobManager) execSQL(ctx context.Context, sql string, args ...interface{}) ([]chunk.Row, []*ast.ResultField, error) {
+	rs, err := m.sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx, sql, args...)
+	if err != nil || rs == nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer terror.Call(rs.Close)
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 1024)
+	return rows, rs.Fields(), errors.Trace(err)
+}
@@ -0,0 +1,186 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttlworker_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/ttl/ttlworker"
+	"github.com/stretchr/testify/require"
+)
+
+func alwaysRunning() bool {
+	return true
+}
+
+func newJobManager(tk *testkit.TestKit) *ttlworker.JobManager {
+	return ttlworker.NewJobManager(tk.Session(), domain.GetDomain(tk.Session()).InfoSchema)
+}
+
+func insertRows(tk *testkit.TestKit, table string, now time.Time, start, n int) {
+	for i := start; i < start+n; i++ {
+		// The rows with an even id are expired.
+		createdAt := now.Add(-time.Hour)
+		if i%2 == 0 {
+			createdAt = now.Add(-48 * time.Hour)
+		}
+		tk.MustExec(fmt.Sprintf("insert into %s values (%d, '%s')", table, i, createdAt.Format("2006-01-02 15:04:05")))
+	}
+}
+
+func TestRunJobs(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set global tidb_ttl_scan_batch_size = 3")
+	tk.MustExec("set global tidb_ttl_delete_batch_size = 2")
+	now := time.Now()
+
+	tk.MustExec("create table t1 (id int primary key, created_at datetime)")
+	tk.MustExec("create table t2 (id varchar(10) primary key clustered, created_at datetime)")
+	tk.MustExec("create table t3 (id int, created_at timestamp) partition by hash(id) partitions 3")
+	tk.MustExec("create table t4 (id int primary key, created_at datetime)")
+	for _, tbl := range []string{"t1", "t2", "t3", "t4"} {
+		insertRows(tk, tbl, now, 0, 20)
+		tk.MustExec(fmt.Sprintf("alter table %s attributes='ttl=created_at + interval 1 day'", tbl))
+	}
+	tk.MustQuery("split table t1 by (5), (10), (15)").Check(testkit.Rows("3 1"))
+	tk.MustExec("alter table t4 attributes='ttl=created_at + interval 1 day,ttl_enable=off'")
+
+	m := newJobManager(tk)
+	require.NoError(t, m.RunJobs(context.Background(), now, alwaysRunning))
+	for _, tbl := range []string{"t1", "t2", "t3"} {
+		tk.MustQuery(fmt.Sprintf("select count(*) from %s", tbl)).Check(testkit.Rows("10"))
+		tk.MustQuery(fmt.Sprintf("select count(*) from %s where id %% 2 = 0", tbl)).Check(testkit.Rows("0"))
+	}
+	tk.MustQuery("select count(*) from t4").Check(testkit.Rows("20"))
+	tk.MustQuery(`select table_name, ttl_column, ttl_interval, ttl_enable, job_state, total_regions = scanned_regions, deleted_rows, error_message
+		from information_schema.tidb_ttl_table_status where table_schema = 'test' order by table_name`).Check(testkit.Rows(
+		"t1 created_at INTERVAL 1 DAY ON finished 1 10 <nil>",
+		"t2 created_at INTERVAL 1 DAY ON finished 1 10 <nil>",
+		"t3 created_at INTERVAL 1 DAY ON finished 1 10 <nil>",
+		"t4 created_at INTERVAL 1 DAY OFF <nil> <nil> <nil> <nil>",
+	))
+	tk.MustQuery("select total_regions from information_schema.tidb_ttl_table_status where table_name = 't1'").Check(testkit.Rows("4"))
+	tk.MustQuery("select total_regions from information_schema.tidb_ttl_table_status where table_name = 't3'").Check(testkit.Rows("3"))
+
+	// The jobs are not run again within tidb_ttl_job_run_interval.
+	insertRows(tk, "t1", now, 20, 2)
+	require.NoError(t, m.RunJobs(context.Background(), now.Add(time.Minute), alwaysRunning))
+	tk.MustQuery("select count(*) from t1").Check(testkit.Rows("12"))
+	require.NoError(t, m.RunJobs(context.Background(), now.Add(time.Hour), alwaysRunning))
+	tk.MustQuery("select count(*) from t1").Check(testkit.Rows("11"))
+	tk.MustQuery("select deleted_rows from information_schema.tidb_ttl_table_status where table_name = 't1'").Check(testkit.Rows("1"))
+
+	// The status of the dropped table or the table without TTL is removed.
+	tk.MustExec("drop table t2")
+	tk.MustExec("alter table t3 attributes=default")
+	require.NoError(t, m.RunJobs(context.Background(), now.Add(time.Hour), alwaysRunning))
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_table_status").Check(testkit.Rows("1"))
+}
+
+func TestRunJobsTimeZone(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.time_zone = '+08:00'")
+	defer tk.MustExec("set @@global.time_zone = default")
+	tk.MustExec("set @@time_zone = '+08:00'")
+	now := time.Now()
+	tk.MustExec("create table t1 (id int primary key, created_at datetime)")
+	tk.MustExec("create table t2 (id int primary key, created_at timestamp)")
+	for _, tbl := range []string{"t1", "t2"} {
+		// The rows are written in the global time_zone, the even ones are expired.
+		for i := 0; i < 4; i++ {
+			createdAt := now.Add(-time.Hour)
+			if i%2 == 0 {
+				createdAt = now.Add(-6 * time.Hour)
+			}
+			tk.MustExec(fmt.Sprintf("insert into %s values (%d, '%s')", tbl, i, createdAt.In(time.FixedZone("", 8*60*60)).Format("2006-01-02 15:04:05")))
+		}
+		tk.MustExec(fmt.Sprintf("alter table %s attributes='ttl=created_at + interval 5 hour'", tbl))
+	}
+
+	// The job follows the global time_zone rather than the time zone of its session.
+	jobTK := testkit.NewTestKit(t, store)
+	jobTK.MustExec("set @@time_zone = '-05:00'")
+	require.NoError(t, newJobManager(jobTK).RunJobs(context.Background(), now, alwaysRunning))
+	for _, tbl := range []string{"t1", "t2"} {
+		tk.MustQuery(fmt.Sprintf("select id from %s order by id", tbl)).Check(testkit.Rows("1", "3"))
+	}
+}
+
+func TestRunJobsStopped(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	now := time.Now()
+	tk.MustExec("create table t (id int primary key, created_at datetime)")
+	insertRows(tk, "t", now, 0, 10)
+	tk.MustExec("alter table t attributes='ttl=created_at + interval 1 day'")
+
+	m := newJobManager(tk)
+	tk.MustExec("set global tidb_ttl_job_enable = off")
+	require.NoError(t, m.RunJobs(context.Background(), now, alwaysRunning))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("10"))
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_table_status").Check(testkit.Rows("0"))
+	tk.MustExec("set global tidb_ttl_job_enable = on")
+
+	// The job stopped in the middle is left running, and it is restarted by the next call.
+	calls := 0
+	stopAfterStart := func() bool {
+		calls++
+		return calls <= 1
+	}
+	require.NoError(t, m.RunJobs(context.Background(), now, stopAfterStart))
+	tk.MustQuery("select job_state, count(*) from mysql.tidb_ttl_table_status, t group by job_state").Check(testkit.Rows("running 10"))
+	require.NoError(t, m.RunJobs(context.Background(), now.Add(time.Minute), alwaysRunning))
+	tk.MustQuery("select job_state, deleted_rows from mysql.tidb_ttl_table_status").Check(testkit.Rows("finished 5"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+}
+
+func TestRunJobsWithInvalidColumn(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, created_at datetime)")
+	tk.MustExec("alter table t attributes='ttl=created_at + interval 1 day'")
+	tk.MustExec("alter table t modify column created_at varchar(32)")
+
+	m := newJobManager(tk)
+	require.NoError(t, m.RunJobs(context.Background(), time.Now(), alwaysRunning))
+	tk.MustQuery("select job_state, error_message like '%is not a time column' from mysql.tidb_ttl_table_status").Check(testkit.Rows("failed 1"))
+}
@@ -30,6 +30,16 @@ const UnspecifiedLength = -1
 // ErrorLength is error length for blob or text.
 const ErrorLength = 0
 
+// ArrayFlag marks the hidden column of a multi-valued index part, which stores the JSON array evaluated
+// from CAST(expr AS type ARRAY) and has an index entry for each element of the array. It's not defined
+// by the parser, so a high bit is used to avoid the conflicts with the flags in mysql.
+const ArrayFlag uint = 1 << 30
+
+// HasArrayFlag checks if ArrayFlag is set.
+func HasArrayFlag(flag uint) bool {
+	return (flag & ArrayFlag) > 0
+}
+
 // FieldType records field type information.
 type FieldType = ast.FieldType
 
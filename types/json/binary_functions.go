@@ -1021,6 +1021,51 @@ func ContainsBinary(obj, target BinaryJSON) bool {
 	}
 }
 
+// MemberOfBinary is the implementation of MEMBER OF in MySQL, it returns whether the value is an element
+// of the array. If the second argument is not an array, it's compared with the value directly.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-search-functions.html#operator_member-of
+func MemberOfBinary(value, array BinaryJSON) bool {
+	if array.TypeCode != TypeCodeArray {
+		return CompareBinary(value, array) == 0
+	}
+	len := array.GetElemCount()
+	for i := 0; i < len; i++ {
+		if CompareBinary(array.arrayGetElem(i), value) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// OverlapsBinary is the implementation of JSON_OVERLAPS in MySQL. Two objects overlap if they share a key-value
+// pair, otherwise the non-array document is wrapped as an array and they overlap if they share an element.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-search-functions.html#function_json-overlaps
+func OverlapsBinary(obj, target BinaryJSON) bool {
+	if obj.TypeCode == TypeCodeObject && target.TypeCode == TypeCodeObject {
+		len := target.GetElemCount()
+		for i := 0; i < len; i++ {
+			exp, exists := obj.objectSearchKey(target.objectGetKey(i))
+			if exists && CompareBinary(exp, target.objectGetVal(i)) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if obj.TypeCode != TypeCodeArray {
+		obj, target = target, obj
+	}
+	if target.TypeCode != TypeCodeArray {
+		return MemberOfBinary(target, obj)
+	}
+	len := target.GetElemCount()
+	for i := 0; i < len; i++ {
+		if MemberOfBinary(target.arrayGetElem(i), obj) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetElemDepth for JSON_DEPTH
 // Returns the maximum depth of a JSON document
 // rules referenced by MySQL JSON_DEPTH function
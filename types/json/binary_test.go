@@ -400,6 +400,62 @@ func TestBinaryJSONContains(t *testing.T) {
 	}
 }
 
+func TestBinaryJSONMemberOf(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		value    string
+		array    string
+		expected bool
+	}{
+		{`1`, `[1, 2]`, true},
+		{`1.0`, `[1, 2]`, true},
+		{`"1"`, `[1, 2]`, false},
+		{`3`, `[1, 2]`, false},
+		{`[1]`, `[[1], 2]`, true},
+		{`[1]`, `[1, 2]`, false},
+		{`{"a": 1}`, `[{"a": 1}]`, true},
+		{`1`, `1`, true},
+		{`1`, `{"a": 1}`, false},
+		{`1`, `[]`, false},
+	}
+	for _, test := range tests {
+		value := mustParseBinaryFromString(t, test.value)
+		array := mustParseBinaryFromString(t, test.array)
+		require.Equal(t, test.expected, MemberOfBinary(value, array), "%s member of %s", test.value, test.array)
+	}
+}
+
+func TestBinaryJSONOverlaps(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		input    string
+		target   string
+		expected bool
+	}{
+		{`[1, 2]`, `[2, 3]`, true},
+		{`[1, 2]`, `[3, 4]`, false},
+		{`[1, 2]`, `[]`, false},
+		{`[1, [2]]`, `[2]`, false},
+		{`[1, [2]]`, `[[2]]`, true},
+		{`[1, 2]`, `2`, true},
+		{`2`, `[1, 2]`, true},
+		{`2`, `2`, true},
+		{`2`, `3`, false},
+		{`{"a": 1, "b": 2}`, `{"b": 2}`, true},
+		{`{"a": 1, "b": 2}`, `{"b": 1}`, false},
+		{`{"a": 1}`, `[{"a": 1}]`, true},
+		{`{"a": 1}`, `[1]`, false},
+	}
+	for _, test := range tests {
+		obj := mustParseBinaryFromString(t, test.input)
+		target := mustParseBinaryFromString(t, test.target)
+		require.Equal(t, test.expected, OverlapsBinary(obj, target), "json_overlaps(%s, %s)", test.input, test.target)
+		require.Equal(t, test.expected, OverlapsBinary(target, obj), "json_overlaps(%s, %s)", test.target, test.input)
+	}
+}
+
 func TestBinaryJSONCopy(t *testing.T) {
 	t.Parallel()
 
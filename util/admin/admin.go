@@ -17,6 +17,7 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"math"
 	"sort"
 	"time"
@@ -25,6 +26,8 @@ import (
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
@@ -111,12 +114,16 @@ func IsJobRollbackable(job *model.Job) bool {
 		}
 	case model.ActionAddTablePartition:
 		return job.SchemaState == model.StateNone || job.SchemaState == model.StateReplicaOnly
+	case ddlutil.ActionReorganizePartition, ddlutil.ActionAlterTablePartitioning, ddlutil.ActionRemovePartitioning:
+		// The reads have been switched to the new partitions in the delete reorganization state.
+		return job.SchemaState != model.StateDeleteReorganization
 	case model.ActionDropColumn, model.ActionDropColumns, model.ActionDropTablePartition,
 		model.ActionRebaseAutoID, model.ActionShardRowID,
 		model.ActionTruncateTable, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
-		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable, model.ActionModifyTableAutoIdCache:
+		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable, model.ActionModifyTableAutoIdCache,
+		model.ActionAddCheckConstraint, model.ActionDropCheckConstraint, model.ActionAlterCheckConstraint:
 		return job.SchemaState == model.StateNone
 	}
 	return true
@@ -417,6 +424,69 @@ func CheckRecordAndIndex(sessCtx sessionctx.Context, txn kv.Transaction, t table
 	return nil
 }
 
+// CheckMultiValuedIndexCount checks whether the number of entries in the multi-valued index is equal to the
+// number of distinct entries the table records should have, since a record has an entry for each distinct element.
+func CheckMultiValuedIndexCount(sessCtx sessionctx.Context, txn kv.Transaction, t table.Table, idx table.Index) error {
+	sc := sessCtx.GetSessionVars().StmtCtx
+	mvOffset := table.FindMultiValuedColumn(t.Meta(), idx.Meta())
+	cols := make([]*table.Column, len(idx.Meta().Columns))
+	for i, col := range idx.Meta().Columns {
+		cols[i] = t.Cols()[col.Offset]
+	}
+	elemTp, err := table.MultiValuedElemType(cols[mvOffset].ToInfo())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var recordCnt int64
+	startKey := tablecodec.EncodeRecordKey(t.RecordPrefix(), kv.IntHandle(math.MinInt64))
+	countFunc := func(h kv.Handle, vals []types.Datum, cols []*table.Column) (bool, error) {
+		if vals[mvOffset].IsNull() {
+			recordCnt++
+			return true, nil
+		}
+		elems, err := table.MultiValuedElems(sc, vals[mvOffset], elemTp, cols[mvOffset].GeneratedExprString)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		keys := make(map[string]struct{}, len(elems))
+		for _, elem := range elems {
+			vals[mvOffset] = elem
+			key, _, err := idx.GenIndexKey(sc, vals, h, nil)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			keys[string(key)] = struct{}{}
+		}
+		recordCnt += int64(len(keys))
+		return true, nil
+	}
+	if err := iterRecords(sessCtx, txn, t, startKey, cols, countFunc); err != nil {
+		return errors.Trace(err)
+	}
+
+	var idxCnt int64
+	it, err := idx.SeekFirst(txn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer it.Close()
+	for {
+		_, _, err := it.Next()
+		if terror.ErrorEqual(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		idxCnt++
+	}
+	if recordCnt != idxCnt {
+		return ErrAdminCheckTable.GenWithStack("table entry count %d != index(%s) count %d", recordCnt, idx.Meta().Name.O, idxCnt)
+	}
+	return nil
+}
+
 func makeRowDecoder(t table.Table, sctx sessionctx.Context) (*decoder.RowDecoder, error) {
 	dbName := model.NewCIStr(sctx.GetSessionVars().CurrentDB)
 	exprCols, _, err := expression.ColumnInfos2ColumnsAndNames(sctx, dbName, t.Meta().Name, t.Meta().Cols(), t.Meta())
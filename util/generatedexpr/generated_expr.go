@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 )
 
@@ -70,6 +71,16 @@ func ParseExpression(expr string) (node ast.ExprNode, err error) {
 	return node, util.SyntaxError(err)
 }
 
+// UnwrapArrayCast unwraps the generated expression of a multi-valued index column, which is stored as
+// CAST(expr AS type) without the ARRAY keyword. It returns the JSON expression and the element type.
+func UnwrapArrayCast(node ast.ExprNode) (ast.ExprNode, *types.FieldType, error) {
+	cast, ok := node.(*ast.FuncCastExpr)
+	if !ok {
+		return nil, nil, errors.Errorf("invalid generated expression of multi-valued index")
+	}
+	return cast.Expr, cast.Tp, nil
+}
+
 // SimpleResolveName resolves all column names in the expression node.
 func SimpleResolveName(node ast.ExprNode, tblInfo *model.TableInfo) (ast.ExprNode, error) {
 	nr := nameResolver{tblInfo, nil}
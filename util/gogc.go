@@ -18,6 +18,7 @@ import (
 	"os"
 	"runtime/debug"
 	"strconv"
+	"sync"
 	"sync/atomic"
 
 	"github.com/pingcap/tidb/metrics"
@@ -25,6 +26,18 @@ import (
 
 var gogcValue int64
 
+// gogcMu serializes SetGOGC and TuneGOGCForMemoryLimit, so that the GOGC in effect is always decided by
+// the latest gogcValue and tunedGOGC.
+var gogcMu sync.Mutex
+
+// tunedGOGC is the GOGC set by TuneGOGCForMemoryLimit, 0 means GOGC is not tuned and gogcValue takes effect.
+// It's protected by gogcMu.
+var tunedGOGC int
+
+// minTunedGOGC is the lower bound of the tuned GOGC, so that the GC doesn't run continuously when
+// the heap is close to the memory limit.
+const minTunedGOGC = 10
+
 func init() {
 	gogcValue = 100
 	if val, err := strconv.Atoi(os.Getenv("GOGC")); err == nil {
@@ -38,12 +51,51 @@ func SetGOGC(val int) {
 	if val <= 0 {
 		val = 100
 	}
+	gogcMu.Lock()
+	defer gogcMu.Unlock()
+	atomic.StoreInt64(&gogcValue, int64(val))
+	// The tuned GOGC keeps taking effect until the heap is away from the memory limit,
+	// unless the new GOGC is even lower.
+	if tunedGOGC != 0 && tunedGOGC < val {
+		return
+	}
+	tunedGOGC = 0
 	debug.SetGCPercent(val)
 	metrics.GOGC.Set(float64(val))
-	atomic.StoreInt64(&gogcValue, int64(val))
 }
 
 // GetGOGC returns the current value of GOGC.
 func GetGOGC() int {
 	return int(atomic.LoadInt64(&gogcValue))
 }
+
+// TuneGOGCForMemoryLimit tunes GOGC down when the heap in use exceeds limit*trigger, so that the next GC is
+// triggered before the heap reaches the limit. GOGC is restored to the value set by SetGOGC when the heap
+// falls below limit*trigger again. limit == 0 means there is no memory limit.
+func TuneGOGCForMemoryLimit(heapInUse, limit uint64, trigger float64) {
+	gogcMu.Lock()
+	defer gogcMu.Unlock()
+	gogc := GetGOGC()
+	var val int
+	if limit > 0 && heapInUse > 0 && float64(heapInUse) >= float64(limit)*trigger {
+		if heapInUse < limit {
+			// The heap grows by GOGC percent before the next GC.
+			val = int((limit - heapInUse) * 100 / heapInUse)
+		}
+		if val < minTunedGOGC {
+			val = minTunedGOGC
+		}
+		if val >= gogc {
+			val = 0
+		}
+	}
+	if tunedGOGC == val {
+		return
+	}
+	tunedGOGC = val
+	if val == 0 {
+		val = gogc
+	}
+	debug.SetGCPercent(val)
+	metrics.GOGC.Set(float64(val))
+}
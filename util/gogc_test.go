@@ -0,0 +1,105 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"runtime/debug"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func currentGCPercent() int {
+	val := debug.SetGCPercent(100)
+	debug.SetGCPercent(val)
+	return val
+}
+
+func TestTuneGOGCForMemoryLimit(t *testing.T) {
+	defer SetGOGC(GetGOGC())
+	defer TuneGOGCForMemoryLimit(0, 0, 0.7)
+	SetGOGC(100)
+
+	// The heap is below limit*trigger.
+	TuneGOGCForMemoryLimit(600, 1000, 0.7)
+	assert.Equal(t, 100, currentGCPercent())
+
+	// The heap grows to the limit before the next GC.
+	TuneGOGCForMemoryLimit(800, 1000, 0.7)
+	assert.Equal(t, 25, currentGCPercent())
+	TuneGOGCForMemoryLimit(990, 1000, 0.7)
+	assert.Equal(t, minTunedGOGC, currentGCPercent())
+	TuneGOGCForMemoryLimit(2000, 1000, 0.7)
+	assert.Equal(t, minTunedGOGC, currentGCPercent())
+
+	// The tuned GOGC takes effect until the heap is below limit*trigger again.
+	SetGOGC(200)
+	assert.Equal(t, minTunedGOGC, currentGCPercent())
+	assert.Equal(t, 200, GetGOGC())
+	TuneGOGCForMemoryLimit(500, 1000, 0.7)
+	assert.Equal(t, 200, currentGCPercent())
+
+	// The tuned GOGC never exceeds the GOGC set by users.
+	SetGOGC(20)
+	TuneGOGCForMemoryLimit(800, 1000, 0.7)
+	assert.Equal(t, 20, currentGCPercent())
+
+	// A GOGC set by users which is lower than the tuned one takes effect at once.
+	SetGOGC(100)
+	TuneGOGCForMemoryLimit(800, 1000, 0.7)
+	assert.Equal(t, 25, currentGCPercent())
+	SetGOGC(15)
+	assert.Equal(t, 15, currentGCPercent())
+	TuneGOGCForMemoryLimit(800, 1000, 0.7)
+	assert.Equal(t, 15, currentGCPercent())
+
+	// No limit.
+	SetGOGC(100)
+	TuneGOGCForMemoryLimit(800, 0, 0.7)
+	assert.Equal(t, 100, currentGCPercent())
+}
+
+func TestSetGOGCWhileTuning(t *testing.T) {
+	defer SetGOGC(GetGOGC())
+	defer TuneGOGCForMemoryLimit(0, 0, 0.7)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetGOGC(100 + (i*100+j)%200)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				TuneGOGCForMemoryLimit(800, 1000, 0.7)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The heap stays near the limit, the tuned GOGC must not be overwritten by the GOGC set by users.
+	TuneGOGCForMemoryLimit(800, 1000, 0.7)
+	assert.Equal(t, 25, currentGCPercent())
+	// The latest GOGC set by users is restored.
+	SetGOGC(150)
+	assert.Equal(t, 25, currentGCPercent())
+	TuneGOGCForMemoryLimit(500, 1000, 0.7)
+	assert.Equal(t, 150, currentGCPercent())
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvcache
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// InstancePlanCache is the plan cache shared by all the sessions of the TiDB instance.
+var InstancePlanCache = NewInstanceLRUCache(math.MaxInt64, memory.LabelForInstancePlanCache)
+
+// InstanceLRUCache is a least recently used cache shared by all the sessions of a TiDB instance, it's safe for
+// concurrent use. Each key has a pool of values, a value taken from the pool by Take is used by only one user until
+// it's put back by Put, so the values themselves don't need to be safe for concurrent use.
+// The memory usage of the pooled values is tracked, and the least recently used keys are evicted once it exceeds
+// the quota.
+type InstanceLRUCache struct {
+	mu         sync.Mutex
+	quota      int64
+	elements   map[string]*list.Element
+	cache      *list.List
+	memTracker *memory.Tracker
+
+	// onEvict function will be called if any eviction happened
+	onEvict func(Key, Value)
+}
+
+// instanceCacheEntry is the value of list.Element.
+type instanceCacheEntry struct {
+	key      Key
+	values   []Value
+	memUsage []int64
+	hits     uint64
+	lastUsed time.Time
+}
+
+func (entry *instanceCacheEntry) totalMemUsage() int64 {
+	var total int64
+	for _, usage := range entry.memUsage {
+		total += usage
+	}
+	return total
+}
+
+// InstanceCacheItem is the snapshot of a key in the InstanceLRUCache.
+type InstanceCacheItem struct {
+	Key Key
+	// Values is the number of the values which are not taken by any user.
+	Values   int
+	Hits     uint64
+	MemUsage int64
+	LastUsed time.Time
+}
+
+// NewInstanceLRUCache creates an InstanceLRUCache object, whose memory usage is limited by "quota" bytes.
+func NewInstanceLRUCache(quota int64, label int) *InstanceLRUCache {
+	return &InstanceLRUCache{
+		quota:      quota,
+		elements:   make(map[string]*list.Element),
+		cache:      list.New(),
+		memTracker: memory.NewTracker(label, -1),
+	}
+}
+
+// SetOnEvict set the function called on each evicted value.
+func (l *InstanceLRUCache) SetOnEvict(onEvict func(Key, Value)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onEvict = onEvict
+}
+
+// MemTracker returns the memory tracker of the cache.
+func (l *InstanceLRUCache) MemTracker() *memory.Tracker {
+	return l.memTracker
+}
+
+// Take removes the first value of the key for which match returns true from the cache and returns it. The caller
+// owns the value until it's put back.
+func (l *InstanceLRUCache) Take(key Key, match func(Value) bool) (value Value, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, exists := l.elements[string(key.Hash())]
+	if !exists {
+		return nil, false
+	}
+	entry := element.Value.(*instanceCacheEntry)
+	for i, v := range entry.values {
+		if !match(v) {
+			continue
+		}
+		l.memTracker.Consume(-entry.memUsage[i])
+		entry.values = append(entry.values[:i], entry.values[i+1:]...)
+		entry.memUsage = append(entry.memUsage[:i], entry.memUsage[i+1:]...)
+		entry.hits++
+		entry.lastUsed = time.Now()
+		l.cache.MoveToFront(element)
+		return v, true
+	}
+	return nil, false
+}
+
+// Put adds the value of the key to the cache, memUsage is the memory used by the value.
+func (l *InstanceLRUCache) Put(key Key, value Value, memUsage int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hash := string(key.Hash())
+	element, exists := l.elements[hash]
+	if exists {
+		l.cache.MoveToFront(element)
+	} else {
+		element = l.cache.PushFront(&instanceCacheEntry{key: key})
+		l.elements[hash] = element
+	}
+	entry := element.Value.(*instanceCacheEntry)
+	entry.values = append(entry.values, value)
+	entry.memUsage = append(entry.memUsage, memUsage)
+	entry.lastUsed = time.Now()
+	l.memTracker.Consume(memUsage)
+	l.evictIfNeeded()
+}
+
+// evictIfNeeded evicts the least recently used keys until the memory usage doesn't exceed the quota.
+func (l *InstanceLRUCache) evictIfNeeded() {
+	for l.memTracker.BytesConsumed() > l.quota {
+		lru := l.cache.Back()
+		if lru == nil {
+			break
+		}
+		l.removeElement(lru, true)
+	}
+}
+
+func (l *InstanceLRUCache) removeElement(element *list.Element, evicted bool) {
+	entry := element.Value.(*instanceCacheEntry)
+	l.cache.Remove(element)
+	delete(l.elements, string(entry.key.Hash()))
+	l.memTracker.Consume(-entry.totalMemUsage())
+	if evicted && l.onEvict != nil {
+		for _, v := range entry.values {
+			l.onEvict(entry.key, v)
+		}
+	}
+}
+
+// SetQuota sets the memory quota of the cache in bytes, the least recently used keys are evicted if the memory
+// usage exceeds the new quota.
+func (l *InstanceLRUCache) SetQuota(quota int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.quota = quota
+	l.evictIfNeeded()
+}
+
+// Quota returns the memory quota of the cache in bytes.
+func (l *InstanceLRUCache) Quota() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.quota
+}
+
+// DeleteAll deletes all the values from the cache, the values being used are not affected.
+func (l *InstanceLRUCache) DeleteAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for lru := l.cache.Back(); lru != nil; lru = l.cache.Back() {
+		l.removeElement(lru, false)
+	}
+}
+
+// Size gets the number of the keys in the cache.
+func (l *InstanceLRUCache) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cache.Len()
+}
+
+// Items returns the snapshots of all the keys in the cache, from the most recently used one to the least.
+func (l *InstanceLRUCache) Items() []InstanceCacheItem {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	items := make([]InstanceCacheItem, 0, l.cache.Len())
+	for ele := l.cache.Front(); ele != nil; ele = ele.Next() {
+		entry := ele.Value.(*instanceCacheEntry)
+		items = append(items, InstanceCacheItem{
+			Key:      entry.key,
+			Values:   len(entry.values),
+			Hits:     entry.hits,
+			MemUsage: entry.totalMemUsage(),
+			LastUsed: entry.lastUsed,
+		})
+	}
+	return items
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvcache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceLRUTakePut(t *testing.T) {
+	t.Parallel()
+
+	lru := NewInstanceLRUCache(100, memory.LabelForInstancePlanCache)
+	key := newMockHashKey(1)
+	matchAll := func(Value) bool { return true }
+
+	_, ok := lru.Take(key, matchAll)
+	require.False(t, ok)
+
+	lru.Put(key, int64(1), 10)
+	lru.Put(key, int64(2), 20)
+	require.Equal(t, 1, lru.Size())
+	require.Equal(t, int64(30), lru.MemTracker().BytesConsumed())
+
+	// A value is taken out of the pool, the other users can't take it again.
+	v, ok := lru.Take(key, func(v Value) bool { return v.(int64) == 2 })
+	require.True(t, ok)
+	require.Equal(t, int64(2), v)
+	require.Equal(t, int64(10), lru.MemTracker().BytesConsumed())
+	_, ok = lru.Take(key, func(v Value) bool { return v.(int64) == 2 })
+	require.False(t, ok)
+
+	v, ok = lru.Take(key, matchAll)
+	require.True(t, ok)
+	require.Equal(t, int64(1), v)
+	_, ok = lru.Take(key, matchAll)
+	require.False(t, ok)
+
+	lru.Put(key, v, 10)
+	items := lru.Items()
+	require.Len(t, items, 1)
+	require.Equal(t, key, items[0].Key)
+	require.Equal(t, 1, items[0].Values)
+	require.Equal(t, uint64(2), items[0].Hits)
+	require.Equal(t, int64(10), items[0].MemUsage)
+
+	lru.DeleteAll()
+	require.Equal(t, 0, lru.Size())
+	require.Equal(t, int64(0), lru.MemTracker().BytesConsumed())
+}
+
+func TestInstanceLRUQuota(t *testing.T) {
+	t.Parallel()
+
+	lru := NewInstanceLRUCache(100, memory.LabelForInstancePlanCache)
+	evicted := make(map[Key][]Value)
+	lru.SetOnEvict(func(key Key, value Value) {
+		evicted[key] = append(evicted[key], value)
+	})
+	keys := make([]*mockCacheKey, 3)
+	for i := range keys {
+		keys[i] = newMockHashKey(int64(i))
+		lru.Put(keys[i], int64(i), 40)
+	}
+	// The least recently used key 0 is evicted.
+	require.Equal(t, 2, lru.Size())
+	require.Equal(t, []Value{int64(0)}, evicted[keys[0]])
+	require.Equal(t, int64(80), lru.MemTracker().BytesConsumed())
+
+	// Taking a value makes the key the most recently used one.
+	v, ok := lru.Take(keys[1], func(Value) bool { return true })
+	require.True(t, ok)
+	lru.Put(keys[1], v, 40)
+	lru.SetQuota(50)
+	require.Equal(t, 1, lru.Size())
+	require.Equal(t, []Value{int64(2)}, evicted[keys[2]])
+	require.Equal(t, int64(50), lru.Quota())
+
+	// A value larger than the quota is not kept.
+	lru.Put(keys[0], int64(0), 60)
+	require.Equal(t, 0, lru.Size())
+	require.Equal(t, int64(0), lru.MemTracker().BytesConsumed())
+}
+
+func TestInstanceLRUConcurrentTake(t *testing.T) {
+	t.Parallel()
+
+	lru := NewInstanceLRUCache(1<<20, memory.LabelForInstancePlanCache)
+	key := newMockHashKey(1)
+	for i := 0; i < 10; i++ {
+		lru.Put(key, i, 1)
+	}
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		taken = make(map[int]int)
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, ok := lru.Take(key, func(Value) bool { return true })
+			require.True(t, ok)
+			mu.Lock()
+			taken[v.(int)]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	// Each value is taken by exactly one user.
+	require.Len(t, taken, 10)
+	for _, cnt := range taken {
+		require.Equal(t, 1, cnt)
+	}
+}
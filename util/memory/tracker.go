@@ -552,4 +552,14 @@ const (
 	LabelForSimpleTask int = -18
 	// LabelForCTEStorage represents the label of CTE storage
 	LabelForCTEStorage int = -19
+	// LabelForWindowPartition represents the label of the window partition
+	LabelForWindowPartition int = -20
+	// LabelForHashAggWorker represents the label of the hash agg worker
+	LabelForHashAggWorker int = -21
+	// LabelForCursorFetch represents the label of the server-side cursors
+	LabelForCursorFetch int = -22
+	// LabelForInstancePlanCache represents the label of the instance plan cache
+	LabelForInstancePlanCache int = -23
+	// LabelForForeignKey represents the label of the foreign key checker
+	LabelForForeignKey int = -24
 )
@@ -280,6 +280,9 @@ func (c *Context) StoreQueryFeedback(_ interface{}) {}
 // StoreIndexUsage strores the index usage information.
 func (c *Context) StoreIndexUsage(_ int64, _ int64, _ int64) {}
 
+// StoreColumnStatsUsage stores the column stats usage information.
+func (c *Context) StoreColumnStatsUsage(_ int64, _ int64) {}
+
 // GetTxnWriteThroughputSLI implements the sessionctx.Context interface.
 func (c *Context) GetTxnWriteThroughputSLI() *sli.TxnWriteThroughputSLI {
 	return &sli.TxnWriteThroughputSLI{}
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/tikv/client-go/v2/oracle"
 )
 
@@ -45,6 +46,7 @@ type ProcessInfo struct {
 	CurTxnStartTS    uint64
 	StmtCtx          *stmtctx.StatementContext
 	StatsInfo        func(interface{}) map[string]uint64
+	CursorStats      *CursorStats
 	// MaxExecutionTime is the timeout for select statement, in milliseconds.
 	// If the query takes too long, kill it.
 	MaxExecutionTime uint64
@@ -109,7 +111,31 @@ func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
 			diskConsumed = pi.StmtCtx.DiskTracker.BytesConsumed()
 		}
 	}
-	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz))
+	openCursors, cursorBytesConsumed, cursorDiskConsumed := int64(0), int64(0), int64(0)
+	if pi.CursorStats != nil {
+		openCursors = atomic.LoadInt64(&pi.CursorStats.OpenCursors)
+		cursorBytesConsumed = pi.CursorStats.MemTracker.BytesConsumed()
+		cursorDiskConsumed = pi.CursorStats.DiskTracker.BytesConsumed()
+	}
+	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz),
+		openCursors, cursorBytesConsumed, cursorDiskConsumed)
+}
+
+// CursorStats is the statistics of the server-side cursors opened by a connection.
+type CursorStats struct {
+	// OpenCursors is the number of the open cursors, it's accessed atomically.
+	OpenCursors int64
+	// MemTracker and DiskTracker track the usage of the cursors whose rows are materialized.
+	MemTracker  *memory.Tracker
+	DiskTracker *memory.Tracker
+}
+
+// NewCursorStats creates a new CursorStats.
+func NewCursorStats() *CursorStats {
+	return &CursorStats{
+		MemTracker:  memory.NewTracker(memory.LabelForCursorFetch, -1),
+		DiskTracker: memory.NewTracker(memory.LabelForCursorFetch, -1),
+	}
 }
 
 // ascServerStatus is a slice of all defined server status in ascending order.
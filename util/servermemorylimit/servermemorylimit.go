@@ -0,0 +1,234 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package servermemorylimit keeps the memory usage of the tidb-server under tidb_server_memory_limit.
+// It tunes GOGC when the heap is close to the limit, and cancels the query using the most memory when
+// the limit is exceeded, instead of letting the process be killed by the OOM killer.
+package servermemorylimit
+
+import (
+	"fmt"
+	"runtime"
+	rtmetrics "runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/parser"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+// OpsSessionKill is the operation of canceling the query using the most memory.
+const OpsSessionKill = "SessionKill"
+
+const (
+	// maxOpsHistory is the number of the operations kept in the history.
+	maxOpsHistory = 50
+	// maxSQLTextLen is the max length of the SQL text kept in the history.
+	maxSQLTextLen = 4096
+	// maxKillWaitTime is the max time to wait for a canceled query to finish. Another query may be
+	// canceled after it even if the canceled one is still running.
+	maxKillWaitTime = 30 * time.Second
+)
+
+// Handle is the handler of the server memory limit.
+type Handle struct {
+	exitCh chan struct{}
+	sm     atomic.Value
+}
+
+// NewServerMemoryLimitHandle builds a new server memory limit handler.
+func NewServerMemoryLimitHandle(exitCh chan struct{}) *Handle {
+	return &Handle{exitCh: exitCh}
+}
+
+// SetSessionManager sets the SessionManager which is used to fetching the info
+// of all active sessions.
+func (smlh *Handle) SetSessionManager(sm util.SessionManager) *Handle {
+	smlh.sm.Store(sm)
+	return smlh
+}
+
+// Run starts a server memory limit checker goroutine at the start time of the server.
+func (smlh *Handle) Run() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	sm := smlh.sm.Load().(util.SessionManager)
+	killer := &sessionKiller{}
+	samples := newHeapInUseSamples()
+	for {
+		select {
+		case <-ticker.C:
+			// The heap is not read when there is no limit, check only restores GOGC and forgets the canceled query.
+			var heapInUse uint64
+			if variable.ServerMemoryLimit.Load() > 0 {
+				heapInUse = readHeapInUse(samples)
+			}
+			killer.check(sm, heapInUse)
+		case <-smlh.exitCh:
+			return
+		}
+	}
+}
+
+// newHeapInUseSamples returns the samples of runtime/metrics whose sum is the HeapInuse of runtime.MemStats.
+// They're read without stopping the world, unlike runtime.ReadMemStats.
+func newHeapInUseSamples() []rtmetrics.Sample {
+	return []rtmetrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/memory/classes/heap/unused:bytes"},
+	}
+}
+
+func readHeapInUse(samples []rtmetrics.Sample) uint64 {
+	rtmetrics.Read(samples)
+	var heapInUse uint64
+	for _, sample := range samples {
+		if sample.Value.Kind() == rtmetrics.KindUint64 {
+			heapInUse += sample.Value.Uint64()
+		}
+	}
+	return heapInUse
+}
+
+// sessionKiller cancels the query using the most memory when the heap exceeds the limit. Only one query
+// is canceled at a time, the next one is chosen after the memory of the canceled one is released.
+type sessionKiller struct {
+	// killing is the process info of the canceled query which is still running.
+	killing  *util.ProcessInfo
+	killTime time.Time
+}
+
+func (k *sessionKiller) check(sm util.SessionManager, heapInUse uint64) {
+	limit := variable.ServerMemoryLimit.Load()
+	util.TuneGOGCForMemoryLimit(heapInUse, limit, variable.ServerMemoryLimitGCTrigger.Load())
+
+	if k.killing != nil {
+		// The session stores a new process info when the query finishes.
+		info, ok := sm.GetProcessInfo(k.killing.ID)
+		if ok && info == k.killing && time.Since(k.killTime) < maxKillWaitTime {
+			return
+		}
+		if ok && info == k.killing {
+			logutil.BgLogger().Warn("the query canceled by tidb_server_memory_limit is still running",
+				zap.Uint64("conn_id", info.ID), zap.Duration("wait_time", time.Since(k.killTime)))
+		}
+		k.killing = nil
+		// Release the memory of the canceled query at once, the heap is checked again in the next round.
+		runtime.GC()
+		return
+	}
+	if limit == 0 || heapInUse <= limit {
+		return
+	}
+
+	minSize := variable.ServerMemoryLimitSessMinSize.Load()
+	var target *util.ProcessInfo
+	var maxMem int64
+	for _, info := range sm.ShowProcessList() {
+		if len(info.Info) == 0 || info.StmtCtx == nil || info.StmtCtx.MemTracker == nil {
+			continue
+		}
+		mem := info.StmtCtx.MemTracker.BytesConsumed()
+		if mem > maxMem && uint64(mem) >= minSize {
+			target, maxMem = info, mem
+		}
+	}
+	if target == nil {
+		return
+	}
+
+	record := newOpsRecord(OpsSessionKill, limit, heapInUse, target, maxMem)
+	logutil.BgLogger().Warn("cancel the query using the most memory because tidb_server_memory_limit is exceeded",
+		zap.Uint64("conn_id", target.ID),
+		zap.String("user", target.User),
+		zap.String("database", target.DB),
+		zap.String("mem", memory.FormatBytes(maxMem)),
+		zap.String("heap_inuse", memory.FormatBytes(int64(heapInUse))),
+		zap.String("memory_limit", memory.FormatBytes(int64(limit))),
+		zap.String("sql", record.SQLText))
+	sm.Kill(target.ID, true)
+	metrics.ServerMemoryLimitKillCounter.Inc()
+	globalOpsHistory.add(record)
+	k.killing, k.killTime = target, time.Now()
+}
+
+// OpsRecord is an operation taken to keep the memory usage under tidb_server_memory_limit.
+// The records are shown in INFORMATION_SCHEMA.MEMORY_USAGE_OPS_HISTORY.
+type OpsRecord struct {
+	Time time.Time
+	Ops  string
+	// MemoryLimit and MemoryCurrent are the limit and the heap in use in bytes when the operation is taken.
+	MemoryLimit   uint64
+	MemoryCurrent uint64
+	// The following fields describe the canceled query, Mem is its memory usage in bytes.
+	ConnID    uint64
+	Mem       int64
+	Host      string
+	DB        string
+	User      string
+	SQLDigest string
+	SQLText   string
+}
+
+func newOpsRecord(ops string, limit, heapInUse uint64, info *util.ProcessInfo, mem int64) *OpsRecord {
+	sql := info.Info
+	if info.RedactSQL {
+		sql = parser.Normalize(sql)
+	}
+	if len(sql) > maxSQLTextLen {
+		sql = fmt.Sprintf("%s len(%d)", sql[:maxSQLTextLen], len(sql))
+	}
+	return &OpsRecord{
+		Time:          time.Now(),
+		Ops:           ops,
+		MemoryLimit:   limit,
+		MemoryCurrent: heapInUse,
+		ConnID:        info.ID,
+		Mem:           mem,
+		Host:          info.Host,
+		DB:            info.DB,
+		User:          info.User,
+		SQLDigest:     info.Digest,
+		SQLText:       sql,
+	}
+}
+
+type opsHistory struct {
+	sync.Mutex
+	records []*OpsRecord
+}
+
+var globalOpsHistory = &opsHistory{}
+
+func (h *opsHistory) add(record *OpsRecord) {
+	h.Lock()
+	defer h.Unlock()
+	if len(h.records) >= maxOpsHistory {
+		h.records = append(h.records[:0], h.records[1:]...)
+	}
+	h.records = append(h.records, record)
+}
+
+// OpsHistory returns the recent operations taken by the server memory limit, from the oldest to the newest.
+func OpsHistory() []*OpsRecord {
+	globalOpsHistory.Lock()
+	defer globalOpsHistory.Unlock()
+	return append([]*OpsRecord{}, globalOpsHistory.records...)
+}
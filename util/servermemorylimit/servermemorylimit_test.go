@@ -0,0 +1,147 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servermemorylimit
+
+import (
+	"crypto/tls"
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSessionManager struct {
+	processes map[uint64]*util.ProcessInfo
+	killed    []uint64
+}
+
+func (msm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
+	return msm.processes
+}
+
+func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
+func (msm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	info, ok := msm.processes[id]
+	return info, ok
+}
+
+func (msm *mockSessionManager) Kill(id uint64, query bool) {
+	msm.killed = append(msm.killed, id)
+}
+
+func (msm *mockSessionManager) KillAllConnections() {}
+
+func (msm *mockSessionManager) UpdateTLSConfig(*tls.Config) {}
+
+func (msm *mockSessionManager) ServerID() uint64 {
+	return 1
+}
+
+func newProcessInfo(id uint64, sql string, mem int64) *util.ProcessInfo {
+	tracker := memory.NewTracker(memory.LabelForSQLText, -1)
+	tracker.Consume(mem)
+	return &util.ProcessInfo{
+		ID:      id,
+		User:    "root",
+		Host:    "127.0.0.1",
+		DB:      "test",
+		Digest:  fmt.Sprintf("digest%d", id),
+		Info:    sql,
+		StmtCtx: &stmtctx.StatementContext{MemTracker: tracker},
+	}
+}
+
+func TestKillQueryUsingMostMemory(t *testing.T) {
+	variable.ServerMemoryLimit.Store(1 << 30)
+	variable.ServerMemoryLimitSessMinSize.Store(100)
+	defer func() {
+		variable.ServerMemoryLimit.Store(0)
+		variable.ServerMemoryLimitSessMinSize.Store(variable.DefTiDBServerMemoryLimitSessMinSize)
+		util.TuneGOGCForMemoryLimit(0, 0, variable.DefTiDBServerMemoryLimitGCTrigger)
+		globalOpsHistory.records = nil
+	}()
+	globalOpsHistory.records = nil
+
+	sm := &mockSessionManager{processes: map[uint64]*util.ProcessInfo{
+		1: newProcessInfo(1, "select * from t where a = 1", 500),
+		2: newProcessInfo(2, "select * from t2", 1000),
+		// The session is idle, the memory is not used by any query.
+		3: newProcessInfo(3, "", 2000),
+		// The memory usage is less than tidb_server_memory_limit_sess_min_size.
+		4: newProcessInfo(4, "select 1", 50),
+	}}
+	sm.processes[1].RedactSQL = true
+	killer := &sessionKiller{}
+
+	// The limit is not exceeded.
+	killer.check(sm, 1<<29)
+	require.Empty(t, sm.killed)
+
+	killer.check(sm, 1<<31)
+	require.Equal(t, []uint64{2}, sm.killed)
+	history := OpsHistory()
+	require.Len(t, history, 1)
+	require.Equal(t, OpsSessionKill, history[0].Ops)
+	require.Equal(t, uint64(1<<30), history[0].MemoryLimit)
+	require.Equal(t, uint64(1<<31), history[0].MemoryCurrent)
+	require.Equal(t, uint64(2), history[0].ConnID)
+	require.Equal(t, int64(1000), history[0].Mem)
+	require.Equal(t, "digest2", history[0].SQLDigest)
+	require.Equal(t, "select * from t2", history[0].SQLText)
+
+	// No other query is canceled until the canceled one finishes.
+	killer.check(sm, 1<<31)
+	require.Equal(t, []uint64{2}, sm.killed)
+	sm.processes[2] = newProcessInfo(2, "", 0)
+	killer.check(sm, 1<<31)
+	require.Equal(t, []uint64{2}, sm.killed)
+
+	killer.check(sm, 1<<31)
+	require.Equal(t, []uint64{2, 1}, sm.killed)
+	history = OpsHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, uint64(1), history[1].ConnID)
+	require.Equal(t, "select * from `t` where `a` = ?", history[1].SQLText)
+
+	// The limit is disabled.
+	sm.processes[1] = newProcessInfo(1, "", 0)
+	killer.check(sm, 1<<31)
+	variable.ServerMemoryLimit.Store(0)
+	killer.check(sm, 1<<31)
+	require.Equal(t, []uint64{2, 1}, sm.killed)
+}
+
+func TestOpsHistory(t *testing.T) {
+	h := &opsHistory{}
+	for i := 0; i < maxOpsHistory+10; i++ {
+		h.add(&OpsRecord{ConnID: uint64(i)})
+	}
+	require.Len(t, h.records, maxOpsHistory)
+	require.Equal(t, uint64(10), h.records[0].ConnID)
+	require.Equal(t, uint64(maxOpsHistory+9), h.records[maxOpsHistory-1].ConnID)
+}
+
+func TestReadHeapInUse(t *testing.T) {
+	samples := newHeapInUseSamples()
+	require.Greater(t, readHeapInUse(samples), uint64(0))
+}
@@ -54,6 +54,8 @@ type ExecOption struct {
 	IgnoreWarning bool
 	SnapshotTS    uint64
 	AnalyzeVer    int
+	// AnalyzePredicateColumns indicates whether ANALYZE only collects the statistics of the predicate columns.
+	AnalyzePredicateColumns bool
 }
 
 // OptionFuncAlias is defined for the optional paramater of ExecRestrictedStmt.
@@ -74,6 +76,11 @@ var ExecOptionAnalyzeVer2 OptionFuncAlias = func(option *ExecOption) {
 	option.AnalyzeVer = 2
 }
 
+// ExecOptionAnalyzePredicateColumns tells ExecRestrictedStmt to collect statistics of the predicate columns only.
+var ExecOptionAnalyzePredicateColumns OptionFuncAlias = func(option *ExecOption) {
+	option.AnalyzePredicateColumns = true
+}
+
 // ExecOptionWithSnapshot tells ExecRestrictedStmt to use a snapshot.
 func ExecOptionWithSnapshot(snapshot uint64) OptionFuncAlias {
 	return func(option *ExecOption) {
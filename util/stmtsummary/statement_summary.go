@@ -353,6 +353,23 @@ type BindableStmt struct {
 	PlanHint  string
 	Charset   string
 	Collation string
+
+	// The following fields are only set by GetBindableStmtPlans.
+	Digest        string
+	NormalizedSQL string
+	PlanDigest    string
+	ExecCount     int64
+	SumLatency    time.Duration
+	FirstSeen     time.Time
+	LastSeen      time.Time
+}
+
+// AvgLatency returns the average latency of the statement.
+func (stmt *BindableStmt) AvgLatency() time.Duration {
+	if stmt.ExecCount == 0 {
+		return 0
+	}
+	return stmt.SumLatency / time.Duration(stmt.ExecCount)
 }
 
 // GetMoreThanOnceBindableStmt gets users' select/update/delete SQLs that occurred more than once.
@@ -367,7 +384,7 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanOnceBindableStmt() []*BindableSt
 		func() {
 			ssbd.Lock()
 			defer ssbd.Unlock()
-			if ssbd.initialized && (ssbd.stmtType == "Select" || ssbd.stmtType == "Delete" || ssbd.stmtType == "Update" || ssbd.stmtType == "Insert" || ssbd.stmtType == "Replace") {
+			if ssbd.initialized && isBindableStmtType(ssbd.stmtType) {
 				if ssbd.history.Len() > 0 {
 					ssElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
 					ssElement.Lock()
@@ -396,6 +413,70 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanOnceBindableStmt() []*BindableSt
 	return stmts
 }
 
+// GetBindableStmtPlans gets all the plans of users' bindable SQLs, the execution information of each plan is
+// summarized from all the intervals in the history.
+func (ssMap *stmtSummaryByDigestMap) GetBindableStmtPlans() []*BindableStmt {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	stmts := make([]*BindableStmt, 0, len(values))
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		func() {
+			ssbd.Lock()
+			defer ssbd.Unlock()
+			if !ssbd.initialized || ssbd.history.Len() == 0 || !isBindableStmtType(ssbd.stmtType) {
+				return
+			}
+			var stmt *BindableStmt
+			for listElement := ssbd.history.Front(); listElement != nil; listElement = listElement.Next() {
+				ssElement := listElement.Value.(*stmtSummaryByDigestElement)
+				ssElement.Lock()
+				// Empty auth users means that it is an internal queries.
+				if len(ssElement.authUsers) == 0 {
+					ssElement.Unlock()
+					continue
+				}
+				if stmt == nil {
+					stmt = &BindableStmt{
+						Schema:        ssbd.schemaName,
+						Digest:        ssbd.digest,
+						NormalizedSQL: ssbd.normalizedSQL,
+						PlanDigest:    ssbd.planDigest,
+						FirstSeen:     ssElement.firstSeen,
+					}
+				}
+				// The latest sample is used, the same as GetMoreThanOnceBindableStmt.
+				stmt.Query = ssElement.sampleSQL
+				if ssElement.prepared {
+					stmt.Query = ssbd.normalizedSQL
+				}
+				stmt.PlanHint = ssElement.planHint
+				stmt.Charset = ssElement.charset
+				stmt.Collation = ssElement.collation
+				stmt.ExecCount += ssElement.execCount
+				stmt.SumLatency += ssElement.sumLatency
+				if ssElement.firstSeen.Before(stmt.FirstSeen) {
+					stmt.FirstSeen = ssElement.firstSeen
+				}
+				if stmt.LastSeen.Before(ssElement.lastSeen) {
+					stmt.LastSeen = ssElement.lastSeen
+				}
+				ssElement.Unlock()
+			}
+			if stmt != nil {
+				stmts = append(stmts, stmt)
+			}
+		}()
+	}
+	return stmts
+}
+
+func isBindableStmtType(stmtType string) bool {
+	return stmtType == "Select" || stmtType == "Delete" || stmtType == "Update" || stmtType == "Insert" || stmtType == "Replace"
+}
+
 // SetEnabled enables or disables statement summary in global(cluster) or session(server) scope.
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value string, inSession bool) error {
 	if err := ssMap.sysVars.setVariable(typeEnable, value, inSession); err != nil {